@@ -0,0 +1,40 @@
+import { check } from "k6";
+import avro from "k6/experimental/avro";
+import msgpack from "k6/experimental/msgpack";
+import protobuf from "k6/experimental/protobuf";
+import http from "k6/http";
+
+// Proto files and protosets can only be loaded in the init context
+const routeGuide = protobuf.load([], "../internal/lib/testutils/grpcservice/route_guide.proto");
+
+const user = avro.parse({
+    type: "record",
+    name: "User",
+    fields: [
+        { name: "id", type: "long" },
+        { name: "name", type: "string" },
+        { name: "email", type: ["null", "string"], default: null },
+    ],
+});
+
+export default function () {
+    const point = routeGuide.encode("main.Point", { latitude: 409146138, longitude: -746188906 });
+    check(routeGuide.decode("main.Point", point), {
+        "is protobuf round trip correct": (p) => p.latitude === 409146138 && p.longitude === -746188906,
+    });
+
+    const note = msgpack.encode({ message: "hello", at: new Date() });
+    check(msgpack.decode(note), {
+        "is msgpack round trip correct": (n) => n.message === "hello" && n.at instanceof Date,
+    });
+
+    const payload = user.encode({ id: 1, name: "k6" });
+    check(user.decode(payload), {
+        "is avro round trip correct": (u) => u.id === 1 && u.name === "k6" && u.email === null,
+    });
+
+    // The encoded values can be sent as binary payloads
+    http.post("http://httpbin.org/post", payload, {
+        headers: { "Content-Type": "avro/binary" },
+    });
+}
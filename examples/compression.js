@@ -0,0 +1,19 @@
+import { check } from "k6";
+import encoding from "k6/encoding";
+import http from "k6/http";
+
+export default function() {
+    // Build a pre-compressed payload, e.g. to publish it on a message queue
+    let str = JSON.stringify({ hello: "world" });
+    let compressed = encoding.compress(str, "gzip");
+    check(compressed, {
+        "is gzip round trip correct": (c) => encoding.decompress(c, "gzip", "s") === str,
+    });
+
+    // Inspect a compressed blob, fetched without a Content-Encoding header
+    let res = http.get("https://quickpizza.grafana.com/test.k6.io/static/favicon.ico", { responseType: "binary" });
+    let zstd = encoding.compress(res.body, "zstd");
+    check(zstd, {
+        "is zstd round trip correct": (c) => encoding.decompress(c, "zstd").byteLength === res.body.byteLength,
+    });
+};
@@ -0,0 +1,34 @@
+import { tempDir } from "k6/experimental/fs";
+import http from "k6/http";
+import { check } from "k6";
+import exec from "k6/execution";
+
+export const options = {
+	vus: 10,
+	iterations: 100,
+};
+
+export default async function() {
+	// Each Virtual User gets its own temporary directory, removed at the end of the test.
+	// Its content is limited to 64MB.
+	const dir = tempDir({ quota: 64 * 1024 * 1024 });
+
+	// Generate a unique file, chunk by chunk, without holding it in memory as a string
+	const name = `upload-${exec.vu.idInTest}-${exec.vu.iterationInScenario}.bin`;
+	const chunk = new Uint8Array(1024 * 1024);
+	chunk.fill(exec.vu.iterationInScenario % 256);
+
+	await dir.write(name, `${name}\n`);
+	for (let i = 0; i < 8; i++) {
+		await dir.append(name, chunk);
+	}
+
+	// The file content is read from the disk when the request body is built
+	const file = await dir.open(name);
+	const res = http.post("http://httpbin.org/post", {
+		upload: http.file(file, name, "application/octet-stream"),
+	});
+	check(res, { "is status 200": (r) => r.status === 200 });
+
+	await dir.remove(name);
+}
@@ -0,0 +1,34 @@
+import { check } from "k6";
+
+// fetch, Headers, Request, Response and AbortController are available globally,
+// so API clients written for browsers can be reused as they are.
+async function createUser(name) {
+    const res = await fetch("https://httpbin.org/post", {
+        method: "POST",
+        headers: new Headers({ "Content-Type": "application/json" }),
+        body: JSON.stringify({ name }),
+        // Abort the request if it takes more than 5 seconds
+        signal: AbortSignal.timeout(5000),
+    });
+    if (!res.ok) {
+        throw new Error(`unexpected status ${res.status}`);
+    }
+    return res.json();
+}
+
+export default async function () {
+    // The requests emit the same http_req_* metrics as the ones made with k6/http
+    const user = await createUser("k6");
+    check(user, {
+        "is name sent": (u) => u.json.name === "k6",
+    });
+
+    const controller = new AbortController();
+    const pending = fetch("https://httpbin.org/delay/10", { signal: controller.signal });
+    controller.abort();
+    try {
+        await pending;
+    } catch (err) {
+        check(err, { "is request aborted": (e) => e.name === "AbortError" });
+    }
+}
@@ -0,0 +1,48 @@
+export default async function () {
+  // input key material, salt and info from the test case 1 of RFC 5869
+  const ikm = hexToArrayBuffer("0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b");
+  const salt = hexToArrayBuffer("000102030405060708090a0b0c");
+  const info = hexToArrayBuffer("f0f1f2f3f4f5f6f7f8f9");
+
+  // import the input key material as CryptoKey
+  const importedKey = await crypto.subtle.importKey("raw", ikm, "HKDF", false, [
+    "deriveBits",
+    "deriveKey",
+  ]);
+
+  // derive 42 bytes of output key material using hkdf
+  const derivedBits = await crypto.subtle.deriveBits(
+    {
+      name: "HKDF",
+      hash: "SHA-256",
+      salt,
+      info,
+    },
+    importedKey,
+    42 * 8
+  );
+
+  const okm = arrayBufferToHex(derivedBits);
+  if (
+    okm !==
+    "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+  ) {
+    throw new Error("unexpected output key material: " + okm);
+  }
+
+  console.log("Derived Bits: ", okm);
+}
+
+const hexToArrayBuffer = (hex) => {
+  const view = new Uint8Array(hex.length / 2);
+  for (let i = 0; i < view.length; i++) {
+    view[i] = parseInt(hex.substr(i * 2, 2), 16);
+  }
+  return view.buffer;
+};
+
+const arrayBufferToHex = (buffer) => {
+  return Array.from(new Uint8Array(buffer))
+    .map((b) => b.toString(16).padStart(2, "0"))
+    .join("");
+};
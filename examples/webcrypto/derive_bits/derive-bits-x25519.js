@@ -0,0 +1,56 @@
+export default async function () {
+  // Generate a key pair for Alice
+  const aliceKeyPair = await crypto.subtle.generateKey(
+    {
+      name: "X25519",
+    },
+    true,
+    ["deriveKey", "deriveBits"]
+  );
+
+  // Generate a key pair for Bob
+  const bobKeyPair = await crypto.subtle.generateKey(
+    {
+      name: "X25519",
+    },
+    true,
+    ["deriveKey", "deriveBits"]
+  );
+
+  // Derive shared secret for Alice
+  const aliceSharedSecret = await deriveSharedSecret(
+    aliceKeyPair.privateKey,
+    bobKeyPair.publicKey
+  );
+
+  // Derive shared secret for Bob
+  const bobSharedSecret = await deriveSharedSecret(
+    bobKeyPair.privateKey,
+    aliceKeyPair.publicKey
+  );
+
+  const alice = printArrayBuffer(aliceSharedSecret);
+  const bob = printArrayBuffer(bobSharedSecret);
+  if (alice.join() !== bob.join()) {
+    throw new Error("shared secrets don't match");
+  }
+
+  console.log("alice shared secret: " + alice);
+  console.log("bob shared secret: " + bob);
+}
+
+async function deriveSharedSecret(privateKey, publicKey) {
+  return crypto.subtle.deriveBits(
+    {
+      name: "X25519",
+      public: publicKey, // An X25519 public key from the other party
+    },
+    privateKey, // Your X25519 private key
+    256 // the number of bits to derive
+  );
+}
+
+const printArrayBuffer = (buffer) => {
+  let view = new Uint8Array(buffer);
+  return Array.from(view);
+};
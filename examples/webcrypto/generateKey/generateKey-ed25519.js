@@ -0,0 +1,11 @@
+export default async function () {
+  const key = await crypto.subtle.generateKey(
+    {
+      name: "Ed25519",
+    },
+    true,
+    ["sign", "verify"]
+  );
+
+  console.log(JSON.stringify(key));
+}
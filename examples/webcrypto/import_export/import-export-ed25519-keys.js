@@ -0,0 +1,71 @@
+export default async function () {
+  // the example key pair from RFC 8037
+  const jwk = {
+    kty: "OKP",
+    crv: "Ed25519",
+    x: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+    d: "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+  };
+
+  const privateKey = await crypto.subtle.importKey(
+    "jwk",
+    jwk,
+    { name: "Ed25519" },
+    true,
+    ["sign"]
+  );
+
+  console.log("imported: " + JSON.stringify(privateKey));
+
+  // round-trip the private key through the pkcs8 format
+  const pkcs8 = await crypto.subtle.exportKey("pkcs8", privateKey);
+  const reimportedPrivateKey = await crypto.subtle.importKey(
+    "pkcs8",
+    pkcs8,
+    { name: "Ed25519" },
+    true,
+    ["sign"]
+  );
+
+  const exportedJWK = await crypto.subtle.exportKey("jwk", reimportedPrivateKey);
+  if (exportedJWK.x !== jwk.x || exportedJWK.d !== jwk.d) {
+    throw new Error("exported jwk doesn't match: " + JSON.stringify(exportedJWK));
+  }
+
+  console.log("exported again: " + JSON.stringify(exportedJWK));
+
+  // round-trip the public key through the spki and raw formats
+  const publicKey = await crypto.subtle.importKey(
+    "jwk",
+    { kty: "OKP", crv: "Ed25519", x: jwk.x },
+    { name: "Ed25519" },
+    true,
+    ["verify"]
+  );
+
+  const spki = await crypto.subtle.exportKey("spki", publicKey);
+  const reimportedPublicKey = await crypto.subtle.importKey(
+    "spki",
+    spki,
+    { name: "Ed25519" },
+    true,
+    ["verify"]
+  );
+
+  const raw = await crypto.subtle.exportKey("raw", reimportedPublicKey);
+  console.log("raw public key: " + Array.from(new Uint8Array(raw)));
+
+  const data = new Uint8Array([1, 2, 3, 4]);
+  const signature = await crypto.subtle.sign("Ed25519", reimportedPrivateKey, data);
+  const verified = await crypto.subtle.verify(
+    "Ed25519",
+    reimportedPublicKey,
+    signature,
+    data
+  );
+  if (!verified) {
+    throw new Error("signature verification failed");
+  }
+
+  console.log("verified: ", verified);
+}
@@ -28,6 +28,7 @@ const (
 	OutputExtension
 	SecretSourceExtension
 	SubcommandExtension
+	ExecutorExtension
 )
 
 func (e ExtensionType) String() string {
@@ -41,6 +42,8 @@ func (e ExtensionType) String() string {
 		s = "secret-source"
 	case SubcommandExtension:
 		s = "subcommand"
+	case ExecutorExtension:
+		s = "executor"
 	}
 	return s
 }
@@ -106,7 +109,8 @@ func GetAll() []*Extension {
 	defer mx.RUnlock()
 
 	js, out, subcommand := extensions[JSExtension], extensions[OutputExtension], extensions[SubcommandExtension]
-	result := make([]*Extension, 0, len(js)+len(out)+len(subcommand))
+	executor := extensions[ExecutorExtension]
+	result := make([]*Extension, 0, len(js)+len(out)+len(subcommand)+len(executor))
 
 	for _, e := range js {
 		result = append(result, e)
@@ -117,6 +121,9 @@ func GetAll() []*Extension {
 	for _, e := range subcommand {
 		result = append(result, e)
 	}
+	for _, e := range executor {
+		result = append(result, e)
+	}
 
 	sort.Slice(result, func(i, j int) bool {
 		if result[i].Path == result[j].Path {
@@ -167,4 +174,5 @@ func init() {
 	extensions[OutputExtension] = make(map[string]*Extension)
 	extensions[SecretSourceExtension] = make(map[string]*Extension)
 	extensions[SubcommandExtension] = make(map[string]*Extension)
+	extensions[ExecutorExtension] = make(map[string]*Extension)
 }
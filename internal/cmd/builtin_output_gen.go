@@ -7,11 +7,11 @@ import (
 	"strings"
 )
 
-const _builtinOutputName = "cloudcsvdatadogexperimental-prometheus-rwinfluxdbjsonkafkastatsdexperimental-opentelemetryopentelemetrysummary"
+const _builtinOutputName = "cloudcsvdatadogexperimental-prometheus-rwinfluxdbjsonkafkastatsdexperimental-opentelemetryopentelemetrysummaryexperimental-prometheus-pullparquetsqlite"
 
-var _builtinOutputIndex = [...]uint8{0, 5, 8, 15, 41, 49, 53, 58, 64, 90, 103, 110}
+var _builtinOutputIndex = [...]uint8{0, 5, 8, 15, 41, 49, 53, 58, 64, 90, 103, 110, 138, 145, 151}
 
-const _builtinOutputLowerName = "cloudcsvdatadogexperimental-prometheus-rwinfluxdbjsonkafkastatsdexperimental-opentelemetryopentelemetrysummary"
+const _builtinOutputLowerName = "cloudcsvdatadogexperimental-prometheus-rwinfluxdbjsonkafkastatsdexperimental-opentelemetryopentelemetrysummaryexperimental-prometheus-pullparquetsqlite"
 
 func (i builtinOutput) String() string {
 	if i >= builtinOutput(len(_builtinOutputIndex)-1) {
@@ -35,9 +35,12 @@ func _builtinOutputNoOp() {
 	_ = x[builtinOutputExperimentalOpentelemetry-(8)]
 	_ = x[builtinOutputOpentelemetry-(9)]
 	_ = x[builtinOutputSummary-(10)]
+	_ = x[builtinOutputExperimentalPrometheusPull-(11)]
+	_ = x[builtinOutputParquet-(12)]
+	_ = x[builtinOutputSqlite-(13)]
 }
 
-var _builtinOutputValues = []builtinOutput{builtinOutputCloud, builtinOutputCSV, builtinOutputDatadog, builtinOutputExperimentalPrometheusRW, builtinOutputInfluxdb, builtinOutputJSON, builtinOutputKafka, builtinOutputStatsd, builtinOutputExperimentalOpentelemetry, builtinOutputOpentelemetry, builtinOutputSummary}
+var _builtinOutputValues = []builtinOutput{builtinOutputCloud, builtinOutputCSV, builtinOutputDatadog, builtinOutputExperimentalPrometheusRW, builtinOutputInfluxdb, builtinOutputJSON, builtinOutputKafka, builtinOutputStatsd, builtinOutputExperimentalOpentelemetry, builtinOutputOpentelemetry, builtinOutputSummary, builtinOutputExperimentalPrometheusPull, builtinOutputParquet, builtinOutputSqlite}
 
 var _builtinOutputNameToValueMap = map[string]builtinOutput{
 	_builtinOutputName[0:5]:          builtinOutputCloud,
@@ -62,6 +65,12 @@ var _builtinOutputNameToValueMap = map[string]builtinOutput{
 	_builtinOutputLowerName[90:103]:  builtinOutputOpentelemetry,
 	_builtinOutputName[103:110]:      builtinOutputSummary,
 	_builtinOutputLowerName[103:110]: builtinOutputSummary,
+	_builtinOutputName[110:138]:      builtinOutputExperimentalPrometheusPull,
+	_builtinOutputLowerName[110:138]: builtinOutputExperimentalPrometheusPull,
+	_builtinOutputName[138:145]:      builtinOutputParquet,
+	_builtinOutputLowerName[138:145]: builtinOutputParquet,
+	_builtinOutputName[145:151]:      builtinOutputSqlite,
+	_builtinOutputLowerName[145:151]: builtinOutputSqlite,
 }
 
 var _builtinOutputNames = []string{
@@ -76,6 +85,9 @@ var _builtinOutputNames = []string{
 	_builtinOutputName[64:90],
 	_builtinOutputName[90:103],
 	_builtinOutputName[103:110],
+	_builtinOutputName[110:138],
+	_builtinOutputName[138:145],
+	_builtinOutputName[145:151],
 }
 
 // builtinOutputString retrieves an enum value from the enum constants string name.
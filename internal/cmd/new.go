@@ -3,22 +3,28 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.k6.io/k6/v2/cmd/state"
 	"go.k6.io/k6/v2/internal/cmd/templates"
+	"go.k6.io/k6/v2/internal/js"
 	"go.k6.io/k6/v2/lib/fsext"
 )
 
-const defaultNewScriptName = "script.js"
+const (
+	defaultNewScriptName = "script.js"
+	typeDefinitionsName  = "k6.d.ts"
+)
 
 type newScriptCmd struct {
 	gs             *state.GlobalState
 	overwriteFiles bool
 	templateType   string
 	projectID      string
+	types          bool
 }
 
 func (c *newScriptCmd) flagSet() *pflag.FlagSet {
@@ -27,6 +33,8 @@ func (c *newScriptCmd) flagSet() *pflag.FlagSet {
 	flags.BoolVarP(&c.overwriteFiles, "force", "f", false, "overwrite existing files")
 	flags.StringVar(&c.templateType, "template", "minimal", "template type (choices: minimal, protocol, browser) or relative/absolute path to a custom template file") //nolint:lll
 	flags.StringVar(&c.projectID, "project-id", "", "specify the Grafana Cloud project ID for the test")
+	flags.BoolVar(&c.types, "types", false, "write the TypeScript definitions of the k6 modules in "+typeDefinitionsName+
+		" next to the script, for the editors' completion, most of the types are any")
 	return flags
 }
 
@@ -92,9 +100,26 @@ func (c *newScriptCmd) run(_ *cobra.Command, args []string) (err error) {
 		return err
 	}
 
+	if c.types {
+		return c.writeTypeDefinitions(filepath.Join(filepath.Dir(target), typeDefinitionsName))
+	}
+
 	return nil
 }
 
+// writeTypeDefinitions writes the TypeScript definitions of the k6 modules,
+// which editors use to complete the imports of the script. They are too loose
+// to type check the script, see js.TypeDefinitions.
+func (c *newScriptCmd) writeTypeDefinitions(target string) error {
+	defs := js.TypeDefinitions(c.gs.Logger)
+	if err := fsext.WriteFile(c.gs.FS, target, []byte(defs), 0o644); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(c.gs.Stdout, "Type definitions created: %s.\n", target)
+	return err
+}
+
 func getCmdNewScript(gs *state.GlobalState) *cobra.Command {
 	c := &newScriptCmd{gs: gs}
 
@@ -112,7 +137,10 @@ func getCmdNewScript(gs *state.GlobalState) *cobra.Command {
     $ {{.}} new --template protocol
 
     # Create a cloud-ready script with a specific project ID
-    $ {{.}} new --project-id 12315`[1:])
+    $ {{.}} new --project-id 12315
+
+    # Create a script with the type definitions of the k6 modules
+    $ {{.}} new --types test.ts`[1:])
 
 	initCmd := &cobra.Command{
 		Use:   "new [file]",
@@ -124,6 +124,21 @@ func TestNewScriptCmd_ProjectID(t *testing.T) {
 	assert.Contains(t, string(data), "projectID: 1422")
 }
 
+func TestNewScriptCmd_Types(t *testing.T) {
+	t.Parallel()
+
+	ts := tests.NewGlobalTestState(t)
+	ts.CmdArgs = []string{"k6", "new", "--types", "tests/script.ts"}
+
+	newRootCommand(ts.GlobalState).execute()
+
+	data, err := fsext.ReadFile(ts.FS, filepath.Join("tests", typeDefinitionsName))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), `declare module "k6/http" {`)
+	assert.Contains(t, ts.Stdout.String(), "Type definitions created: "+filepath.Join("tests", typeDefinitionsName))
+}
+
 func TestNewScriptCmd_LocalTemplate(t *testing.T) {
 	t.Parallel()
 
@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -13,7 +14,11 @@ import (
 	"go.k6.io/k6/v2/internal/output/influxdb"
 	"go.k6.io/k6/v2/internal/output/json"
 	"go.k6.io/k6/v2/internal/output/opentelemetry"
+	"go.k6.io/k6/v2/internal/output/parquet"
+	"go.k6.io/k6/v2/internal/output/processing"
+	"go.k6.io/k6/v2/internal/output/prometheuspull"
 	"go.k6.io/k6/v2/internal/output/prometheusrw/remotewrite"
+	"go.k6.io/k6/v2/internal/output/sqlite"
 	"go.k6.io/k6/v2/lib"
 	"go.k6.io/k6/v2/output"
 
@@ -39,6 +44,9 @@ const (
 	builtinOutputExperimentalOpentelemetry
 	builtinOutputOpentelemetry
 	builtinOutputSummary
+	builtinOutputExperimentalPrometheusPull
+	builtinOutputParquet
+	builtinOutputSqlite
 )
 
 // TODO: move this to an output sub-module after we get rid of the old collectors?
@@ -49,6 +57,8 @@ func getAllOutputConstructors() (map[string]output.Constructor, error) {
 		builtinOutputCloud.String():    cloud.New,
 		builtinOutputCSV.String():      csv.New,
 		builtinOutputInfluxdb.String(): influxdb.New,
+		builtinOutputParquet.String():  parquet.New,
+		builtinOutputSqlite.String():   sqlite.New,
 		builtinOutputKafka.String(): func(_ output.Params) (output.Output, error) {
 			return nil, errors.New("the kafka output was deprecated in k6 v0.32.0 and removed in k6 v0.34.0, " +
 				"please use the new xk6 kafka output extension instead - https://github.com/k6io/xk6-output-kafka")
@@ -68,7 +78,11 @@ func getAllOutputConstructors() (map[string]output.Constructor, error) {
 		builtinOutputExperimentalPrometheusRW.String(): func(params output.Params) (output.Output, error) {
 			return remotewrite.New(params)
 		},
-		"web-dashboard": dashboard.New,
+		builtinOutputExperimentalPrometheusPull.String(): func(params output.Params) (output.Output, error) {
+			return prometheuspull.New(params)
+		},
+		"web-dashboard":            dashboard.New,
+		dashboard.ReportOutputName: dashboard.NewReport,
 		builtinOutputExperimentalOpentelemetry.String(): func(params output.Params) (output.Output, error) {
 			params.Logger.Warnf("OpenTelemetry output has been graduated as a stable output."+
 				"You can now use just %q instead of %q. The experimental version will be removed in future versions.",
@@ -115,6 +129,10 @@ func createOutputs(
 	if err != nil {
 		return nil, err
 	}
+	processingConfig, err := processing.ParseConfig(test.derivedConfig.OutputProcessing)
+	if err != nil {
+		return nil, err
+	}
 	baseParams := output.Params{
 		ScriptPath:     test.source.URL,
 		Logger:         gs.Logger,
@@ -135,6 +153,7 @@ func createOutputs(
 	}
 
 	result := make([]output.Output, 0, len(outputs))
+	enabledTypes := make([]string, 0, len(outputs))
 
 	for _, outputFullArg := range outputs {
 		outputType, outputArg, _ := strings.Cut(outputFullArg, "=")
@@ -178,7 +197,23 @@ func createOutputs(
 			}
 		}
 
+		// the output is wrapped once its optional interfaces were used
+		if rules, ok := processingConfig[outputType]; ok {
+			p, err := processing.New(rules, test.preInitState.Registry, gs.Logger.WithField("output", outputType))
+			if err != nil {
+				return nil, fmt.Errorf("invalid outputProcessing option for the '%s' output: %w", outputType, err)
+			}
+			out = processing.NewOutput(out, p)
+		}
+
 		result = append(result, out)
+		enabledTypes = append(enabledTypes, outputType)
+	}
+
+	for outputType := range processingConfig {
+		if !slices.Contains(enabledTypes, outputType) {
+			gs.Logger.Warnf("The outputProcessing option has rules for the '%s' output, which isn't enabled", outputType)
+		}
 	}
 
 	return result, nil
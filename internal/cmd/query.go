@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"go.k6.io/k6/v2/cmd/state"
+	"go.k6.io/k6/v2/internal/output/sqlite"
+)
+
+type cmdQuery struct {
+	globalState *state.GlobalState
+	isJSON      bool
+}
+
+// getCmdQuery returns the built-in `k6 x query` subcommand, running SQL
+// queries against a database written by the sqlite output.
+func getCmdQuery(gs *state.GlobalState) *cobra.Command {
+	c := &cmdQuery{globalState: gs}
+
+	exampleText := getExampleText(gs, `
+  # Count the samples of each metric
+  $ {{.}} x query results.db "SELECT metric, count(*) FROM samples GROUP BY metric"
+
+  # Get the 99th percentile of the requests' duration per URL and per minute
+  $ {{.}} x query results.db "SELECT json_extract(tags, '$.url') AS url,
+      datetime(timestamp / 60000000 * 60, 'unixepoch') AS minute, percentile(value, 99) AS p99
+      FROM samples WHERE metric = 'http_req_duration' GROUP BY url, minute"`[1:])
+
+	queryCmd := &cobra.Command{
+		Use:   "query <database> <sql>",
+		Short: "Query the results written by the sqlite output",
+		Long: `Query the results written by the sqlite output.
+
+The database is opened in read-only mode, and the rows returned by the query
+are printed as a table. Besides the SQLite's functions, the percentile(value, p)
+aggregate function computes the percentiles like the trend metrics do.`,
+		Example: exampleText,
+		Args:    cobra.ExactArgs(2),
+		RunE:    c.run,
+	}
+	queryCmd.Flags().BoolVar(&c.isJSON, "json", false, "output the rows as JSON objects, one per line")
+
+	return queryCmd
+}
+
+func (c *cmdQuery) run(_ *cobra.Command, args []string) (err error) {
+	db, err := sqlite.Open(args[0], true)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := db.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	rows, err := db.QueryContext(c.globalState.Ctx, args[1])
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	var w *tabwriter.Writer
+	if !c.isJSON {
+		w = tabwriter.NewWriter(&buf, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(w, strings.Join(columns, "\t"))
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		if c.isJSON {
+			writeJSONRow(&buf, columns, values)
+			continue
+		}
+		cells := make([]string, len(values))
+		for i, v := range values {
+			cells[i] = formatQueryValue(v)
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if w != nil {
+		_ = w.Flush()
+	}
+	printToStdout(c.globalState, buf.String())
+	return nil
+}
+
+// writeJSONRow writes the row as a JSON object, keeping the columns' order.
+func writeJSONRow(buf *bytes.Buffer, columns []string, values []any) {
+	buf.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		v := values[i]
+		if b, ok := v.([]byte); ok {
+			v = string(b)
+		}
+		// the column names and the values scanned from SQLite can't fail to be marshaled
+		name, _ := json.Marshal(column) //nolint:errchkjson
+		value, _ := json.Marshal(v)     //nolint:errchkjson
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteString("}\n")
+}
+
+func formatQueryValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/cmd/tests"
+	"go.k6.io/k6/v2/internal/output/sqlite"
+)
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "results.db")
+	db, err := sqlite.Open(fileName, false)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE samples (metric TEXT, value REAL, tags TEXT);
+		INSERT INTO samples VALUES
+			('http_req_duration', 100, '{"url":"https://k6.io"}'),
+			('http_req_duration', 300, '{"url":"https://k6.io"}'),
+			('http_req_duration', 50.5, NULL)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	const query = `SELECT tags ->> 'url' AS url, count(*) AS count, percentile(value, 50) AS p50
+		FROM samples GROUP BY url ORDER BY url`
+
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+
+		ts := tests.NewGlobalTestState(t)
+		ts.CmdArgs = []string{"k6", "x", "query", fileName, query}
+		ExecuteWithGlobalState(ts.GlobalState)
+
+		assert.Equal(t, ""+
+			"url             count   p50\n"+
+			"NULL            1       50.5\n"+
+			"https://k6.io   2       200\n", ts.Stdout.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		ts := tests.NewGlobalTestState(t)
+		ts.CmdArgs = []string{"k6", "x", "query", "--json", fileName, query}
+		ExecuteWithGlobalState(ts.GlobalState)
+
+		assert.Equal(t, ""+
+			`{"url":null,"count":1,"p50":50.5}`+"\n"+
+			`{"url":"https://k6.io","count":2,"p50":200}`+"\n", ts.Stdout.String())
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		t.Parallel()
+
+		ts := tests.NewGlobalTestState(t)
+		ts.ExpectedExitCode = -1
+		ts.CmdArgs = []string{"k6", "x", "query", fileName, "DELETE FROM samples"}
+		ExecuteWithGlobalState(ts.GlobalState)
+
+		assert.Contains(t, ts.Stderr.String(), "readonly database")
+	})
+}
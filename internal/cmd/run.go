@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -28,6 +29,7 @@ import (
 	"go.k6.io/k6/v2/internal/lib/trace"
 	"go.k6.io/k6/v2/internal/metrics/engine"
 	"go.k6.io/k6/v2/internal/output/cloud"
+	"go.k6.io/k6/v2/internal/output/processing"
 	summaryoutput "go.k6.io/k6/v2/internal/output/summary"
 	"go.k6.io/k6/v2/internal/ui/pb"
 	"go.k6.io/k6/v2/js/common"
@@ -107,6 +109,7 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	test.preInitState.DataController = controller
 	printBanner(c.gs)
 	if test.keyLogger != nil {
 		defer func() {
@@ -186,9 +189,14 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 
 	thresholdsEnabled := !testRunState.RuntimeOptions.NoThresholds.Bool
 
+	// The JUnit and TAP reports are made of the end-of-test summary's data, so
+	// it's collected for them even if the summary itself is disabled.
+	reportsEnabled := testRunState.RuntimeOptions.SummaryJUnit.String != "" ||
+		testRunState.RuntimeOptions.SummaryTAP.String != ""
+
 	// We'll need to pipe metrics to the MetricsEngine and process them if any
-	// of these are enabled: thresholds, end-of-test summary
-	shouldProcessMetrics := summaryEnabled || thresholdsEnabled
+	// of these are enabled: thresholds, end-of-test summary, summary reports
+	shouldProcessMetrics := summaryEnabled || reportsEnabled || thresholdsEnabled
 	var metricsIngester *engine.OutputIngester
 	if shouldProcessMetrics {
 		err = metricsEngine.InitSubMetricsAndThresholds(conf.Options, !thresholdsEnabled)
@@ -202,7 +210,7 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	executionState := execScheduler.GetState()
-	if summaryEnabled {
+	if summaryEnabled || reportsEnabled {
 		// Despite having the revamped [summary.Summary], we still keep the use of the
 		// [lib.LegacySummary] for backwards compatibility:
 		// - the data structure for custom `handleSummary()` implementations.
@@ -223,6 +231,9 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 		summaryMeta := summary.Meta{
 			Script: string(test.source.Data),
 			IsCloud: slices.ContainsFunc(outputs, func(o output.Output) bool {
+				if processed, ok := o.(*processing.Output); ok {
+					o = processed.Unwrap()
+				}
 				_, isCloud := o.(*cloud.Output)
 				return isCloud
 			}),
@@ -255,12 +266,18 @@ func (c *cmdRun) run(cmd *cobra.Command, args []string) (err error) {
 			summary.NewMachineReadableSummary = testRunState.RuntimeOptions.NewMachineReadableSummary.Valid &&
 				testRunState.RuntimeOptions.NewMachineReadableSummary.Bool
 
-			summaryResult, hsErr := test.initRunner.HandleSummary(globalCtx, legacySummary(), summary, summaryMeta)
-			if hsErr == nil {
-				hsErr = handleSummaryResult(c.gs.FS, c.gs.Stdout, c.gs.Stderr, summaryResult)
+			if summaryEnabled {
+				summaryResult, hsErr := test.initRunner.HandleSummary(globalCtx, legacySummary(), summary, summaryMeta)
+				if hsErr == nil {
+					hsErr = handleSummaryResult(c.gs.FS, c.gs.Stdout, c.gs.Stderr, summaryResult)
+				}
+				if hsErr != nil {
+					logger.WithError(hsErr).Error("failed to handle the end-of-test summary")
+				}
 			}
-			if hsErr != nil {
-				logger.WithError(hsErr).Error("failed to handle the end-of-test summary")
+
+			if rErr := exportSummaryReports(c.gs, testRunState.RuntimeOptions, summary); rErr != nil {
+				logger.WithError(rErr).Error("failed to export the end-of-test summary reports")
 			}
 		}()
 	}
@@ -579,6 +596,26 @@ a commandline interface for interacting with it.`,
 	return runCmd
 }
 
+// exportSummaryReports writes the thresholds and the checks of the summary as
+// JUnit XML and TAP reports, if their files are set by the runtime options.
+func exportSummaryReports(gs *state.GlobalState, rtOpts lib.RuntimeOptions, s *summary.Summary) error {
+	result := make(map[string]io.Reader)
+
+	if rtOpts.SummaryJUnit.String != "" {
+		report, err := summary.ToJUnit(s)
+		if err != nil {
+			return err
+		}
+		result[rtOpts.SummaryJUnit.String] = bytes.NewReader(report)
+	}
+
+	if rtOpts.SummaryTAP.String != "" {
+		result[rtOpts.SummaryTAP.String] = bytes.NewReader(summary.ToTAP(s))
+	}
+
+	return handleSummaryResult(gs.FS, gs.Stdout, gs.Stderr, result)
+}
+
 func handleSummaryResult(fs fsext.Fs, stdOut, stdErr io.Writer, result map[string]io.Reader) error {
 	var errs []error
 
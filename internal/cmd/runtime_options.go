@@ -28,6 +28,8 @@ func runtimeOptionFlagSet(includeSysEnv bool) *pflag.FlagSet {
 base: pure Sobek - Golang JS VM supporting ES6+
 extended: base + sets "global" as alias for "globalThis"
 `)
+	flags.Bool("bundle", false, "bundle the local modules, the JSON files and the TypeScript ones,"+
+		" imported by the test, using the paths of its tsconfig.json. The types are stripped, not checked")
 	flags.StringP("type", "t", "", "override test type, \"js\" or \"archive\"")
 	flags.StringArrayP("env", "e", nil, "add/override environment variable with `VAR=value`")
 	flags.Bool("no-thresholds", false, "don't run thresholds")
@@ -38,6 +40,8 @@ extended: base + sets "global" as alias for "globalThis"
 		"",
 		"output the end-of-test summary report to JSON file",
 	)
+	flags.String("summary-junit", "", "output the end-of-test thresholds and checks to JUnit XML file")
+	flags.String("summary-tap", "", "output the end-of-test thresholds and checks to TAP file")
 	// TODO(@joanlopez): remove by k6 v2.0, once the new summary model is the default and the only one.
 	flags.Bool("new-machine-readable-summary", false, "enables the new machine-readable summary, "+
 		"which is used for summary exports and as handleSummary() argument")
@@ -79,9 +83,12 @@ func runtimeOptionsFromFlags(flags *pflag.FlagSet) lib.RuntimeOptions {
 		TestType:                  getNullString(flags, "type"),
 		IncludeSystemEnvVars:      getNullBool(flags, "include-system-env-vars"),
 		CompatibilityMode:         getNullString(flags, "compatibility-mode"),
+		Bundle:                    getNullBool(flags, "bundle"),
 		NoThresholds:              getNullBool(flags, "no-thresholds"),
 		SummaryMode:               getNullString(flags, "summary-mode"),
 		SummaryExport:             getNullString(flags, "summary-export"),
+		SummaryJUnit:              getNullString(flags, "summary-junit"),
+		SummaryTAP:                getNullString(flags, "summary-tap"),
 		NewMachineReadableSummary: getNullBool(flags, "new-machine-readable-summary"),
 		TracesOutput:              getNullString(flags, "traces-output"),
 		Env:                       make(map[string]string),
@@ -109,6 +116,10 @@ func populateRuntimeOptionsFromEnv(opts lib.RuntimeOptions, environment map[stri
 		return opts, err
 	}
 
+	if err := saveBoolFromEnv(environment, "K6_BUNDLE", &opts.Bundle); err != nil {
+		return opts, err
+	}
+
 	if err := saveBoolFromEnv(environment, "K6_NO_THRESHOLDS", &opts.NoThresholds); err != nil {
 		return opts, err
 	}
@@ -127,6 +138,14 @@ func populateRuntimeOptionsFromEnv(opts lib.RuntimeOptions, environment map[stri
 		opts.SummaryExport = null.StringFrom(envVar)
 	}
 
+	if envVar, ok := environment["K6_SUMMARY_JUNIT"]; !opts.SummaryJUnit.Valid && ok {
+		opts.SummaryJUnit = null.StringFrom(envVar)
+	}
+
+	if envVar, ok := environment["K6_SUMMARY_TAP"]; !opts.SummaryTAP.Valid && ok {
+		opts.SummaryTAP = null.StringFrom(envVar)
+	}
+
 	if err := saveBoolFromEnv(
 		environment, "K6_NEW_MACHINE_READABLE_SUMMARY", &opts.NewMachineReadableSummary,
 	); err != nil {
@@ -53,7 +53,9 @@ func getX(gs *state.GlobalState) *cobra.Command {
 		Long: `Namespace for extension-provided subcommands.
 
 This command serves as a parent for subcommands registered by k6 extensions,
-allowing them to extend k6's functionality with custom commands.
+allowing them to extend k6's functionality with custom commands. It also
+contains the built-in "query" subcommand, to analyze the results written by
+the sqlite output.
 
 Run "k6 x explore" to see the full list of official and community-provided subcommands.
 `,
@@ -76,7 +78,7 @@ Run "k6 x explore" to see the full list of official and community-provided subco
 		},
 	}
 
-	baked := extensionSubcommands(gs)
+	baked := append([]*cobra.Command{getCmdQuery(gs)}, extensionSubcommands(gs)...)
 	cmd.AddCommand(baked...)
 	cmd.AddCommand(registryStubs(gs, baked)...)
 
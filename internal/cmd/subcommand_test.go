@@ -83,6 +83,16 @@ func TestExtensionSubcommands(t *testing.T) {
 	})
 }
 
+func TestExtensionSubcommandReservedName(t *testing.T) {
+	t.Parallel()
+
+	require.PanicsWithValue(t, "subcommand name reserved by k6: query", func() {
+		subcommand.RegisterExtension("query", func(*state.GlobalState) *cobra.Command {
+			return &cobra.Command{Use: "query"}
+		})
+	})
+}
+
 func TestXCommandHelpDisplayCommands(t *testing.T) {
 	t.Parallel()
 
@@ -10,6 +10,7 @@ import (
 	"maps"
 	"net/url"
 	"path/filepath"
+	"runtime"
 	"slices"
 	"strings"
 	"sync"
@@ -24,8 +25,10 @@ import (
 	"go.k6.io/k6/v2/errext"
 	"go.k6.io/k6/v2/errext/exitcodes"
 	"go.k6.io/k6/v2/ext"
+	"go.k6.io/k6/v2/internal/execution/local"
 	"go.k6.io/k6/v2/internal/features"
 	"go.k6.io/k6/v2/internal/js"
+	"go.k6.io/k6/v2/internal/js/compiler"
 	"go.k6.io/k6/v2/internal/loader"
 	"go.k6.io/k6/v2/js/modules"
 	"go.k6.io/k6/v2/lib"
@@ -86,6 +89,12 @@ func loadLocalTestWithoutRunner(gs *state.GlobalState, cmd *cobra.Command, args
 			lib.CompatibilityModeExperimentalEnhanced.String(), lib.CompatibilityModeBase.String())
 	}
 
+	if runtimeOptions.Bundle.Bool {
+		if err := bundleSource(gs, src, runtimeOptions); err != nil {
+			return nil, fmt.Errorf("could not bundle '%s': %w", sourceRootPath, err)
+		}
+	}
+
 	registry := metrics.NewRegistry()
 	state := &lib.TestPreInitState{
 		Logger:         gs.Logger,
@@ -101,6 +110,8 @@ func loadLocalTestWithoutRunner(gs *state.GlobalState, cmd *cobra.Command, args
 		SecretsManager: gs.SecretsManager,
 		TestStatus:     gs.TestStatus,
 		FeatureFlags:   &features.Flags{},
+		// k6 run replaces it with the controller of the test run
+		DataController: local.NewController(),
 	}
 
 	test := &loadedTest{
@@ -522,6 +533,40 @@ func readSource(gs *state.GlobalState, filename string) (*loader.SourceData, map
 	return src, filesystems, pwd, err
 }
 
+// bundleSource replaces the source of a local JS test with its bundle, which
+// includes all the local modules it imports, so it becomes the only file of
+// the test and its archive.
+func bundleSource(gs *state.GlobalState, src *loader.SourceData, runtimeOptions lib.RuntimeOptions) error {
+	if src.URL.Scheme != "file" || src.URL.Path == "/-" {
+		gs.Logger.Warnf("Only the local tests can be bundled, ignoring the bundle option for '%s'", src.URL)
+		return nil
+	}
+
+	testType := runtimeOptions.TestType.String
+	if testType == "" {
+		testType = detectTestType(src.Data)
+	}
+	if testType != testTypeJS {
+		return nil
+	}
+
+	// the path of a file URL has a leading slash before the volume on Windows,
+	// e.g. "/C:/test.js"
+	filename := filepath.FromSlash(src.URL.Path)
+	if runtime.GOOS == "windows" {
+		filename = strings.TrimPrefix(filename, string(filepath.Separator))
+	}
+
+	gs.Logger.Debugf("Bundling '%s'...", src.URL)
+	code, err := compiler.Bundle(gs.FS, filename)
+	if err != nil {
+		return err
+	}
+	src.Data = []byte(code)
+
+	return nil
+}
+
 func detectTestType(data []byte) string {
 	if _, err := tar.NewReader(bytes.NewReader(data)).Next(); err == nil {
 		return testTypeArchive
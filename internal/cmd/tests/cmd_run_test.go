@@ -33,6 +33,7 @@ import (
 	"go.k6.io/k6/v2/internal/lib/testutils"
 	"go.k6.io/k6/v2/internal/lib/testutils/httpmultibin"
 	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
 	"go.k6.io/k6/v2/lib/fsext"
 )
 
@@ -521,7 +522,7 @@ func TestExecutionTestOptionsDefaultValues(t *testing.T) {
 	loglines := ts.LoggerHook.Drain()
 	require.Len(t, loglines, 1)
 
-	expected := `{"paused":null,"executionSegment":null,"executionSegmentSequence":null,"noSetup":null,"setupTimeout":null,"noTeardown":null,"teardownTimeout":null,"rps":null,"dns":{"ttl":null,"select":null,"policy":null},"maxRedirects":null,"userAgent":null,"batch":null,"batchPerHost":null,"httpDebug":null,"insecureSkipTLSVerify":null,"tlsCipherSuites":null,"tlsVersion":null,"tlsAuth":null,"throw":null,"thresholds":null,"blacklistIPs":null,"blockHostnames":null,"hosts":null,"noConnectionReuse":null,"noVUConnectionReuse":null,"minIterationDuration":null,"ext":null,"summaryTrendStats":["avg", "min", "med", "max", "p(90)", "p(95)"],"summaryTimeUnit":null,"systemTags":["check","error","error_code","expected_response","group","method","name","proto","scenario","service","status","subproto","tls_version","url"],"tags":null,"metricSamplesBufferSize":null,"noCookiesReset":null,"discardResponseBodies":null,"consoleOutput":null,"scenarios":{"default":{"vus":null,"iterations":1,"executor":"shared-iterations","maxDuration":null,"startTime":null,"env":null,"tags":null,"gracefulStop":null,"exec":null}},"localIPs":null,"features":null}`
+	expected := `{"paused":null,"executionSegment":null,"executionSegmentSequence":null,"noSetup":null,"setupTimeout":null,"noTeardown":null,"teardownTimeout":null,"rps":null,"dns":{"ttl":null,"select":null,"policy":null},"maxRedirects":null,"userAgent":null,"batch":null,"batchPerHost":null,"httpDebug":null,"insecureSkipTLSVerify":null,"tlsCipherSuites":null,"tlsVersion":null,"tlsAuth":null,"throw":null,"thresholds":null,"blacklistIPs":null,"blockHostnames":null,"hosts":null,"noConnectionReuse":null,"noVUConnectionReuse":null,"minIterationDuration":null,"ext":null,"summaryTrendStats":["avg", "min", "med", "max", "p(90)", "p(95)"],"summaryTimeUnit":null,"systemTags":["check","error","error_code","expected_response","group","method","name","proto","scenario","service","status","subproto","tls_version","url"],"tags":null,"metricSamplesBufferSize":null,"noCookiesReset":null,"traceRequests":null,"seed":null,"discardResponseBodies":null,"consoleOutput":null,"scenarios":{"default":{"vus":null,"iterations":1,"executor":"shared-iterations","maxDuration":null,"startTime":null,"env":null,"tags":null,"gracefulStop":null,"exec":null}},"localIPs":null,"features":null}`
 	assert.JSONEq(t, expected, loglines[0].Message)
 }
 
@@ -2596,6 +2597,74 @@ func TestTypeScriptSupportWithoutAutomaticExtensionResolution(t *testing.T) {
 	assert.Contains(t, stderr, `something 42`)
 }
 
+func TestBundle(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"tsconfig.json": `{
+			// the alias of the local modules
+			"compilerOptions": { "baseUrl": ".", "paths": { "@lib/*": ["lib/*"] } }
+		}`,
+		"lib/greet.ts": `
+			export function greet(name: string): string {
+				return "hello " + name;
+			}
+		`,
+		"test.ts": `
+			import { greet } from "@lib/greet";
+			export const options = { iterations: 1 };
+			export default function () {
+				console.log(greet("bundle"));
+			};
+		`,
+	}
+
+	newTestState := func(t *testing.T) *GlobalTestState {
+		ts := NewGlobalTestState(t)
+		for name, content := range files {
+			require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, filepath.FromSlash(name)), []byte(content), 0o644))
+		}
+		return ts
+	}
+
+	t.Run("run", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestState(t)
+		ts.CmdArgs = []string{"k6", "run", "--quiet", "--bundle", "test.ts"}
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		assert.Contains(t, ts.Stderr.String(), "hello bundle")
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestState(t)
+		ts.CmdArgs = []string{"k6", "archive", "--bundle", "test.ts"}
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		data, err := fsext.ReadFile(ts.FS, "archive.tar")
+		require.NoError(t, err)
+
+		arc, err := lib.ReadArchive(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Contains(t, string(arc.Data), "function greet(name)")
+		assert.NotContains(t, string(arc.Data), `from "@lib/greet"`)
+
+		// the archive runs without the local modules
+		ts = NewGlobalTestState(t)
+		require.NoError(t, fsext.WriteFile(ts.FS, filepath.Join(ts.Cwd, "archive.tar"), data, 0o644))
+		ts.CmdArgs = []string{"k6", "run", "--quiet", "archive.tar"}
+
+		cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+		assert.Contains(t, ts.Stderr.String(), "hello bundle")
+	})
+}
+
 func TestBasicSecrets(t *testing.T) {
 	// This is the example it will be nice if we can just run it ,but in this case it needs extra arguments so ... maybe not such a great idea
 	t.Parallel()
@@ -2963,6 +3032,81 @@ func TestSummaryExport(t *testing.T) {
 	}
 }
 
+func TestSummaryJUnitAndTAP(t *testing.T) {
+	t.Parallel()
+
+	script := `
+		import { check } from "k6";
+
+		export const options = {
+			iterations: 2,
+			thresholds: {
+				checks: ['rate==1'],
+				iterations: ['count==2'],
+			},
+		};
+
+		export default function () {
+			check(true, { "TRUE is TRUE": (r) => r });
+			check(__ITER, { "is first iteration": (i) => i === 0 });
+		};
+	`
+
+	ts := getSingleFileTestState(t, script, []string{"--summary-junit=results.xml", "--summary-tap=results.tap"},
+		exitcodes.ThresholdsHaveFailed)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	junit, err := fsext.ReadFile(ts.FS, "results.xml")
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="k6" tests="4" failures="2" time="`)
+	assert.Contains(t, string(junit), `
+  <testsuite name="thresholds" tests="2" failures="1">
+    <testcase name="checks: rate==1" classname="thresholds">
+      <failure message="checks: rate==1 failed with rate=0.75"></failure>
+      <system-out>rate=0.75</system-out>
+    </testcase>
+    <testcase name="iterations: count==2" classname="thresholds">
+      <system-out>count=2</system-out>
+    </testcase>
+  </testsuite>
+  <testsuite name="checks" tests="2" failures="1">
+    <testcase name="TRUE is TRUE" classname="checks">
+      <system-out>passes=2 fails=0</system-out>
+    </testcase>
+    <testcase name="is first iteration" classname="checks">
+      <failure message="is first iteration failed with passes=1 fails=1"></failure>
+      <system-out>passes=1 fails=1</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`)
+
+	tap, err := fsext.ReadFile(ts.FS, "results.tap")
+	require.NoError(t, err)
+	assert.Equal(t, `TAP version 13
+1..4
+not ok 1 - thresholds checks: rate==1
+  ---
+  rate: 0.75
+  ...
+ok 2 - thresholds iterations: count==2
+  ---
+  count: 2
+  ...
+ok 3 - checks TRUE is TRUE
+  ---
+  passes: 2
+  fails: 0
+  ...
+not ok 4 - checks is first iteration
+  ---
+  passes: 1
+  fails: 1
+  ...
+`, string(tap))
+}
+
 func TestHandleSummary(t *testing.T) {
 	t.Parallel()
 	mainScript := `
@@ -3469,3 +3613,86 @@ func TestPLZCloudSecretsEnvVars(t *testing.T) {
 	assert.Contains(t, stderr, `level=info msg="***SECRET_REDACTED***" source=console`)
 	assert.NotContains(t, stderr, "plz-secret-value")
 }
+
+func TestRunOutputProcessing(t *testing.T) {
+	t.Parallel()
+
+	script := `
+		import { Counter } from "k6/metrics";
+
+		const requests = new Counter("my_requests");
+
+		export const options = {
+			iterations: 2,
+			outputProcessing: {
+				json: [
+					{ action: "keep", metric: "my_requests|iterations" },
+					{ action: "rename", metric: "my_(.*)", to: "processed_$1" },
+					{ action: "replaceTag", tag: "url", regex: "/users/\\d+", replacement: "/users/{id}" },
+				],
+				csv: [{ action: "dropTag", tag: "url" }],
+			},
+		};
+
+		export default function () {
+			requests.add(1, { url: "https://k6.io/users/" + __ITER });
+		}
+	`
+	ts := getSingleFileTestState(t, script, []string{"--out", "json=results.json", "--no-usage-report"}, 0)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	stderr := ts.Stderr.String()
+	t.Log(stderr)
+	assert.Contains(t, stderr, "The outputProcessing option has rules for the 'csv' output, which isn't enabled")
+
+	jsonResults, err := fsext.ReadFile(ts.FS, "results.json")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 1}, getSampleValues(t, jsonResults, "processed_requests",
+		map[string]string{"url": "https://k6.io/users/{id}"}))
+	assert.Len(t, getSampleValues(t, jsonResults, "iterations", nil), 2)
+	assert.Empty(t, getSampleValues(t, jsonResults, "my_requests", nil))
+	assert.Empty(t, getSampleValues(t, jsonResults, "iteration_duration", nil))
+
+	// the processing doesn't apply to the end-of-test summary
+	stdout := ts.Stdout.String()
+	assert.Contains(t, stdout, "my_requests")
+	assert.Contains(t, stdout, "iteration_duration")
+	assert.NotContains(t, stdout, "processed_requests")
+}
+
+func TestSummaryJUnitAndTAPWithSummaryDisabled(t *testing.T) {
+	t.Parallel()
+
+	script := `
+		import { check } from "k6";
+
+		export const options = {
+			iterations: 1,
+		};
+
+		export default function () {
+			check(true, { "TRUE is TRUE": (r) => r });
+		};
+	`
+
+	ts := getSingleFileTestState(t, script,
+		[]string{"--summary-mode=disabled", "--summary-junit=results.xml", "--summary-tap=results.tap"}, 0)
+	cmd.ExecuteWithGlobalState(ts.GlobalState)
+
+	assert.NotContains(t, ts.Stdout.String(), "TOTAL RESULTS")
+
+	junit, err := fsext.ReadFile(ts.FS, "results.xml")
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), `<testcase name="TRUE is TRUE" classname="checks">`)
+
+	tap, err := fsext.ReadFile(ts.FS, "results.tap")
+	require.NoError(t, err)
+	assert.Equal(t, `TAP version 13
+1..1
+ok 1 - checks TRUE is TRUE
+  ---
+  passes: 1
+  fails: 0
+  ...
+`, string(tap))
+}
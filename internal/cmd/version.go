@@ -168,10 +168,11 @@ func versionDetailsWithExtensions(exts []*ext.Extension) (map[string]any, error)
 	// extInfo represents the JSON structure for an extension in the version details
 	// modeled after k6 extension registry structure
 	type extInfo struct {
-		Module  string   `json:"module"`
-		Version string   `json:"version"`
-		Imports []string `json:"imports,omitempty"`
-		Outputs []string `json:"outputs,omitempty"`
+		Module    string   `json:"module"`
+		Version   string   `json:"version"`
+		Imports   []string `json:"imports,omitempty"`
+		Outputs   []string `json:"outputs,omitempty"`
+		Executors []string `json:"executors,omitempty"`
 	}
 
 	infoList := make([]*extInfo, 0, len(exts))
@@ -200,6 +201,8 @@ func versionDetailsWithExtensions(exts []*ext.Extension) (map[string]any, error)
 			// currently, no special handling is needed for secret source extensions
 		case ext.SubcommandExtension:
 			// currently, no special handling is needed for subcommand extensions
+		case ext.ExecutorExtension:
+			info.Executors = append(info.Executors, e.Name)
 		default:
 			// report unhandled extension type for future proofing
 			return details, fmt.Errorf("unhandled extension type: %s", e.Type)
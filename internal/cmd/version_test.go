@@ -151,6 +151,30 @@ func TestVersionDetailsWithExtensions(t *testing.T) {
 				require.Nil(t, extMap["imports"])
 			},
 		},
+		{
+			name: "single executor extension",
+			exts: []*ext.Extension{
+				{
+					Name:    "custom-arrival-rate",
+					Path:    "github.com/grafana/xk6-executor-custom",
+					Version: "v0.1.0",
+					Type:    ext.ExecutorExtension,
+				},
+			},
+			expected: func(t *testing.T, details map[string]any) {
+				require.Contains(t, details, "extensions")
+				extListRaw, ok := details["extensions"].([]any)
+				require.True(t, ok, "extensions should be a slice")
+				require.Len(t, extListRaw, 1)
+
+				extMap := extListRaw[0].(map[string]any)
+				require.Equal(t, "github.com/grafana/xk6-executor-custom", extMap["module"])
+				require.Equal(t, "v0.1.0", extMap["version"])
+				require.Equal(t, []any{"custom-arrival-rate"}, extMap["executors"])
+				require.Nil(t, extMap["imports"])
+				require.Nil(t, extMap["outputs"])
+			},
+		},
 		{
 			name: "multiple extensions from same module",
 			exts: []*ext.Extension{
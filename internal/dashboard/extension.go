@@ -10,6 +10,7 @@ package dashboard
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"sync/atomic"
 	"time"
 
@@ -43,6 +44,9 @@ type extension struct {
 
 	name string
 
+	// headless is set for the HTML report output, that doesn't start the web server.
+	headless bool
+
 	param *paramData
 
 	logger logrus.FieldLogger
@@ -66,6 +70,25 @@ func newWithAssets(params output.Params, assets *assets) (*extension, error) {
 		return nil, err
 	}
 
+	if err := validateSections(opts.Sections); err != nil {
+		return nil, err
+	}
+
+	for _, tag := range sectionsTags(opts.Sections) {
+		if !slices.Contains(opts.Tags, tag) {
+			opts.Tags = append(opts.Tags, tag)
+		}
+	}
+
+	if len(opts.Config) != 0 {
+		config, err := loadConfigJSON(opts.Config, &process{logger: params.Logger, fs: params.FS})
+		if err != nil {
+			return nil, err
+		}
+
+		assets.config = config
+	}
+
 	offset, _ := lib.GetEndOffset(params.ExecutionPlan)
 	period := opts.period(offset)
 
@@ -90,6 +113,10 @@ func newWithAssets(params output.Params, assets *assets) (*extension, error) {
 
 // Description returns a human-readable description of the output.
 func (ext *extension) Description() string {
+	if ext.headless {
+		return fmt.Sprintf("%s (%s)", ext.name, ext.options.Export)
+	}
+
 	if ext.options.Port < 0 {
 		return ext.name
 	}
@@ -104,6 +131,13 @@ func (ext *extension) SetThresholds(thresholds map[string]metrics.Thresholds) {
 
 // Start starts metrics aggregation and event streaming.
 func (ext *extension) Start() error {
+	config, err := withSections(ext.assets.config, ext.options.Sections, ext.param.Thresholds)
+	if err != nil {
+		return err
+	}
+
+	ext.assets.config = config
+
 	if len(ext.options.Record) != 0 {
 		ext.addEventListener(newRecorder(ext.options.Record, ext.proc))
 	}
@@ -112,8 +146,10 @@ func (ext *extension) Start() error {
 
 	ext.addEventListener(brf)
 
-	if err := ext.startServer(brf); err != nil {
-		return err
+	if !ext.headless {
+		if err := ext.startServer(brf); err != nil {
+			return err
+		}
 	}
 
 	ext.cumulative = newMeter(0, time.Now(), ext.options.Tags)
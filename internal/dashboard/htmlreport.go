@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2023 Raintank, Inc. dba Grafana Labs
+//
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package dashboard
+
+import (
+	"net/url"
+	"strings"
+
+	"go.k6.io/k6/v2/output"
+)
+
+// ReportOutputName defines the output name for the HTML report, the dashboard's
+// report exported at the end of the test run without starting the web server.
+const ReportOutputName = "html-report"
+
+const defaultReportExport = "report.html"
+
+// NewReport creates new HTML report output instance. The argument is the
+// report's file name or the dashboard's parameters, e.g.
+// "export=report.html&sections=thresholds,checks".
+func NewReport(params output.Params) (output.Output, error) {
+	params.ConfigArgument = reportQuery(params.ConfigArgument)
+
+	ext, err := newWithAssets(params, newCustomizedAssets(new(process).fromParams(params)))
+	if err != nil {
+		return nil, err
+	}
+
+	ext.headless = true
+
+	if len(ext.options.Export) == 0 {
+		ext.options.Export = defaultReportExport
+	}
+
+	return ext, nil
+}
+
+func reportQuery(arg string) string {
+	if len(arg) == 0 || strings.Contains(arg, "=") {
+		return arg
+	}
+
+	return paramExport + "=" + url.QueryEscape(arg)
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2023 Raintank, Inc. dba Grafana Labs
+//
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package dashboard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/lib/fsext"
+	"go.k6.io/k6/v2/output"
+)
+
+func TestReportQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":                    "",
+		"report.html":         "export=report.html",
+		"my report.html":      "export=my+report.html",
+		"export=a.html&tag=x": "export=a.html&tag=x",
+		"sections=checks":     "sections=checks",
+	}
+
+	for arg, expected := range tests {
+		assert.Equal(t, expected, reportQuery(arg), arg)
+	}
+}
+
+func TestReportHeadless(t *testing.T) {
+	t.Parallel()
+
+	fs := fsext.NewMemMapFs()
+
+	out, err := NewReport(output.Params{
+		OutputType:     ReportOutputName,
+		ConfigArgument: "export=out/report.html&period=50ms",
+		Logger:         testutils.NewLogger(t),
+		FS:             fs,
+		Environment:    map[string]string{},
+	})
+	require.NoError(t, err)
+
+	ext, ok := out.(*extension)
+	require.True(t, ok)
+	assert.Equal(t, "html-report (out/report.html)", ext.Description())
+
+	require.NoError(t, ext.Start())
+	assert.Nil(t, ext.server)
+
+	var rep *reporter
+	for _, listener := range ext.listeners {
+		if r, ok := listener.(*reporter); ok {
+			rep = r
+		}
+	}
+	require.NotNil(t, rep)
+
+	// the report is only written with more than one snapshot
+	require.Eventually(t, func() bool {
+		rep.mu.RLock()
+		defer rep.mu.RUnlock()
+
+		return rep.snapshotCount > 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, ext.Stop())
+
+	content, err := fsext.ReadFile(fs, "out/report.html")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<html")
+}
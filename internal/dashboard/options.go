@@ -28,14 +28,16 @@ const (
 func defaultTags() []string { return []string{"group"} }
 
 type options struct {
-	Port   int
-	Host   string
-	Period time.Duration
-	Open   bool
-	Export string
-	Record string
-	Tags   []string
-	TagsS  string
+	Port     int
+	Host     string
+	Period   time.Duration
+	Open     bool
+	Export   string
+	Record   string
+	Tags     []string
+	TagsS    string
+	Config   string
+	Sections []string
 }
 
 func envopts(env map[string]string) (*options, error) {
@@ -94,6 +96,14 @@ func envopts(env map[string]string) (*options, error) {
 		opts.Tags = strings.Split(v, ",")
 	}
 
+	if v, ok := env[envConfig]; ok {
+		opts.Config = v
+	}
+
+	if v, ok := env[envSections]; ok {
+		opts.Sections = strings.Split(v, ",")
+	}
+
 	return opts, nil
 }
 
@@ -156,6 +166,14 @@ func getopts(query string, env map[string]string) (*options, error) {
 		opts.Tags = append(opts.Tags, strings.Split(v, ",")...)
 	}
 
+	if v := value.Get(paramConfig); len(v) != 0 {
+		opts.Config = v
+	}
+
+	if v := value.Get(paramSections); len(v) != 0 {
+		opts.Sections = strings.Split(v, ",")
+	}
+
 	return opts, err
 }
 
@@ -224,4 +242,10 @@ const (
 	paramTag  = "tag"
 	paramTags = "tags"
 	envTags   = envPrefix + "TAGS"
+
+	paramConfig = "config"
+	envConfig   = envPrefix + "CONFIG"
+
+	paramSections = "sections"
+	envSections   = envPrefix + "SECTIONS"
 )
@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2023 Raintank, Inc. dba Grafana Labs
+//
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.k6.io/k6/v2/metrics"
+)
+
+const (
+	sectionThresholds = "thresholds"
+	sectionChecks     = "checks"
+	sectionScenarios  = "scenarios"
+)
+
+type configTab struct {
+	ID       string          `json:"id"`
+	Title    string          `json:"title"`
+	Summary  string          `json:"summary,omitempty"`
+	Sections []configSection `json:"sections"`
+}
+
+type configSection struct {
+	ID     string        `json:"id"`
+	Title  string        `json:"title"`
+	Panels []configPanel `json:"panels"`
+}
+
+type configPanel struct {
+	ID        string        `json:"id"`
+	Title     string        `json:"title"`
+	Kind      string        `json:"kind"`
+	FullWidth bool          `json:"fullWidth,omitempty"`
+	Series    []configSerie `json:"series"`
+}
+
+type configSerie struct {
+	Query  string `json:"query"`
+	Legend string `json:"legend,omitempty"`
+}
+
+// validateSections returns an error for the first unknown section.
+func validateSections(sections []string) error {
+	for _, section := range sections {
+		switch section {
+		case sectionThresholds, sectionChecks, sectionScenarios:
+		default:
+			return fmt.Errorf("unknown dashboard section %q, only %q, %q and %q are supported",
+				section, sectionThresholds, sectionChecks, sectionScenarios)
+		}
+	}
+
+	return nil
+}
+
+// sectionsTags returns the tags the sections break the metrics down by.
+func sectionsTags(sections []string) []string {
+	var tags []string
+
+	for _, section := range sections {
+		switch section {
+		case sectionChecks:
+			tags = append(tags, metrics.TagCheck.String())
+		case sectionScenarios:
+			tags = append(tags, metrics.TagScenario.String())
+		}
+	}
+
+	return tags
+}
+
+// withSections returns the UI config with a tab appended for each section.
+func withSections(config json.RawMessage, sections []string, thresholds map[string][]string) (json.RawMessage, error) {
+	if len(sections) == 0 {
+		return config, nil
+	}
+
+	conf := map[string]any{}
+
+	if err := json.Unmarshal(config, &conf); err != nil {
+		return nil, err
+	}
+
+	tabs, _ := conf["tabs"].([]any)
+
+	for _, section := range sections {
+		id := "tab-" + strconv.Itoa(len(tabs))
+
+		var tab *configTab
+
+		switch section {
+		case sectionThresholds:
+			tab = thresholdsTab(id, thresholds)
+		case sectionChecks:
+			tab = checksTab(id)
+		case sectionScenarios:
+			tab = scenariosTab(id)
+		}
+
+		tabs = append(tabs, tab)
+	}
+
+	conf["tabs"] = tabs
+
+	return json.Marshal(conf)
+}
+
+func thresholdsTab(id string, thresholds map[string][]string) *configTab {
+	panels := make([]configPanel, 0, len(thresholds))
+
+	names := make([]string, 0, len(thresholds))
+	for name := range thresholds {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		panels = append(panels, configPanel{
+			Title:  name + ": " + strings.Join(thresholds[name], ", "),
+			Kind:   "summary",
+			Series: []configSerie{{Query: "[?name==" + rawString(name) + "]"}},
+		})
+	}
+
+	return newConfigTab(id, "Thresholds",
+		"This chapter provides the aggregated values of the metrics with thresholds, failed thresholds are highlighted.",
+		panels...)
+}
+
+func checksTab(id string) *configTab {
+	return newConfigTab(id, "Checks",
+		"This chapter provides the rate of the successful checks, for the entire test run and over time.",
+		configPanel{
+			Title:  "Checks",
+			Kind:   "summary",
+			Series: []configSerie{{Query: "[?tags.check]"}},
+		},
+		configPanel{
+			Title:     "Check Success Rate",
+			Kind:      "chart",
+			FullWidth: true,
+			Series:    []configSerie{{Query: "checks[?tags.check && rate]"}},
+		},
+	)
+}
+
+func scenariosTab(id string) *configTab {
+	return newConfigTab(id, "Scenarios",
+		"This chapter provides the aggregated values of the metrics of each scenario.",
+		configPanel{
+			Title:     "Iteration Duration p(95)",
+			Kind:      "chart",
+			FullWidth: true,
+			Series:    []configSerie{{Query: "iteration_duration[?tags.scenario && p95]"}},
+		},
+		configPanel{Title: "Trends", Kind: "summary", Series: []configSerie{{Query: "[?tags.scenario && trend]"}}},
+		configPanel{Title: "Counters", Kind: "summary", Series: []configSerie{{Query: "[?tags.scenario && counter]"}}},
+		configPanel{Title: "Rates", Kind: "summary", Series: []configSerie{{Query: "[?tags.scenario && rate]"}}},
+	)
+}
+
+func newConfigTab(id, title, summary string, panels ...configPanel) *configTab {
+	section := configSection{ID: id + ".section-0", Panels: panels}
+
+	for idx := range section.Panels {
+		section.Panels[idx].ID = section.ID + ".panel-" + strconv.Itoa(idx)
+	}
+
+	return &configTab{ID: id, Title: title, Summary: summary, Sections: []configSection{section}}
+}
+
+// rawString returns the JMESPath raw string literal of the value.
+func rawString(value string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(value, `\`, `\\`), "'", `\'`) + "'"
+}
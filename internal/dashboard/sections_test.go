@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2023 Raintank, Inc. dba Grafana Labs
+//
+// SPDX-License-Identifier: AGPL-3.0-only
+
+package dashboard
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSections(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateSections(nil))
+	require.NoError(t, validateSections([]string{sectionThresholds, sectionChecks, sectionScenarios}))
+
+	err := validateSections([]string{sectionChecks, "groups"})
+	require.ErrorContains(t, err, `unknown dashboard section "groups"`)
+}
+
+func TestWithSections(t *testing.T) {
+	t.Parallel()
+
+	config := json.RawMessage(`{"title":"dashboard","tabs":[{"id":"tab-0"}]}`)
+
+	t.Run("no sections", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := withSections(config, nil, nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(config), string(got))
+	})
+
+	t.Run("tabs appended", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := withSections(config, []string{sectionChecks, sectionScenarios}, nil)
+		require.NoError(t, err)
+
+		var conf struct {
+			Title string      `json:"title"`
+			Tabs  []configTab `json:"tabs"`
+		}
+		require.NoError(t, json.Unmarshal(got, &conf))
+
+		assert.Equal(t, "dashboard", conf.Title)
+		require.Len(t, conf.Tabs, 3)
+		assert.Equal(t, "tab-0", conf.Tabs[0].ID)
+		assert.Equal(t, "tab-1", conf.Tabs[1].ID)
+		assert.Equal(t, "Checks", conf.Tabs[1].Title)
+		assert.Equal(t, "tab-2", conf.Tabs[2].ID)
+		assert.Equal(t, "Scenarios", conf.Tabs[2].Title)
+
+		section := conf.Tabs[2].Sections[0]
+		assert.Equal(t, "tab-2.section-0", section.ID)
+		assert.Equal(t, "tab-2.section-0.panel-1", section.Panels[1].ID)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := withSections(json.RawMessage(`[`), []string{sectionChecks}, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestThresholdsTab(t *testing.T) {
+	t.Parallel()
+
+	tab := thresholdsTab("tab-3", map[string][]string{
+		"http_req_duration":           {"p(95)<500", "p(99)<1000"},
+		`checks{check:it's a \ test}`: {"rate>0.9"},
+	})
+
+	assert.Equal(t, "Thresholds", tab.Title)
+	require.Len(t, tab.Sections, 1)
+
+	panels := tab.Sections[0].Panels
+	require.Len(t, panels, 2)
+
+	assert.Equal(t, `checks{check:it's a \ test}: rate>0.9`, panels[0].Title)
+	assert.Equal(t, `[?name=='checks{check:it\'s a \\ test}']`, panels[0].Series[0].Query)
+	assert.Equal(t, "tab-3.section-0.panel-0", panels[0].ID)
+
+	assert.Equal(t, "http_req_duration: p(95)<500, p(99)<1000", panels[1].Title)
+	assert.Equal(t, "[?name=='http_req_duration']", panels[1].Series[0].Query)
+	assert.Equal(t, "summary", panels[1].Kind)
+}
+
+func TestRawString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":           "''",
+		"http_reqs":  "'http_reqs'",
+		"it's":       `'it\'s'`,
+		`back\slash`: `'back\\slash'`,
+		`escaped\'\`: `'escaped\\\'\\'`,
+	}
+
+	for value, expected := range tests {
+		assert.Equal(t, expected, rawString(value), value)
+	}
+}
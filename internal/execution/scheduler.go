@@ -1,9 +1,11 @@
 package execution
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"runtime"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -161,7 +163,7 @@ func (e *Scheduler) getRunStats() string {
 
 func (e *Scheduler) initVUsConcurrently(
 	ctx context.Context, samplesOut chan<- metrics.SampleContainer, count uint64,
-	concurrency int, logger logrus.FieldLogger,
+	concurrency int, addVU func(lib.InitializedVU), logger logrus.FieldLogger,
 ) chan error {
 	doneInits := make(chan error, count) // poor man's waitgroup with results
 	limiter := make(chan struct{})
@@ -171,7 +173,7 @@ func (e *Scheduler) initVUsConcurrently(
 			for range limiter {
 				newVU, err := e.initVU(ctx, samplesOut, logger)
 				if err == nil {
-					e.state.AddInitializedVU(newVU)
+					addVU(newVU)
 				}
 				doneInits <- err
 			}
@@ -265,7 +267,21 @@ func (e *Scheduler) initVUsAndExecutors(ctx context.Context, samplesOut chan<- m
 	defer cancel(nil)
 
 	e.state.SetExecutionStatus(lib.ExecutionStatusInitVUs)
-	doneInits := e.initVUsConcurrently(subctx, samplesOut, vusToInitialize, runtime.GOMAXPROCS(0), logger)
+	addVU := e.state.AddInitializedVU
+	var (
+		seededVUs   []lib.InitializedVU
+		seededVUsMx sync.Mutex
+	)
+	if e.state.Test.Options.Seed.Valid {
+		// The VUs are added to the buffer once they are all initialized, in the
+		// order of their IDs, so the executors use them in the same order.
+		addVU = func(vu lib.InitializedVU) {
+			seededVUsMx.Lock()
+			defer seededVUsMx.Unlock()
+			seededVUs = append(seededVUs, vu)
+		}
+	}
+	doneInits := e.initVUsConcurrently(subctx, samplesOut, vusToInitialize, runtime.GOMAXPROCS(0), addVU, logger)
 
 	initializedVUs := new(uint64)
 	vusFmt := pb.GetFixedLengthIntFormat(int64(vusToInitialize)) //nolint:gosec
@@ -304,6 +320,13 @@ func (e *Scheduler) initVUsAndExecutors(ctx context.Context, samplesOut chan<- m
 		return initErr
 	}
 
+	slices.SortFunc(seededVUs, func(a, b lib.InitializedVU) int {
+		return cmp.Compare(a.GetID(), b.GetID())
+	})
+	for _, vu := range seededVUs {
+		e.state.AddInitializedVU(vu)
+	}
+
 	e.state.SetInitVUFunc(func(ctx context.Context, logger *logrus.Entry) (lib.InitializedVU, error) {
 		return e.initVU(ctx, samplesOut, logger)
 	})
@@ -110,6 +110,43 @@ func TestSchedulerRun(t *testing.T) {
 	assert.NoError(t, <-err)
 }
 
+// reverseInitRunner initializes the VUs with the highest IDs first, as they
+// take less time.
+type reverseInitRunner struct {
+	*minirunner.MiniRunner
+	vus uint64
+}
+
+func (r *reverseInitRunner) NewVU(
+	ctx context.Context, idLocal, idGlobal uint64, out chan<- metrics.SampleContainer,
+) (lib.InitializedVU, error) {
+	time.Sleep(time.Duration(r.vus-idLocal) * 5 * time.Millisecond)
+	return r.MiniRunner.NewVU(ctx, idLocal, idGlobal, out)
+}
+
+func TestSchedulerInitSeededVUsInOrder(t *testing.T) {
+	t.Parallel()
+
+	const vus = 10
+	runner := &reverseInitRunner{MiniRunner: &minirunner.MiniRunner{}, vus: vus}
+	_, cancel, execScheduler, _ := newTestScheduler(t, runner, nil, lib.Options{
+		VUs:        null.IntFrom(vus),
+		Iterations: null.IntFrom(vus),
+		Seed:       null.IntFrom(42),
+	})
+	defer cancel()
+
+	state := execScheduler.GetState()
+	require.EqualValues(t, vus, state.GetInitializedVUsCount())
+
+	logger := logrus.NewEntry(logrus.New())
+	for id := uint64(1); id <= vus; id++ {
+		vu, err := state.GetPlannedVU(logger, false)
+		require.NoError(t, err)
+		assert.Equal(t, id, vu.GetID())
+	}
+}
+
 func TestSchedulerRunNonDefault(t *testing.T) {
 	t.Parallel()
 
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"math/rand" // nosemgrep: math-random-used // used for the seeded random values
 	"net/url"
 	"path/filepath"
 	"runtime"
@@ -22,6 +23,7 @@ import (
 	"go.k6.io/k6/v2/internal/js/compiler"
 	"go.k6.io/k6/v2/internal/js/eventloop"
 	"go.k6.io/k6/v2/internal/js/modules/k6/webcrypto"
+	"go.k6.io/k6/v2/internal/js/tc55/fetch"
 	"go.k6.io/k6/v2/internal/js/tc55/timers"
 	"go.k6.io/k6/v2/internal/lib/consts"
 	"go.k6.io/k6/v2/internal/loader"
@@ -392,19 +394,28 @@ func (b *Bundle) instantiate(vuImpl *moduleVUImpl, vuID uint64) (*BundleInstance
 }
 
 // registerGlobals registers the globals for the runtime.
-// e.g. timers and webcrypto.
+// e.g. timers, webcrypto and fetch.
 func registerGlobals(vuImpl *moduleVUImpl) error {
 	err := timers.SetupGlobally(vuImpl)
 	if err != nil {
 		return err
 	}
 
-	return webcrypto.SetupGlobally(vuImpl)
+	err = webcrypto.SetupGlobally(vuImpl)
+	if err != nil {
+		return err
+	}
+
+	return fetch.SetupGlobally(vuImpl)
 }
 
 func (b *Bundle) setupJSRuntime(rt *sobek.Runtime, vuID uint64, logger logrus.FieldLogger) error {
 	rt.SetFieldNameMapper(common.FieldNameMapper{})
-	rt.SetRandSource(common.NewRandSource())
+	if seed := b.Options.Seed; seed.Valid {
+		rt.SetRandSource(rand.New(rand.NewSource(lib.DeriveSeed(seed.Int64, vuID))).Float64) //nolint:gosec
+	} else {
+		rt.SetRandSource(common.NewRandSource())
+	}
 
 	env := make(map[string]string, len(b.preInitState.RuntimeOptions.Env))
 	maps.Copy(env, b.preInitState.RuntimeOptions.Env)
@@ -0,0 +1,60 @@
+package compiler
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/evanw/esbuild/pkg/api"
+
+	"go.k6.io/k6/v2/lib/fsext"
+)
+
+// Bundle bundles the script and all the local modules it imports, including
+// the JSON ones, in a single ES module, with the types of the TypeScript ones
+// stripped. The imports are resolved using the paths of the tsconfig.json
+// file of the script's directory, if any, and the main fields of the packages
+// in the node_modules directories.
+//
+// The script's filename is an absolute path on the filesystem, all the files
+// are read from it. The k6 modules and the remote ones are left as imports.
+// The returned code has an inline source map, so the stack traces point to
+// the original files.
+//
+// The types are only stripped, not checked, as esbuild doesn't type check.
+func Bundle(filesystem fsext.Fs, filename string) (string, error) {
+	dir := filepath.Dir(filename)
+
+	tsconfig, err := fsext.ReadFile(filesystem, filepath.Join(dir, "tsconfig.json"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	resolver, err := newFSResolver(filesystem, dir, tsconfig)
+	if err != nil {
+		return "", err
+	}
+
+	result := api.Build(api.BuildOptions{
+		EntryPoints:    []string{filename},
+		AbsWorkingDir:  dir,
+		Bundle:         true,
+		Write:          false,
+		Format:         api.FormatESModule,
+		Target:         api.ESNext,
+		Platform:       api.PlatformNeutral,
+		TsconfigRaw:    string(tsconfig),
+		Sourcemap:      api.SourceMapInline,
+		SourcesContent: api.SourcesContentInclude,
+		LegalComments:  api.LegalCommentsNone,
+		LogLevel:       api.LogLevelSilent,
+		Charset:        api.CharsetUTF8,
+		Plugins:        []api.Plugin{resolver.plugin()},
+	})
+
+	if len(result.Errors) > 0 {
+		return "", esbuildMessageError(result.Errors[0])
+	}
+
+	return string(result.OutputFiles[0].Contents), nil
+}
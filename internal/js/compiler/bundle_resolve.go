@@ -0,0 +1,317 @@
+package compiler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/evanw/esbuild/pkg/api"
+
+	"go.k6.io/k6/v2/lib/fsext"
+)
+
+// resolveExtensions are the extensions tried, in order, for the imports
+// without one.
+var resolveExtensions = []string{".ts", ".js", ".mjs", ".json"}
+
+// mainFields are the fields of package.json with the entry point of a package.
+var mainFields = []string{"module", "main"}
+
+// fsResolver resolves and loads the modules of a bundle from a filesystem,
+// instead of esbuild reading them from the disk.
+//
+// It supports the relative and absolute imports, the paths and the baseUrl
+// of tsconfig.json, and the packages of the node_modules directories, but not
+// their "exports" maps.
+type fsResolver struct {
+	fs fsext.Fs
+
+	// baseURL is the directory of the bare imports, if set by tsconfig.json.
+	baseURL string
+	// pathsDir is the directory the paths of tsconfig.json are relative to.
+	pathsDir string
+	paths    map[string][]string
+}
+
+func newFSResolver(filesystem fsext.Fs, dir string, tsconfig []byte) (*fsResolver, error) {
+	r := &fsResolver{fs: filesystem, pathsDir: dir}
+	if len(tsconfig) == 0 {
+		return r, nil
+	}
+
+	var conf struct {
+		CompilerOptions struct {
+			BaseURL string              `json:"baseUrl"`
+			Paths   map[string][]string `json:"paths"`
+		} `json:"compilerOptions"`
+	}
+	if err := json.Unmarshal(stripJSONComments(tsconfig), &conf); err != nil {
+		return nil, fmt.Errorf("invalid tsconfig.json: %w", err)
+	}
+
+	r.paths = conf.CompilerOptions.Paths
+	if baseURL := conf.CompilerOptions.BaseURL; baseURL != "" {
+		r.baseURL = filepath.FromSlash(baseURL)
+		if !filepath.IsAbs(r.baseURL) {
+			r.baseURL = filepath.Join(dir, r.baseURL)
+		}
+		r.pathsDir = r.baseURL
+	}
+
+	return r, nil
+}
+
+func (r *fsResolver) plugin() api.Plugin {
+	return api.Plugin{
+		Name: "fs",
+		Setup: func(build api.PluginBuild) {
+			build.OnResolve(api.OnResolveOptions{Filter: ".*"}, r.onResolve)
+			build.OnLoad(api.OnLoadOptions{Filter: ".*", Namespace: "file"}, r.onLoad)
+		},
+	}
+}
+
+func (r *fsResolver) onResolve(args api.OnResolveArgs) (api.OnResolveResult, error) {
+	if isExternal(args.Path) {
+		return api.OnResolveResult{Path: args.Path, External: true}, nil
+	}
+
+	if path, ok := r.resolve(args.Path, args.ResolveDir); ok {
+		return api.OnResolveResult{Path: path}, nil
+	}
+
+	return api.OnResolveResult{
+		Errors: []api.Message{{Text: fmt.Sprintf("Could not resolve %q", args.Path)}},
+	}, nil
+}
+
+func (r *fsResolver) onLoad(args api.OnLoadArgs) (api.OnLoadResult, error) {
+	data, err := fsext.ReadFile(r.fs, args.Path)
+	if err != nil {
+		return api.OnLoadResult{}, err
+	}
+
+	contents := string(data)
+
+	return api.OnLoadResult{Contents: &contents, Loader: api.LoaderDefault}, nil
+}
+
+// isExternal returns whether the import is left as is in the bundle, for the
+// k6 modules and the remote ones.
+func isExternal(specifier string) bool {
+	return specifier == "k6" || strings.HasPrefix(specifier, "k6/") ||
+		strings.HasPrefix(specifier, "http://") || strings.HasPrefix(specifier, "https://")
+}
+
+// resolve returns the path of the file imported from the directory.
+func (r *fsResolver) resolve(specifier, dir string) (string, bool) {
+	if specifier == "." || specifier == ".." ||
+		strings.HasPrefix(specifier, "./") || strings.HasPrefix(specifier, "../") ||
+		filepath.IsAbs(filepath.FromSlash(specifier)) {
+		path := filepath.FromSlash(specifier)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		return r.resolvePath(path)
+	}
+
+	if path, ok := r.resolveAlias(specifier); ok {
+		return path, true
+	}
+
+	if r.baseURL != "" {
+		if path, ok := r.resolvePath(filepath.Join(r.baseURL, filepath.FromSlash(specifier))); ok {
+			return path, true
+		}
+	}
+
+	return r.resolvePackage(specifier, dir)
+}
+
+// resolveAlias resolves the import with the paths of tsconfig.json. Like
+// TypeScript, an exact pattern wins, then the one with the longest prefix.
+func (r *fsResolver) resolveAlias(specifier string) (string, bool) {
+	var (
+		pattern, wildcard string
+		prefixLen         = -1
+	)
+
+	for key := range r.paths {
+		prefix, suffix, hasWildcard := strings.Cut(key, "*")
+		if !hasWildcard {
+			if key == specifier {
+				pattern, wildcard = key, ""
+				break
+			}
+			continue
+		}
+		if len(prefix) > prefixLen && len(specifier) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(specifier, prefix) && strings.HasSuffix(specifier, suffix) {
+			pattern, wildcard = key, specifier[len(prefix):len(specifier)-len(suffix)]
+			prefixLen = len(prefix)
+		}
+	}
+
+	for _, target := range r.paths[pattern] {
+		path := filepath.FromSlash(strings.Replace(target, "*", wildcard, 1))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(r.pathsDir, path)
+		}
+		if resolved, ok := r.resolvePath(path); ok {
+			return resolved, true
+		}
+	}
+
+	return "", false
+}
+
+// resolvePackage resolves the import of a package, or of a file of it, from
+// the node_modules directories of the directory and of its parents.
+func (r *fsResolver) resolvePackage(specifier, dir string) (string, bool) {
+	name, subpath := specifier, ""
+	if idx := packageNameEnd(specifier); idx >= 0 {
+		name, subpath = specifier[:idx], specifier[idx+1:]
+	}
+
+	for {
+		pkg := filepath.Join(dir, "node_modules", filepath.FromSlash(name))
+		if r.isDir(pkg) {
+			return r.resolvePath(filepath.Join(pkg, filepath.FromSlash(subpath)))
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// packageNameEnd returns the index of the slash after the package name, or -1
+// if the import is the package itself. The name of a scoped package has a
+// slash, e.g. "@scope/name".
+func packageNameEnd(specifier string) int {
+	offset := 0
+	if strings.HasPrefix(specifier, "@") {
+		offset = strings.IndexByte(specifier, '/') + 1
+	}
+
+	idx := strings.IndexByte(specifier[offset:], '/')
+	if idx < 0 {
+		return -1
+	}
+
+	return offset + idx
+}
+
+// resolvePath returns the path of the file, the one with an extension added,
+// or the entry point of the directory.
+func (r *fsResolver) resolvePath(path string) (string, bool) {
+	if r.isFile(path) {
+		return path, true
+	}
+
+	for _, ext := range resolveExtensions {
+		if r.isFile(path + ext) {
+			return path + ext, true
+		}
+	}
+
+	// the TypeScript modules are imported with the extension of their output,
+	// e.g. "./lib.js" for "./lib.ts"
+	if ext := filepath.Ext(path); ext == ".js" || ext == ".mjs" {
+		ts := strings.TrimSuffix(path, ext) + ".ts"
+		if r.isFile(ts) {
+			return ts, true
+		}
+	}
+
+	if r.isDir(path) {
+		return r.resolveDir(path)
+	}
+
+	return "", false
+}
+
+// resolveDir returns the path of the directory's entry point, from the main
+// fields of its package.json or its index file.
+func (r *fsResolver) resolveDir(dir string) (string, bool) {
+	if data, err := fsext.ReadFile(r.fs, filepath.Join(dir, "package.json")); err == nil {
+		pkg := make(map[string]any)
+		if err := json.Unmarshal(data, &pkg); err == nil {
+			for _, field := range mainFields {
+				main, _ := pkg[field].(string)
+				if main == "" {
+					continue
+				}
+				if path, ok := r.resolvePath(filepath.Join(dir, filepath.FromSlash(main))); ok {
+					return path, true
+				}
+			}
+		}
+	}
+
+	index := filepath.Join(dir, "index")
+	for _, ext := range resolveExtensions {
+		if r.isFile(index + ext) {
+			return index + ext, true
+		}
+	}
+
+	return "", false
+}
+
+func (r *fsResolver) isFile(path string) bool {
+	info, err := r.fs.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func (r *fsResolver) isDir(path string) bool {
+	info, err := r.fs.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// stripJSONComments returns the JSON with comments of tsconfig.json without
+// its comments and trailing commas, so it's valid JSON.
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(data) && data[end] != '"' {
+				if data[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(data))
+			out = append(out, data[i:end]...)
+			i = end - 1
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i+1 < len(data) && data[i+1] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			i += 2 + end + 1
+		case c == '}' || c == ']':
+			trimmed := bytes.TrimRight(out, " \t\r\n")
+			if len(trimmed) > 0 && trimmed[len(trimmed)-1] == ',' {
+				out = append(trimmed[:len(trimmed)-1], out[len(trimmed):]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,111 @@
+package compiler
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/grafana/sobek/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/lib/fsext"
+)
+
+func newBundleFS(t *testing.T, dir string, files map[string]string) fsext.Fs {
+	t.Helper()
+
+	fs := fsext.NewMemMapFs()
+	for name, content := range files {
+		require.NoError(t, fsext.WriteFile(fs, filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0o600))
+	}
+
+	return fs
+}
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.FromSlash("/test")
+	fs := newBundleFS(t, dir, map[string]string{
+		"tsconfig.json": `{
+			// the aliases of the local modules
+			"compilerOptions": {
+				"baseUrl": ".",
+				"paths": {"@lib/*": ["lib/*"], "config": ["config/default.json"]}, /* trailing comma */
+			},
+		}`,
+		"data.json":           `{"users": ["alice", "bob"]}`,
+		"config/default.json": `{"greeting": "Hello, "}`,
+		"lib/greet.ts": `
+			import config from "config";
+			export function greet(name: string): string { return config.greeting + name; }
+		`,
+		"lib/format/index.ts":                  `export function upper(s: string): string { return s.toUpperCase(); }`,
+		"node_modules/@scope/pkg/package.json": `{"name": "@scope/pkg", "main": "dist/main.js"}`,
+		"node_modules/@scope/pkg/dist/main.js": `export const packaged = "from the package";`,
+		"script.ts": `
+			import http from "k6/http";
+			import { greet } from "@lib/greet";
+			import { upper } from "./lib/format";
+			import { packaged } from "@scope/pkg";
+			import data from "./data.json";
+
+			export const options = { vus: 1 };
+
+			export default function (): void {
+				http.get("https://test.k6.io");
+				greet(upper(data.users[0]) + packaged);
+			}
+		`,
+	})
+
+	code, err := Bundle(fs, filepath.Join(dir, "script.ts"))
+	require.NoError(t, err)
+
+	assert.Contains(t, code, `import http from "k6/http";`)
+	assert.Contains(t, code, `function greet(name) {`)
+	assert.Contains(t, code, `"Hello, "`)
+	assert.Contains(t, code, `function upper(s) {`)
+	assert.Contains(t, code, `"from the package"`)
+	assert.Contains(t, code, `"alice"`)
+	assert.NotContains(t, code, `from "@lib/greet"`)
+	assert.NotContains(t, code, `from "@scope/pkg"`)
+	assert.Contains(t, code, "//# sourceMappingURL=data:application/json;base64,")
+
+	_, err = sobek.Parse("script.js", code, parser.IsModule)
+	require.NoError(t, err)
+}
+
+func TestBundleError(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.FromSlash("/test")
+	fs := newBundleFS(t, dir, map[string]string{
+		"script.ts": "import { missing } from \"./missing\";\nmissing();",
+	})
+
+	_, err := Bundle(fs, filepath.Join(dir, "script.ts"))
+	var perr *parser.Error
+	require.ErrorAs(t, err, &perr)
+	assert.Contains(t, perr.Message, `Could not resolve "./missing"`)
+	assert.Equal(t, 1, perr.Position.Line)
+}
+
+func TestStripJSONComments(t *testing.T) {
+	t.Parallel()
+
+	got := stripJSONComments([]byte(`{
+		// a comment
+		"a": "// not a comment", /* a block
+		comment */ "b": ["/* not a comment */", "\"quoted\"",],
+	}`))
+
+	var v map[string]any
+	require.NoError(t, json.Unmarshal(got, &v))
+	assert.Equal(t, map[string]any{
+		"a": "// not a comment",
+		"b": []any{"/* not a comment */", `"quoted"`},
+	}, v)
+}
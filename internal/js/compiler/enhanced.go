@@ -36,7 +36,10 @@ func esbuildCheckError(result *api.TransformResult) (bool, error) {
 		return false, nil
 	}
 
-	msg := result.Errors[0]
+	return true, esbuildMessageError(result.Errors[0])
+}
+
+func esbuildMessageError(msg api.Message) error {
 	err := &parser.Error{Message: msg.Text}
 
 	if msg.Location != nil {
@@ -47,5 +50,5 @@ func esbuildCheckError(result *api.TransformResult) (bool, error) {
 		}
 	}
 
-	return true, err
+	return err
 }
@@ -1,6 +1,7 @@
 package js
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"reflect"
@@ -10,17 +11,26 @@ import (
 	"github.com/grafana/sobek"
 	"github.com/sirupsen/logrus"
 
+	"go.k6.io/k6/v2/internal/lib/trace"
+	"go.k6.io/k6/v2/internal/log"
 	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/metrics"
 )
 
 // console represents a JS console implemented as a logrus.FieldLogger.
 type console struct {
 	logger logrus.FieldLogger
+
+	// vu is set for the consoles of the VUs, to add the attributes of their
+	// entries, see withVU.
+	vu modules.VU
 }
 
 // Creates a console with the standard logrus logger.
 func newConsole(logger logrus.FieldLogger) *console {
-	return &console{logger.WithField("source", "console")}
+	return &console{logger: logger.WithField("source", "console")}
 }
 
 // Creates a console logger with its output set to the file at the provided `filepath`.
@@ -36,7 +46,41 @@ func newFileConsole(filepath string, formatter logrus.Formatter, level logrus.Le
 	l.SetOutput(f)
 	l.SetFormatter(formatter)
 
-	return &console{l}, nil
+	return &console{logger: l}, nil
+}
+
+// withVU returns a copy of the console adding the VU, the iteration, the
+// scenario and the trace of the entries as their attributes, for the hooks
+// exporting them.
+func (c *console) withVU(vu modules.VU) *console {
+	return &console{logger: c.logger, vu: vu}
+}
+
+// attributes returns the attributes of the entries logged by the VU's current
+// iteration, or nil outside of the iterations.
+func (c console) attributes() map[string]string {
+	if c.vu == nil {
+		return nil
+	}
+	state := c.vu.State()
+	if state == nil {
+		return nil
+	}
+
+	attrs := map[string]string{
+		metrics.TagVU.String():   strconv.FormatUint(state.VUID, 10),
+		metrics.TagIter.String(): strconv.FormatInt(state.Iteration, 10),
+	}
+	if scenario := lib.GetScenarioState(c.vu.Context()); scenario != nil {
+		attrs[metrics.TagScenario.String()] = scenario.Name
+	}
+	metadata := state.Tags.GetCurrentValues().Metadata
+	for _, key := range []string{trace.MetadataTraceID, trace.MetadataSpanID} {
+		if value, ok := metadata[key]; ok {
+			attrs[key] = value
+		}
+	}
+	return attrs
 }
 
 func (c console) log(level logrus.Level, args ...sobek.Value) {
@@ -49,15 +93,25 @@ func (c console) log(level logrus.Level, args ...sobek.Value) {
 	}
 	msg := strs.String()
 
+	logger := c.logger
+	if c.vu != nil {
+		if l, ok := logger.(interface {
+			WithContext(ctx context.Context) *logrus.Entry
+		}); ok {
+			// the attributes are only computed by the hooks exporting the entries
+			logger = l.WithContext(log.WithAttributes(c.vu.Context(), c.attributes))
+		}
+	}
+
 	switch level {
 	case logrus.DebugLevel:
-		c.logger.Debug(msg)
+		logger.Debug(msg)
 	case logrus.InfoLevel:
-		c.logger.Info(msg)
+		logger.Info(msg)
 	case logrus.WarnLevel:
-		c.logger.Warn(msg)
+		logger.Warn(msg)
 	case logrus.ErrorLevel:
-		c.logger.Error(msg)
+		logger.Error(msg)
 	default:
 		panic("unsupported log level: " + level.String())
 	}
@@ -16,7 +16,9 @@ import (
 	"gopkg.in/guregu/null.v3"
 
 	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/internal/lib/trace"
 	"go.k6.io/k6/v2/internal/loader"
+	k6log "go.k6.io/k6/v2/internal/log"
 	"go.k6.io/k6/v2/internal/usage"
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/lib"
@@ -30,7 +32,7 @@ func TestConsoleContext(t *testing.T) {
 	rt.SetFieldNameMapper(common.FieldNameMapper{})
 
 	logger, hook := logtest.NewNullLogger()
-	_ = rt.Set("console", &console{logger})
+	_ = rt.Set("console", &console{logger: logger})
 
 	_, err := rt.RunString(`console.log("a")`)
 	require.NoError(t, err)
@@ -491,6 +493,54 @@ func TestConsoleLevels(t *testing.T) {
 	}
 }
 
+// attributesHook records the attributes of the entries, when they are fired.
+type attributesHook struct {
+	attrs []map[string]string
+}
+
+func (h *attributesHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *attributesHook) Fire(entry *logrus.Entry) error {
+	h.attrs = append(h.attrs, k6log.Attributes(entry.Context))
+	return nil
+}
+
+func TestConsoleAttributes(t *testing.T) {
+	t.Parallel()
+
+	r, err := getSimpleRunner(t, "/script.js", `
+		exports.options = { traceRequests: true };
+		exports.default = function() { console.log("hello"); }
+	`)
+	require.NoError(t, err)
+
+	ctx := lib.WithScenarioState(t.Context(), &lib.ScenarioState{Name: "default"})
+
+	samples := make(chan metrics.SampleContainer, 100)
+	initVU, err := r.newVU(ctx, 1, 1, samples)
+	require.NoError(t, err)
+
+	vu := initVU.Activate(&lib.VUActivationParams{RunContext: ctx})
+
+	logger := extractLogger(vu)
+	logger.Out = io.Discard
+	hook := &attributesHook{}
+	logger.AddHook(hook)
+
+	require.NoError(t, vu.RunOnce())
+	require.NoError(t, vu.RunOnce())
+
+	require.Len(t, hook.attrs, 2)
+	attrs := hook.attrs[1]
+	assert.Equal(t, "1", attrs["vu"])
+	assert.Equal(t, "1", attrs["iter"])
+	assert.Equal(t, "default", attrs["scenario"])
+	assert.Len(t, attrs[trace.MetadataTraceID], 32)
+	assert.Len(t, attrs[trace.MetadataSpanID], 16)
+}
+
 func TestFileConsole(t *testing.T) {
 	t.Parallel()
 	var (
@@ -12,9 +12,17 @@ import (
 	"go.k6.io/k6/v2/internal/js/modules/k6/data"
 	"go.k6.io/k6/v2/internal/js/modules/k6/encoding"
 	"go.k6.io/k6/v2/internal/js/modules/k6/execution"
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/avro"
 	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/csv"
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/dns"
 	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/fs"
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/jwt"
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/mqtt"
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/msgpack"
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/protobuf"
 	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/streams"
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/tcp"
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/udp"
 	"go.k6.io/k6/v2/internal/js/modules/k6/grpc"
 	"go.k6.io/k6/v2/internal/js/modules/k6/metrics"
 	"go.k6.io/k6/v2/internal/js/modules/k6/secrets"
@@ -47,9 +55,17 @@ func getInternalJSModules() map[string]any {
 		"k6/ws":          ws.New(),
 
 		// Experimental modules
-		"k6/experimental/csv":     csv.New(),
-		"k6/experimental/fs":      fs.New(),
-		"k6/experimental/streams": streams.New(),
+		"k6/experimental/avro":     avro.New(),
+		"k6/experimental/csv":      csv.New(),
+		"k6/experimental/dns":      dns.New(),
+		"k6/experimental/fs":       fs.New(),
+		"k6/experimental/jwt":      jwt.New(),
+		"k6/experimental/mqtt":     mqtt.New(),
+		"k6/experimental/msgpack":  msgpack.New(),
+		"k6/experimental/protobuf": protobuf.New(),
+		"k6/experimental/streams":  streams.New(),
+		"k6/experimental/tcp":      tcp.New(),
+		"k6/experimental/udp":      udp.New(),
 
 		// Deprecated modules
 		"k6/experimental/websockets": newWarnExperimentalModule(websockets.New(),
@@ -48,7 +48,19 @@ func New() *RootModule {
 // NewModuleInstance implements the modules.Module interface to return
 // a new instance for each VU.
 func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
-	return &Crypto{vu: vu, randReader: rand.Read}
+	c := &Crypto{vu: vu}
+	c.randReader = c.readRandom
+	return c
+}
+
+// readRandom fills b with random bytes, from the seeded random generator of
+// the VU if the seed option is set, for the test run to be reproducible.
+func (c *Crypto) readRandom(b []byte) (int, error) {
+	if state := c.vu.State(); state != nil && state.Rand != nil {
+		return state.Rand.Read(b)
+	}
+
+	return rand.Read(b)
 }
 
 // Exports returns the exports of the execution module.
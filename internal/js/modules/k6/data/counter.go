@@ -0,0 +1,105 @@
+package data
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/lib"
+)
+
+const counterDataIDPrefix = "k6/data/Counter/"
+
+// counter is an integer shared between all the VUs of the k6 instance, which
+// is atomically updated.
+type counter struct {
+	name string
+
+	once sync.Once
+	err  error
+
+	value atomic.Int64
+}
+
+// load initializes the counter with the data chunk the controller has for it,
+// so the counter's initial value is the same for all the instances of the test
+// run. The later updates are only seen by the VUs of this instance.
+func (c *counter) load(controller lib.DataController) error {
+	c.once.Do(func() {
+		if controller == nil {
+			return
+		}
+
+		data, err := controller.GetOrCreateData(counterDataIDPrefix+c.name, func() ([]byte, error) {
+			return []byte("0"), nil
+		})
+		if err != nil {
+			c.err = err
+			return
+		}
+
+		value, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.value.Store(value)
+	})
+
+	return c.err
+}
+
+// counter is a constructor returning an integer identified by the name, which
+// is shared and atomically updated by all the VUs.
+func (d *Data) counter(call sobek.ConstructorCall) *sobek.Object {
+	rt := d.vu.Runtime()
+
+	name := call.Argument(0).String()
+	if name == "" {
+		common.Throw(rt, errors.New("empty name provided to Counter's constructor"))
+	}
+
+	c := d.state.loadOrStoreCounter(name)
+
+	load := func() {
+		if err := c.load(d.controller()); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+
+	obj := rt.NewObject()
+
+	must(rt, obj.Set("get", func() int64 {
+		load()
+		return c.value.Load()
+	}))
+	must(rt, obj.Set("set", func(value int64) {
+		load()
+		c.value.Store(value)
+	}))
+	must(rt, obj.Set("add", func(delta sobek.Value) int64 {
+		load()
+		if common.IsNullish(delta) {
+			return c.value.Add(1)
+		}
+		return c.value.Add(delta.ToInteger())
+	}))
+	must(rt, obj.Set("increment", func() int64 {
+		load()
+		return c.value.Add(1)
+	}))
+	must(rt, obj.Set("decrement", func() int64 {
+		load()
+		return c.value.Add(-1)
+	}))
+	must(rt, obj.Set("compareAndSwap", func(expected, value int64) bool {
+		load()
+		return c.value.CompareAndSwap(expected, value)
+	}))
+
+	return obj
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/lib"
+)
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	runtime, err := newConfiguredRuntime(t)
+	require.NoError(t, err)
+
+	_, err = runtime.VU.Runtime().RunString(`
+		var c = new data.Counter("accounts");
+		if (c.get() !== 0) {
+			throw new Error("unexpected initial value: " + c.get());
+		}
+		if (c.increment() !== 1 || c.add() !== 2 || c.add(5) !== 7 || c.decrement() !== 6) {
+			throw new Error("unexpected value: " + c.get());
+		}
+		if (c.compareAndSwap(5, 10) || !c.compareAndSwap(6, 10) || c.get() !== 10) {
+			throw new Error("unexpected swap: " + c.get());
+		}
+		c.set(100);
+	`)
+	require.NoError(t, err)
+
+	another, err := configuredRuntimeFromAnother(t, runtime)
+	require.NoError(t, err)
+
+	v, err := another.VU.Runtime().RunString(`new data.Counter("accounts").get()`)
+	require.NoError(t, err)
+	require.EqualValues(t, 100, v.ToInteger())
+
+	_, err = another.VU.Runtime().RunString(`new data.Counter("")`)
+	require.ErrorContains(t, err, "empty name provided to Counter's constructor")
+}
+
+func TestCounterConcurrentIncrements(t *testing.T) {
+	t.Parallel()
+
+	runtime, err := newConfiguredRuntime(t)
+	require.NoError(t, err)
+
+	const vus, iterations = 10, 100
+
+	var wg sync.WaitGroup
+	for range vus {
+		vu, err := configuredRuntimeFromAnother(t, runtime)
+		require.NoError(t, err)
+
+		wg.Go(func() {
+			_, _ = vu.VU.Runtime().RunString(`
+				var c = new data.Counter("ids");
+				for (var i = 0; i < 100; i++) {
+					c.increment();
+				}
+			`)
+		})
+	}
+	wg.Wait()
+
+	v, err := runtime.VU.Runtime().RunString(`new data.Counter("ids").get()`)
+	require.NoError(t, err)
+	require.EqualValues(t, vus*iterations, v.ToInteger())
+}
+
+func TestCounterExecutionSegmentWarning(t *testing.T) {
+	t.Parallel()
+
+	runtime, err := newConfiguredRuntime(t)
+	require.NoError(t, err)
+
+	_, err = runtime.VU.Runtime().RunString(`var c = new data.Counter("orders"); var m = new data.SharedMap("orders");`)
+	require.NoError(t, err)
+
+	segment, err := lib.NewExecutionSegmentFromString("0:1/2")
+	require.NoError(t, err)
+	logger, hook := testutils.NewLoggerWithHook(t, logrus.WarnLevel)
+	runtime.MoveToVUContext(&lib.State{
+		Options: lib.Options{ExecutionSegment: segment},
+		Logger:  logger,
+	})
+
+	_, err = runtime.VU.Runtime().RunString(`c.increment(); c.increment(); m.set("a", 1);`)
+	require.NoError(t, err)
+
+	lines := hook.Lines()
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], "The test run is split with the execution segment 0:1/2")
+}
@@ -14,6 +14,7 @@ import (
 
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
 )
 
 type (
@@ -21,18 +22,33 @@ type (
 	// instances for each VU.
 	RootModule struct {
 		shared sharedArrays
+		state  sharedState
 	}
 
 	// Data represents an instance of the data module.
 	Data struct {
-		vu     modules.VU
-		shared *sharedArrays
+		vu           modules.VU
+		shared       *sharedArrays
+		state        *sharedState
+		preInitState *lib.TestPreInitState
 	}
 
 	sharedArrays struct {
 		data map[string]sharedArray
 		mu   sync.RWMutex
 	}
+
+	// sharedState holds the writable SharedMap and Counter instances, which
+	// are shared between all the VUs of the k6 instance. Only their initial
+	// values come from the DataController, the writes aren't shared with the
+	// other instances of a test run split with execution segments.
+	sharedState struct {
+		maps     map[string]*sharedMap
+		counters map[string]*counter
+		mu       sync.Mutex
+
+		segmentWarning sync.Once
+	}
 )
 
 var (
@@ -46,16 +62,26 @@ func New() *RootModule {
 		shared: sharedArrays{
 			data: make(map[string]sharedArray),
 		},
+		state: sharedState{
+			maps:     make(map[string]*sharedMap),
+			counters: make(map[string]*counter),
+		},
 	}
 }
 
 // NewModuleInstance implements the modules.Module interface to return
 // a new instance for each VU.
 func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
-	return &Data{
+	d := &Data{
 		vu:     vu,
 		shared: &rm.shared,
+		state:  &rm.state,
 	}
+	if initEnv := vu.InitEnv(); initEnv != nil {
+		d.preInitState = initEnv.TestPreInitState
+	}
+
+	return d
 }
 
 // Exports returns the exports of the data module.
@@ -63,6 +89,8 @@ func (d *Data) Exports() modules.Exports {
 	return modules.Exports{
 		Named: map[string]any{
 			"SharedArray": d.sharedArray,
+			"SharedMap":   d.sharedMap,
+			"Counter":     d.counter,
 		},
 	}
 }
@@ -219,3 +247,59 @@ func getSharedArrayFromCall(rt *sobek.Runtime, call sobek.Callable) (sharedArray
 
 	return sharedArray{arr: arr}, nil
 }
+
+// controller returns the execution controller of the test run, it is read on
+// use because it is set only once the test is loaded. Without one, e.g. when
+// the module is used outside of the k6 commands, the values start empty.
+func (d *Data) controller() lib.DataController {
+	d.warnIfSegmented()
+	if d.preInitState == nil {
+		return nil
+	}
+	return d.preInitState.DataController
+}
+
+// warnIfSegmented warns once if the test run is split with execution segments,
+// as the SharedMap and Counter values are then only shared by the VUs of each
+// instance, e.g. the same value can be consumed once per instance.
+func (d *Data) warnIfSegmented() {
+	state := d.vu.State()
+	if state == nil || state.Options.ExecutionSegment.FloatLength() == 1 {
+		return
+	}
+	d.state.segmentWarning.Do(func() {
+		state.Logger.Warnf("The test run is split with the execution segment %s, the values of "+
+			"k6/data's SharedMap and Counter are only shared by the VUs of this instance",
+			state.Options.ExecutionSegment)
+	})
+}
+
+func (s *sharedState) loadOrStoreMap(name string) *sharedMap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.maps[name]
+	if !ok {
+		m = &sharedMap{name: name}
+		s.maps[name] = m
+	}
+	return m
+}
+
+func (s *sharedState) loadOrStoreCounter(name string) *counter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[name]
+	if !ok {
+		c = &counter{name: name}
+		s.counters[name] = c
+	}
+	return c
+}
+
+func must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
@@ -0,0 +1,194 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/lib"
+)
+
+const sharedMapDataIDPrefix = "k6/data/SharedMap/"
+
+// sharedMap is a key-value store shared between all the VUs of the k6 instance,
+// with its values kept as JSON strings, like the ones of the SharedArray.
+type sharedMap struct {
+	name string
+
+	once sync.Once
+	err  error
+
+	mu   sync.Mutex
+	data map[string]string
+}
+
+// load initializes the map with the data chunk the controller has for it, so
+// the map's initial state is the same for all the instances of the test run.
+// The later writes are only seen by the VUs of this instance.
+func (m *sharedMap) load(controller lib.DataController) error {
+	m.once.Do(func() {
+		m.data = make(map[string]string)
+		if controller == nil {
+			return
+		}
+
+		data, err := controller.GetOrCreateData(sharedMapDataIDPrefix+m.name, func() ([]byte, error) {
+			return []byte("{}"), nil
+		})
+		if err != nil {
+			m.err = err
+			return
+		}
+
+		m.err = json.Unmarshal(data, &m.data)
+	})
+
+	return m.err
+}
+
+func (m *sharedMap) get(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.data[key]
+	return value, ok
+}
+
+func (m *sharedMap) set(key, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.data[key] = value
+}
+
+func (m *sharedMap) delete(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.data[key]
+	delete(m.data, key)
+	return ok
+}
+
+// compareAndSwap sets the value of the key only if its current value is the
+// expected one, a nil expected value means that the key must not exist.
+func (m *sharedMap) compareAndSwap(key string, expected *string, value string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, ok := m.data[key]
+	if ok != (expected != nil) || (ok && current != *expected) {
+		return false
+	}
+
+	m.data[key] = value
+	return true
+}
+
+func (m *sharedMap) keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.data))
+	for key := range m.data {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (m *sharedMap) size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.data)
+}
+
+// sharedMap is a constructor returning a key-value store identified by the
+// name, which is shared and writable by all the VUs.
+func (d *Data) sharedMap(call sobek.ConstructorCall) *sobek.Object {
+	rt := d.vu.Runtime()
+
+	name := call.Argument(0).String()
+	if name == "" {
+		common.Throw(rt, errors.New("empty name provided to SharedMap's constructor"))
+	}
+
+	m := d.state.loadOrStoreMap(name)
+	stringify, parse := jsonFuncs(rt)
+
+	load := func() {
+		if err := m.load(d.controller()); err != nil {
+			common.Throw(rt, err)
+		}
+	}
+	toJSON := func(value sobek.Value) string {
+		if value == nil || sobek.IsUndefined(value) {
+			common.Throw(rt, errors.New("undefined can't be stored in a SharedMap"))
+		}
+		s, err := stringify(sobek.Undefined(), value)
+		if err != nil {
+			common.Throw(rt, err)
+		}
+		return s.String()
+	}
+	fromJSON := func(value string) sobek.Value {
+		v, err := parse(sobek.Undefined(), rt.ToValue(value))
+		if err != nil {
+			common.Throw(rt, err)
+		}
+		return v
+	}
+
+	obj := rt.NewObject()
+
+	must(rt, obj.Set("get", func(key string) sobek.Value {
+		load()
+		value, ok := m.get(key)
+		if !ok {
+			return sobek.Undefined()
+		}
+		return fromJSON(value)
+	}))
+	must(rt, obj.Set("set", func(key string, value sobek.Value) {
+		load()
+		m.set(key, toJSON(value))
+	}))
+	must(rt, obj.Set("has", func(key string) bool {
+		load()
+		_, ok := m.get(key)
+		return ok
+	}))
+	must(rt, obj.Set("delete", func(key string) bool {
+		load()
+		return m.delete(key)
+	}))
+	must(rt, obj.Set("compareAndSwap", func(key string, expected, value sobek.Value) bool {
+		load()
+		var exp *string
+		if expected != nil && !sobek.IsUndefined(expected) {
+			s := toJSON(expected)
+			exp = &s
+		}
+		return m.compareAndSwap(key, exp, toJSON(value))
+	}))
+	must(rt, obj.Set("keys", func() []string {
+		load()
+		return m.keys()
+	}))
+	must(rt, obj.DefineAccessorProperty("size", rt.ToValue(func() int {
+		load()
+		return m.size()
+	}), nil, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+	return obj
+}
+
+func jsonFuncs(rt *sobek.Runtime) (stringify, parse sobek.Callable) {
+	jsonObj := rt.GlobalObject().Get("JSON").ToObject(rt)
+	stringify, _ = sobek.AssertFunction(jsonObj.Get("stringify"))
+	parse, _ = sobek.AssertFunction(jsonObj.Get("parse"))
+	return stringify, parse
+}
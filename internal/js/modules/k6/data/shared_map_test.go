@@ -0,0 +1,164 @@
+package data
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedMap(t *testing.T) {
+	t.Parallel()
+
+	runtime, err := newConfiguredRuntime(t)
+	require.NoError(t, err)
+
+	_, err = runtime.VU.Runtime().RunString(`
+		var m = new data.SharedMap("accounts");
+		m.set("alice", {id: 1, roles: ["admin"]});
+
+		var alice = m.get("alice");
+		if (alice.id !== 1 || alice.roles[0] !== "admin") {
+			throw new Error("unexpected value: " + JSON.stringify(alice));
+		}
+		alice.id = 2;
+		if (m.get("alice").id !== 1) {
+			throw new Error("the stored value should be a copy");
+		}
+		if (m.get("bob") !== undefined || m.has("bob")) {
+			throw new Error("bob should not exist");
+		}
+
+		if (m.compareAndSwap("alice", {id: 2, roles: ["admin"]}, "wrong")) {
+			throw new Error("swapped with an unexpected value");
+		}
+		if (!m.compareAndSwap("alice", {id: 1, roles: ["admin"]}, "swapped") || m.get("alice") !== "swapped") {
+			throw new Error("not swapped with the expected value");
+		}
+		if (!m.compareAndSwap("bob", undefined, "new") || m.compareAndSwap("bob", undefined, "again")) {
+			throw new Error("unexpected swap of a missing key");
+		}
+
+		if (m.size !== 2 || m.keys().sort().join() !== "alice,bob") {
+			throw new Error("unexpected keys: " + m.keys());
+		}
+		if (!m.delete("bob") || m.delete("bob") || m.size !== 1) {
+			throw new Error("unexpected delete");
+		}
+	`)
+	require.NoError(t, err)
+
+	another, err := configuredRuntimeFromAnother(t, runtime)
+	require.NoError(t, err)
+
+	_, err = another.VU.Runtime().RunString(`
+		var m = new data.SharedMap("accounts");
+		if (m.get("alice") !== "swapped") {
+			throw new Error("the map is not shared: " + m.get("alice"));
+		}
+		m.set("undefined", undefined);
+	`)
+	require.ErrorContains(t, err, "undefined can't be stored in a SharedMap")
+}
+
+func TestSharedMapConsumedOnce(t *testing.T) {
+	t.Parallel()
+
+	runtime, err := newConfiguredRuntime(t)
+	require.NoError(t, err)
+
+	_, err = runtime.VU.Runtime().RunString(`
+		var orders = new data.SharedMap("orders");
+		for (var i = 0; i < 100; i++) {
+			orders.set("order-" + i, "available");
+		}
+	`)
+	require.NoError(t, err)
+
+	const vus = 10
+	consumed := make([]int64, vus)
+
+	var wg sync.WaitGroup
+	for i := range vus {
+		vu, err := configuredRuntimeFromAnother(t, runtime)
+		require.NoError(t, err)
+
+		wg.Go(func() {
+			v, err := vu.VU.Runtime().RunString(`
+				var orders = new data.SharedMap("orders");
+				var consumed = 0;
+				for (var i = 0; i < 100; i++) {
+					if (orders.compareAndSwap("order-" + i, "available", "consumed")) {
+						consumed++;
+					}
+				}
+				consumed;
+			`)
+			if err == nil {
+				consumed[i] = v.ToInteger()
+			}
+		})
+	}
+	wg.Wait()
+
+	var total int64
+	for _, c := range consumed {
+		total += c
+	}
+	require.EqualValues(t, 100, total)
+}
+
+type testDataController struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ids  []string
+}
+
+func (c *testDataController) GetOrCreateData(id string, callback func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ids = append(c.ids, id)
+	if data, ok := c.data[id]; ok {
+		return data, nil
+	}
+	return callback()
+}
+
+func TestSharedStateDataController(t *testing.T) {
+	t.Parallel()
+
+	controller := &testDataController{
+		data: map[string][]byte{
+			"k6/data/SharedMap/accounts": []byte(`{"alice":"{\"id\":1}"}`),
+			"k6/data/Counter/orders":     []byte(`41`),
+		},
+	}
+
+	runtime, err := newConfiguredRuntime(t)
+	require.NoError(t, err)
+	runtime.VU.InitEnv().DataController = controller
+
+	_, err = runtime.VU.Runtime().RunString(`
+		var accounts = new data.SharedMap("accounts");
+		var orders = new data.Counter("orders");
+		var users = new data.Counter("users");
+
+		if (accounts.get("alice").id !== 1) {
+			throw new Error("unexpected initial value: " + JSON.stringify(accounts.get("alice")));
+		}
+		if (orders.increment() !== 42 || orders.get() !== 42) {
+			throw new Error("unexpected initial count: " + orders.get());
+		}
+		if (users.get() !== 0) {
+			throw new Error("unexpected default count: " + users.get());
+		}
+	`)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		"k6/data/SharedMap/accounts",
+		"k6/data/Counter/orders",
+		"k6/data/Counter/users",
+	}, controller.ids)
+}
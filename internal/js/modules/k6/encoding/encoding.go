@@ -3,9 +3,11 @@ package encoding
 
 import (
 	"encoding/base64"
+	"fmt"
 
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib/netext/httpext"
 )
 
 type (
@@ -39,8 +41,10 @@ func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 func (e *Encoding) Exports() modules.Exports {
 	return modules.Exports{
 		Named: map[string]any{
-			"b64encode": e.b64Encode,
-			"b64decode": e.b64Decode,
+			"b64encode":  e.b64Encode,
+			"b64decode":  e.b64Decode,
+			"compress":   e.compress,
+			"decompress": e.decompress,
 		},
 	}
 }
@@ -102,3 +106,66 @@ func (e *Encoding) b64Decode(input, encoding, format string) any {
 
 	return out
 }
+
+// compress returns the input compressed with the given algorithm, one of
+// "gzip", "deflate", "br" (or "brotli") and "zstd", as an ArrayBuffer.
+// The data type of input can be a string, []byte or ArrayBuffer.
+func (e *Encoding) compress(input any, algorithm string) any {
+	data, err := common.ToBytes(input)
+	if err != nil {
+		common.Throw(e.vu.Runtime(), err)
+	}
+
+	compression, err := parseCompressionType(algorithm)
+	if err != nil {
+		common.Throw(e.vu.Runtime(), err)
+	}
+
+	output, err := httpext.Compress(compression, data)
+	if err != nil {
+		common.Throw(e.vu.Runtime(), err)
+	}
+
+	ab := e.vu.Runtime().NewArrayBuffer(output)
+	return &ab
+}
+
+// decompress returns the input decompressed with the given algorithm. If
+// format is "s" it returns the data as a string, otherwise as an ArrayBuffer.
+func (e *Encoding) decompress(input any, algorithm, format string) any {
+	data, err := common.ToBytes(input)
+	if err != nil {
+		common.Throw(e.vu.Runtime(), err)
+	}
+
+	compression, err := parseCompressionType(algorithm)
+	if err != nil {
+		common.Throw(e.vu.Runtime(), err)
+	}
+
+	output, err := httpext.Decompress(compression, data)
+	if err != nil {
+		common.Throw(e.vu.Runtime(), err)
+	}
+
+	if format == "s" {
+		return string(output)
+	}
+
+	ab := e.vu.Runtime().NewArrayBuffer(output)
+	return &ab
+}
+
+func parseCompressionType(algorithm string) (httpext.CompressionType, error) {
+	if algorithm == "brotli" {
+		return httpext.CompressionTypeBr, nil
+	}
+
+	compression, err := httpext.CompressionTypeString(algorithm)
+	if err != nil {
+		return compression, fmt.Errorf("unsupported compression algorithm %q, expected one of %v",
+			algorithm, httpext.CompressionTypeValues())
+	}
+
+	return compression, nil
+}
@@ -216,4 +216,60 @@ func TestEncodingAlgorithms(t *testing.T) {
 			assert.NoError(t, err)
 		})
 	})
+
+	t.Run("Compression", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("RoundTrip", func(t *testing.T) {
+			t.Parallel()
+
+			rt := makeRuntime(t)
+			_, err := rt.RunString(`
+			var data = "k6 compression ".repeat(100);
+			["gzip", "deflate", "br", "brotli", "zstd"].forEach(function(algorithm) {
+				var compressed = encoding.compress(data, algorithm);
+				if (!(compressed instanceof ArrayBuffer) || compressed.byteLength >= data.length) {
+					throw new Error(algorithm + " compression mismatch: " + compressed.byteLength);
+				}
+				var decompressed = encoding.decompress(compressed, algorithm, "s");
+				if (decompressed !== data) {
+					throw new Error(algorithm + " decompression mismatch: " + decompressed);
+				}
+				var binary = encoding.decompress(new Uint8Array(compressed), algorithm);
+				if (binary.byteLength !== data.length) {
+					throw new Error(algorithm + " binary decompression mismatch: " + binary.byteLength);
+				}
+			});`)
+			assert.NoError(t, err)
+		})
+		t.Run("Dec", func(t *testing.T) {
+			t.Parallel()
+
+			rt := makeRuntime(t)
+			_, err := rt.RunString(`
+			var gzipped = encoding.b64decode("H4sIAAAAAAACA8tIzcnJVyjPL8pJAQCFEUoNCwAAAA==");
+			var deflated = encoding.b64decode("eJzLSM3JyVcozy/KSQEAGgsEXQ==");
+			if (encoding.decompress(gzipped, "gzip", "s") !== "hello world") {
+				throw new Error("gzip decompression mismatch");
+			}
+			if (encoding.decompress(deflated, "deflate", "s") !== "hello world") {
+				throw new Error("deflate decompression mismatch");
+			}`)
+			assert.NoError(t, err)
+		})
+		t.Run("UnknownAlgorithm", func(t *testing.T) {
+			t.Parallel()
+
+			rt := makeRuntime(t)
+			_, err := rt.RunString(`encoding.compress("hello world", "lzw")`)
+			assert.ErrorContains(t, err, `unsupported compression algorithm "lzw"`)
+		})
+		t.Run("InvalidData", func(t *testing.T) {
+			t.Parallel()
+
+			rt := makeRuntime(t)
+			_, err := rt.RunString(`encoding.decompress("hello world", "gzip")`)
+			assert.ErrorContains(t, err, "gzip: invalid header")
+		})
+	})
 }
@@ -0,0 +1,126 @@
+// Package avro provides the encoding and decoding of Apache Avro data for
+// the k6, using the binary encoding of the Avro specification.
+package avro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+)
+
+type (
+	// RootModule is the global module instance that will create module
+	// instances for each VU.
+	RootModule struct{}
+
+	// Avro represents an instance of the avro module.
+	Avro struct {
+		vu modules.VU
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &Avro{}
+)
+
+// New returns a pointer to a new RootModule instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface to return
+// a new instance for each VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &Avro{vu: vu}
+}
+
+// Exports returns the exports of the avro module.
+func (a *Avro) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"parse": a.parse,
+		},
+	}
+}
+
+// parse parses the given Avro schema, either as a JSON string or as an
+// object, and returns the Schema encoding and decoding its values.
+func (a *Avro) parse(definition sobek.Value) (*Schema, error) {
+	if common.IsNullish(definition) {
+		return nil, errors.New("a schema definition is required")
+	}
+
+	var raw []byte
+	if str, ok := definition.Export().(string); ok {
+		raw = []byte(str)
+	} else {
+		var err error
+		raw, err = json.Marshal(definition.Export())
+		if err != nil {
+			return nil, fmt.Errorf("unable to serialise the schema: %w", err)
+		}
+	}
+
+	var d any
+	if err := json.Unmarshal(raw, &d); err != nil {
+		// a bare name is also a valid schema, e.g. "string"
+		d = string(raw)
+	}
+
+	s, err := parseSchema(d)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Avro schema: %w", err)
+	}
+
+	return &Schema{rt: a.vu.Runtime(), schema: s}, nil
+}
+
+// Schema encodes and decodes the values of a parsed Avro schema.
+type Schema struct {
+	rt     *sobek.Runtime
+	schema *schema
+}
+
+// Encode returns the Avro binary encoding of the value, as an ArrayBuffer.
+func (s *Schema) Encode(value sobek.Value) (*sobek.ArrayBuffer, error) {
+	e := &encoder{rt: s.rt}
+	if err := e.encode(s.schema, value); err != nil {
+		return nil, fmt.Errorf("unable to encode the value: %w", err)
+	}
+
+	ab := s.rt.NewArrayBuffer(e.buf)
+	return &ab, nil
+}
+
+// Decode returns the value of the given Avro binary encoded data.
+//
+// The values of unions are returned as is, without being wrapped in an object
+// named after their type.
+func (s *Schema) Decode(input sobek.Value) (sobek.Value, error) {
+	if common.IsNullish(input) {
+		return nil, errors.New("the data to decode is required")
+	}
+
+	data, err := common.ToBytes(input.Export())
+	if err != nil {
+		return nil, err
+	}
+
+	d := &decoder{rt: s.rt, data: data}
+	value, err := d.decode(s.schema)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the value: %w", err)
+	}
+
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("unexpected %d trailing bytes after the Avro value", len(d.data)-d.pos)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,208 @@
+package avro
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modulestest"
+)
+
+func makeRuntime(t *testing.T) *sobek.Runtime {
+	rt := sobek.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+	m, ok := New().NewModuleInstance(
+		&modulestest.VU{
+			CtxField:     context.Background(),
+			RuntimeField: rt,
+			InitEnvField: &common.InitEnvironment{},
+		},
+	).(*Avro)
+	require.True(t, ok)
+	require.NoError(t, rt.Set("avro", m.Exports().Named))
+	_, err := rt.RunString(`
+	function hex(buffer) {
+		return Array.from(new Uint8Array(buffer), (b) => b.toString(16).padStart(2, "0")).join("");
+	}
+	function unhex(s) {
+		return new Uint8Array((s.match(/../g) || []).map((b) => parseInt(b, 16))).buffer;
+	}`)
+	require.NoError(t, err)
+
+	return rt
+}
+
+func TestAvro(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Encode", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			schema   string
+			value    string
+			expected string
+		}{
+			{schema: `"null"`, value: `null`, expected: ""},
+			{schema: `"boolean"`, value: `true`, expected: "01"},
+			{schema: `"int"`, value: `0`, expected: "00"},
+			{schema: `"int"`, value: `-1`, expected: "01"},
+			{schema: `"int"`, value: `1`, expected: "02"},
+			{schema: `"int"`, value: `-64`, expected: "7f"},
+			{schema: `"int"`, value: `64`, expected: "8001"},
+			{schema: `"long"`, value: `9007199254740993n`, expected: "8280808080808020"},
+			{schema: `"float"`, value: `1.5`, expected: "0000c03f"},
+			{schema: `"double"`, value: `1.5`, expected: "000000000000f83f"},
+			{schema: `"string"`, value: `"foo"`, expected: "06666f6f"},
+			{schema: `"bytes"`, value: `new Uint8Array([1, 2])`, expected: "040102"},
+			{schema: `{"type": "fixed", "name": "md5", "size": 2}`, value: `new Uint8Array([1, 2]).buffer`, expected: "0102"},
+			{schema: `{"type": "enum", "name": "suit", "symbols": ["SPADES", "HEARTS"]}`, value: `"HEARTS"`, expected: "02"},
+			{schema: `{"type": "array", "items": "long"}`, value: `[3, 27]`, expected: "04063600"},
+			{schema: `{"type": "array", "items": "long"}`, value: `[]`, expected: "00"},
+			{schema: `{"type": "map", "values": "int"}`, value: `{ a: 1 }`, expected: "0202610200"},
+			{schema: `["null", "string"]`, value: `null`, expected: "00"},
+			{schema: `["null", "string"]`, value: `"a"`, expected: "020261"},
+			{schema: `["null", "string"]`, value: `{ string: "a" }`, expected: "020261"},
+			{schema: `{"type": "string", "logicalType": "uuid"}`, value: `"a"`, expected: "0261"},
+			{
+				schema:   `{"type": "record", "name": "test", "fields": [{"name": "a", "type": "long"}, {"name": "b", "type": "string"}]}`,
+				value:    `{ a: 27, b: "foo" }`,
+				expected: "3606666f6f",
+			},
+			{
+				schema:   `{"type": "record", "name": "test", "fields": [{"name": "a", "type": "long", "default": 27}, {"name": "b", "type": "string"}]}`,
+				value:    `{ b: "foo" }`,
+				expected: "3606666f6f",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.schema+" "+tc.value, func(t *testing.T) {
+				t.Parallel()
+
+				rt := makeRuntime(t)
+				v, err := rt.RunString(`hex(avro.parse(` + "`" + tc.schema + "`" + `).encode(` + tc.value + `))`)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, v.String())
+			})
+		}
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			schema   string
+			data     string
+			expected string
+		}{
+			{schema: `"null"`, data: "", expected: `null`},
+			{schema: `"int"`, data: "8001", expected: `64`},
+			{schema: `"long"`, data: "8280808080808020", expected: `9007199254740993n`},
+			{schema: `"float"`, data: "0000c03f", expected: `1.5`},
+			{schema: `"string"`, data: "06666f6f", expected: `"foo"`},
+			{schema: `{"type": "enum", "name": "suit", "symbols": ["SPADES", "HEARTS"]}`, data: "02", expected: `"HEARTS"`},
+			{schema: `{"type": "array", "items": "long"}`, data: "04063600", expected: `[3,27]`},
+			// a block with a negative count is followed by its size in bytes
+			{schema: `{"type": "array", "items": "long"}`, data: "03040636020200", expected: `[3,27,1]`},
+			{schema: `{"type": "map", "values": "int"}`, data: "0202610200", expected: `{"a":1}`},
+			{schema: `["null", "string"]`, data: "020261", expected: `"a"`},
+			{
+				schema:   `{"type": "record", "name": "test", "fields": [{"name": "a", "type": "long"}, {"name": "b", "type": "string"}]}`,
+				data:     "3606666f6f",
+				expected: `{"a":27,"b":"foo"}`,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.schema+" "+tc.data, func(t *testing.T) {
+				t.Parallel()
+
+				rt := makeRuntime(t)
+				v, err := rt.RunString(`
+				var decoded = avro.parse(` + "`" + tc.schema + "`" + `).decode(unhex("` + tc.data + `"));
+				typeof decoded === "bigint" ? decoded + "n" : JSON.stringify(decoded)`)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, v.String())
+			})
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		rt := makeRuntime(t)
+		_, err := rt.RunString(`
+		const schema = avro.parse({
+			type: "record",
+			name: "User",
+			namespace: "com.example",
+			fields: [
+				{ name: "id", type: "long" },
+				{ name: "name", type: "string" },
+				{ name: "email", type: ["null", "string"], default: null },
+				{ name: "role", type: { type: "enum", name: "Role", symbols: ["ADMIN", "USER"] } },
+				{ name: "tags", type: { type: "array", items: "string" }, default: [] },
+				{ name: "attributes", type: { type: "map", values: "double" } },
+				{ name: "avatar", type: "bytes" },
+				{ name: "manager", type: ["null", "User"], default: null },
+			],
+		});
+
+		const value = {
+			id: 42,
+			name: "k6",
+			role: "ADMIN",
+			attributes: { score: 0.5 },
+			avatar: new Uint8Array([1, 2, 3]),
+			manager: { id: 1, name: "boss", email: "boss@example.com", role: "USER", tags: ["a"], attributes: {}, avatar: new Uint8Array(0) },
+		};
+		const decoded = schema.decode(schema.encode(value));
+		if (decoded.id !== 42 || decoded.name !== "k6" || decoded.email !== null || decoded.role !== "ADMIN"
+			|| decoded.tags.length !== 0 || decoded.attributes.score !== 0.5 || hex(decoded.avatar) !== "010203"
+			|| decoded.manager.email !== "boss@example.com" || decoded.manager.tags[0] !== "a"
+			|| decoded.manager.manager !== null) {
+			throw new Error("unexpected decoded value: " + JSON.stringify(decoded));
+		}
+		`)
+		require.NoError(t, err)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			script   string
+			expected string
+		}{
+			{script: `avro.parse("unknown")`, expected: `unknown type "unknown"`},
+			{script: `avro.parse({ type: "record", fields: [] })`, expected: `the "name" attribute is required`},
+			{script: `avro.parse(["null", "null"])`, expected: `union contains more than one "null" type`},
+			{script: `avro.parse("int").encode(1.5)`, expected: "expected an integer, got 1.5"},
+			{script: `avro.parse("int").encode(2 ** 31)`, expected: "out of the range of the int type"},
+			{script: `avro.parse("string").encode(1)`, expected: "expected a string, got 1"},
+			{
+				script:   `avro.parse({ type: "record", name: "r", fields: [{ name: "a", type: "int" }] }).encode({})`,
+				expected: `missing field "a" of the record "r"`,
+			},
+			{script: `avro.parse(["null", "int"]).encode("a")`, expected: `the value "a" matches none of the union types`},
+			{script: `avro.parse("string").decode(unhex("06"))`, expected: "unexpected end of the Avro data"},
+			{script: `avro.parse("int").decode(unhex("0202"))`, expected: "unexpected 1 trailing bytes"},
+			{script: `avro.parse("int").decode(null)`, expected: "the data to decode is required"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.script, func(t *testing.T) {
+				t.Parallel()
+
+				rt := makeRuntime(t)
+				_, err := rt.RunString(tc.script)
+				require.ErrorContains(t, err, tc.expected)
+			})
+		}
+	})
+}
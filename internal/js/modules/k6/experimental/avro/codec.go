@@ -0,0 +1,474 @@
+package avro
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+)
+
+// maxSafeInteger is the largest integer exactly represented by a JavaScript
+// number.
+const maxSafeInteger = 1<<53 - 1
+
+type encoder struct {
+	rt  *sobek.Runtime
+	buf []byte
+}
+
+//nolint:cyclop,funlen,gocognit
+func (e *encoder) encode(s *schema, v sobek.Value) error {
+	switch s.kind {
+	case kindNull:
+		if !common.IsNullish(v) {
+			return fmt.Errorf("expected null, got %s", describe(v))
+		}
+	case kindBoolean:
+		b, ok := v.Export().(bool)
+		if !ok {
+			return fmt.Errorf("expected a boolean, got %s", describe(v))
+		}
+		if b {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	case kindInt, kindLong:
+		i, err := toInteger(v)
+		if err != nil {
+			return err
+		}
+		if s.kind == kindInt && (i < math.MinInt32 || i > math.MaxInt32) {
+			return fmt.Errorf("the value %d is out of the range of the int type", i)
+		}
+		e.buf = binary.AppendVarint(e.buf, i)
+	case kindFloat, kindDouble:
+		f, ok := toNumber(v)
+		if !ok {
+			return fmt.Errorf("expected a number, got %s", describe(v))
+		}
+		if s.kind == kindFloat {
+			e.buf = binary.LittleEndian.AppendUint32(e.buf, math.Float32bits(float32(f)))
+		} else {
+			e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(f))
+		}
+	case kindBytes:
+		b, err := toBytes(v)
+		if err != nil {
+			return err
+		}
+		e.buf = binary.AppendVarint(e.buf, int64(len(b)))
+		e.buf = append(e.buf, b...)
+	case kindString:
+		str, ok := v.Export().(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %s", describe(v))
+		}
+		e.buf = binary.AppendVarint(e.buf, int64(len(str)))
+		e.buf = append(e.buf, str...)
+	case kindFixed:
+		b, err := toBytes(v)
+		if err != nil {
+			return err
+		}
+		if len(b) != s.size {
+			return fmt.Errorf("expected %d bytes for the fixed %q, got %d", s.size, s.name, len(b))
+		}
+		e.buf = append(e.buf, b...)
+	case kindEnum:
+		str, ok := v.Export().(string)
+		i := slices.Index(s.symbols, str)
+		if !ok || i < 0 {
+			return fmt.Errorf("expected a symbol of the enum %q, got %s", s.name, describe(v))
+		}
+		e.buf = binary.AppendVarint(e.buf, int64(i))
+	case kindRecord:
+		obj, ok := v.(*sobek.Object)
+		if !ok || isArray(obj) {
+			return fmt.Errorf("expected an object for the record %q, got %s", s.name, describe(v))
+		}
+		for _, f := range s.fields {
+			value := obj.Get(f.name)
+			if sobek.IsUndefined(value) || value == nil {
+				if !f.hasDefault {
+					return fmt.Errorf("missing field %q of the record %q", f.name, s.name)
+				}
+				value = e.rt.ToValue(f.defaultValue)
+			}
+			if err := e.encode(f.schema, value); err != nil {
+				return fmt.Errorf("field %q: %w", f.name, err)
+			}
+		}
+	case kindArray:
+		obj, ok := v.(*sobek.Object)
+		if !ok || !isArray(obj) {
+			return fmt.Errorf("expected an array, got %s", describe(v))
+		}
+		length := obj.Get("length").ToInteger()
+		if length > 0 {
+			e.buf = binary.AppendVarint(e.buf, length)
+			for i := range length {
+				if err := e.encode(s.items, obj.Get(fmt.Sprint(i))); err != nil {
+					return fmt.Errorf("item %d: %w", i, err)
+				}
+			}
+		}
+		e.buf = append(e.buf, 0)
+	case kindMap:
+		obj, ok := v.(*sobek.Object)
+		if !ok || isArray(obj) {
+			return fmt.Errorf("expected an object, got %s", describe(v))
+		}
+		keys := obj.Keys()
+		if len(keys) > 0 {
+			e.buf = binary.AppendVarint(e.buf, int64(len(keys)))
+			for _, key := range keys {
+				e.buf = binary.AppendVarint(e.buf, int64(len(key)))
+				e.buf = append(e.buf, key...)
+				if err := e.encode(s.values, obj.Get(key)); err != nil {
+					return fmt.Errorf("key %q: %w", key, err)
+				}
+			}
+		}
+		e.buf = append(e.buf, 0)
+	case kindUnion:
+		i, value, err := selectBranch(s, v)
+		if err != nil {
+			return err
+		}
+		e.buf = binary.AppendVarint(e.buf, int64(i))
+		return e.encode(s.branches[i], value)
+	}
+
+	return nil
+}
+
+// selectBranch returns the index of the union branch matching the value, and
+// the value to encode with it.
+//
+// The value can be wrapped in an object with a single property named after
+// the branch, like in the JSON encoding of Avro, or given as is, in which case
+// the first matching branch is selected.
+func selectBranch(s *schema, v sobek.Value) (int, sobek.Value, error) {
+	if obj, ok := v.(*sobek.Object); ok && !isArray(obj) {
+		if keys := obj.Keys(); len(keys) == 1 {
+			for i, branch := range s.branches {
+				if branch.typeName() == keys[0] {
+					return i, obj.Get(keys[0]), nil
+				}
+			}
+		}
+	}
+
+	for i, branch := range s.branches {
+		if matches(branch, v) {
+			return i, v, nil
+		}
+	}
+
+	return 0, nil, fmt.Errorf("the value %s matches none of the union types", describe(v))
+}
+
+//nolint:cyclop
+func matches(s *schema, v sobek.Value) bool {
+	switch s.kind {
+	case kindNull:
+		return common.IsNullish(v)
+	case kindBoolean:
+		_, ok := v.Export().(bool)
+		return ok
+	case kindInt:
+		i, err := toInteger(v)
+		return err == nil && i >= math.MinInt32 && i <= math.MaxInt32
+	case kindLong:
+		_, err := toInteger(v)
+		return err == nil
+	case kindFloat, kindDouble:
+		_, ok := toNumber(v)
+		return ok
+	case kindString:
+		_, ok := v.Export().(string)
+		return ok
+	case kindEnum:
+		str, ok := v.Export().(string)
+		return ok && slices.Contains(s.symbols, str)
+	case kindBytes, kindFixed:
+		obj, ok := v.(*sobek.Object)
+		if !ok {
+			return false
+		}
+		b, err := toBytes(obj)
+		return err == nil && (s.kind == kindBytes || len(b) == s.size)
+	case kindArray:
+		obj, ok := v.(*sobek.Object)
+		return ok && isArray(obj)
+	case kindMap:
+		obj, ok := v.(*sobek.Object)
+		return ok && !isArray(obj)
+	case kindRecord:
+		obj, ok := v.(*sobek.Object)
+		if !ok || isArray(obj) {
+			return false
+		}
+		for _, f := range s.fields {
+			if !f.hasDefault && sobek.IsUndefined(obj.Get(f.name)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isArray(obj *sobek.Object) bool {
+	return obj.ClassName() == "Array"
+}
+
+func toNumber(v sobek.Value) (float64, bool) {
+	switch x := v.Export().(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	default:
+		return 0, false
+	}
+}
+
+func toInteger(v sobek.Value) (int64, error) {
+	switch x := v.Export().(type) {
+	case int64:
+		return x, nil
+	case float64:
+		if x != math.Trunc(x) || x < math.MinInt64 || x >= math.MaxInt64 {
+			return 0, fmt.Errorf("expected an integer, got %v", x)
+		}
+		return int64(x), nil
+	case *big.Int:
+		if !x.IsInt64() {
+			return 0, fmt.Errorf("the BigInt %s is out of the range of the long type", x)
+		}
+		return x.Int64(), nil
+	default:
+		return 0, fmt.Errorf("expected an integer, got %s", describe(v))
+	}
+}
+
+func toBytes(v sobek.Value) ([]byte, error) {
+	if common.IsNullish(v) {
+		return nil, fmt.Errorf("expected bytes, got %s", describe(v))
+	}
+
+	b, err := common.ToBytes(v.Export())
+	if err != nil {
+		return nil, fmt.Errorf("expected bytes, got %s", describe(v))
+	}
+
+	return b, nil
+}
+
+func describe(v sobek.Value) string {
+	if v == nil || sobek.IsUndefined(v) {
+		return "undefined"
+	}
+	if obj, ok := v.(*sobek.Object); ok {
+		if isArray(obj) {
+			return "an array"
+		}
+		return "an object"
+	}
+
+	if str, ok := v.Export().(string); ok {
+		return fmt.Sprintf("%q", str)
+	}
+
+	return v.String()
+}
+
+type decoder struct {
+	rt   *sobek.Runtime
+	data []byte
+	pos  int
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of the Avro data")
+
+func (d *decoder) read(n int64) ([]byte, error) {
+	if n < 0 || int64(len(d.data)-d.pos) < n {
+		return nil, errUnexpectedEnd
+	}
+
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+func (d *decoder) readLong() (int64, error) {
+	i, n := binary.Varint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, errUnexpectedEnd
+	}
+
+	d.pos += n
+	return i, nil
+}
+
+// readBlockCount reads the number of items of the next block of an array or
+// a map, skipping the size of the block when it's included.
+//
+// The count is checked against the remaining data, when items take at least
+// one byte, so corrupted data doesn't lead to huge allocations.
+func (d *decoder) readBlockCount(nullItems bool) (int64, error) {
+	count, err := d.readLong()
+	if err != nil {
+		return 0, err
+	}
+
+	if count < 0 {
+		if _, err := d.readLong(); err != nil {
+			return 0, err
+		}
+		count = -count
+	}
+
+	if !nullItems && count > int64(len(d.data)-d.pos) {
+		return 0, errUnexpectedEnd
+	}
+
+	return count, nil
+}
+
+//nolint:cyclop,funlen,gocognit
+func (d *decoder) decode(s *schema) (sobek.Value, error) {
+	switch s.kind {
+	case kindNull:
+		return sobek.Null(), nil
+	case kindBoolean:
+		b, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		return d.rt.ToValue(b[0] != 0), nil
+	case kindInt, kindLong:
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i > maxSafeInteger || i < -maxSafeInteger {
+			return d.rt.ToValue(big.NewInt(i)), nil
+		}
+		return d.rt.ToValue(i), nil
+	case kindFloat:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return d.rt.ToValue(float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))), nil
+	case kindDouble:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return d.rt.ToValue(math.Float64frombits(binary.LittleEndian.Uint64(b))), nil
+	case kindBytes, kindString:
+		n, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		if s.kind == kindString {
+			return d.rt.ToValue(string(b)), nil
+		}
+		return d.rt.ToValue(d.rt.NewArrayBuffer(append([]byte(nil), b...))), nil
+	case kindFixed:
+		b, err := d.read(int64(s.size))
+		if err != nil {
+			return nil, err
+		}
+		return d.rt.ToValue(d.rt.NewArrayBuffer(append([]byte(nil), b...))), nil
+	case kindEnum:
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.symbols)) {
+			return nil, fmt.Errorf("invalid index %d of the enum %q", i, s.name)
+		}
+		return d.rt.ToValue(s.symbols[i]), nil
+	case kindRecord:
+		obj := d.rt.NewObject()
+		for _, f := range s.fields {
+			value, err := d.decode(f.schema)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.name, err)
+			}
+			if err := obj.Set(f.name, value); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	case kindArray:
+		var items []any
+		for {
+			count, err := d.readBlockCount(s.items.kind == kindNull)
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return d.rt.NewArray(items...), nil
+			}
+			for range count {
+				item, err := d.decode(s.items)
+				if err != nil {
+					return nil, fmt.Errorf("item %d: %w", len(items), err)
+				}
+				items = append(items, item)
+			}
+		}
+	case kindMap:
+		obj := d.rt.NewObject()
+		for {
+			count, err := d.readBlockCount(false)
+			if err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return obj, nil
+			}
+			for range count {
+				key, err := d.decode(&schema{kind: kindString})
+				if err != nil {
+					return nil, err
+				}
+				value, err := d.decode(s.values)
+				if err != nil {
+					return nil, fmt.Errorf("key %q: %w", key.String(), err)
+				}
+				if err := obj.Set(key.String(), value); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case kindUnion:
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.branches)) {
+			return nil, fmt.Errorf("invalid union index %d", i)
+		}
+		return d.decode(s.branches[i])
+	default:
+		return nil, fmt.Errorf("unsupported type %q", s.kind)
+	}
+}
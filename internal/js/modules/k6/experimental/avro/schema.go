@@ -0,0 +1,243 @@
+package avro
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// kind is the type of an Avro schema, as defined by the [specification].
+//
+// [specification]: https://avro.apache.org/docs/1.11.1/specification/#schema-declaration
+type kind string
+
+const (
+	kindNull    kind = "null"
+	kindBoolean kind = "boolean"
+	kindInt     kind = "int"
+	kindLong    kind = "long"
+	kindFloat   kind = "float"
+	kindDouble  kind = "double"
+	kindBytes   kind = "bytes"
+	kindString  kind = "string"
+	kindRecord  kind = "record"
+	kindEnum    kind = "enum"
+	kindArray   kind = "array"
+	kindMap     kind = "map"
+	kindUnion   kind = "union"
+	kindFixed   kind = "fixed"
+)
+
+// schema is a parsed Avro schema.
+type schema struct {
+	kind kind
+	// name is the full name of the named types: records, enums and fixed.
+	name string
+
+	fields   []field   // record
+	symbols  []string  // enum
+	items    *schema   // array
+	values   *schema   // map
+	branches []*schema // union
+	size     int       // fixed
+}
+
+type field struct {
+	name         string
+	schema       *schema
+	defaultValue any
+	hasDefault   bool
+}
+
+// typeName returns the name identifying the schema in a union.
+func (s *schema) typeName() string {
+	if s.name != "" {
+		return s.name
+	}
+
+	return string(s.kind)
+}
+
+type parser struct {
+	// names holds the named types defined so far, by full name.
+	names map[string]*schema
+}
+
+func parseSchema(definition any) (*schema, error) {
+	p := &parser{names: make(map[string]*schema)}
+	return p.parse(definition, "")
+}
+
+func (p *parser) parse(definition any, namespace string) (*schema, error) {
+	switch d := definition.(type) {
+	case string:
+		return p.parseName(d, namespace)
+	case []any:
+		return p.parseUnion(d, namespace)
+	case map[string]any:
+		return p.parseComplex(d, namespace)
+	default:
+		return nil, fmt.Errorf("invalid schema definition %v", definition)
+	}
+}
+
+func (p *parser) parseName(name string, namespace string) (*schema, error) {
+	switch k := kind(name); k {
+	case kindNull, kindBoolean, kindInt, kindLong, kindFloat, kindDouble, kindBytes, kindString:
+		return &schema{kind: k}, nil
+	}
+
+	if s, ok := p.names[fullName(name, namespace)]; ok {
+		return s, nil
+	}
+	if s, ok := p.names[name]; ok {
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unknown type %q", name)
+}
+
+func (p *parser) parseUnion(definitions []any, namespace string) (*schema, error) {
+	s := &schema{kind: kindUnion}
+
+	seen := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		branch, err := p.parse(definition, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		if branch.kind == kindUnion {
+			return nil, errors.New("unions can't immediately contain other unions")
+		}
+		if seen[branch.typeName()] {
+			return nil, fmt.Errorf("union contains more than one %q type", branch.typeName())
+		}
+		seen[branch.typeName()] = true
+
+		s.branches = append(s.branches, branch)
+	}
+
+	return s, nil
+}
+
+//nolint:cyclop,funlen
+func (p *parser) parseComplex(definition map[string]any, namespace string) (*schema, error) {
+	t, ok := definition["type"].(string)
+	if !ok {
+		// the type is itself a schema, e.g. {"type": {"type": "array", ...}}
+		if definition["type"] == nil {
+			return nil, errors.New(`the "type" attribute is required`)
+		}
+		return p.parse(definition["type"], namespace)
+	}
+
+	switch kind(t) {
+	case kindRecord, "error", kindEnum, kindFixed:
+	case kindArray:
+		items, err := p.parse(definition["items"], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("invalid array items: %w", err)
+		}
+		return &schema{kind: kindArray, items: items}, nil
+	case kindMap:
+		values, err := p.parse(definition["values"], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("invalid map values: %w", err)
+		}
+		return &schema{kind: kindMap, values: values}, nil
+	default:
+		// primitive types, possibly annotated with a logical type, are
+		// encoded as their underlying type
+		return p.parseName(t, namespace)
+	}
+
+	// named types
+	name, _ := definition["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf(`the "name" attribute is required for the %s types`, t)
+	}
+	if ns, ok := definition["namespace"].(string); ok {
+		namespace = ns
+	}
+
+	s := &schema{name: fullName(name, namespace)}
+	if _, ok := p.names[s.name]; ok {
+		return nil, fmt.Errorf("type %q is defined more than once", s.name)
+	}
+	// the type is registered before its fields are parsed, so it can be recursive
+	p.names[s.name] = s
+
+	// the enclosing namespace of the nested types is the namespace of the full name
+	if i := strings.LastIndex(s.name, "."); i >= 0 {
+		namespace = s.name[:i]
+	} else {
+		namespace = ""
+	}
+
+	switch kind(t) {
+	case kindEnum:
+		s.kind = kindEnum
+		symbols, _ := definition["symbols"].([]any)
+		for _, symbol := range symbols {
+			str, ok := symbol.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid symbol %v of the enum %q", symbol, s.name)
+			}
+			s.symbols = append(s.symbols, str)
+		}
+		if len(s.symbols) == 0 {
+			return nil, fmt.Errorf("the enum %q has no symbols", s.name)
+		}
+	case kindFixed:
+		s.kind = kindFixed
+		size, ok := definition["size"].(float64)
+		if !ok || size < 0 || size != float64(int(size)) {
+			return nil, fmt.Errorf("invalid size of the fixed %q", s.name)
+		}
+		s.size = int(size)
+	default:
+		s.kind = kindRecord
+		fields, ok := definition["fields"].([]any)
+		if !ok {
+			return nil, fmt.Errorf(`the "fields" attribute is required for the record %q`, s.name)
+		}
+		for _, f := range fields {
+			fd, err := p.parseField(f, namespace)
+			if err != nil {
+				return nil, fmt.Errorf("invalid field of the record %q: %w", s.name, err)
+			}
+			s.fields = append(s.fields, fd)
+		}
+	}
+
+	return s, nil
+}
+
+func (p *parser) parseField(definition any, namespace string) (field, error) {
+	d, ok := definition.(map[string]any)
+	if !ok {
+		return field{}, fmt.Errorf("invalid field definition %v", definition)
+	}
+
+	name, _ := d["name"].(string)
+	if name == "" {
+		return field{}, errors.New(`the "name" attribute is required`)
+	}
+
+	s, err := p.parse(d["type"], namespace)
+	if err != nil {
+		return field{}, fmt.Errorf("%q: %w", name, err)
+	}
+
+	defaultValue, hasDefault := d["default"]
+	return field{name: name, schema: s, defaultValue: defaultValue, hasDefault: hasDefault}, nil
+}
+
+func fullName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+
+	return namespace + "." + name
+}
@@ -0,0 +1,174 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"go.k6.io/k6/v2/lib"
+)
+
+// distribution is the policy used to distribute the records of a CSV file
+// between the VUs of a test.
+type distribution string
+
+const (
+	// distributionPerVU makes each VU read all the records, sequentially.
+	distributionPerVU distribution = "per-vu"
+
+	// distributionUnique makes the VUs read the records sequentially, each
+	// record being read by a single VU of the test.
+	distributionUnique distribution = "unique"
+
+	// distributionRandom makes the VUs read random records, a record can be
+	// read multiple times.
+	distributionRandom distribution = "random"
+
+	// distributionRandomUnique makes the VUs read the records in a random
+	// order, each record being read by a single VU of the test.
+	distributionRandomUnique distribution = "random-unique"
+)
+
+// exhaustion is the behavior of a parser once all its records are read.
+type exhaustion string
+
+const (
+	// exhaustionDone makes the parser return a done result.
+	exhaustionDone exhaustion = "done"
+
+	// exhaustionRecycle makes the parser start over with the first record.
+	exhaustionRecycle exhaustion = "recycle"
+
+	// exhaustionStop stops the scenario and makes the parser return a done
+	// result.
+	exhaustionStop exhaustion = "stop"
+)
+
+func parseDistribution(value string) (distribution, error) {
+	switch d := distribution(value); d {
+	case distributionPerVU, distributionUnique, distributionRandom, distributionRandomUnique:
+		return d, nil
+	default:
+		return "", fmt.Errorf("unknown distribution %q, it must be one of %q, %q, %q or %q", value,
+			distributionPerVU, distributionUnique, distributionRandom, distributionRandomUnique)
+	}
+}
+
+func parseExhaustion(value string) (exhaustion, error) {
+	switch e := exhaustion(value); e {
+	case exhaustionDone, exhaustionRecycle, exhaustionStop:
+		return e, nil
+	default:
+		return "", fmt.Errorf("unknown onExhausted value %q, it must be one of %q, %q or %q", value,
+			exhaustionDone, exhaustionRecycle, exhaustionStop)
+	}
+}
+
+// sharedRecords holds the records of a CSV file that are distributed between
+// all the VUs of the test by a parser.
+//
+// The records are distributed the same way as the iterations of a scenario,
+// so each instance of a test run with execution segments only reads the
+// records of its own segment.
+type sharedRecords struct {
+	records []any
+
+	// order is the order in which the records are read, it is the same for
+	// all the instances, so they read different records.
+	order []int
+
+	mu    sync.Mutex
+	index *lib.SegmentedIndex
+}
+
+// newSharedRecords reads all the records of the reader, in the order the
+// distribution reads them.
+func newSharedRecords(name string, r *Reader, d distribution) (*sharedRecords, error) {
+	var records []any
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read record; reason: %w", err)
+		}
+
+		records = append(records, record)
+	}
+
+	s := &sharedRecords{records: records}
+	if d == distributionRandomUnique {
+		// The order is seeded by the name, which is derived from the file's path
+		// and the parser's options, for all the instances to share it.
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(name))
+		s.order = rand.New(rand.NewPCG(h.Sum64(), 0)).Perm(len(records)) //nolint:gosec
+	}
+
+	return s, nil
+}
+
+// random returns a random record.
+func (s *sharedRecords) random() (any, bool) {
+	if len(s.records) == 0 {
+		return nil, false
+	}
+
+	return copyRecord(s.records[rand.IntN(len(s.records))]), true //nolint:gosec
+}
+
+// next returns the next record of the execution segment, or false if all of
+// them are read and they are not recycled.
+func (s *sharedRecords) next(et *lib.ExecutionTuple, recycle bool) (any, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.records) == 0 {
+		return nil, false, nil
+	}
+
+	if s.index == nil {
+		if et == nil {
+			var err error
+			if et, err = lib.NewExecutionTuple(nil, nil); err != nil {
+				return nil, false, err
+			}
+		}
+		s.index = lib.NewSegmentedIndex(et)
+	}
+
+	_, unscaled := s.index.Next()
+	i := int(unscaled - 1)
+	if i >= len(s.records) {
+		if !recycle {
+			s.index.Prev()
+			return nil, false, nil
+		}
+		i %= len(s.records)
+	}
+
+	if s.order != nil {
+		i = s.order[i]
+	}
+
+	return copyRecord(s.records[i]), true, nil
+}
+
+// copyRecord returns a copy of the record, so the shared one can't be
+// modified by the VU reading it.
+func copyRecord(record any) any {
+	switch r := record.(type) {
+	case []string:
+		return slices.Clone(r)
+	case map[string]string:
+		return maps.Clone(r)
+	default:
+		return record
+	}
+}
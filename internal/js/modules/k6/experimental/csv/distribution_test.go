@@ -0,0 +1,42 @@
+package csv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/lib"
+)
+
+func TestSharedRecordsNextExecutionSegments(t *testing.T) {
+	t.Parallel()
+
+	records := []any{"0", "1", "2", "3", "4", "5"}
+
+	seq, err := lib.NewExecutionSegmentSequenceFromString("0,1/3,2/3,1")
+	require.NoError(t, err)
+
+	read := make(map[any]int)
+	for _, segment := range []string{"0:1/3", "1/3:2/3", "2/3:1"} {
+		es, err := lib.NewExecutionSegmentFromString(segment)
+		require.NoError(t, err)
+		et, err := lib.NewExecutionTuple(es, &seq)
+		require.NoError(t, err)
+
+		s := &sharedRecords{records: records}
+		for {
+			record, ok, err := s.next(et, false)
+			require.NoError(t, err)
+			if !ok {
+				break
+			}
+			read[record]++
+		}
+	}
+
+	assert.Len(t, read, len(records))
+	for _, record := range records {
+		assert.Equal(t, 1, read[record], "record %s", record)
+	}
+}
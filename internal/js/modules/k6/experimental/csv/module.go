@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
 	"sync/atomic"
 
 	"go.k6.io/k6/v2/internal/js/modules/k6/data"
@@ -22,6 +23,7 @@ import (
 
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
 )
 
 type (
@@ -29,6 +31,11 @@ type (
 	// module for each VU.
 	RootModule struct {
 		dataModuleInstance *data.Data
+
+		// records holds the records of the parsers distributing them between
+		// all the VUs, by the name of their file, options and distribution.
+		records   map[string]*sharedRecords
+		recordsMu sync.Mutex
 	}
 
 	// ModuleInstance represents an instance of the fs module for a single VU.
@@ -46,7 +53,9 @@ var (
 
 // New returns a pointer to a new [RootModule] instance.
 func New() *RootModule {
-	return &RootModule{}
+	return &RootModule{
+		records: make(map[string]*sharedRecords),
+	}
 }
 
 // NewModuleInstance implements the modules.Module interface and returns a new
@@ -83,6 +92,14 @@ type Parser struct {
 	// input file.
 	reader *Reader
 
+	// source is the input file, which is read again when the records are
+	// recycled.
+	source io.ReadSeeker
+
+	// records holds the records shared with the other VUs, when they are not
+	// read by each VU with the per-vu distribution.
+	records *sharedRecords
+
 	// options holds the parser's as provided by the user.
 	options options
 
@@ -191,41 +208,117 @@ func (mi *ModuleInstance) NewParser(call sobek.ConstructorCall) *sobek.Object {
 	// Create a new Parser instance
 	parser := Parser{
 		reader:  r,
+		source:  file.ReadSeekStater,
 		options: options,
 		vu:      mi.vu,
 	}
 
+	if options.Distribution != distributionPerVU {
+		parser.records, err = mi.loadOrStoreRecords(file, options, r)
+		if err != nil {
+			common.Throw(rt, fmt.Errorf("failed to read the records; reason: %w", err))
+		}
+	}
+
 	return rt.ToValue(&parser).ToObject(rt)
 }
 
+// loadOrStoreRecords returns the records shared between the parsers of the
+// file with the same options, reading them from the reader if this is the
+// first parser.
+func (mi *ModuleInstance) loadOrStoreRecords(file fs.File, options options, r *Reader) (*sharedRecords, error) {
+	name, err := buildSharedArrayName(file, options)
+	if err != nil {
+		return nil, err
+	}
+	name += "." + string(options.Distribution)
+
+	mi.recordsMu.Lock()
+	defer mi.recordsMu.Unlock()
+
+	if records, ok := mi.records[name]; ok {
+		return records, nil
+	}
+
+	records, err := newSharedRecords(name, r, options.Distribution)
+	if err != nil {
+		return nil, err
+	}
+	mi.records[name] = records
+
+	return records, nil
+}
+
 // Next returns the next row in the CSV file.
 func (p *Parser) Next() *sobek.Promise {
 	promise, resolve, reject := promises.New(p.vu)
 
-	go func() {
-		var record any
-		var done bool
-		var err error
+	var et *lib.ExecutionTuple
+	if es := lib.GetExecutionState(p.vu.Context()); es != nil {
+		et = es.ExecutionTuple
+	}
+	scenario := lib.GetScenarioState(p.vu.Context())
 
-		record, err = p.reader.Read()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				resolve(parseResult{Done: true, Value: []string{}})
-				return
+	go func() {
+		record, err := p.read(et)
+		if errors.Is(err, io.EOF) {
+			if p.options.OnExhausted == exhaustionStop && scenario != nil && scenario.Stop != nil {
+				scenario.Stop()
 			}
 
+			resolve(parseResult{Done: true, Value: []string{}})
+			return
+		}
+		if err != nil {
 			reject(err)
 			return
 		}
 
 		p.currentLine.Add(1)
 
-		resolve(parseResult{Done: done, Value: record})
+		resolve(parseResult{Done: false, Value: record})
 	}()
 
 	return promise
 }
 
+// read returns the next record of the parser's distribution, or [io.EOF] if
+// all of them are read.
+func (p *Parser) read(et *lib.ExecutionTuple) (any, error) {
+	recycle := p.options.OnExhausted == exhaustionRecycle
+
+	switch p.options.Distribution {
+	case distributionRandom:
+		if record, ok := p.records.random(); ok {
+			return record, nil
+		}
+		return nil, io.EOF
+	case distributionUnique, distributionRandomUnique:
+		record, ok, err := p.records.next(et, recycle)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, io.EOF
+		}
+		return record, nil
+	default:
+		record, err := p.reader.Read()
+		if !errors.Is(err, io.EOF) || !recycle || p.currentLine.Load() == 0 {
+			return record, err
+		}
+
+		// Read the file again from its start, once all the records are read.
+		if _, err = p.source.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if p.reader, err = NewReaderFrom(p.source, p.options); err != nil {
+			return nil, err
+		}
+		return p.reader.Read()
+	}
+}
+
 func buildSharedArrayName(file fs.File, opts options) (string, error) {
 	delimiter := string(opts.Delimiter)
 	if delimiter == "" {
@@ -313,6 +406,13 @@ type options struct {
 	// should be returned. Same thing applies if the [FromLine] option is set to a value greater
 	// than 0.
 	AsObjects null.Bool `js:"asObjects"`
+
+	// Distribution is the policy used by a parser to distribute the records
+	// between the VUs.
+	Distribution distribution `js:"distribution"`
+
+	// OnExhausted is the behavior of a parser once all its records are read.
+	OnExhausted exhaustion `js:"onExhausted"`
 }
 
 // newDefaultParserOptions creates a new options instance with default values.
@@ -321,6 +421,8 @@ func newDefaultParserOptions() options {
 		Delimiter:     ',',
 		SkipFirstLine: false,
 		AsObjects:     null.BoolFrom(false),
+		Distribution:  distributionPerVU,
+		OnExhausted:   exhaustionDone,
 	}
 }
 
@@ -359,6 +461,22 @@ func newParserOptionsFrom(obj *sobek.Object) (options, error) {
 		options.AsObjects = null.BoolFrom(v.ToBoolean())
 	}
 
+	if v := obj.Get("distribution"); v != nil {
+		d, err := parseDistribution(v.String())
+		if err != nil {
+			return options, err
+		}
+		options.Distribution = d
+	}
+
+	if v := obj.Get("onExhausted"); v != nil {
+		e, err := parseExhaustion(v.String())
+		if err != nil {
+			return options, err
+		}
+		options.OnExhausted = e
+	}
+
 	if options.FromLine.Valid && options.ToLine.Valid && options.FromLine.Int64 >= options.ToLine.Int64 {
 		return options, fmt.Errorf("fromLine must be less than or equal to toLine")
 	}
@@ -465,6 +465,139 @@ func TestParserNext(t *testing.T) {
 	})
 }
 
+func TestParserNextDistribution(t *testing.T) {
+	t.Parallel()
+
+	const data = "a\nb\nc\n"
+
+	tests := map[string]struct {
+		options string
+		want    string
+	}{
+		"per-vu": {
+			options: `{}`,
+			want:    "a,b,c,|a,b,c,",
+		},
+		"per-vu recycled": {
+			options: `{ distribution: "per-vu", onExhausted: "recycle" }`,
+			want:    "a,b,c,a|a,b,c,a",
+		},
+		"unique": {
+			options: `{ distribution: "unique" }`,
+			want:    "a,b,c,|,,,",
+		},
+		"unique recycled": {
+			options: `{ distribution: "unique", onExhausted: "recycle" }`,
+			want:    "a,b,c,a|b,c,a,b",
+		},
+		"unique stopped": {
+			options: `{ distribution: "unique", onExhausted: "stop" }`,
+			want:    "a,b,c,|,,,",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := newConfiguredRuntime(t)
+			require.NoError(t, err)
+
+			r.VU.InitEnvField.FileSystems["file"] = newTestFs(t, func(fs fsext.Fs) error {
+				return fsext.WriteFile(fs, testFilePath, []byte(data), 0o644)
+			})
+
+			_, err = r.RunOnEventLoop(wrapInAsyncLambda(fmt.Sprintf(`
+				const read = async (parser) => {
+					const values = [];
+					for (let i = 0; i < 4; i++) {
+						const { done, value } = await parser.next();
+						values.push(done ? "" : value[0]);
+					}
+					return values.join();
+				};
+
+				const first = new csv.Parser(await fs.open(%[1]q), %[2]s);
+				const second = new csv.Parser(await fs.open(%[1]q), %[2]s);
+
+				const got = await read(first) + "|" + await read(second);
+				if (got !== %[3]q) {
+					throw new Error("Expected the records to be %[3]s, but got " + got);
+				}
+			`, testFilePath, tc.options, tc.want)))
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("random", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		r.VU.InitEnvField.FileSystems["file"] = newTestFs(t, func(fs fsext.Fs) error {
+			return fsext.WriteFile(fs, testFilePath, []byte(data), 0o644)
+		})
+
+		_, err = r.RunOnEventLoop(wrapInAsyncLambda(fmt.Sprintf(`
+			const parser = new csv.Parser(await fs.open(%q), { distribution: "random" });
+			for (let i = 0; i < 10; i++) {
+				const { done, value } = await parser.next();
+				if (done || !["a", "b", "c"].includes(value[0])) {
+					throw new Error("Expected a random record, but got " + value);
+				}
+			}
+		`, testFilePath)))
+		require.NoError(t, err)
+	})
+
+	t.Run("random-unique", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		r.VU.InitEnvField.FileSystems["file"] = newTestFs(t, func(fs fsext.Fs) error {
+			return fsext.WriteFile(fs, testFilePath, []byte(data), 0o644)
+		})
+
+		_, err = r.RunOnEventLoop(wrapInAsyncLambda(fmt.Sprintf(`
+			const parser = new csv.Parser(await fs.open(%q), { distribution: "random-unique" });
+			const values = [];
+			for (let i = 0; i < 3; i++) {
+				const { done, value } = await parser.next();
+				if (done) {
+					throw new Error("Expected to read a record, but got done=true");
+				}
+				values.push(value[0]);
+			}
+			if (values.sort().join() !== "a,b,c") {
+				throw new Error("Expected each record to be read once, but got " + values);
+			}
+			if (!(await parser.next()).done) {
+				throw new Error("Expected done=true once all the records are read");
+			}
+		`, testFilePath)))
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown distribution", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := newConfiguredRuntime(t)
+		require.NoError(t, err)
+
+		r.VU.InitEnvField.FileSystems["file"] = newTestFs(t, func(fs fsext.Fs) error {
+			return fsext.WriteFile(fs, testFilePath, []byte(data), 0o644)
+		})
+
+		_, err = r.RunOnEventLoop(wrapInAsyncLambda(fmt.Sprintf(`
+			new csv.Parser(await fs.open(%q), { distribution: "round-robin" });
+		`, testFilePath)))
+		require.ErrorContains(t, err, `unknown distribution "round-robin"`)
+	})
+}
+
 func TestParse(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,349 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/metrics"
+)
+
+// maxMessageSize is the maximum size of a DNS message over TCP, and the size
+// of the buffer for the ones received over UDP.
+const maxMessageSize = 65535
+
+// newQuery builds the wire format of a query and returns it with its ID.
+func newQuery(name string, qtype dnsmessage.Type, recursion bool) ([]byte, uint16, error) {
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid query name %q: %w", name, err)
+	}
+
+	var idBytes [2]byte
+	if _, err = rand.Read(idBytes[:]); err != nil {
+		return nil, 0, err
+	}
+	id := binary.BigEndian.Uint16(idBytes[:])
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: recursion})
+	b.EnableCompression()
+	if err = b.StartQuestions(); err != nil {
+		return nil, 0, err
+	}
+	if err = b.Question(dnsmessage.Question{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, 0, err
+	}
+	msg, err := b.Finish()
+	return msg, id, err
+}
+
+// exchange sends the query and parses its response, emitting the metrics for
+// it. The measured duration includes establishing the connection, if the
+// protocol requires one.
+func (mi *ModuleInstance) exchange(
+	ctx context.Context, state *lib.State, opts *queryParams, query []byte, id uint16,
+) (*Response, error) {
+	queryCtx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	opts.tagsAndMeta.SetSystemTagOrMetaIfEnabled(state.Options.SystemTags, metrics.TagProto, string(opts.protocol))
+
+	start := time.Now()
+	raw, err := mi.roundTrip(queryCtx, state, opts, query)
+	end := time.Now()
+
+	var resp *Response
+	if err == nil {
+		resp, err = parseResponse(raw, id)
+	}
+	if err != nil {
+		mi.emitFailure(ctx, state, opts.tagsAndMeta, end)
+		return nil, err
+	}
+
+	mi.emitResponse(ctx, state, opts.tagsAndMeta, resp, end, end.Sub(start))
+	return resp, nil
+}
+
+func (mi *ModuleInstance) roundTrip(
+	ctx context.Context, state *lib.State, opts *queryParams, query []byte,
+) ([]byte, error) {
+	switch opts.protocol {
+	case ProtocolUDP:
+		return exchangeUDP(ctx, state, opts.server, query)
+	case ProtocolTCP:
+		return exchangeStream(ctx, state, opts.server, query, false)
+	case ProtocolDoT:
+		return exchangeStream(ctx, state, opts.server, query, true)
+	case ProtocolDoH:
+		return exchangeHTTPS(ctx, state, opts.server, query)
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", opts.protocol)
+	}
+}
+
+func exchangeUDP(ctx context.Context, state *lib.State, server string, query []byte) ([]byte, error) {
+	conn, err := state.Dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+	setDeadline(ctx, conn)
+
+	if _, err = conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxMessageSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// exchangeStream sends the query over TCP, or TLS if secure is set, prefixing
+// the message with its length as required by RFC 1035 section 4.2.2.
+func exchangeStream(ctx context.Context, state *lib.State, server string, query []byte, secure bool) ([]byte, error) {
+	conn, err := state.Dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if secure {
+		var config *tls.Config
+		if state.TLSConfig != nil {
+			config = state.TLSConfig.Clone()
+		} else {
+			config = &tls.Config{} //nolint:gosec // the defaults from the options are used when set
+		}
+		config.NextProtos = []string{"dot"}
+		config.ServerName, _, _ = net.SplitHostPort(server)
+		tlsConn := tls.Client(conn, config)
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			return nil, fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		conn = tlsConn
+	}
+	setDeadline(ctx, conn)
+
+	msg := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(msg, uint16(len(query))) //nolint:gosec // the query is way smaller
+	copy(msg[2:], query)
+	if _, err = conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	var length [2]byte
+	if _, err = io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err = io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// exchangeHTTPS sends the query as a POST request, as defined in RFC 8484,
+// through the VU's HTTP transport.
+func exchangeHTTPS(ctx context.Context, state *lib.State, server string, query []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+	if state.Options.UserAgent.Valid {
+		req.Header.Set("User-Agent", state.Options.UserAgent.String)
+	}
+
+	res, err := state.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DNS over HTTPS server responded with status %d", res.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(res.Body, maxMessageSize))
+}
+
+func setDeadline(ctx context.Context, conn net.Conn) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+}
+
+func parseResponse(raw []byte, id uint16) (*Response, error) {
+	var p dnsmessage.Parser
+	h, err := p.Start(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DNS response: %w", err)
+	}
+	if !h.Response || h.ID != id {
+		return nil, errors.New("invalid DNS response: it doesn't match the query")
+	}
+	if err = p.SkipAllQuestions(); err != nil {
+		return nil, fmt.Errorf("invalid DNS response: %w", err)
+	}
+
+	resp := &Response{
+		Rcode:         rcodeName(h.RCode),
+		Truncated:     h.Truncated,
+		Authoritative: h.Authoritative,
+		Answers:       []Record{},
+		Authorities:   []Record{},
+		Additionals:   []Record{},
+	}
+
+	sections := []struct {
+		all    func() ([]dnsmessage.Resource, error)
+		target *[]Record
+	}{
+		{p.AllAnswers, &resp.Answers},
+		{p.AllAuthorities, &resp.Authorities},
+		{p.AllAdditionals, &resp.Additionals},
+	}
+	for _, section := range sections {
+		resources, err := section.all()
+		if err != nil {
+			// truncated responses might be cut in the middle of a section
+			if h.Truncated {
+				break
+			}
+			return nil, fmt.Errorf("invalid DNS response: %w", err)
+		}
+		records := make([]Record, 0, len(resources))
+		for _, r := range resources {
+			if r.Header.Type == dnsmessage.TypeOPT {
+				continue
+			}
+			records = append(records, newRecord(r))
+		}
+		*section.target = records
+	}
+
+	return resp, nil
+}
+
+func newRecord(r dnsmessage.Resource) Record {
+	record := Record{
+		Name: r.Header.Name.String(),
+		Type: strings.TrimPrefix(r.Header.Type.String(), "Type"),
+		TTL:  r.Header.TTL,
+	}
+
+	switch body := r.Body.(type) {
+	case *dnsmessage.AResource:
+		record.Data = net.IP(body.A[:]).String()
+	case *dnsmessage.AAAAResource:
+		record.Data = net.IP(body.AAAA[:]).String()
+	case *dnsmessage.CNAMEResource:
+		record.Data = body.CNAME.String()
+	case *dnsmessage.NSResource:
+		record.Data = body.NS.String()
+	case *dnsmessage.PTRResource:
+		record.Data = body.PTR.String()
+	case *dnsmessage.MXResource:
+		record.Data = strconv.Itoa(int(body.Pref)) + " " + body.MX.String()
+	case *dnsmessage.SRVResource:
+		record.Data = fmt.Sprintf("%d %d %d %s", body.Priority, body.Weight, body.Port, body.Target)
+	case *dnsmessage.TXTResource:
+		record.Data = strings.Join(body.TXT, "")
+	case *dnsmessage.SOAResource:
+		record.Data = fmt.Sprintf("%s %s %d %d %d %d %d",
+			body.NS, body.MBox, body.Serial, body.Refresh, body.Retry, body.Expire, body.MinTTL)
+	}
+
+	return record
+}
+
+// rcodeName returns the mnemonic of the response code, like NOERROR or NXDOMAIN.
+func rcodeName(rcode dnsmessage.RCode) string {
+	switch rcode {
+	case dnsmessage.RCodeSuccess:
+		return "NOERROR"
+	case dnsmessage.RCodeFormatError:
+		return "FORMERR"
+	case dnsmessage.RCodeServerFailure:
+		return "SERVFAIL"
+	case dnsmessage.RCodeNameError:
+		return "NXDOMAIN"
+	case dnsmessage.RCodeNotImplemented:
+		return "NOTIMP"
+	case dnsmessage.RCodeRefused:
+		return "REFUSED"
+	default:
+		return strconv.Itoa(int(rcode))
+	}
+}
+
+func (mi *ModuleInstance) emitResponse(
+	ctx context.Context, state *lib.State, tagsAndMeta *metrics.TagsAndMeta, resp *Response,
+	t time.Time, duration time.Duration,
+) {
+	tags := tagsAndMeta.Tags.With("rcode", resp.Rcode)
+	failed := 0.0
+	if resp.Rcode != "NOERROR" {
+		failed = 1
+	}
+
+	samples := []metrics.Sample{
+		{
+			TimeSeries: metrics.TimeSeries{Metric: mi.metrics.QueryDuration, Tags: tags},
+			Time:       t,
+			Metadata:   tagsAndMeta.Metadata,
+			Value:      metrics.D(duration),
+		},
+		{
+			TimeSeries: metrics.TimeSeries{Metric: mi.metrics.QueryFailed, Tags: tags},
+			Time:       t,
+			Metadata:   tagsAndMeta.Metadata,
+			Value:      failed,
+		},
+	}
+	if resp.Truncated {
+		samples = append(samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: mi.metrics.Truncated, Tags: tags},
+			Time:       t,
+			Metadata:   tagsAndMeta.Metadata,
+			Value:      1,
+		})
+	}
+
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.ConnectedSamples{
+		Samples: samples,
+		Tags:    tags,
+		Time:    t,
+	})
+}
+
+func (mi *ModuleInstance) emitFailure(
+	ctx context.Context, state *lib.State, tagsAndMeta *metrics.TagsAndMeta, t time.Time,
+) {
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: mi.metrics.QueryFailed, Tags: tagsAndMeta.Tags},
+		Time:       t,
+		Metadata:   tagsAndMeta.Metadata,
+		Value:      1,
+	})
+}
@@ -0,0 +1,245 @@
+// Package dns implements the k6/experimental/dns module, which allows scripts
+// to send DNS queries to a chosen server over UDP, TCP, DNS over TLS or DNS
+// over HTTPS.
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/types"
+	"go.k6.io/k6/v2/metrics"
+)
+
+const defaultQueryTimeout = 10 * time.Second
+
+type (
+	// RootModule is the global module instance that will create module
+	// instances for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the dns module for every VU.
+	ModuleInstance struct {
+		vu      modules.VU
+		metrics *instanceMetrics
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// New returns a pointer to a new RootModule instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface to return
+// a new instance for each VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	metrics, err := registerMetrics(vu.InitEnv().Registry)
+	if err != nil {
+		common.Throw(vu.Runtime(), fmt.Errorf("failed to register dns module metrics: %w", err))
+	}
+
+	return &ModuleInstance{vu: vu, metrics: metrics}
+}
+
+// Exports returns the exports of the dns module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"query": mi.query,
+		},
+	}
+}
+
+// Protocol is the transport used for sending the queries.
+type Protocol string
+
+// The supported protocols.
+const (
+	ProtocolUDP Protocol = "udp"
+	ProtocolTCP Protocol = "tcp"
+	ProtocolDoT Protocol = "dot"
+	ProtocolDoH Protocol = "doh"
+)
+
+// queryTypes are the supported record types of the queries.
+//
+//nolint:gochecknoglobals
+var queryTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"MX":    dnsmessage.TypeMX,
+	"NS":    dnsmessage.TypeNS,
+	"PTR":   dnsmessage.TypePTR,
+	"SOA":   dnsmessage.TypeSOA,
+	"SRV":   dnsmessage.TypeSRV,
+	"TXT":   dnsmessage.TypeTXT,
+}
+
+type queryParams struct {
+	server      string
+	protocol    Protocol
+	timeout     time.Duration
+	recursion   bool
+	tagsAndMeta *metrics.TagsAndMeta
+}
+
+// Response is the result of a query as exposed to the script.
+type Response struct {
+	Rcode         string   `js:"rcode"`
+	Truncated     bool     `js:"truncated"`
+	Authoritative bool     `js:"authoritative"`
+	Answers       []Record `js:"answers"`
+	Authorities   []Record `js:"authorities"`
+	Additionals   []Record `js:"additionals"`
+}
+
+// Record is a resource record of a response.
+type Record struct {
+	Name string `js:"name"`
+	Type string `js:"type"`
+	TTL  uint32 `js:"ttl"`
+	// Data is the textual representation of the record's data, for example the
+	// IP address of A records, or "<priority> <weight> <port> <target>" for SRV ones.
+	Data string `js:"data"`
+}
+
+// query sends a query for the name and record type to the server from the
+// parameters, and resolves with its response. Responses with an error code are
+// resolved as well, and only transport errors reject the returned promise.
+func (mi *ModuleInstance) query(name string, recordType string, params sobek.Value) *sobek.Promise {
+	rt := mi.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	state := mi.vu.State()
+	if state == nil {
+		must(rt, reject(errors.New("sending queries is only allowed in the VU context")))
+		return promise
+	}
+
+	qtype, ok := queryTypes[strings.ToUpper(recordType)]
+	if !ok {
+		must(rt, reject(fmt.Errorf("unsupported record type %q", recordType)))
+		return promise
+	}
+
+	opts, err := mi.parseQueryParams(state, params)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+
+	msg, id, err := newQuery(name, qtype, opts.recursion)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+
+	ctx := mi.vu.Context()
+	callback := mi.vu.RegisterCallback()
+	go func() {
+		resp, err := mi.exchange(ctx, state, opts, msg, id)
+		callback(func() error {
+			if err != nil {
+				return reject(err)
+			}
+			return resolve(resp)
+		})
+	}()
+
+	return promise
+}
+
+func (mi *ModuleInstance) parseQueryParams(state *lib.State, raw sobek.Value) (*queryParams, error) {
+	if common.IsNullish(raw) {
+		return nil, errors.New("the query requires the server parameter")
+	}
+
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	params := &queryParams{
+		protocol:    ProtocolUDP,
+		timeout:     defaultQueryTimeout,
+		recursion:   true,
+		tagsAndMeta: &tagsAndMeta,
+	}
+
+	rt := mi.vu.Runtime()
+	obj := raw.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "server":
+			params.server = v.String()
+		case "protocol":
+			params.protocol = Protocol(strings.ToLower(v.String()))
+		case "timeout":
+			timeout, err := types.GetDurationValue(v.Export())
+			if err != nil {
+				return nil, fmt.Errorf("invalid query timeout: %w", err)
+			}
+			if timeout <= 0 {
+				return nil, fmt.Errorf("the query timeout should be positive, but got %s", timeout)
+			}
+			params.timeout = timeout
+		case "recursion":
+			params.recursion = v.ToBoolean()
+		case "tags":
+			if err := common.ApplyCustomUserTags(rt, params.tagsAndMeta, v); err != nil {
+				return nil, fmt.Errorf("invalid query tags option: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown query option %s", k)
+		}
+	}
+
+	if params.server == "" {
+		return nil, errors.New("the query requires the server parameter")
+	}
+
+	switch params.protocol {
+	case ProtocolUDP, ProtocolTCP:
+		params.server = withDefaultPort(params.server, "53")
+	case ProtocolDoT:
+		params.server = withDefaultPort(params.server, "853")
+	case ProtocolDoH:
+		u, err := url.Parse(params.server)
+		if err != nil || u.Scheme != "https" {
+			return nil, fmt.Errorf("DNS over HTTPS requires an https URL as server, but got %q", params.server)
+		}
+	default:
+		return nil, fmt.Errorf(
+			"unsupported protocol %q, the supported ones are udp, tcp, dot and doh", params.protocol)
+	}
+
+	return params, nil
+}
+
+// withDefaultPort appends the port to the server address if it doesn't have one.
+func withDefaultPort(server, port string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), port)
+}
+
+// must is a small helper that will panic if err is not nil.
+func must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
@@ -0,0 +1,281 @@
+package dns
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"go.k6.io/k6/v2/internal/lib/testutils/mockresolver"
+	"go.k6.io/k6/v2/js/modulestest"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/netext"
+	"go.k6.io/k6/v2/metrics"
+)
+
+// answer builds the response of the test server for the provided query.
+// Queries for missing.k6.io. get NXDOMAIN, the ones for big.k6.io. get a
+// truncated response and for anything else a fixed record of the queried type.
+func answer(t *testing.T, query []byte) []byte {
+	t.Helper()
+
+	var p dnsmessage.Parser
+	h, err := p.Start(query)
+	require.NoError(t, err)
+	q, err := p.Question()
+	require.NoError(t, err)
+
+	h.Response = true
+	switch q.Name.String() {
+	case "missing.k6.io.":
+		h.RCode = dnsmessage.RCodeNameError
+	case "big.k6.io.":
+		h.Truncated = true
+	}
+
+	b := dnsmessage.NewBuilder(nil, h)
+	require.NoError(t, b.StartQuestions())
+	require.NoError(t, b.Question(q))
+	require.NoError(t, b.StartAnswers())
+	if h.RCode == dnsmessage.RCodeSuccess {
+		rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 300}
+		switch q.Type { //nolint:exhaustive
+		case dnsmessage.TypeA:
+			require.NoError(t, b.AResource(rh, dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}))
+		case dnsmessage.TypeSRV:
+			require.NoError(t, b.SRVResource(rh, dnsmessage.SRVResource{
+				Priority: 10, Weight: 5, Port: 8080, Target: dnsmessage.MustNewName("backend.k6.io."),
+			}))
+		case dnsmessage.TypeTXT:
+			require.NoError(t, b.TXTResource(rh, dnsmessage.TXTResource{TXT: []string{"v=spf1 ", "-all"}}))
+		}
+	}
+	resp, err := b.Finish()
+	require.NoError(t, err)
+	return resp
+}
+
+func serveUDP(t *testing.T) string {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+
+	go func() {
+		buf := make([]byte, maxMessageSize)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = pc.WriteTo(answer(t, buf[:n]), addr)
+		}
+	}()
+
+	return pc.LocalAddr().String()
+}
+
+func serveStream(t *testing.T, l net.Listener) string {
+	t.Helper()
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				r := bufio.NewReader(conn)
+				var length [2]byte
+				if _, err := io.ReadFull(r, length[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(r, query); err != nil {
+					return
+				}
+				resp := answer(t, query)
+				binary.BigEndian.PutUint16(length[:], uint16(len(resp))) //nolint:gosec
+				_, _ = conn.Write(append(length[:], resp...))
+			}()
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func newTestRuntime(
+	t *testing.T, samples chan metrics.SampleContainer, tlsConfig *tls.Config, transport http.RoundTripper,
+) *modulestest.Runtime {
+	t.Helper()
+
+	runtime := modulestest.NewRuntime(t)
+	m := new(RootModule).NewModuleInstance(runtime.VU)
+	require.NoError(t, runtime.VU.Runtime().Set("dns", m.Exports().Named))
+
+	runtime.MoveToVUContext(&lib.State{
+		Dialer:    netext.NewDialer(net.Dialer{}, mockresolver.New(nil)),
+		TLSConfig: tlsConfig,
+		Transport: transport,
+		Options: lib.Options{
+			SystemTags: metrics.NewSystemTagSet(metrics.TagProto),
+		},
+		Samples:        samples,
+		BuiltinMetrics: runtime.BuiltinMetrics,
+		Tags:           lib.NewVUStateTags(runtime.VU.InitEnvField.Registry.RootTagSet()),
+	})
+
+	return runtime
+}
+
+const queriesScript = `(async () => {
+	const params = { server: SERVER, protocol: PROTOCOL }
+
+	let res = await dns.query("service.k6.io", "SRV", params)
+	if (res.rcode !== "NOERROR" || res.answers.length !== 1) {
+		throw new Error("unexpected SRV response " + JSON.stringify(res))
+	}
+	const srv = res.answers[0]
+	if (srv.name !== "service.k6.io." || srv.type !== "SRV" || srv.ttl !== 300 || srv.data !== "10 5 8080 backend.k6.io.") {
+		throw new Error("unexpected SRV record " + JSON.stringify(srv))
+	}
+
+	res = await dns.query("k6.io", "a", params)
+	if (res.answers[0].data !== "10.0.0.1") {
+		throw new Error("unexpected A response " + JSON.stringify(res))
+	}
+
+	res = await dns.query("k6.io", "TXT", params)
+	if (res.answers[0].data !== "v=spf1 -all") {
+		throw new Error("unexpected TXT response " + JSON.stringify(res))
+	}
+
+	res = await dns.query("missing.k6.io", "A", params)
+	if (res.rcode !== "NXDOMAIN" || res.answers.length !== 0) {
+		throw new Error("unexpected NXDOMAIN response " + JSON.stringify(res))
+	}
+
+	res = await dns.query("big.k6.io", "A", params)
+	if (!res.truncated) {
+		throw new Error("unexpected truncated response " + JSON.stringify(res))
+	}
+})()`
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/dns-message", r.Header.Get("Content-Type"))
+		query, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/dns-message")
+		_, _ = w.Write(answer(t, query))
+	}))
+	t.Cleanup(srv.Close)
+	transport := srv.Client().Transport
+	clientConfig := transport.(*http.Transport).TLSClientConfig //nolint:forcetypeassert
+
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	tlsListener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: srv.TLS.Certificates,
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+
+	servers := map[string]string{
+		"udp": serveUDP(t),
+		"tcp": serveStream(t, tcpListener),
+		"dot": serveStream(t, tlsListener),
+		"doh": srv.URL + "/dns-query",
+	}
+
+	for protocol, server := range servers {
+		t.Run(protocol, func(t *testing.T) {
+			t.Parallel()
+
+			samples := make(chan metrics.SampleContainer, 1000)
+			runtime := newTestRuntime(t, samples, clientConfig, transport)
+			require.NoError(t, runtime.VU.Runtime().Set("SERVER", server))
+			require.NoError(t, runtime.VU.Runtime().Set("PROTOCOL", protocol))
+
+			_, err := runtime.RunOnEventLoop(queriesScript)
+			require.NoError(t, err)
+
+			values := make(map[string][]float64)
+			for _, sampleContainer := range metrics.GetBufferedSamples(samples) {
+				for _, sample := range sampleContainer.GetSamples() {
+					values[sample.Metric.Name] = append(values[sample.Metric.Name], sample.Value)
+					assert.Equal(t, protocol, sample.Tags.Map()["proto"])
+				}
+			}
+			assert.Len(t, values["dns_query_duration"], 5)
+			assert.Equal(t, []float64{0, 0, 0, 1, 0}, values["dns_query_failed"])
+			assert.Equal(t, []float64{1}, values["dns_truncated_responses"])
+		})
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	t.Parallel()
+
+	// nothing is reading from it, so queries never get a response
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+
+	samples := make(chan metrics.SampleContainer, 1000)
+	runtime := newTestRuntime(t, samples, nil, nil)
+	_, err = runtime.RunOnEventLoop(`(async () => {
+		try {
+			await dns.query("k6.io", "A", { server: "` + pc.LocalAddr().String() + `", timeout: "50ms" })
+		} catch (e) {
+			return
+		}
+		throw new Error("the query should have timed out")
+	})()`)
+	require.NoError(t, err)
+
+	var failed []float64
+	for _, sampleContainer := range metrics.GetBufferedSamples(samples) {
+		for _, sample := range sampleContainer.GetSamples() {
+			if sample.Metric.Name == "dns_query_failed" {
+				failed = append(failed, sample.Value)
+			}
+		}
+	}
+	assert.Equal(t, []float64{1}, failed)
+}
+
+func TestQueryInvalidParams(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`dns.query("k6.io", "A")`:                                            "the query requires the server parameter",
+		`dns.query("k6.io", "HINFO", { server: "127.0.0.1" })`:               `unsupported record type "HINFO"`,
+		`dns.query("k6.io", "A", { server: "127.0.0.1", protocol: "quic" })`: `unsupported protocol "quic"`,
+		`dns.query("k6.io", "A", { server: "http://dns", protocol: "doh" })`: "requires an https URL",
+		`dns.query("k6.io", "A", { server: "127.0.0.1", retries: 1 })`:       "unknown query option retries",
+	}
+
+	for code, expected := range tests {
+		t.Run(code, func(t *testing.T) {
+			t.Parallel()
+
+			runtime := newTestRuntime(t, make(chan metrics.SampleContainer, 10), nil, nil)
+			_, err := runtime.RunOnEventLoop(code)
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package dns
+
+import "go.k6.io/k6/v2/metrics"
+
+// instanceMetrics contains the metrics for the dns module.
+type instanceMetrics struct {
+	QueryDuration *metrics.Metric
+	QueryFailed   *metrics.Metric
+	Truncated     *metrics.Metric
+}
+
+// registerMetrics registers and returns the metrics in the provided registry
+func registerMetrics(registry *metrics.Registry) (*instanceMetrics, error) {
+	var err error
+	m := &instanceMetrics{}
+
+	if m.QueryDuration, err = registry.NewMetric("dns_query_duration", metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	if m.QueryFailed, err = registry.NewMetric("dns_query_failed", metrics.Rate); err != nil {
+		return nil, err
+	}
+
+	if m.Truncated, err = registry.NewMetric("dns_truncated_responses", metrics.Counter); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -37,6 +37,10 @@ const (
 
 	// EOFError is emitted when the end of a file has been reached.
 	EOFError
+
+	// QuotaExceededError is emitted when a write would exceed the disk
+	// quota of a temporary directory.
+	QuotaExceededError
 )
 
 // fsError represents a custom error object emitted by the fs module.
@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"bytes"
 	"io"
 	"path/filepath"
 	"sync/atomic"
@@ -37,6 +38,10 @@ type FileInfo struct {
 
 	// Size holds the size of the file in bytes.
 	Size int64 `json:"size"`
+
+	// IsDirectory indicates whether the entry is a directory, when listing
+	// the content of a temporary directory.
+	IsDirectory bool `json:"isDirectory" js:"isDirectory"`
 }
 
 // Read reads up to len(into) bytes into the provided byte slice.
@@ -74,6 +79,13 @@ func (f *file) Read(into []byte) (n int, err error) {
 // Ensure that `file` implements the io.Reader interface.
 var _ io.Reader = (*file)(nil)
 
+// openContent returns a reader of the whole file, independent of its offset.
+func (f *file) openContent() (io.ReadCloser, int64, error) {
+	return io.NopCloser(bytes.NewReader(f.data)), f.size(), nil
+}
+
+var _ contentOpener = (*file)(nil)
+
 // Seek sets the offset for the next operation on the file, under the mode given by `whence`.
 //
 // `offset` indicates the number of bytes to move the offset. Based on
@@ -83,9 +95,22 @@ var _ io.Reader = (*file)(nil)
 // When using SeekModeStart, the offset must be positive.
 // Negative offsets are allowed when using `SeekModeCurrent` or `SeekModeEnd`.
 func (f *file) Seek(offset int64, whence SeekMode) (int64, error) { //nolint:govet
-	startingOffset := f.offset.Load()
+	newOffset, err := seekOffset(f.offset.Load(), f.size(), offset, whence)
+	if err != nil {
+		return 0, err
+	}
+
+	// Update the file instance's offset to the new selected position
+	f.offset.Store(newOffset)
+
+	return newOffset, nil
+}
 
-	newOffset := startingOffset
+// seekOffset returns the offset resulting from seeking `offset` bytes, under
+// the mode given by `whence`, from the `current` offset of a file of the
+// given size.
+func seekOffset(current, size, offset int64, whence SeekMode) (int64, error) {
+	newOffset := current
 	switch whence {
 	case SeekModeStart:
 		if offset < 0 {
@@ -100,7 +125,7 @@ func (f *file) Seek(offset int64, whence SeekMode) (int64, error) { //nolint:gov
 			return 0, newFsError(TypeError, "offset cannot be positive when using SeekModeEnd")
 		}
 
-		newOffset = (f.size() - 1) + offset
+		newOffset = (size - 1) + offset
 	default:
 		return 0, newFsError(TypeError, "invalid seek mode")
 	}
@@ -109,13 +134,10 @@ func (f *file) Seek(offset int64, whence SeekMode) (int64, error) { //nolint:gov
 		return 0, newFsError(TypeError, "seeking before start of file")
 	}
 
-	if newOffset > f.size() {
+	if newOffset > size {
 		return 0, newFsError(TypeError, "seeking beyond end of file")
 	}
 
-	// Update the file instance's offset to the new selected position
-	f.offset.Store(newOffset)
-
 	return newOffset, nil
 }
 
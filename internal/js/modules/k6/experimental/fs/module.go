@@ -24,13 +24,18 @@ type (
 	// RootModule is the global module instance that will create instances of our
 	// module for each VU.
 	RootModule struct {
-		cache *cache
+		cache   *cache
+		scratch *scratchSpace
 	}
 
 	// ModuleInstance represents an instance of the fs module for a single VU.
 	ModuleInstance struct {
-		vu    modules.VU
-		cache *cache
+		vu      modules.VU
+		cache   *cache
+		scratch *scratchSpace
+
+		// tempDir holds the VU's temporary directory, once created.
+		tempDir *TempDir
 	}
 )
 
@@ -42,14 +47,15 @@ var (
 // New returns a pointer to a new [RootModule] instance.
 func New() *RootModule {
 	return &RootModule{
-		cache: &cache{},
+		cache:   &cache{},
+		scratch: &scratchSpace{},
 	}
 }
 
 // NewModuleInstance implements the modules.Module interface and returns a new
 // instance of our module for the given VU.
 func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
-	return &ModuleInstance{vu: vu, cache: rm.cache}
+	return &ModuleInstance{vu: vu, cache: rm.cache, scratch: rm.scratch}
 }
 
 // Exports implements the modules.Module interface and returns the exports of
@@ -57,7 +63,8 @@ func (rm *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
 func (mi *ModuleInstance) Exports() modules.Exports {
 	return modules.Exports{
 		Named: map[string]any{
-			"open": mi.Open,
+			"open":    mi.Open,
+			"tempDir": mi.TempDir,
 			"SeekMode": map[string]any{
 				"Start":   SeekModeStart,
 				"Current": SeekModeCurrent,
@@ -215,6 +222,47 @@ func (f *File) Stat() *sobek.Promise {
 	return promise
 }
 
+// ReadableFileOf returns the file that other modules can read, like k6/http
+// uploading it, if the value is a [File]. Its methods are kept off [File] so
+// that they aren't exposed to the scripts.
+func ReadableFileOf(v any) (common.ReadableFile, bool) {
+	f, ok := v.(*File)
+	if !ok {
+		return nil, false
+	}
+
+	return readableFile{file: f}, true
+}
+
+// readableFile implements [common.ReadableFile] for a [File].
+type readableFile struct {
+	file *File
+}
+
+var _ common.ReadableFile = readableFile{}
+
+// FilePath returns the path of the file, as presented to [Open].
+func (f readableFile) FilePath() string {
+	return f.file.Path
+}
+
+// contentOpener is implemented by the files which can be read as a whole
+// without moving their offset.
+type contentOpener interface {
+	openContent() (io.ReadCloser, int64, error)
+}
+
+// OpenContent returns a reader of the whole content of the file and its size.
+// It doesn't move the file's offset.
+func (f readableFile) OpenContent() (io.ReadCloser, int64, error) {
+	opener, ok := f.file.ReadSeekStater.(contentOpener)
+	if !ok {
+		return nil, 0, fmt.Errorf("the content of the file %q can't be read", f.file.Path)
+	}
+
+	return opener.openContent()
+}
+
 // Read the file's content, and writes it into the provided Uint8Array.
 //
 // Resolves to either the number of bytes read during the operation
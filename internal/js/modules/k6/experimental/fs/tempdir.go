@@ -0,0 +1,505 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/grafana/sobek"
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/v2/internal/event"
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/js/promises"
+)
+
+// defaultTempDirQuota is the default maximum size, in bytes, of the content
+// of a VU's temporary directory.
+const defaultTempDirQuota = 1 << 30 // 1 GiB
+
+// scratchSpace is the directory holding the temporary directories of all
+// the VUs.
+//
+// It is created the first time a VU asks for its temporary directory, and
+// removed with all its content when the test exits.
+type scratchSpace struct {
+	// parent is the directory the scratch space is created in, defaulting
+	// to the OS temporary directory when empty.
+	parent string
+
+	once sync.Once
+	root string
+	err  error
+}
+
+// init creates the scratch space, and registers its removal at the end of
+// the test.
+func (s *scratchSpace) init(vu modules.VU) (string, error) {
+	s.once.Do(func() {
+		s.root, s.err = os.MkdirTemp(s.parent, "k6-fs-")
+		if s.err != nil {
+			return
+		}
+
+		global := vu.Events().Global
+		if global == nil {
+			return
+		}
+
+		var logger logrus.FieldLogger
+		if state := vu.State(); state != nil {
+			logger = state.Logger
+		} else {
+			logger = vu.InitEnv().Logger
+		}
+
+		subID, exitCh := global.Subscribe(event.Exit)
+		go func() {
+			e, ok := <-exitCh
+			if !ok {
+				return
+			}
+			defer e.Done()
+			global.Unsubscribe(subID)
+
+			if err := os.RemoveAll(s.root); err != nil {
+				logger.WithError(err).Warnf("unable to remove the temporary directory %q", s.root)
+			}
+		}()
+	})
+
+	return s.root, s.err
+}
+
+// TempDir is a sandboxed temporary directory, private to a VU, in which
+// files can be written, appended to, listed, removed, and opened.
+//
+// The total size of the files it holds is limited by a quota.
+type TempDir struct {
+	// Path holds the absolute path of the directory on the file system.
+	Path string `json:"path"`
+
+	vu    modules.VU
+	quota int64
+
+	// mu serializes the operations on the directory, so the used size
+	// is kept accurate.
+	mu   sync.Mutex
+	used int64
+}
+
+// TempDirOptions holds the options of a VU's temporary directory.
+type TempDirOptions struct {
+	// Quota is the maximum size, in bytes, of the content of the directory.
+	Quota int64 `js:"quota"`
+}
+
+// TempDir returns the temporary directory of the VU, creating it on the
+// first call.
+//
+// The options are only taken into account when the directory is created.
+func (mi *ModuleInstance) TempDir(options sobek.Value) (*TempDir, error) {
+	if mi.tempDir != nil {
+		return mi.tempDir, nil
+	}
+
+	opts := TempDirOptions{Quota: defaultTempDirQuota}
+	if !common.IsNullish(options) {
+		if err := mi.vu.Runtime().ExportTo(options, &opts); err != nil {
+			return nil, newFsError(TypeError, "tempDir() failed; reason: invalid options: "+err.Error())
+		}
+		if opts.Quota <= 0 {
+			return nil, newFsError(TypeError, "tempDir() failed; reason: the quota must be a positive number")
+		}
+	}
+
+	root, err := mi.scratch.init(mi.vu)
+	if err != nil {
+		return nil, fmt.Errorf("tempDir() failed; reason: unable to create the scratch space: %w", err)
+	}
+
+	path, err := os.MkdirTemp(root, "vu-")
+	if err != nil {
+		return nil, fmt.Errorf("tempDir() failed; reason: unable to create the directory: %w", err)
+	}
+
+	mi.tempDir = &TempDir{Path: path, vu: mi.vu, quota: opts.Quota}
+
+	return mi.tempDir, nil
+}
+
+// Write writes the data to the named file, creating it and its parent
+// directories if needed, and replacing its content otherwise.
+func (d *TempDir) Write(name sobek.Value, data sobek.Value) *sobek.Promise {
+	return d.writeFile("write", name, data, false)
+}
+
+// Append appends the data to the named file, creating it and its parent
+// directories if needed.
+func (d *TempDir) Append(name sobek.Value, data sobek.Value) *sobek.Promise {
+	return d.writeFile("append", name, data, true)
+}
+
+func (d *TempDir) writeFile(op string, name sobek.Value, data sobek.Value, appending bool) *sobek.Promise {
+	promise, resolve, reject := promises.New(d.vu)
+
+	path, err := d.resolve(op, name)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	if common.IsNullish(data) {
+		reject(newFsError(TypeError, op+"() failed; reason: data cannot be null or undefined"))
+		return promise
+	}
+
+	b, err := common.ToBytes(data.Export())
+	if err != nil {
+		reject(newFsError(TypeError, op+"() failed; reason: "+err.Error()))
+		return promise
+	}
+
+	// The data is copied, as the underlying buffer could be modified by the
+	// script while the file is being written.
+	b = append([]byte(nil), b...)
+
+	go func() {
+		if err := d.write(op, path, b, appending); err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(sobek.Undefined())
+	}()
+
+	return promise
+}
+
+func (d *TempDir) write(op, path string, data []byte, appending bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var previousSize int64
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		return newFsError(InvalidResourceError, fmt.Sprintf("%s() failed; reason: %q is a directory", op, d.name(path)))
+	case err == nil && !appending:
+		previousSize = info.Size()
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%s() failed; reason: %w", op, err)
+	}
+
+	if d.used-previousSize+int64(len(data)) > d.quota {
+		return newFsError(QuotaExceededError, fmt.Sprintf(
+			"%s() failed; reason: writing %d bytes to %q would exceed the quota of %d bytes",
+			op, len(data), d.name(path), d.quota,
+		))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("%s() failed; reason: %w", op, err)
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appending {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	f, err := os.OpenFile(path, flag, 0o600) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("%s() failed; reason: %w", op, err)
+	}
+
+	n, err := f.Write(data)
+	d.used += int64(n) - previousSize
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("%s() failed; reason: %w", op, err)
+	}
+
+	return nil
+}
+
+// List returns a promise resolving to the [FileInfo] of the entries of the
+// named directory, or of the temporary directory itself if no name is given.
+func (d *TempDir) List(name sobek.Value) *sobek.Promise {
+	promise, resolve, reject := promises.New(d.vu)
+
+	path := d.Path
+	if !common.IsNullish(name) {
+		var err error
+		if path, err = d.resolve("list", name); err != nil {
+			reject(err)
+			return promise
+		}
+	}
+
+	go func() {
+		entries, err := os.ReadDir(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			reject(newFsError(NotFoundError, fmt.Sprintf("no such file or directory %q", d.name(path))))
+			return
+		}
+		if err != nil {
+			reject(fmt.Errorf("list() failed; reason: %w", err))
+			return
+		}
+
+		infos := make([]*FileInfo, 0, len(entries))
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil {
+				reject(fmt.Errorf("list() failed; reason: %w", err))
+				return
+			}
+
+			infos = append(infos, newFileInfo(info))
+		}
+
+		resolve(infos)
+	}()
+
+	return promise
+}
+
+// Remove removes the named file, or directory with all its content.
+func (d *TempDir) Remove(name sobek.Value) *sobek.Promise {
+	promise, resolve, reject := promises.New(d.vu)
+
+	path, err := d.resolve("remove", name)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	if path == d.Path {
+		reject(newFsError(ForbiddenError, "remove() failed; reason: the temporary directory itself cannot be removed"))
+		return promise
+	}
+
+	go func() {
+		if err := d.remove(path); err != nil {
+			reject(err)
+			return
+		}
+
+		resolve(sobek.Undefined())
+	}()
+
+	return promise
+}
+
+func (d *TempDir) remove(path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return newFsError(NotFoundError, fmt.Sprintf("no such file or directory %q", d.name(path)))
+	}
+	if err != nil {
+		return fmt.Errorf("remove() failed; reason: %w", err)
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("remove() failed; reason: %w", err)
+	}
+	d.used -= size
+
+	return nil
+}
+
+// Open opens the named file, and returns a promise that will resolve to a
+// [File] instance reading it from the disk.
+//
+// Unlike the files opened with the module's open function, the file can be
+// opened outside of the init context, and its content isn't held in memory.
+func (d *TempDir) Open(name sobek.Value) *sobek.Promise {
+	promise, resolve, reject := promises.New(d.vu)
+
+	path, err := d.resolve("open", name)
+	if err != nil {
+		reject(err)
+		return promise
+	}
+
+	go func() {
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			reject(newFsError(NotFoundError, fmt.Sprintf("no such file or directory %q", d.name(path))))
+			return
+		}
+		if err != nil {
+			reject(fmt.Errorf("open() failed; reason: %w", err))
+			return
+		}
+		if info.IsDir() {
+			reject(newFsError(
+				InvalidResourceError,
+				fmt.Sprintf("cannot open %q: opening a directory is not supported", d.name(path)),
+			))
+			return
+		}
+
+		resolve(&File{
+			Path:           path,
+			ReadSeekStater: &diskFile{path: path},
+			vu:             d.vu,
+		})
+	}()
+
+	return promise
+}
+
+// resolve returns the path of the named entry of the directory.
+//
+// Names are always resolved within the directory: absolute names are
+// relative to it, and names can't reach its parent using "..".
+func (d *TempDir) resolve(op string, name sobek.Value) (string, error) {
+	if common.IsNullish(name) {
+		return "", newFsError(TypeError, op+"() failed; reason: name cannot be null or undefined")
+	}
+
+	nameStr := name.String()
+	if nameStr == "" {
+		return "", newFsError(TypeError, op+"() failed; reason: name cannot be empty")
+	}
+
+	// Cleaning the name as an absolute path drops any leading "..".
+	cleaned := filepath.Clean(string(filepath.Separator) + filepath.FromSlash(nameStr))
+
+	return filepath.Join(d.Path, cleaned), nil
+}
+
+// name returns the name of the entry at the given path, as presented to the
+// script, relative to the directory.
+func (d *TempDir) name(path string) string {
+	rel, err := filepath.Rel(d.Path, path)
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// diskFile is a file of a temporary directory, read from the disk.
+//
+// The file is opened for each operation, so that no file descriptor is left
+// open once the script is done with the file.
+type diskFile struct {
+	path string
+
+	// offset holds the current offset in the file
+	offset atomic.Int64
+}
+
+var (
+	_ ReadSeekStater = (*diskFile)(nil)
+	_ io.WriterTo    = (*diskFile)(nil)
+	_ contentOpener  = (*diskFile)(nil)
+)
+
+// Stat returns a FileInfo describing the file.
+func (f *diskFile) Stat() *FileInfo {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return &FileInfo{Name: filepath.Base(f.path)}
+	}
+
+	return newFileInfo(info)
+}
+
+// Read reads up to len(into) bytes into the provided byte slice.
+//
+// If the end of the file has been reached, it returns io.EOF.
+func (f *diskFile) Read(into []byte) (int, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	n, err := file.ReadAt(into, f.offset.Load())
+	f.offset.Add(int64(n))
+
+	return n, err
+}
+
+// Seek sets the offset for the next operation on the file, under the mode
+// given by `whence`, with the same semantics as the files opened in the
+// init context.
+func (f *diskFile) Seek(offset int64, whence int) (int64, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return 0, err
+	}
+
+	newOffset, err := seekOffset(f.offset.Load(), info.Size(), offset, whence)
+	if err != nil {
+		return 0, err
+	}
+
+	f.offset.Store(newOffset)
+
+	return newOffset, nil
+}
+
+// WriteTo writes the remaining content of the file to w, which lets
+// [io.Copy] stream the file without opening it for every read.
+func (f *diskFile) WriteTo(w io.Writer) (int64, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	n, err := io.Copy(w, io.NewSectionReader(file, f.offset.Load(), 1<<63-1))
+	f.offset.Add(n)
+
+	return n, err
+}
+
+// openContent opens the file to read it as a whole, independent of its offset.
+func (f *diskFile) openContent() (io.ReadCloser, int64, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, 0, err
+	}
+
+	return file, info.Size(), nil
+}
+
+func newFileInfo(info fs.FileInfo) *FileInfo {
+	return &FileInfo{Name: info.Name(), Size: info.Size(), IsDirectory: info.IsDir()}
+}
@@ -0,0 +1,209 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/event"
+	"go.k6.io/k6/v2/internal/js/compiler"
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modulestest"
+)
+
+func TestTempDir(t *testing.T) {
+	t.Parallel()
+
+	t.Run("write, append, list, open and remove files", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := newTempDirRuntime(t)
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const dir = fs.tempDir();
+			if (fs.tempDir() !== dir) {
+				throw 'expected the same temporary directory to be returned';
+			}
+
+			await dir.write("hello.txt", "Bonjour");
+			await dir.append("hello.txt", new Uint8Array([44, 32]).buffer);
+			await dir.append("hello.txt", "le monde");
+			await dir.write("nested/data.bin", new Uint8Array([1, 2, 3]));
+
+			const entries = await dir.list();
+			const summary = entries.map((e) => e.name + ':' + e.size + ':' + e.isDirectory).join(',');
+			if (summary !== 'hello.txt:17:false,nested:' + entries[1].size + ':true') {
+				throw 'unexpected entries ' + summary;
+			}
+
+			const nested = await dir.list("nested");
+			if (nested.length !== 1 || nested[0].name !== 'data.bin' || nested[0].size !== 3) {
+				throw 'unexpected nested entries ' + JSON.stringify(nested);
+			}
+
+			const file = await dir.open("hello.txt");
+			const info = await file.stat();
+			if (info.name !== 'hello.txt' || info.size !== 17) {
+				throw 'unexpected file info ' + JSON.stringify(info);
+			}
+
+			const buffer = new Uint8Array(17);
+			await file.seek(9, fs.SeekMode.Start);
+			let bytesRead = await file.read(buffer);
+			if (bytesRead !== 8 || String.fromCharCode(...buffer.subarray(0, 8)) !== 'le monde') {
+				throw 'unexpected read of ' + bytesRead + ' bytes';
+			}
+			if (await file.read(buffer) !== null) {
+				throw 'expected EOF';
+			}
+
+			await dir.remove("nested");
+			if ((await dir.list()).length !== 1) {
+				throw 'expected the nested directory to be removed';
+			}
+
+			try {
+				await dir.open("nested/data.bin");
+				throw 'expected open to fail';
+			} catch (err) {
+				if (err.name !== 'NotFoundError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+		require.NoError(t, err)
+	})
+
+	t.Run("names are resolved within the directory", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := newTempDirRuntime(t)
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const dir = fs.tempDir();
+			await dir.write("../../escaped.txt", "data");
+			await dir.write("/absolute.txt", "data");
+
+			const names = (await dir.list()).map((e) => e.name).join(',');
+			if (names !== 'absolute.txt,escaped.txt') {
+				throw 'unexpected entries ' + names;
+			}
+
+			try {
+				await dir.remove("..");
+				throw 'expected remove to fail';
+			} catch (err) {
+				if (err.name !== 'ForbiddenError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+		`))
+		require.NoError(t, err)
+	})
+
+	t.Run("writes are limited by the quota", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := newTempDirRuntime(t)
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			const dir = fs.tempDir({ quota: 10 });
+			await dir.write("a.txt", "12345678");
+
+			try {
+				await dir.append("a.txt", "123");
+				throw 'expected append to fail';
+			} catch (err) {
+				if (err.name !== 'QuotaExceededError') {
+					throw 'unexpected error: ' + err;
+				}
+			}
+
+			// replacing the content of a file only accounts for the new content
+			await dir.write("a.txt", "1234567890");
+
+			await dir.remove("a.txt");
+			await dir.write("b.txt", "1234567890");
+		`))
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := newTempDirRuntime(t)
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			try {
+				fs.tempDir({ quota: -1 });
+				throw 'expected tempDir to fail';
+			} catch (err) {
+				if (!String(err).includes('the quota must be a positive number')) {
+					throw 'unexpected error: ' + err;
+				}
+			}
+
+			const dir = fs.tempDir();
+			for (const [name, data] of [[null, "data"], ["", "data"], ["a.txt", undefined], ["a.txt", 42]]) {
+				try {
+					await dir.write(name, data);
+					throw 'expected write to fail';
+				} catch (err) {
+					if (err.name !== 'TypeError') {
+						throw 'unexpected error: ' + err;
+					}
+				}
+			}
+		`))
+		require.NoError(t, err)
+	})
+
+	t.Run("the scratch space is removed on exit", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, rm := newTempDirRuntime(t)
+		events := event.NewEventSystem(10, logrus.New())
+		runtime.VU.EventsField = common.Events{Global: events, Local: event.NewEventSystem(10, logrus.New())}
+
+		_, err := runtime.RunOnEventLoop(wrapInAsyncLambda(`
+			await fs.tempDir().write("a.txt", "data");
+		`))
+		require.NoError(t, err)
+
+		root := rm.scratch.root
+		entries, err := os.ReadDir(root)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		waitDone := events.Emit(&event.Event{Type: event.Exit, Data: &event.ExitData{}})
+		require.NoError(t, waitDone(context.Background()))
+
+		_, err = os.Stat(root)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+// newTempDirRuntime returns a runtime with the fs module available, whose
+// temporary directories are created in a directory removed after the test.
+func newTempDirRuntime(t *testing.T) (*modulestest.Runtime, *RootModule) {
+	t.Helper()
+
+	runtime := modulestest.NewRuntime(t)
+
+	rm := New()
+	rm.scratch.parent = t.TempDir()
+
+	err := runtime.SetupModuleSystem(
+		map[string]any{"k6/experimental/fs": rm}, nil, compiler.New(runtime.VU.InitEnv().Logger),
+	)
+	require.NoError(t, err)
+
+	_, err = runtime.VU.Runtime().RunString(initGlobals)
+	require.NoError(t, err)
+
+	return runtime, rm
+}
@@ -0,0 +1,389 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // RSA-OAEP is defined with SHA-1
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// contentEncryption describes a JWE content encryption algorithm, as defined
+// by the [specification].
+//
+// [specification]: https://www.rfc-editor.org/rfc/rfc7518#section-5.1
+type contentEncryption struct {
+	keySize int
+	// hash is the hash function of the AES-CBC-HMAC algorithms, nil for AES-GCM.
+	hash func() hash.Hash
+}
+
+var contentEncryptions = map[string]contentEncryption{ //nolint:gochecknoglobals
+	"A128GCM":       {keySize: 16},
+	"A192GCM":       {keySize: 24},
+	"A256GCM":       {keySize: 32},
+	"A128CBC-HS256": {keySize: 32, hash: sha256.New},
+	"A192CBC-HS384": {keySize: 48, hash: sha512.New384},
+	"A256CBC-HS512": {keySize: 64, hash: sha512.New},
+}
+
+var errDecryptionFailed = errors.New("unable to decrypt the JWE")
+
+func lookupContentEncryption(enc string) (contentEncryption, error) {
+	ce, ok := contentEncryptions[enc]
+	if !ok {
+		return contentEncryption{}, fmt.Errorf("unsupported JWE content encryption %q", enc)
+	}
+
+	return ce, nil
+}
+
+// encryptJWE encrypts the payload, and returns the compact serialization of
+// the JWE with the given encoded protected header.
+func encryptJWE(alg, enc string, key any, protected string, payload []byte) (string, error) {
+	ce, err := lookupContentEncryption(enc)
+	if err != nil {
+		return "", err
+	}
+
+	cek, encryptedKey, err := wrapContentKey(alg, key, ce.keySize)
+	if err != nil {
+		return "", err
+	}
+
+	iv, ciphertext, tag, err := ce.seal(cek, payload, []byte(protected))
+	if err != nil {
+		return "", err
+	}
+
+	return protected + "." +
+		encodeSegment(encryptedKey) + "." +
+		encodeSegment(iv) + "." +
+		encodeSegment(ciphertext) + "." +
+		encodeSegment(tag), nil
+}
+
+// decryptJWE decrypts the payload of the five parts of a compact serialized JWE.
+func decryptJWE(alg, enc string, key any, parts []string) ([]byte, error) {
+	ce, err := lookupContentEncryption(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments [4][]byte
+	for i := range segments {
+		segments[i], err = decodeSegment(parts[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWE: %w", err)
+		}
+	}
+	encryptedKey, iv, ciphertext, tag := segments[0], segments[1], segments[2], segments[3]
+
+	cek, err := unwrapContentKey(alg, key, encryptedKey, ce.keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	return ce.open(cek, iv, ciphertext, tag, []byte(parts[0]))
+}
+
+// wrapContentKey returns the content encryption key, and its encrypted form
+// for the JWE, for the given key management algorithm.
+func wrapContentKey(alg string, key any, size int) ([]byte, []byte, error) {
+	if alg == "dir" {
+		cek, err := secretKey(key, alg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(cek) != size {
+			return nil, nil, fmt.Errorf("algorithm dir requires a %d bytes key, got %d", size, len(cek))
+		}
+
+		return cek, []byte{}, nil
+	}
+
+	cek := make([]byte, size)
+	if _, err := rand.Read(cek); err != nil {
+		return nil, nil, fmt.Errorf("unable to generate the content encryption key: %w", err)
+	}
+
+	switch alg {
+	case "RSA-OAEP", "RSA-OAEP-256":
+		k, ok := publicKey(key).(*rsa.PublicKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("algorithm %s requires an RSA public key, got %T", alg, key)
+		}
+
+		encryptedKey, err := rsa.EncryptOAEP(oaepHash(alg), rand.Reader, k, cek, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to encrypt the content encryption key: %w", err)
+		}
+
+		return cek, encryptedKey, nil
+	case "A128KW", "A192KW", "A256KW":
+		kek, err := keyWrapKey(alg, key)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		encryptedKey, err := aesKeyWrap(kek, cek)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return cek, encryptedKey, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported JWE key management algorithm %q", alg)
+	}
+}
+
+func unwrapContentKey(alg string, key any, encryptedKey []byte, size int) ([]byte, error) {
+	var (
+		cek []byte
+		err error
+	)
+	switch alg {
+	case "dir":
+		if len(encryptedKey) != 0 {
+			return nil, errors.New("invalid JWE: the encrypted key must be empty with algorithm dir")
+		}
+		cek, err = secretKey(key, alg)
+	case "RSA-OAEP", "RSA-OAEP-256":
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("algorithm %s requires an RSA private key, got %T", alg, key)
+		}
+		cek, err = rsa.DecryptOAEP(oaepHash(alg), nil, k, encryptedKey, nil)
+	case "A128KW", "A192KW", "A256KW":
+		var kek []byte
+		kek, err = keyWrapKey(alg, key)
+		if err != nil {
+			return nil, err
+		}
+		cek, err = aesKeyUnwrap(kek, encryptedKey)
+	default:
+		return nil, fmt.Errorf("unsupported JWE key management algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cek) != size {
+		return nil, errDecryptionFailed
+	}
+
+	return cek, nil
+}
+
+func oaepHash(alg string) hash.Hash {
+	if alg == "RSA-OAEP-256" {
+		return sha256.New()
+	}
+
+	return sha1.New() //nolint:gosec // RSA-OAEP is defined with SHA-1
+}
+
+func keyWrapKey(alg string, key any) ([]byte, error) {
+	kek, err := secretKey(key, alg)
+	if err != nil {
+		return nil, err
+	}
+
+	// the size of the key is encoded in the name of the algorithm, e.g. A128KW
+	var bits int
+	if _, err := fmt.Sscanf(alg, "A%dKW", &bits); err != nil || len(kek)*8 != bits {
+		return nil, fmt.Errorf("algorithm %s requires a %d bits key, got %d", alg, bits, len(kek)*8)
+	}
+
+	return kek, nil
+}
+
+// seal encrypts the plaintext with the content encryption key, and returns the
+// initialization vector, the ciphertext and the authentication tag.
+func (ce contentEncryption) seal(cek, plaintext, aad []byte) ([]byte, []byte, []byte, error) {
+	if ce.hash == nil {
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		iv := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(iv); err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to generate the initialization vector: %w", err)
+		}
+
+		sealed := aead.Seal(nil, iv, plaintext, aad)
+		split := len(sealed) - aead.Overhead()
+		return iv, sealed[:split], sealed[split:], nil
+	}
+
+	macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to generate the initialization vector: %w", err)
+	}
+
+	// PKCS#7 padding
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := append(bytes.Clone(plaintext), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return iv, ciphertext, ce.cbcTag(macKey, aad, iv, ciphertext), nil
+}
+
+// open authenticates and decrypts the ciphertext with the content encryption key.
+func (ce contentEncryption) open(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if ce.hash == nil {
+		aead, err := newGCM(cek)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(iv) != aead.NonceSize() {
+			return nil, errDecryptionFailed
+		}
+
+		plaintext, err := aead.Open(nil, iv, append(bytes.Clone(ciphertext), tag...), aad)
+		if err != nil {
+			return nil, errDecryptionFailed
+		}
+		return plaintext, nil
+	}
+
+	macKey, encKey := cek[:len(cek)/2], cek[len(cek)/2:]
+	if !hmac.Equal(tag, ce.cbcTag(macKey, aad, iv, ciphertext)) {
+		return nil, errDecryptionFailed
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errDecryptionFailed
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, errDecryptionFailed
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// cbcTag computes the authentication tag of the AES-CBC-HMAC algorithms, as
+// defined by the [specification].
+//
+// [specification]: https://www.rfc-editor.org/rfc/rfc7518#section-5.2.2.1
+func (ce contentEncryption) cbcTag(macKey, aad, iv, ciphertext []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(ce.hash, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+
+	return mac.Sum(nil)[:len(macKey)]
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// keyWrapIV is the default initial value of the AES key wrap algorithm.
+var keyWrapIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6} //nolint:gochecknoglobals
+
+// aesKeyWrap wraps the key with the key encryption key, as defined by [RFC 3394].
+//
+// [RFC 3394]: https://www.rfc-editor.org/rfc/rfc3394#section-2.2.1
+func aesKeyWrap(kek, key []byte) ([]byte, error) {
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, errors.New("the key to wrap must be a multiple of 64 bits")
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(key) / 8
+	out := make([]byte, len(key)+8)
+	copy(out, keyWrapIV)
+	copy(out[8:], key)
+
+	buf := make([]byte, aes.BlockSize)
+	for j := range 6 {
+		for i := 1; i <= n; i++ {
+			copy(buf, out[:8])
+			copy(buf[8:], out[i*8:(i+1)*8])
+			block.Encrypt(buf, buf)
+
+			t := uint64(n*j + i) //nolint:gosec // n and j are small
+			binary.BigEndian.PutUint64(out[:8], binary.BigEndian.Uint64(buf[:8])^t)
+			copy(out[i*8:], buf[8:])
+		}
+	}
+
+	return out, nil
+}
+
+// aesKeyUnwrap unwraps the key with the key encryption key, as defined by [RFC 3394].
+//
+// [RFC 3394]: https://www.rfc-editor.org/rfc/rfc3394#section-2.2.2
+func aesKeyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, errDecryptionFailed
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+	out := bytes.Clone(wrapped)
+
+	buf := make([]byte, aes.BlockSize)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i) //nolint:gosec // n and j are small
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(out[:8])^t)
+			copy(buf[8:], out[i*8:(i+1)*8])
+			block.Decrypt(buf, buf)
+
+			copy(out[:8], buf[:8])
+			copy(out[i*8:], buf[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(out[:8], keyWrapIV) != 1 {
+		return nil, errDecryptionFailed
+	}
+
+	return out[8:], nil
+}
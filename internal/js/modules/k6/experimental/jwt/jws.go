@@ -0,0 +1,190 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type signingFamily int
+
+const (
+	hmacFamily signingFamily = iota
+	rsaPKCS1v15Family
+	rsaPSSFamily
+	ecdsaFamily
+	eddsaFamily
+)
+
+// signingMethod describes a JWS algorithm, as defined by the [specification].
+//
+// [specification]: https://www.rfc-editor.org/rfc/rfc7518#section-3.1
+type signingMethod struct {
+	family signingFamily
+	hash   crypto.Hash
+	// curve is the name of the elliptic curve of the ECDSA algorithms.
+	curve string
+}
+
+var signingMethods = map[string]signingMethod{ //nolint:gochecknoglobals
+	"HS256": {family: hmacFamily, hash: crypto.SHA256},
+	"HS384": {family: hmacFamily, hash: crypto.SHA384},
+	"HS512": {family: hmacFamily, hash: crypto.SHA512},
+	"RS256": {family: rsaPKCS1v15Family, hash: crypto.SHA256},
+	"RS384": {family: rsaPKCS1v15Family, hash: crypto.SHA384},
+	"RS512": {family: rsaPKCS1v15Family, hash: crypto.SHA512},
+	"PS256": {family: rsaPSSFamily, hash: crypto.SHA256},
+	"PS384": {family: rsaPSSFamily, hash: crypto.SHA384},
+	"PS512": {family: rsaPSSFamily, hash: crypto.SHA512},
+	"ES256": {family: ecdsaFamily, hash: crypto.SHA256, curve: "P-256"},
+	"ES384": {family: ecdsaFamily, hash: crypto.SHA384, curve: "P-384"},
+	"ES512": {family: ecdsaFamily, hash: crypto.SHA512, curve: "P-521"},
+	"EdDSA": {family: eddsaFamily},
+}
+
+var errInvalidSignature = errors.New("invalid JWS signature")
+
+func lookupSigningMethod(alg string) (signingMethod, error) {
+	method, ok := signingMethods[alg]
+	if !ok {
+		return signingMethod{}, fmt.Errorf("unsupported JWS algorithm %q", alg)
+	}
+
+	return method, nil
+}
+
+// digest returns the hash of the signing input, for the algorithms signing it.
+func (m signingMethod) digest(input []byte) []byte {
+	h := m.hash.New()
+	h.Write(input)
+	return h.Sum(nil)
+}
+
+func signJWS(alg string, key any, input []byte) ([]byte, error) {
+	method, err := lookupSigningMethod(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch method.family {
+	case hmacFamily:
+		secret, err := secretKey(key, alg)
+		if err != nil {
+			return nil, err
+		}
+
+		mac := hmac.New(method.hash.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case rsaPKCS1v15Family, rsaPSSFamily:
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("algorithm %s requires an RSA private key, got %T", alg, key)
+		}
+
+		if method.family == rsaPSSFamily {
+			return rsa.SignPSS(rand.Reader, k, method.hash, method.digest(input), &rsa.PSSOptions{
+				SaltLength: rsa.PSSSaltLengthEqualsHash,
+			})
+		}
+		return rsa.SignPKCS1v15(rand.Reader, k, method.hash, method.digest(input))
+	case ecdsaFamily:
+		k, ok := key.(*ecdsa.PrivateKey)
+		if !ok || k.Curve.Params().Name != method.curve {
+			return nil, fmt.Errorf("algorithm %s requires an ECDSA %s private key, got %T", alg, method.curve, key)
+		}
+
+		r, s, err := ecdsa.Sign(rand.Reader, k, method.digest(input))
+		if err != nil {
+			return nil, fmt.Errorf("unable to sign: %w", err)
+		}
+
+		// the signature is the concatenation of r and s, as fixed size big-endian integers
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature := make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+		return signature, nil
+	case eddsaFamily:
+		k, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("algorithm %s requires an Ed25519 private key, got %T", alg, key)
+		}
+
+		return ed25519.Sign(k, input), nil
+	default:
+		return nil, fmt.Errorf("unsupported JWS algorithm %q", alg)
+	}
+}
+
+func verifyJWS(alg string, key any, input, signature []byte) error {
+	method, err := lookupSigningMethod(alg)
+	if err != nil {
+		return err
+	}
+
+	switch method.family {
+	case hmacFamily:
+		expected, err := signJWS(alg, key, input)
+		if err != nil {
+			return err
+		}
+
+		if !hmac.Equal(signature, expected) {
+			return errInvalidSignature
+		}
+		return nil
+	case rsaPKCS1v15Family, rsaPSSFamily:
+		k, ok := publicKey(key).(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %s requires an RSA public key, got %T", alg, key)
+		}
+
+		if method.family == rsaPSSFamily {
+			err = rsa.VerifyPSS(k, method.hash, method.digest(input), signature, &rsa.PSSOptions{
+				SaltLength: rsa.PSSSaltLengthAuto,
+			})
+		} else {
+			err = rsa.VerifyPKCS1v15(k, method.hash, method.digest(input), signature)
+		}
+		if err != nil {
+			return errInvalidSignature
+		}
+		return nil
+	case ecdsaFamily:
+		k, ok := publicKey(key).(*ecdsa.PublicKey)
+		if !ok || k.Curve.Params().Name != method.curve {
+			return fmt.Errorf("algorithm %s requires an ECDSA %s public key, got %T", alg, method.curve, key)
+		}
+
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errInvalidSignature
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(k, method.digest(input), r, s) {
+			return errInvalidSignature
+		}
+		return nil
+	case eddsaFamily:
+		k, ok := publicKey(key).(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("algorithm %s requires an Ed25519 public key, got %T", alg, key)
+		}
+
+		if !ed25519.Verify(k, input, signature) {
+			return errInvalidSignature
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported JWS algorithm %q", alg)
+	}
+}
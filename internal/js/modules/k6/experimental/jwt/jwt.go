@@ -0,0 +1,581 @@
+// Package jwt provides the signing, verification, encryption and decryption
+// of JSON Web Tokens for the k6, as JWS and JWE compact serializations.
+package jwt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+)
+
+type (
+	// RootModule is the global module instance that will create module
+	// instances for each VU.
+	RootModule struct{}
+
+	// JWT represents an instance of the JWT module.
+	JWT struct {
+		vu modules.VU
+
+		// now returns the current time, the claims are checked against.
+		now func() time.Time
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &JWT{}
+)
+
+// New returns a pointer to a new RootModule instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface to return
+// a new instance for each VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &JWT{vu: vu, now: time.Now}
+}
+
+// Exports returns the exports of the JWT module.
+func (mi *JWT) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"sign":    mi.sign,
+			"verify":  mi.verify,
+			"encrypt": mi.encrypt,
+			"decrypt": mi.decrypt,
+			"decode":  mi.decode,
+		},
+	}
+}
+
+// sign returns the payload signed as a JWS, in its compact serialization.
+func (mi *JWT) sign(payload sobek.Value, key sobek.Value, options sobek.Value) (string, error) {
+	opts, err := newSignOptions(mi.vu.Runtime(), options)
+	if err != nil {
+		return "", err
+	}
+
+	k, err := parseKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := mi.buildPayload(payload, opts.claimsOptions)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := buildHeader(opts.header, map[string]any{"alg": opts.algorithm, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodeSegment(header) + "." + encodeSegment(data)
+	signature, err := signJWS(opts.algorithm, k, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// verify checks the signature of a JWS, and the validity of its claims, and
+// returns its payload.
+func (mi *JWT) verify(token string, key sobek.Value, options sobek.Value) (sobek.Value, error) {
+	opts, err := newVerifyOptions(mi.vu.Runtime(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid JWS: expected 3 parts separated by dots")
+	}
+
+	header, err := decodeHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	alg, err := opts.checkAlgorithm(header)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWS signature: %w", err)
+	}
+
+	if err := verifyJWS(alg, k, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	data, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWS payload: %w", err)
+	}
+
+	payload, err := mi.checkPayload(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.complete {
+		return mi.vu.Runtime().ToValue(payload), nil
+	}
+
+	return mi.vu.Runtime().ToValue(map[string]any{
+		"header":    header,
+		"payload":   payload,
+		"signature": parts[2],
+	}), nil
+}
+
+// encrypt returns the payload encrypted as a JWE, in its compact serialization.
+func (mi *JWT) encrypt(payload sobek.Value, key sobek.Value, options sobek.Value) (string, error) {
+	opts, err := newEncryptOptions(mi.vu.Runtime(), options)
+	if err != nil {
+		return "", err
+	}
+
+	k, err := parseKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := mi.buildPayload(payload, opts.claimsOptions)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := buildHeader(opts.header, map[string]any{"alg": opts.algorithm, "enc": opts.encryption})
+	if err != nil {
+		return "", err
+	}
+
+	return encryptJWE(opts.algorithm, opts.encryption, k, encodeSegment(header), data)
+}
+
+// decrypt decrypts a JWE, checks the validity of its claims, and returns its
+// payload.
+func (mi *JWT) decrypt(token string, key sobek.Value, options sobek.Value) (sobek.Value, error) {
+	opts, err := newVerifyOptions(mi.vu.Runtime(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, errors.New("invalid JWE: expected 5 parts separated by dots")
+	}
+
+	header, err := decodeHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	alg, err := opts.checkAlgorithm(header)
+	if err != nil {
+		return nil, err
+	}
+
+	enc, ok := header["enc"].(string)
+	if !ok {
+		return nil, errors.New("invalid JWE header: enc is required")
+	}
+
+	data, err := decryptJWE(alg, enc, k, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := mi.checkPayload(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if !opts.complete {
+		return mi.vu.Runtime().ToValue(payload), nil
+	}
+
+	return mi.vu.Runtime().ToValue(map[string]any{
+		"header":  header,
+		"payload": payload,
+	}), nil
+}
+
+// decode returns the header, payload and signature of a JWS, without
+// verifying it.
+func (mi *JWT) decode(token string) (sobek.Value, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("invalid JWS: expected 3 parts separated by dots")
+	}
+
+	header, err := decodeHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decodeSegment(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWS payload: %w", err)
+	}
+
+	return mi.vu.Runtime().ToValue(map[string]any{
+		"header":    header,
+		"payload":   unmarshalPayload(data),
+		"signature": parts[2],
+	}), nil
+}
+
+// buildPayload returns the bytes of the payload of a token: the string itself,
+// or the claims serialized as JSON, completed with the time based claims of
+// the options.
+func (mi *JWT) buildPayload(payload sobek.Value, opts claimsOptions) ([]byte, error) {
+	if common.IsNullish(payload) {
+		return nil, errors.New("a payload is required")
+	}
+
+	if s, ok := payload.Export().(string); ok {
+		if opts.expiresIn != nil || opts.notBefore != nil {
+			return nil, errors.New("expiresIn and notBefore require the payload to be an object of claims")
+		}
+
+		return []byte(s), nil
+	}
+
+	claims, ok := payload.Export().(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("the payload must be a string or an object of claims, got %T", payload.Export())
+	}
+
+	now := mi.now()
+	if opts.expiresIn != nil {
+		claims["exp"] = now.Add(*opts.expiresIn).Unix()
+	}
+	if opts.notBefore != nil {
+		claims["nbf"] = now.Add(*opts.notBefore).Unix()
+	}
+
+	data, err := json.Marshal(claims)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize the claims: %w", err)
+	}
+
+	return data, nil
+}
+
+// checkPayload returns the decoded payload of a token, after checking the
+// validity of its claims, if it holds any.
+func (mi *JWT) checkPayload(data []byte, opts verifyOptions) (any, error) {
+	payload := unmarshalPayload(data)
+
+	claims, ok := payload.(map[string]any)
+	if !ok {
+		return payload, nil
+	}
+
+	if err := opts.checkClaims(claims, mi.now()); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// unmarshalPayload returns the claims of a payload, or the payload as a
+// string if it doesn't hold a JSON object.
+func unmarshalPayload(data []byte) any {
+	var claims map[string]any
+	if err := json.Unmarshal(data, &claims); err != nil || claims == nil {
+		return string(data)
+	}
+
+	return claims
+}
+
+// buildHeader serializes the header, the custom parameters are merged with
+// the ones required by the token.
+func buildHeader(custom map[string]any, required map[string]any) ([]byte, error) {
+	header := make(map[string]any, len(custom)+len(required))
+	for k, v := range custom {
+		header[k] = v
+	}
+	for k, v := range required {
+		header[k] = v
+	}
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialize the header: %w", err)
+	}
+
+	return data, nil
+}
+
+func decodeHeader(segment string) (map[string]any, error) {
+	data, err := decodeSegment(segment)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+
+	var header map[string]any
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+
+	return header, nil
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
+
+// claimsOptions are the options adding the time based claims to a payload.
+type claimsOptions struct {
+	expiresIn *time.Duration
+	notBefore *time.Duration
+}
+
+func (o *claimsOptions) parse(obj *sobek.Object) {
+	if v := obj.Get("expiresIn"); !common.IsNullish(v) {
+		d := time.Duration(v.ToFloat() * float64(time.Second))
+		o.expiresIn = &d
+	}
+
+	if v := obj.Get("notBefore"); !common.IsNullish(v) {
+		d := time.Duration(v.ToFloat() * float64(time.Second))
+		o.notBefore = &d
+	}
+}
+
+type signOptions struct {
+	claimsOptions
+
+	algorithm string
+	header    map[string]any
+}
+
+func newSignOptions(rt *sobek.Runtime, options sobek.Value) (signOptions, error) {
+	opts := signOptions{algorithm: "HS256"}
+	if common.IsNullish(options) {
+		return opts, nil
+	}
+
+	obj := options.ToObject(rt)
+	opts.claimsOptions.parse(obj)
+
+	if v := obj.Get("algorithm"); !common.IsNullish(v) {
+		opts.algorithm = v.String()
+	}
+
+	header, err := exportHeader(rt, obj)
+	if err != nil {
+		return opts, err
+	}
+	opts.header = header
+
+	return opts, nil
+}
+
+type encryptOptions struct {
+	claimsOptions
+
+	algorithm  string
+	encryption string
+	header     map[string]any
+}
+
+func newEncryptOptions(rt *sobek.Runtime, options sobek.Value) (encryptOptions, error) {
+	opts := encryptOptions{algorithm: "dir", encryption: "A256GCM"}
+	if common.IsNullish(options) {
+		return opts, nil
+	}
+
+	obj := options.ToObject(rt)
+	opts.claimsOptions.parse(obj)
+
+	if v := obj.Get("algorithm"); !common.IsNullish(v) {
+		opts.algorithm = v.String()
+	}
+
+	if v := obj.Get("encryption"); !common.IsNullish(v) {
+		opts.encryption = v.String()
+	}
+
+	header, err := exportHeader(rt, obj)
+	if err != nil {
+		return opts, err
+	}
+	opts.header = header
+
+	return opts, nil
+}
+
+func exportHeader(rt *sobek.Runtime, obj *sobek.Object) (map[string]any, error) {
+	v := obj.Get("header")
+	if common.IsNullish(v) {
+		return nil, nil //nolint:nilnil // no custom header is valid
+	}
+
+	var header map[string]any
+	if err := rt.ExportTo(v, &header); err != nil {
+		return nil, fmt.Errorf("header must be an object: %w", err)
+	}
+
+	return header, nil
+}
+
+type verifyOptions struct {
+	algorithms       []string
+	issuer           string
+	subject          string
+	audience         string
+	clockTolerance   time.Duration
+	ignoreExpiration bool
+	complete         bool
+}
+
+func newVerifyOptions(rt *sobek.Runtime, options sobek.Value) (verifyOptions, error) {
+	var opts verifyOptions
+	if common.IsNullish(options) {
+		return opts, nil
+	}
+
+	obj := options.ToObject(rt)
+
+	if v := obj.Get("algorithms"); !common.IsNullish(v) {
+		if err := rt.ExportTo(v, &opts.algorithms); err != nil {
+			return opts, fmt.Errorf("algorithms must be an array of strings: %w", err)
+		}
+	}
+
+	if v := obj.Get("issuer"); !common.IsNullish(v) {
+		opts.issuer = v.String()
+	}
+
+	if v := obj.Get("subject"); !common.IsNullish(v) {
+		opts.subject = v.String()
+	}
+
+	if v := obj.Get("audience"); !common.IsNullish(v) {
+		opts.audience = v.String()
+	}
+
+	if v := obj.Get("clockTolerance"); !common.IsNullish(v) {
+		opts.clockTolerance = time.Duration(v.ToFloat() * float64(time.Second))
+	}
+
+	if v := obj.Get("ignoreExpiration"); v != nil {
+		opts.ignoreExpiration = v.ToBoolean()
+	}
+
+	if v := obj.Get("complete"); v != nil {
+		opts.complete = v.ToBoolean()
+	}
+
+	return opts, nil
+}
+
+// checkAlgorithm returns the algorithm of the header, if it's one of the
+// allowed ones.
+func (o verifyOptions) checkAlgorithm(header map[string]any) (string, error) {
+	alg, ok := header["alg"].(string)
+	if !ok || alg == "" {
+		return "", errors.New("invalid header: alg is required")
+	}
+
+	if alg == "none" {
+		return "", errors.New("unsecured tokens, using the none algorithm, aren't supported")
+	}
+
+	if len(o.algorithms) > 0 && !slices.Contains(o.algorithms, alg) {
+		return "", fmt.Errorf("algorithm %q isn't one of the allowed %v", alg, o.algorithms)
+	}
+
+	return alg, nil
+}
+
+// checkClaims checks the registered claims of the payload, as defined by the
+// [specification], against the current time and the expected values.
+//
+// [specification]: https://www.rfc-editor.org/rfc/rfc7519#section-4.1
+func (o verifyOptions) checkClaims(claims map[string]any, now time.Time) error {
+	if exp, ok, err := numericDate(claims, "exp"); err != nil {
+		return err
+	} else if ok && !o.ignoreExpiration && !now.Before(exp.Add(o.clockTolerance)) {
+		return fmt.Errorf("token is expired since %s", exp.UTC().Format(time.RFC3339))
+	}
+
+	if nbf, ok, err := numericDate(claims, "nbf"); err != nil {
+		return err
+	} else if ok && now.Add(o.clockTolerance).Before(nbf) {
+		return fmt.Errorf("token isn't valid before %s", nbf.UTC().Format(time.RFC3339))
+	}
+
+	if o.issuer != "" && claims["iss"] != o.issuer {
+		return fmt.Errorf("unexpected issuer %v, expected %q", claims["iss"], o.issuer)
+	}
+
+	if o.subject != "" && claims["sub"] != o.subject {
+		return fmt.Errorf("unexpected subject %v, expected %q", claims["sub"], o.subject)
+	}
+
+	if o.audience != "" && !hasAudience(claims["aud"], o.audience) {
+		return fmt.Errorf("unexpected audience %v, expected %q", claims["aud"], o.audience)
+	}
+
+	return nil
+}
+
+func numericDate(claims map[string]any, name string) (time.Time, bool, error) {
+	v, ok := claims[name]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	seconds, ok := v.(float64)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("invalid %s claim: expected a number of seconds, got %T", name, v)
+	}
+
+	return time.Unix(0, int64(seconds*float64(time.Second))), true, nil
+}
+
+func hasAudience(aud any, audience string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == audience
+	case []any:
+		return slices.Contains(a, any(audience))
+	default:
+		return false
+	}
+}
@@ -0,0 +1,406 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modulestest"
+)
+
+func makeRuntime(t *testing.T, now time.Time) *sobek.Runtime {
+	t.Helper()
+
+	rt := sobek.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+	m, ok := New().NewModuleInstance(
+		&modulestest.VU{
+			RuntimeField: rt,
+			InitEnvField: &common.InitEnvironment{},
+			CtxField:     context.Background(),
+		},
+	).(*JWT)
+	require.True(t, ok)
+	m.now = func() time.Time { return now }
+	require.NoError(t, rt.Set("jwt", m.Exports().Named))
+	return rt
+}
+
+func pemEncode(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func privateKeyPEM(t *testing.T, key any) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pemEncode(t, "PRIVATE KEY", der)
+}
+
+func publicKeyPEM(t *testing.T, key any) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+	return pemEncode(t, "PUBLIC KEY", der)
+}
+
+func TestSignVerify(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	p521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	secret := "a secret of at least the size of the hash output....."
+	tests := []struct {
+		alg                   string
+		privateKey, publicKey string
+	}{
+		{alg: "HS256", privateKey: secret, publicKey: secret},
+		{alg: "HS384", privateKey: secret, publicKey: secret},
+		{alg: "HS512", privateKey: secret, publicKey: secret},
+		{alg: "RS256", privateKey: privateKeyPEM(t, rsaKey), publicKey: publicKeyPEM(t, &rsaKey.PublicKey)},
+		{alg: "RS384", privateKey: privateKeyPEM(t, rsaKey), publicKey: publicKeyPEM(t, &rsaKey.PublicKey)},
+		{alg: "RS512", privateKey: privateKeyPEM(t, rsaKey), publicKey: publicKeyPEM(t, &rsaKey.PublicKey)},
+		{alg: "PS256", privateKey: privateKeyPEM(t, rsaKey), publicKey: publicKeyPEM(t, &rsaKey.PublicKey)},
+		{alg: "PS384", privateKey: privateKeyPEM(t, rsaKey), publicKey: publicKeyPEM(t, &rsaKey.PublicKey)},
+		{alg: "PS512", privateKey: privateKeyPEM(t, rsaKey), publicKey: publicKeyPEM(t, &rsaKey.PublicKey)},
+		{alg: "ES256", privateKey: privateKeyPEM(t, p256Key), publicKey: publicKeyPEM(t, &p256Key.PublicKey)},
+		{alg: "ES384", privateKey: privateKeyPEM(t, p384Key), publicKey: publicKeyPEM(t, &p384Key.PublicKey)},
+		{alg: "ES512", privateKey: privateKeyPEM(t, p521Key), publicKey: publicKeyPEM(t, &p521Key.PublicKey)},
+		{alg: "EdDSA", privateKey: privateKeyPEM(t, edPrivateKey), publicKey: publicKeyPEM(t, edPublicKey)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg, func(t *testing.T) {
+			t.Parallel()
+
+			rt := makeRuntime(t, time.Now())
+			require.NoError(t, rt.Set("privateKey", tt.privateKey))
+			require.NoError(t, rt.Set("publicKey", tt.publicKey))
+			require.NoError(t, rt.Set("alg", tt.alg))
+
+			_, err := rt.RunString(`
+				const token = jwt.sign({ sub: "k6" }, privateKey, { algorithm: alg, expiresIn: 60 });
+
+				const decoded = jwt.decode(token);
+				if (decoded.header.alg !== alg || decoded.header.typ !== "JWT") {
+					throw new Error("unexpected header: " + JSON.stringify(decoded.header));
+				}
+
+				const claims = jwt.verify(token, publicKey, { algorithms: [alg] });
+				if (claims.sub !== "k6") {
+					throw new Error("unexpected claims: " + JSON.stringify(claims));
+				}
+
+				const parts = token.split(".");
+				const tampered = parts[0] + "." + parts[1] + "." + parts[2].split("").reverse().join("");
+				let failed = false;
+				try {
+					jwt.verify(tampered, publicKey);
+				} catch (e) {
+					failed = true;
+				}
+				if (!failed) {
+					throw new Error("a tampered token was verified");
+				}
+			`)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifyVectors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("HS256", func(t *testing.T) {
+		t.Parallel()
+
+		// https://www.rfc-editor.org/rfc/rfc7515#appendix-A.1
+		rt := makeRuntime(t, time.Unix(1300819000, 0))
+		_, err := rt.RunString(`
+			const key = {
+				kty: "oct",
+				k: "AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow",
+			};
+			const token = "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+				".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+				".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk";
+
+			const claims = jwt.verify(token, key, { issuer: "joe" });
+			if (claims.exp !== 1300819380 || claims["http://example.com/is_root"] !== true) {
+				throw new Error("unexpected claims: " + JSON.stringify(claims));
+			}
+		`)
+		require.NoError(t, err)
+	})
+
+	t.Run("EdDSA", func(t *testing.T) {
+		t.Parallel()
+
+		// https://www.rfc-editor.org/rfc/rfc8037#appendix-A.4
+		rt := makeRuntime(t, time.Now())
+		_, err := rt.RunString(`
+			const key = {
+				kty: "OKP",
+				crv: "Ed25519",
+				x: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+			};
+			const token = "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc" +
+				".hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg";
+
+			const payload = jwt.verify(token, key);
+			if (payload !== "Example of Ed25519 signing") {
+				throw new Error("unexpected payload: " + payload);
+			}
+		`)
+		require.NoError(t, err)
+	})
+}
+
+func TestVerifyClaims(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name    string
+		claims  string
+		options string
+		err     string
+	}{
+		{name: "Valid", claims: `{ exp: 1700000060, nbf: 1699999940 }`, options: `{}`},
+		{name: "Expired", claims: `{ exp: 1700000000 }`, options: `{}`, err: "token is expired"},
+		{name: "ExpiredTolerated", claims: `{ exp: 1699999990 }`, options: `{ clockTolerance: 30 }`},
+		{name: "ExpiredIgnored", claims: `{ exp: 1600000000 }`, options: `{ ignoreExpiration: true }`},
+		{name: "NotBefore", claims: `{ nbf: 1700000060 }`, options: `{}`, err: "token isn't valid before"},
+		{name: "InvalidExpiration", claims: `{ exp: "tomorrow" }`, options: `{}`, err: "invalid exp claim"},
+		{name: "Issuer", claims: `{ iss: "k6" }`, options: `{ issuer: "k6" }`},
+		{name: "WrongIssuer", claims: `{ iss: "other" }`, options: `{ issuer: "k6" }`, err: "unexpected issuer"},
+		{name: "Subject", claims: `{ sub: "vu" }`, options: `{ subject: "vu" }`},
+		{name: "Audience", claims: `{ aud: ["api", "web"] }`, options: `{ audience: "web" }`},
+		{name: "WrongAudience", claims: `{ aud: "api" }`, options: `{ audience: "web" }`, err: "unexpected audience"},
+		{
+			name: "Algorithms", claims: `{}`, options: `{ algorithms: ["HS512"] }`,
+			err: `algorithm "HS256" isn't one of the allowed [HS512]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt := makeRuntime(t, now)
+			_, err := rt.RunString(`
+				const token = jwt.sign(` + tt.claims + `, "secret");
+				jwt.verify(token, "secret", ` + tt.options + `);
+				jwt.decrypt(jwt.encrypt(` + tt.claims + `, "0123456789abcdef", { encryption: "A128GCM" }),
+					"0123456789abcdef", Object.assign(` + tt.options + `, { algorithms: undefined }));
+			`)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestSignOptions(t *testing.T) {
+	t.Parallel()
+
+	rt := makeRuntime(t, time.Unix(1700000000, 0))
+	_, err := rt.RunString(`
+		const token = jwt.sign({ sub: "k6" }, "secret", {
+			algorithm: "HS512",
+			header: { kid: "key-1", alg: "none" },
+			expiresIn: 60,
+			notBefore: -60,
+		});
+
+		const { header, payload } = jwt.decode(token);
+		if (header.kid !== "key-1" || header.alg !== "HS512") {
+			throw new Error("unexpected header: " + JSON.stringify(header));
+		}
+		if (payload.exp !== 1700000060 || payload.nbf !== 1699999940) {
+			throw new Error("unexpected payload: " + JSON.stringify(payload));
+		}
+
+		const complete = jwt.verify(token, "secret", { complete: true });
+		if (complete.header.kid !== "key-1" || complete.payload.sub !== "k6" || !complete.signature) {
+			throw new Error("unexpected complete result: " + JSON.stringify(complete));
+		}
+	`)
+	require.NoError(t, err)
+
+	_, err = rt.RunString(`jwt.sign("a string payload", "secret", { expiresIn: 60 })`)
+	require.ErrorContains(t, err, "require the payload to be an object of claims")
+
+	_, err = rt.RunString(`jwt.sign({}, "secret", { algorithm: "none" })`)
+	require.ErrorContains(t, err, `unsupported JWS algorithm "none"`)
+
+	_, err = rt.RunString(`jwt.sign({}, "secret", { algorithm: "RS256" })`)
+	require.ErrorContains(t, err, "algorithm RS256 requires an RSA private key")
+}
+
+func TestVerifyUnsecured(t *testing.T) {
+	t.Parallel()
+
+	rt := makeRuntime(t, time.Now())
+	_, err := rt.RunString(`jwt.verify("eyJhbGciOiJub25lIn0.eyJzdWIiOiJrNiJ9.", "secret")`)
+	require.ErrorContains(t, err, "unsecured tokens")
+}
+
+func TestVerifyCertificate(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "k6"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	rt := makeRuntime(t, time.Now())
+	require.NoError(t, rt.Set("privateKey", privateKeyPEM(t, key)))
+	require.NoError(t, rt.Set("certificate", pemEncode(t, "CERTIFICATE", der)))
+
+	_, err = rt.RunString(`
+		const token = jwt.sign({ sub: "k6" }, privateKey, { algorithm: "ES256" });
+		if (jwt.verify(token, certificate).sub !== "k6") {
+			throw new Error("unexpected claims");
+		}
+	`)
+	require.NoError(t, err)
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		alg, enc               string
+		encryptKey, decryptKey string
+	}{
+		{alg: "dir", enc: "A128GCM", encryptKey: "0123456789abcdef", decryptKey: "0123456789abcdef"},
+		{alg: "dir", enc: "A256GCM", encryptKey: "0123456789abcdef0123456789abcdef", decryptKey: "0123456789abcdef0123456789abcdef"},
+		{alg: "dir", enc: "A128CBC-HS256", encryptKey: "0123456789abcdef0123456789abcdef", decryptKey: "0123456789abcdef0123456789abcdef"},
+		{alg: "A128KW", enc: "A128CBC-HS256", encryptKey: "0123456789abcdef", decryptKey: "0123456789abcdef"},
+		{alg: "A192KW", enc: "A192CBC-HS384", encryptKey: "0123456789abcdef01234567", decryptKey: "0123456789abcdef01234567"},
+		{alg: "A256KW", enc: "A256CBC-HS512", encryptKey: "0123456789abcdef0123456789abcdef", decryptKey: "0123456789abcdef0123456789abcdef"},
+		{alg: "RSA-OAEP", enc: "A256GCM", encryptKey: publicKeyPEM(t, &rsaKey.PublicKey), decryptKey: privateKeyPEM(t, rsaKey)},
+		{alg: "RSA-OAEP-256", enc: "A192GCM", encryptKey: publicKeyPEM(t, &rsaKey.PublicKey), decryptKey: privateKeyPEM(t, rsaKey)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alg+"/"+tt.enc, func(t *testing.T) {
+			t.Parallel()
+
+			rt := makeRuntime(t, time.Now())
+			require.NoError(t, rt.Set("encryptKey", tt.encryptKey))
+			require.NoError(t, rt.Set("decryptKey", tt.decryptKey))
+			require.NoError(t, rt.Set("alg", tt.alg))
+			require.NoError(t, rt.Set("enc", tt.enc))
+
+			_, err := rt.RunString(`
+				const token = jwt.encrypt({ sub: "k6" }, encryptKey, { algorithm: alg, encryption: enc, expiresIn: 60 });
+				if (token.split(".").length !== 5) {
+					throw new Error("unexpected token: " + token);
+				}
+
+				const { header, payload } = jwt.decrypt(token, decryptKey, { complete: true });
+				if (header.alg !== alg || header.enc !== enc || payload.sub !== "k6") {
+					throw new Error("unexpected result: " + JSON.stringify({ header, payload }));
+				}
+
+				const parts = token.split(".");
+				parts[3] = parts[3].split("").reverse().join("");
+				let failed = false;
+				try {
+					jwt.decrypt(parts.join("."), decryptKey);
+				} catch (e) {
+					failed = true;
+				}
+				if (!failed) {
+					throw new Error("a tampered token was decrypted");
+				}
+			`)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDecryptVector(t *testing.T) {
+	t.Parallel()
+
+	// https://www.rfc-editor.org/rfc/rfc7516#appendix-A.3
+	rt := makeRuntime(t, time.Now())
+	_, err := rt.RunString(`
+		const key = { kty: "oct", k: "GawgguFyGrWKav7AX4VKUg" };
+		const token = "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0" +
+			".6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ" +
+			".AxY8DCtDaGlsbGljb3RoZQ" +
+			".KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY" +
+			".U0m_YmjN04DJvceFICbCVQ";
+
+		const payload = jwt.decrypt(token, key);
+		if (payload !== "Live long and prosper.") {
+			throw new Error("unexpected payload: " + payload);
+		}
+	`)
+	require.NoError(t, err)
+}
+
+func TestAESKeyWrap(t *testing.T) {
+	t.Parallel()
+
+	// https://www.rfc-editor.org/rfc/rfc3394#section-4.1
+	kek, err := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+	require.NoError(t, err)
+	key, err := hex.DecodeString("00112233445566778899AABBCCDDEEFF")
+	require.NoError(t, err)
+
+	wrapped, err := aesKeyWrap(kek, key)
+	require.NoError(t, err)
+	assert.Equal(t, "1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5", hex.EncodeToString(wrapped))
+
+	unwrapped, err := aesKeyUnwrap(kek, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, key, unwrapped)
+
+	wrapped[0] ^= 1
+	_, err = aesKeyUnwrap(kek, wrapped)
+	require.ErrorIs(t, err, errDecryptionFailed)
+}
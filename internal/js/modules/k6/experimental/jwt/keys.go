@@ -0,0 +1,143 @@
+package jwt
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	gox509 "crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	k6x509 "go.k6.io/k6/v2/internal/js/modules/k6/crypto/x509"
+	"go.k6.io/k6/v2/internal/js/modules/k6/webcrypto"
+	"go.k6.io/k6/v2/js/common"
+)
+
+// parseKey returns the key held by the given value, which can be:
+//   - a PEM encoded private key, public key or certificate
+//   - a JSON Web Key object
+//   - a certificate, or its public key, from the k6/crypto/x509 module
+//   - any other string or binary data, as the secret of the symmetric algorithms
+//
+// The returned key is either a []byte secret, or one of the crypto/rsa,
+// crypto/ecdsa and crypto/ed25519 keys.
+func parseKey(v sobek.Value) (any, error) {
+	if common.IsNullish(v) {
+		return nil, errors.New("a key is required")
+	}
+
+	switch k := v.Export().(type) {
+	case string:
+		if strings.Contains(k, "-----BEGIN") {
+			return parsePEMKey([]byte(k))
+		}
+		return []byte(k), nil
+	case k6x509.Certificate:
+		return k.PublicKey.Key, nil
+	case *k6x509.Certificate:
+		return k.PublicKey.Key, nil
+	case k6x509.PublicKey:
+		return k.Key, nil
+	case *k6x509.PublicKey:
+		return k.Key, nil
+	case *rsa.PublicKey, *rsa.PrivateKey, *ecdsa.PublicKey, *ecdsa.PrivateKey,
+		ed25519.PublicKey, ed25519.PrivateKey:
+		return k, nil
+	case map[string]any:
+		if _, ok := k["kty"]; !ok {
+			return nil, errors.New("invalid key: an object key must be a JSON Web Key")
+		}
+
+		data, err := json.Marshal(k)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON Web Key: %w", err)
+		}
+
+		key, _, err := webcrypto.ImportJWK(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON Web Key: %w", err)
+		}
+
+		return key, nil
+	}
+
+	secret, err := common.ToBytes(v.Export())
+	if err != nil {
+		return nil, fmt.Errorf("unsupported key type %T", v.Export())
+	}
+
+	return secret, nil
+}
+
+// parsePEMKey parses the first PEM block of data as a key.
+func parsePEMKey(data []byte) (any, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode the PEM encoded key")
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = gox509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = gox509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = gox509.ParsePKCS1PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = gox509.ParsePKCS1PublicKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = gox509.ParseECPrivateKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *gox509.Certificate
+		cert, err = gox509.ParseCertificate(block.Bytes)
+		if err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the PEM encoded key: %w", err)
+	}
+
+	return key, nil
+}
+
+// publicKey returns the public key of the private keys, and other keys as is.
+func publicKey(key any) any {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey
+	case ed25519.PrivateKey:
+		return k.Public()
+	case *ecdh.PrivateKey:
+		return k.PublicKey()
+	default:
+		return key
+	}
+}
+
+func secretKey(key any, alg string) ([]byte, error) {
+	secret, ok := key.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("algorithm %s requires a secret key, got %T", alg, key)
+	}
+
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("algorithm %s requires a non-empty secret key", alg)
+	}
+
+	return secret, nil
+}
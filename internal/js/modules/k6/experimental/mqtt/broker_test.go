@@ -0,0 +1,225 @@
+package mqtt
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// broker is a minimal in-process MQTT broker for the tests, it supports both
+// 3.1.1 and 5 clients, wildcard subscriptions and all QoS levels, but it
+// doesn't keep any session state.
+//
+// Subscribing to topics starting with "forbidden" fails, and so does
+// publishing to them with QoS 1 or 2 for MQTT 5 clients. Clients connecting
+// with the "bad" username are refused.
+type broker struct {
+	t *testing.T
+
+	m             sync.Mutex
+	subscriptions map[*brokerConn]map[string]byte
+}
+
+type brokerConn struct {
+	conn    net.Conn
+	version byte
+	m       sync.Mutex
+	nextID  uint16
+}
+
+func (bc *brokerConn) write(p packet) {
+	b, err := p.encode()
+	if err != nil {
+		panic(err)
+	}
+	bc.m.Lock()
+	defer bc.m.Unlock()
+	_, _ = bc.conn.Write(b)
+}
+
+func newBroker(t *testing.T, l net.Listener) *broker {
+	t.Helper()
+	t.Cleanup(func() { _ = l.Close() })
+
+	b := &broker{t: t, subscriptions: make(map[*brokerConn]map[string]byte)}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *broker) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	r := bufio.NewReader(conn)
+
+	p, err := readPacket(r)
+	if err != nil || p.kind != packetConnect {
+		return
+	}
+	d := &decoder{b: p.body}
+	d.string() // the protocol name
+	bc := &brokerConn{conn: conn, version: d.byte()}
+	flags := d.byte()
+	d.uint16() // the keep alive
+	if bc.version == protocolV5 {
+		d.properties()
+	}
+	d.string() // the client ID
+	var username string
+	if flags&0x80 != 0 {
+		username = d.string()
+	}
+
+	connack := []byte{0, 0}
+	if username == "bad" {
+		connack[1] = 4
+		if bc.version == protocolV5 {
+			connack[1] = 0x86
+		}
+	}
+	if bc.version == protocolV5 {
+		connack = appendProperties(connack, nil)
+	}
+	bc.write(packet{kind: packetConnack, body: connack})
+	if connack[1] != 0 {
+		return
+	}
+
+	b.m.Lock()
+	b.subscriptions[bc] = make(map[string]byte)
+	b.m.Unlock()
+	defer func() {
+		b.m.Lock()
+		delete(b.subscriptions, bc)
+		b.m.Unlock()
+	}()
+
+	for {
+		p, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		if !b.handle(bc, p) {
+			return
+		}
+	}
+}
+
+func (b *broker) handle(bc *brokerConn, p packet) bool {
+	d := &decoder{b: p.body}
+
+	switch p.kind {
+	case packetPublish:
+		m, err := decodePublish(bc.version, p)
+		if err != nil {
+			return false
+		}
+		forbidden := strings.HasPrefix(m.topic, "forbidden") && bc.version == protocolV5
+		ack := appendUint16(nil, m.id)
+		if forbidden {
+			ack = append(ack, 0x87)
+			ack = appendProperties(ack, nil)
+		}
+		switch m.qos {
+		case 1:
+			bc.write(packet{kind: packetPuback, body: ack})
+		case 2:
+			bc.write(packet{kind: packetPubrec, body: ack})
+		}
+		if !forbidden {
+			b.route(m)
+		}
+	case packetPubrel:
+		bc.write(ackPacket(packetPubcomp, d.uint16()))
+	case packetPuback, packetPubrec, packetPubcomp:
+		if p.kind == packetPubrec {
+			bc.write(ackPacket(packetPubrel, d.uint16()))
+		}
+	case packetSubscribe, packetUnsubscribe:
+		id := d.uint16()
+		if bc.version == protocolV5 {
+			d.properties()
+		}
+		topic := d.string()
+		reply := appendUint16(nil, id)
+		if bc.version == protocolV5 {
+			reply = appendProperties(reply, nil)
+		}
+
+		b.m.Lock()
+		if p.kind == packetUnsubscribe {
+			delete(b.subscriptions[bc], topic)
+			if bc.version == protocolV5 {
+				reply = append(reply, 0)
+			}
+			bc.write(packet{kind: packetUnsuback, body: reply})
+		} else {
+			qos := d.byte() & 0x03
+			if strings.HasPrefix(topic, "forbidden") {
+				qos = 0x80
+			} else {
+				b.subscriptions[bc][topic] = qos
+			}
+			bc.write(packet{kind: packetSuback, body: append(reply, qos)})
+		}
+		b.m.Unlock()
+	case packetPingreq:
+		bc.write(packet{kind: packetPingresp})
+	case packetDisconnect:
+		return false
+	default:
+		b.t.Errorf("unexpected packet of type %d", p.kind)
+		return false
+	}
+	return true
+}
+
+// route delivers the message to all the matching subscriptions, with the
+// smallest QoS between the published and the subscribed one.
+func (b *broker) route(m *incomingMessage) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	for bc, filters := range b.subscriptions {
+		for filter, qos := range filters {
+			if !topicMatches(filter, m.topic) {
+				continue
+			}
+			out := &outgoingMessage{
+				topic:          m.topic,
+				payload:        m.payload,
+				qos:            min(qos, m.qos),
+				retain:         m.retain,
+				userProperties: m.userProperties,
+			}
+			bc.m.Lock()
+			bc.nextID++
+			id := bc.nextID
+			bc.m.Unlock()
+			bc.write(publishPacket(bc.version, out, id))
+			break
+		}
+	}
+}
+
+func topicMatches(filter, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) || (level != "+" && level != topicLevels[i]) {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
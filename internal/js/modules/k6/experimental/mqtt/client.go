@@ -0,0 +1,858 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/socket"
+	"go.k6.io/k6/v2/internal/js/taskqueue"
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/metrics"
+)
+
+// publishedAtProperty is the MQTT 5 user property with the publishing time of
+// a message, in microseconds since the Unix epoch.
+const publishedAtProperty = "k6-published-at"
+
+var errClosed = errors.New("the MQTT connection is closed")
+
+type clientState int
+
+const (
+	stateDisconnected clientState = iota
+	stateConnecting
+	stateConnected
+	stateClosing
+	stateClosed
+)
+
+type client struct {
+	vu        modules.VU
+	metrics   *instanceMetrics
+	params    *clientParams
+	listeners eventListeners
+	obj       *sobek.Object // the object that is given to js to interact with the client
+
+	// fields that should only be accessed on the event loop
+	state clientState
+
+	// fields set when the connection is established, before any goroutine using them is started
+	tq          *taskqueue.TaskQueue
+	tagsAndMeta *metrics.TagsAndMeta
+	conn        net.Conn
+	reader      *bufio.Reader
+	done        chan struct{}
+	wake        chan struct{}
+
+	m        sync.Mutex
+	closed   bool
+	closing  bool
+	nextID   uint16
+	pending  map[uint16]*inflight
+	received map[uint16]struct{} // the IDs of the incoming QoS 2 messages waiting for a PUBREL
+	writes   []writeRequest
+}
+
+// inflight is an operation waiting for its acknowledgement from the broker.
+type inflight struct {
+	expect  byte // the type of the packet which completes the operation
+	started time.Time
+	publish bool
+	// finish resolves or rejects the operation's promise, on the event loop
+	finish func(reason byte, reasonString string, err error) error
+}
+
+type writeRequest struct {
+	p packet
+	// written is called after the packet was written, outside of the event loop
+	written func(error)
+}
+
+type outgoingMessage struct {
+	topic          string
+	payload        []byte
+	qos            byte
+	retain         bool
+	userProperties map[string]string
+}
+
+// defineClient defines all properties and methods for the Client
+func defineClient(rt *sobek.Runtime, c *client) {
+	methods := map[string]any{
+		"connect":          c.connect,
+		"publish":          c.publish,
+		"subscribe":        c.subscribe,
+		"unsubscribe":      c.unsubscribe,
+		"close":            c.close,
+		"addEventListener": c.addEventListener,
+	}
+	for name, method := range methods {
+		must(rt, c.obj.DefineDataProperty(name, rt.ToValue(method), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	}
+	must(rt, c.obj.DefineDataProperty(
+		"clientId", rt.ToValue(c.params.clientID), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, c.obj.DefineAccessorProperty(
+		"connected", rt.ToValue(func() bool { return c.state == stateConnected }), nil,
+		sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+	c.listeners.defineOn(rt, c.obj, eventMessage)
+	c.listeners.defineOn(rt, c.obj, eventError)
+	c.listeners.defineOn(rt, c.obj, eventClose)
+}
+
+// connect connects to the broker at the provided URL, the mqtt:// and tcp://
+// schemes are used for plain connections, and mqtts://, ssl:// and tls:// for
+// TLS ones.
+func (c *client) connect(rawURL string) *sobek.Promise {
+	rt := c.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	state := c.vu.State()
+	if state == nil {
+		must(rt, reject(errors.New("connecting is only allowed in the VU context")))
+		return promise
+	}
+	if c.state != stateDisconnected {
+		must(rt, reject(errors.New("the client can only be connected once")))
+		return promise
+	}
+
+	addr, secure, err := parseBrokerURL(rawURL)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	if !common.IsNullish(c.params.tags) {
+		if err = common.ApplyCustomUserTags(rt, &tagsAndMeta, c.params.tags); err != nil {
+			must(rt, reject(fmt.Errorf("invalid client tags option: %w", err)))
+			return promise
+		}
+	}
+	c.tagsAndMeta = &tagsAndMeta
+	c.state = stateConnecting
+
+	ctx := c.vu.Context()
+	callback := c.vu.RegisterCallback()
+	go func() {
+		conn, reader, err := c.dial(ctx, state, addr, secure)
+		callback(func() error {
+			if err != nil {
+				c.state = stateClosed
+				return reject(err)
+			}
+			c.start(ctx, conn, reader)
+			return resolve(sobek.Undefined())
+		})
+	}()
+
+	return promise
+}
+
+func parseBrokerURL(rawURL string) (string, bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid broker URL: %w", err)
+	}
+
+	var secure bool
+	var port string
+	switch u.Scheme {
+	case "mqtt", "tcp":
+		port = "1883"
+	case "mqtts", "ssl", "tls":
+		secure, port = true, "8883"
+	default:
+		return "", false, fmt.Errorf(
+			"invalid broker URL scheme %q, the supported ones are mqtt, tcp, mqtts, ssl and tls", u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+
+	return net.JoinHostPort(u.Hostname(), port), secure, nil
+}
+
+// dial establishes the connection, and waits for the broker to accept it.
+func (c *client) dial(
+	ctx context.Context, state *lib.State, addr string, secure bool,
+) (net.Conn, *bufio.Reader, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, c.params.connectTimeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, reader, err := func() (net.Conn, *bufio.Reader, error) {
+		conn, err := state.Dialer.DialContext(dialCtx, "tcp", addr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ip, _, splitErr := net.SplitHostPort(conn.RemoteAddr().String()); splitErr == nil {
+			c.tagsAndMeta.SetSystemTagOrMetaIfEnabled(state.Options.SystemTags, metrics.TagIP, ip)
+		}
+
+		if secure {
+			if conn, err = c.handshake(dialCtx, state, conn, addr); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		deadline, _ := dialCtx.Deadline()
+		_ = conn.SetDeadline(deadline)
+		reader := bufio.NewReader(conn)
+		if err = c.connectHandshake(conn, reader); err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+		_ = conn.SetDeadline(time.Time{})
+
+		return conn, reader, nil
+	}()
+	end := time.Now()
+
+	if err != nil {
+		c.emit(ctx, c.metrics.Errors, end, 1)
+		return nil, nil, err
+	}
+	c.emit(ctx, c.metrics.Connecting, end, metrics.D(end.Sub(start)))
+
+	return conn, reader, nil
+}
+
+// handshake upgrades the connection to TLS, using the VU's TLS configuration
+// with the client certificate from the options, if one was provided.
+func (c *client) handshake(ctx context.Context, state *lib.State, conn net.Conn, addr string) (net.Conn, error) {
+	var config *tls.Config
+	if state.TLSConfig != nil {
+		config = state.TLSConfig.Clone()
+	} else {
+		config = &tls.Config{} //nolint:gosec // the defaults from the options are used when set
+	}
+	config.NextProtos = nil
+
+	config.ServerName = c.params.tls.serverName
+	if config.ServerName == "" {
+		config.ServerName, _, _ = net.SplitHostPort(addr)
+	}
+	if c.params.tls.auth != nil {
+		cert, err := c.params.tls.auth.Certificate()
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		config.Certificates = append([]tls.Certificate{*cert}, config.Certificates...)
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// connectHandshake sends the CONNECT packet and waits for the CONNACK.
+func (c *client) connectHandshake(conn net.Conn, reader *bufio.Reader) error {
+	b, err := connectPacket(c.params).encode()
+	if err != nil {
+		return err
+	}
+	if _, err = conn.Write(b); err != nil {
+		return err
+	}
+
+	p, err := readPacket(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read the CONNACK packet: %w", err)
+	}
+	return decodeConnack(c.params.version, p)
+}
+
+// start starts the goroutines handling the established connection, it needs
+// to be called on the event loop.
+func (c *client) start(ctx context.Context, conn net.Conn, reader *bufio.Reader) {
+	c.state = stateConnected
+	c.conn = conn
+	c.reader = reader
+	c.tq = taskqueue.New(c.vu.RegisterCallback)
+	c.done = make(chan struct{})
+	c.wake = make(chan struct{}, 1)
+	c.pending = make(map[uint16]*inflight)
+	c.received = make(map[uint16]struct{})
+
+	go c.readLoop(ctx)
+	go c.writeLoop()
+	go func() {
+		var ping <-chan time.Time
+		if c.params.keepAlive > 0 {
+			ticker := time.NewTicker(c.params.keepAlive)
+			defer ticker.Stop()
+			ping = ticker.C
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				_ = c.conn.Close()
+				return
+			case <-c.done:
+				return
+			case <-ping:
+				_ = c.enqueue(packet{kind: packetPingreq}, nil)
+			}
+		}
+	}()
+}
+
+// enqueue queues the packet to be written, without blocking the event loop.
+func (c *client) enqueue(p packet, written func(error)) error {
+	c.m.Lock()
+	if c.closed {
+		c.m.Unlock()
+		return errClosed
+	}
+	c.writes = append(c.writes, writeRequest{p: p, written: written})
+	c.m.Unlock()
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *client) writeLoop() {
+	for {
+		select {
+		case <-c.wake:
+		case <-c.done:
+			return
+		}
+
+		c.m.Lock()
+		writes := c.writes
+		c.writes = nil
+		c.m.Unlock()
+
+		for _, w := range writes {
+			b, err := w.p.encode()
+			if err == nil {
+				_, err = c.conn.Write(b)
+			}
+			if w.written != nil {
+				w.written(err)
+			}
+			if err != nil {
+				// the read loop will fail and clean up everything
+				_ = c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// readLoop handles the packets from the broker until the connection is closed,
+// and then rejects all the pending operations and emits the close event.
+func (c *client) readLoop(ctx context.Context) {
+	var err error
+	for err == nil {
+		var p packet
+		if p, err = readPacket(c.reader); err == nil {
+			err = c.handle(ctx, p)
+		}
+	}
+
+	c.m.Lock()
+	c.closed = true
+	expected := c.closing || ctx.Err() != nil
+	pending := c.pending
+	c.pending = nil
+	writes := c.writes
+	c.writes = nil
+	c.m.Unlock()
+	close(c.done)
+	_ = c.conn.Close()
+
+	for _, w := range writes {
+		if w.written != nil {
+			w.written(errClosed)
+		}
+	}
+	if !expected {
+		c.emit(ctx, c.metrics.Errors, time.Now(), 1)
+	}
+
+	c.tq.Queue(func() error {
+		c.state = stateClosed
+		for _, f := range pending {
+			if ferr := f.finish(0, "", errClosed); ferr != nil {
+				return ferr
+			}
+		}
+		if !expected {
+			if lerr := c.callErrorListeners(err); lerr != nil {
+				return lerr
+			}
+		}
+		return c.callEventListeners(eventClose, c.newEvent(eventClose, time.Now()))
+	})
+	c.tq.Close()
+}
+
+func (c *client) handle(ctx context.Context, p packet) error {
+	version := c.params.version
+
+	switch p.kind {
+	case packetPublish:
+		m, err := decodePublish(version, p)
+		if err != nil {
+			return err
+		}
+		return c.handlePublish(ctx, m)
+	case packetPuback, packetPubcomp, packetSuback, packetUnsuback:
+		id, reason, reasonString, err := decodeAck(version, p)
+		if err != nil {
+			return err
+		}
+		c.complete(ctx, id, p.kind, reason, reasonString)
+	case packetPubrec:
+		id, reason, reasonString, err := decodeAck(version, p)
+		if err != nil {
+			return err
+		}
+		if reason >= 0x80 {
+			c.complete(ctx, id, p.kind, reason, reasonString)
+			return nil
+		}
+		c.m.Lock()
+		if f, ok := c.pending[id]; ok && f.expect == packetPubrec {
+			f.expect = packetPubcomp
+		}
+		c.m.Unlock()
+		return c.enqueue(ackPacket(packetPubrel, id), nil)
+	case packetPubrel:
+		id, _, _, err := decodeAck(version, p)
+		if err != nil {
+			return err
+		}
+		c.m.Lock()
+		delete(c.received, id)
+		c.m.Unlock()
+		return c.enqueue(ackPacket(packetPubcomp, id), nil)
+	case packetPingresp:
+	case packetDisconnect:
+		d := &decoder{b: p.body}
+		var reason byte
+		var props properties
+		if d.remaining() > 0 {
+			reason = d.byte()
+			if d.remaining() > 0 {
+				props = d.properties()
+			}
+		}
+		return reasonError("disconnected by the broker", reason, props.reasonString)
+	default:
+		return fmt.Errorf("unexpected MQTT packet of type %d", p.kind)
+	}
+
+	return nil
+}
+
+// handlePublish acknowledges the incoming message as required by its QoS, and
+// delivers it to the message listeners.
+func (c *client) handlePublish(ctx context.Context, m *incomingMessage) error {
+	received := time.Now()
+
+	switch m.qos {
+	case 1:
+		if err := c.enqueue(ackPacket(packetPuback, m.id), nil); err != nil {
+			return err
+		}
+	case 2:
+		c.m.Lock()
+		_, duplicate := c.received[m.id]
+		c.received[m.id] = struct{}{}
+		c.m.Unlock()
+		if err := c.enqueue(ackPacket(packetPubrec, m.id), nil); err != nil {
+			return err
+		}
+		if duplicate {
+			return nil
+		}
+	}
+
+	c.emit(ctx, c.metrics.MessagesReceived, received, 1)
+	if v, ok := m.userProperties[publishedAtProperty]; ok {
+		if us, err := strconv.ParseInt(v, 10, 64); err == nil {
+			if latency := received.Sub(time.UnixMicro(us)); latency >= 0 {
+				c.emit(ctx, c.metrics.DeliveryDuration, received, metrics.D(latency))
+			}
+		}
+	}
+
+	c.tq.Queue(func() error {
+		if c.state != stateConnected && c.state != stateClosing {
+			return nil
+		}
+		return c.callEventListeners(eventMessage, c.newMessageEvent(m, received))
+	})
+	return nil
+}
+
+// complete finishes the pending operation with the packet ID, if it is waiting
+// for the provided packet type.
+func (c *client) complete(ctx context.Context, id uint16, kind byte, reason byte, reasonString string) {
+	c.m.Lock()
+	f, ok := c.pending[id]
+	if !ok || f.expect != kind {
+		c.m.Unlock()
+		return
+	}
+	delete(c.pending, id)
+	c.m.Unlock()
+
+	now := time.Now()
+	if reason >= 0x80 {
+		c.emit(ctx, c.metrics.Errors, now, 1)
+	} else if f.publish {
+		c.emit(ctx, c.metrics.PublishAckDuration, now, metrics.D(now.Sub(f.started)))
+	}
+
+	c.tq.Queue(func() error {
+		return f.finish(reason, reasonString, nil)
+	})
+}
+
+// track registers the operation and returns its packet ID.
+func (c *client) track(f *inflight) (uint16, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.closed {
+		return 0, errClosed
+	}
+	for range 0xFFFF {
+		c.nextID++
+		if c.nextID == 0 {
+			c.nextID = 1
+		}
+		if _, used := c.pending[c.nextID]; !used {
+			c.pending[c.nextID] = f
+			return c.nextID, nil
+		}
+	}
+	return 0, errors.New("too many MQTT operations waiting for an acknowledgement")
+}
+
+// publish publishes the payload to the topic, the returned promise resolves
+// when the message is written for QoS 0, and when it is acknowledged by the
+// broker for QoS 1 and 2.
+func (c *client) publish(topic string, payload sobek.Value, params sobek.Value) *sobek.Promise {
+	rt := c.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	if c.state != stateConnected {
+		must(rt, reject(errClosed))
+		return promise
+	}
+
+	m, err := c.parsePublishParams(topic, payload, params)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+	if c.params.version == protocolV5 {
+		m.userProperties[publishedAtProperty] = strconv.FormatInt(time.Now().UnixMicro(), 10)
+	}
+
+	ctx := c.vu.Context()
+	written := func(err error) {
+		if err != nil {
+			return
+		}
+		c.emit(ctx, c.metrics.MessagesSent, time.Now(), 1)
+	}
+
+	if m.qos == 0 {
+		err = c.enqueue(publishPacket(c.params.version, m, 0), func(err error) {
+			written(err)
+			c.tq.Queue(func() error {
+				if err != nil {
+					return reject(err)
+				}
+				return resolve(sobek.Undefined())
+			})
+		})
+		if err != nil {
+			must(rt, reject(err))
+		}
+		return promise
+	}
+
+	expect := packetPuback
+	if m.qos == 2 {
+		expect = packetPubrec
+	}
+	id, err := c.track(&inflight{
+		expect:  expect,
+		started: time.Now(),
+		publish: true,
+		finish: func(reason byte, reasonString string, err error) error {
+			if err != nil {
+				return reject(err)
+			}
+			if reason >= 0x80 {
+				return reject(reasonError("publish failed", reason, reasonString))
+			}
+			return resolve(sobek.Undefined())
+		},
+	})
+	if err == nil {
+		err = c.enqueue(publishPacket(c.params.version, m, id), written)
+	}
+	if err != nil {
+		must(rt, reject(err))
+	}
+
+	return promise
+}
+
+func (c *client) parsePublishParams(topic string, payload sobek.Value, raw sobek.Value) (*outgoingMessage, error) {
+	rt := c.vu.Runtime()
+
+	b, err := socket.ToBytes(rt, payload)
+	if err != nil {
+		return nil, err
+	}
+	m := &outgoingMessage{topic: topic, payload: b, userProperties: make(map[string]string)}
+	if topic == "" {
+		return nil, errors.New("the topic can't be empty")
+	}
+
+	if common.IsNullish(raw) {
+		return m, nil
+	}
+	obj := raw.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "qos":
+			if m.qos, err = parseQoS(v); err != nil {
+				return nil, err
+			}
+		case "retain":
+			m.retain = v.ToBoolean()
+		case "userProperties":
+			if c.params.version != protocolV5 {
+				return nil, errors.New("user properties are only supported by MQTT 5")
+			}
+			props := v.ToObject(rt)
+			for _, name := range props.Keys() {
+				m.userProperties[name] = props.Get(name).String()
+			}
+		default:
+			return nil, fmt.Errorf("unknown publish option %s", k)
+		}
+	}
+
+	return m, nil
+}
+
+func parseQoS(v sobek.Value) (byte, error) {
+	qos := v.ToInteger()
+	if qos < 0 || qos > 2 {
+		return 0, fmt.Errorf("invalid QoS %d, it should be 0, 1 or 2", qos)
+	}
+	return byte(qos), nil
+}
+
+// subscribe subscribes to the topic filter, the returned promise resolves with
+// the QoS granted by the broker.
+func (c *client) subscribe(topic string, params sobek.Value) *sobek.Promise {
+	rt := c.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	if c.state != stateConnected {
+		must(rt, reject(errClosed))
+		return promise
+	}
+
+	var qos byte
+	if !common.IsNullish(params) {
+		obj := params.ToObject(rt)
+		for _, k := range obj.Keys() {
+			var err error
+			switch k {
+			case "qos":
+				qos, err = parseQoS(obj.Get(k))
+			default:
+				err = fmt.Errorf("unknown subscribe option %s", k)
+			}
+			if err != nil {
+				must(rt, reject(err))
+				return promise
+			}
+		}
+	}
+
+	id, err := c.track(&inflight{
+		expect:  packetSuback,
+		started: time.Now(),
+		finish: func(reason byte, reasonString string, err error) error {
+			if err != nil {
+				return reject(err)
+			}
+			if reason >= 0x80 {
+				return reject(reasonError(fmt.Sprintf("subscribing to %q failed", topic), reason, reasonString))
+			}
+			return resolve(reason)
+		},
+	})
+	if err == nil {
+		err = c.enqueue(subscribePacket(c.params.version, id, topic, qos), nil)
+	}
+	if err != nil {
+		must(rt, reject(err))
+	}
+
+	return promise
+}
+
+// unsubscribe unsubscribes from the topic filter.
+func (c *client) unsubscribe(topic string) *sobek.Promise {
+	rt := c.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	if c.state != stateConnected {
+		must(rt, reject(errClosed))
+		return promise
+	}
+
+	id, err := c.track(&inflight{
+		expect:  packetUnsuback,
+		started: time.Now(),
+		finish: func(reason byte, reasonString string, err error) error {
+			if err != nil {
+				return reject(err)
+			}
+			if reason >= 0x80 {
+				return reject(reasonError(fmt.Sprintf("unsubscribing from %q failed", topic), reason, reasonString))
+			}
+			return resolve(sobek.Undefined())
+		},
+	})
+	if err == nil {
+		err = c.enqueue(unsubscribePacket(c.params.version, id, topic), nil)
+	}
+	if err != nil {
+		must(rt, reject(err))
+	}
+
+	return promise
+}
+
+// close sends a DISCONNECT packet and closes the connection, the close event
+// is emitted once it's done.
+func (c *client) close() {
+	if c.state != stateConnected {
+		return
+	}
+	c.state = stateClosing
+
+	c.m.Lock()
+	c.closing = true
+	c.m.Unlock()
+
+	err := c.enqueue(packet{kind: packetDisconnect}, func(error) {
+		_ = c.conn.Close()
+	})
+	if err != nil {
+		_ = c.conn.Close()
+	}
+}
+
+func (c *client) addEventListener(event string, handler func(sobek.Value) (sobek.Value, error)) {
+	if handler == nil {
+		common.Throw(c.vu.Runtime(), fmt.Errorf("handler for event type %q isn't a callable function", event))
+	}
+
+	if err := c.listeners.add(event, handler); err != nil {
+		common.Throw(c.vu.Runtime(), err)
+	}
+}
+
+// newEvent returns an event object, it needs to be called on the event loop.
+func (c *client) newEvent(eventType string, t time.Time) *sobek.Object {
+	rt := c.vu.Runtime()
+	o := rt.NewObject()
+
+	must(rt, o.DefineDataProperty("type", rt.ToValue(eventType), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, o.DefineDataProperty("target", c.obj, sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, o.DefineDataProperty("timestamp",
+		rt.ToValue(float64(t.UnixNano())/1_000_000), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+
+	return o
+}
+
+func (c *client) newMessageEvent(m *incomingMessage, t time.Time) *sobek.Object {
+	rt := c.vu.Runtime()
+	ev := c.newEvent(eventMessage, t)
+
+	userProperties := m.userProperties
+	if userProperties == nil {
+		userProperties = map[string]string{}
+	}
+	fields := map[string]any{
+		"topic":          m.topic,
+		"payload":        string(m.payload),
+		"rawPayload":     rt.NewArrayBuffer(m.payload),
+		"qos":            m.qos,
+		"retain":         m.retain,
+		"duplicate":      m.duplicate,
+		"userProperties": userProperties,
+	}
+	for k, v := range fields {
+		must(rt, ev.DefineDataProperty(k, rt.ToValue(v), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	}
+
+	return ev
+}
+
+func (c *client) callErrorListeners(e error) error {
+	rt := c.vu.Runtime()
+	ev := c.newEvent(eventError, time.Now())
+	must(rt, ev.DefineDataProperty("error", rt.ToValue(e.Error()), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	return c.callEventListeners(eventError, ev)
+}
+
+func (c *client) callEventListeners(eventType string, ev *sobek.Object) error {
+	for _, l := range c.listeners.all(eventType) {
+		if _, err := l(ev); err != nil {
+			if eventType != eventClose {
+				c.close()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *client) emit(ctx context.Context, metric *metrics.Metric, t time.Time, value float64) {
+	metrics.PushIfNotDone(ctx, c.vu.State().Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: metric, Tags: c.tagsAndMeta.Tags},
+		Time:       t,
+		Metadata:   c.tagsAndMeta.Metadata,
+		Value:      value,
+	})
+}
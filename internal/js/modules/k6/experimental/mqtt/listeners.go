@@ -0,0 +1,94 @@
+package mqtt
+
+import (
+	"fmt"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+)
+
+// The events emitted by the clients.
+const (
+	eventMessage = "message"
+	eventError   = "error"
+	eventClose   = "close"
+)
+
+type listener func(sobek.Value) (sobek.Value, error)
+
+// eventListener keeps the listeners of an event type, on is the one set
+// through the on* properties, like onmessage.
+type eventListener struct {
+	on   listener
+	list []listener
+}
+
+func (l *eventListener) all() []listener {
+	if l.on == nil {
+		return l.list
+	}
+	return append([]listener{l.on}, l.list...)
+}
+
+// eventListeners keeps track of the listeners for each event type, it should
+// only be accessed on the event loop.
+type eventListeners map[string]*eventListener
+
+func newEventListeners() eventListeners {
+	return eventListeners{
+		eventMessage: {},
+		eventError:   {},
+		eventClose:   {},
+	}
+}
+
+func (l eventListeners) add(eventType string, fn listener) error {
+	el, ok := l[eventType]
+	if !ok {
+		return fmt.Errorf("unknown event type %s", eventType)
+	}
+	el.list = append(el.list, fn)
+	return nil
+}
+
+func (l eventListeners) all(eventType string) []listener {
+	if el, ok := l[eventType]; ok {
+		return el.all()
+	}
+	return nil
+}
+
+// defineOn defines the on* property of the object for the event type.
+func (l eventListeners) defineOn(rt *sobek.Runtime, obj *sobek.Object, eventType string) {
+	el := l[eventType]
+	property := "on" + eventType
+
+	must(rt, obj.DefineAccessorProperty(
+		property, rt.ToValue(func() sobek.Value {
+			return rt.ToValue(el.on)
+		}), rt.ToValue(func(call sobek.FunctionCall) sobek.Value {
+			arg := call.Argument(0)
+
+			// it's possible to unset handlers by setting them to null
+			if common.IsNullish(arg) {
+				el.on = nil
+				return nil
+			}
+
+			fn, isFunc := sobek.AssertFunction(arg)
+			if !isFunc {
+				common.Throw(rt, fmt.Errorf("a value for '%s' should be callable", property))
+			}
+			el.on = func(v sobek.Value) (sobek.Value, error) { return fn(sobek.Undefined(), v) }
+
+			return nil
+		}), sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+}
+
+// must is a small helper that will panic if err is not nil.
+func must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
@@ -0,0 +1,46 @@
+package mqtt
+
+import "go.k6.io/k6/v2/metrics"
+
+// instanceMetrics contains the metrics for the mqtt module.
+type instanceMetrics struct {
+	Connecting         *metrics.Metric
+	PublishAckDuration *metrics.Metric
+	DeliveryDuration   *metrics.Metric
+	MessagesSent       *metrics.Metric
+	MessagesReceived   *metrics.Metric
+	Errors             *metrics.Metric
+}
+
+// registerMetrics registers and returns the metrics in the provided registry
+func registerMetrics(registry *metrics.Registry) (*instanceMetrics, error) {
+	var err error
+	m := &instanceMetrics{}
+
+	if m.Connecting, err = registry.NewMetric("mqtt_connecting", metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	if m.PublishAckDuration, err = registry.NewMetric(
+		"mqtt_publish_ack_duration", metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	if m.DeliveryDuration, err = registry.NewMetric("mqtt_delivery_duration", metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	if m.MessagesSent, err = registry.NewMetric("mqtt_messages_sent", metrics.Counter); err != nil {
+		return nil, err
+	}
+
+	if m.MessagesReceived, err = registry.NewMetric("mqtt_messages_received", metrics.Counter); err != nil {
+		return nil, err
+	}
+
+	if m.Errors, err = registry.NewMetric("mqtt_errors", metrics.Counter); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
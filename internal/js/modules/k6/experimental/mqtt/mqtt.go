@@ -0,0 +1,225 @@
+// Package mqtt implements the k6/experimental/mqtt module, an MQTT 3.1.1 and
+// 5 client for publishing and subscribing to the topics of a broker.
+//
+// The end-to-end delivery latency is measured by adding the publishing time as
+// a user property of the messages, so it is only available with MQTT 5, and
+// when the publishing and the subscribed clients are both k6 ones.
+package mqtt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/types"
+)
+
+const (
+	defaultKeepAlive      = 60 * time.Second
+	defaultConnectTimeout = 60 * time.Second
+)
+
+type (
+	// RootModule is the global module instance that will create module
+	// instances for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the mqtt module for every VU.
+	ModuleInstance struct {
+		vu      modules.VU
+		metrics *instanceMetrics
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// New returns a pointer to a new RootModule instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface to return
+// a new instance for each VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	metrics, err := registerMetrics(vu.InitEnv().Registry)
+	if err != nil {
+		common.Throw(vu.Runtime(), fmt.Errorf("failed to register mqtt module metrics: %w", err))
+	}
+
+	return &ModuleInstance{vu: vu, metrics: metrics}
+}
+
+// Exports returns the exports of the mqtt module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"Client": mi.newClient,
+		},
+	}
+}
+
+type clientParams struct {
+	version        byte
+	clientID       string
+	username       string
+	password       string
+	keepAlive      time.Duration
+	cleanSession   bool
+	connectTimeout time.Duration
+	tls            tlsParams
+	tags           sobek.Value
+}
+
+type tlsParams struct {
+	serverName string
+	// auth is the client certificate presented to the broker, it's used in
+	// addition to the ones from the tlsAuth option.
+	auth *lib.TLSAuth
+}
+
+// newClient is the constructor of the Client objects. The clients can be
+// created in the init context, but they can only connect in the VU one.
+func (mi *ModuleInstance) newClient(c sobek.ConstructorCall) *sobek.Object {
+	rt := mi.vu.Runtime()
+
+	params, err := mi.parseClientParams(c.Argument(0))
+	if err != nil {
+		common.Throw(rt, err)
+	}
+
+	cl := &client{
+		vu:        mi.vu,
+		metrics:   mi.metrics,
+		params:    params,
+		listeners: newEventListeners(),
+		obj:       rt.NewObject(),
+	}
+	defineClient(rt, cl)
+
+	return cl.obj
+}
+
+func (mi *ModuleInstance) parseClientParams(raw sobek.Value) (*clientParams, error) {
+	params := &clientParams{
+		version:        protocolV5,
+		keepAlive:      defaultKeepAlive,
+		cleanSession:   true,
+		connectTimeout: defaultConnectTimeout,
+	}
+
+	if !common.IsNullish(raw) {
+		rt := mi.vu.Runtime()
+		obj := raw.ToObject(rt)
+		for _, k := range obj.Keys() {
+			v := obj.Get(k)
+			var err error
+			switch k {
+			case "version":
+				params.version, err = parseVersion(v)
+			case "clientId":
+				params.clientID = v.String()
+			case "username":
+				params.username = v.String()
+			case "password":
+				params.password = v.String()
+			case "keepAlive":
+				params.keepAlive, err = parseDuration(v, "keepAlive")
+				if err == nil && params.keepAlive > 0xFFFF*time.Second {
+					err = fmt.Errorf("keepAlive can be at most %s, but got %s", 0xFFFF*time.Second, params.keepAlive)
+				}
+			case "cleanSession":
+				params.cleanSession = v.ToBoolean()
+			case "connectTimeout":
+				params.connectTimeout, err = parseDuration(v, "connectTimeout")
+				if err == nil && params.connectTimeout <= 0 {
+					err = fmt.Errorf("connectTimeout should be positive, but got %s", params.connectTimeout)
+				}
+			case "tls":
+				params.tls, err = parseTLSParams(rt, v)
+			case "tags":
+				params.tags = v
+			default:
+				err = fmt.Errorf("unknown client option %s", k)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if params.clientID == "" {
+		var id [8]byte
+		if _, err := rand.Read(id[:]); err != nil {
+			return nil, err
+		}
+		params.clientID = "k6-" + hex.EncodeToString(id[:])
+	}
+
+	return params, nil
+}
+
+// parseVersion accepts both the names of the versions and the protocol levels.
+func parseVersion(v sobek.Value) (byte, error) {
+	switch v.String() {
+	case "3.1.1", "4":
+		return protocolV311, nil
+	case "5", "5.0":
+		return protocolV5, nil
+	default:
+		return 0, fmt.Errorf(`unsupported MQTT version %q, the supported ones are "3.1.1" and "5"`, v.String())
+	}
+}
+
+func parseDuration(v sobek.Value, name string) (time.Duration, error) {
+	d, err := types.GetDurationValue(v.Export())
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s can't be negative, but got %s", name, d)
+	}
+	return d, nil
+}
+
+func parseTLSParams(rt *sobek.Runtime, raw sobek.Value) (tlsParams, error) {
+	var params tlsParams
+	if common.IsNullish(raw) {
+		return params, nil
+	}
+
+	var fields lib.TLSAuthFields
+	obj := raw.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "serverName":
+			params.serverName = v.String()
+		case "cert":
+			fields.Cert = v.String()
+		case "key":
+			fields.Key = v.String()
+		case "password":
+			fields.Password.String, fields.Password.Valid = v.String(), true
+		default:
+			return params, fmt.Errorf("unknown TLS option %s", k)
+		}
+	}
+
+	if fields.Cert == "" && fields.Key == "" {
+		return params, nil
+	}
+	params.auth = &lib.TLSAuth{TLSAuthFields: fields}
+	if _, err := params.auth.Certificate(); err != nil {
+		return params, fmt.Errorf("invalid TLS client certificate: %w", err)
+	}
+	return params, nil
+}
@@ -0,0 +1,331 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils/mockresolver"
+	"go.k6.io/k6/v2/js/modulestest"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/netext"
+	"go.k6.io/k6/v2/metrics"
+)
+
+type testState struct {
+	runtime *modulestest.Runtime
+	samples chan metrics.SampleContainer
+}
+
+func newTestState(t *testing.T, tlsConfig *tls.Config) *testState {
+	t.Helper()
+
+	runtime := modulestest.NewRuntime(t)
+	samples := make(chan metrics.SampleContainer, 1000)
+
+	m := new(RootModule).NewModuleInstance(runtime.VU)
+	require.NoError(t, runtime.VU.Runtime().Set("mqtt", m.Exports().Named))
+
+	runtime.MoveToVUContext(&lib.State{
+		Dialer: netext.NewDialer(net.Dialer{}, mockresolver.New(map[string][]net.IP{
+			"broker.k6.io": {net.ParseIP("127.0.0.1")},
+		})),
+		TLSConfig: tlsConfig,
+		Options: lib.Options{
+			SystemTags: metrics.NewSystemTagSet(metrics.TagIP),
+		},
+		Samples:        samples,
+		BuiltinMetrics: runtime.BuiltinMetrics,
+		Tags:           lib.NewVUStateTags(runtime.VU.InitEnvField.Registry.RootTagSet()),
+	})
+
+	return &testState{runtime: runtime, samples: samples}
+}
+
+func startBroker(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	newBroker(t, l)
+	return "mqtt://" + l.Addr().String()
+}
+
+func metricCounts(sampleContainers []metrics.SampleContainer) map[string]int {
+	counts := make(map[string]int)
+	for _, sampleContainer := range sampleContainers {
+		for _, sample := range sampleContainer.GetSamples() {
+			counts[sample.Metric.Name]++
+		}
+	}
+	return counts
+}
+
+const publishSubscribeScript = `(async () => {
+	const client = new mqtt.Client({ version: VERSION, keepAlive: "1s", tags: { tag: "value" } })
+	const received = []
+	const allReceived = new Promise((resolve) => {
+		client.onmessage = (e) => {
+			received.push(e)
+			if (received.length === 3) {
+				resolve()
+			}
+		}
+	})
+	const closed = new Promise((resolve) => client.addEventListener("close", resolve))
+	client.addEventListener("error", (e) => { throw new Error(e.error) })
+
+	await client.connect(BROKER)
+	if (!client.connected) {
+		throw new Error("the client should be connected")
+	}
+	const granted = await client.subscribe("sensors/+/temperature", { qos: 2 })
+	if (granted !== 2) {
+		throw new Error("unexpected granted QoS " + granted)
+	}
+
+	await client.publish("sensors/1/temperature", "21.5")
+	await client.publish("sensors/2/temperature", "22", { qos: 1 })
+	await client.publish("sensors/3/temperature", new Uint8Array([50, 51]), { qos: 2, retain: true })
+	await client.publish("sensors/4/humidity", "ignored", { qos: 1 })
+	await allReceived
+
+	const payloads = received.map((e) => e.topic + "=" + e.payload).sort().join(",")
+	if (payloads !== "sensors/1/temperature=21.5,sensors/2/temperature=22,sensors/3/temperature=23") {
+		throw new Error("unexpected messages " + payloads)
+	}
+	const last = received.find((e) => e.topic === "sensors/3/temperature")
+	if (last.qos !== 2 || !last.retain || last.type !== "message" || last.rawPayload.byteLength !== 2) {
+		throw new Error("unexpected message fields " + JSON.stringify(last))
+	}
+
+	await client.unsubscribe("sensors/+/temperature")
+	client.close()
+	await closed
+	if (client.connected) {
+		throw new Error("the client should be disconnected")
+	}
+})()`
+
+func TestPublishSubscribe(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version   string
+		delivered int
+	}{
+		{version: "3.1.1", delivered: 0},
+		{version: "5", delivered: 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.version, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestState(t, nil)
+			require.NoError(t, ts.runtime.VU.Runtime().Set("BROKER", startBroker(t)))
+			require.NoError(t, ts.runtime.VU.Runtime().Set("VERSION", tc.version))
+
+			_, err := ts.runtime.RunOnEventLoop(publishSubscribeScript)
+			require.NoError(t, err)
+
+			samples := metrics.GetBufferedSamples(ts.samples)
+			counts := metricCounts(samples)
+			assert.Equal(t, 1, counts["mqtt_connecting"])
+			assert.Equal(t, 4, counts["mqtt_messages_sent"])
+			assert.Equal(t, 3, counts["mqtt_messages_received"])
+			assert.Equal(t, 3, counts["mqtt_publish_ack_duration"])
+			assert.Equal(t, tc.delivered, counts["mqtt_delivery_duration"])
+			assert.Zero(t, counts["mqtt_errors"])
+
+			for _, sampleContainer := range samples {
+				for _, sample := range sampleContainer.GetSamples() {
+					assert.Equal(t, map[string]string{"ip": "127.0.0.1", "tag": "value"}, sample.Tags.Map())
+				}
+			}
+		})
+	}
+}
+
+func TestUserProperties(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestState(t, nil)
+	_, err := ts.runtime.RunOnEventLoop(`(async () => {
+		const client = new mqtt.Client()
+		const message = new Promise((resolve) => { client.onmessage = resolve })
+		await client.connect("` + startBroker(t) + `")
+		await client.subscribe("devices/#")
+		await client.publish("devices/1", "on", { userProperties: { device: "lamp" } })
+
+		const e = await message
+		if (e.userProperties.device !== "lamp" || e.userProperties["k6-published-at"] === undefined) {
+			throw new Error("unexpected user properties " + JSON.stringify(e.userProperties))
+		}
+		client.close()
+	})()`)
+	require.NoError(t, err)
+}
+
+func TestRefused(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"3.1.1": "connection refused: bad user name or password",
+		"5":     "connection refused with reason code 0x86",
+	}
+
+	for version, expected := range tests {
+		t.Run(version, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestState(t, nil)
+			_, err := ts.runtime.RunOnEventLoop(`(async () => {
+				const client = new mqtt.Client({ version: "` + version + `", username: "bad", password: "secret" })
+				await client.connect("` + startBroker(t) + `")
+			})()`)
+			require.ErrorContains(t, err, expected)
+
+			assert.Equal(t, 1, metricCounts(metrics.GetBufferedSamples(ts.samples))["mqtt_errors"])
+		})
+	}
+}
+
+func TestFailedOperations(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`client.subscribe("forbidden/topic", { qos: 1 })`:     `subscribing to "forbidden/topic" failed with reason code 0x80`,
+		`client.publish("forbidden/topic", "x", { qos: 1 })`:  "publish failed with reason code 0x87",
+		`client.publish("forbidden/topic", "x", { qos: 2 })`:  "publish failed with reason code 0x87",
+		`client.publish("topic", "x", { qos: 3 })`:            "invalid QoS 3",
+		`client.publish("", "x")`:                             "the topic can't be empty",
+		`client.publish("topic", null)`:                       "data can't be null or undefined",
+		`client.subscribe("topic", { noLocal: true })`:        "unknown subscribe option noLocal",
+		`client.publish("topic", "x", { contentType: "a" })`:  "unknown publish option contentType",
+		`(client.close(), client.publish("topic", "closed"))`: "the MQTT connection is closed",
+	}
+
+	for code, expected := range tests {
+		t.Run(code, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestState(t, nil)
+			_, err := ts.runtime.RunOnEventLoop(`(async () => {
+				const client = new mqtt.Client()
+				await client.connect("` + startBroker(t) + `")
+				try {
+					await ` + code + `
+				} finally {
+					client.close()
+				}
+			})()`)
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
+
+func TestBrokerClosingConnection(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		// accept the connection, and close it right away
+		_, _ = conn.Write([]byte{packetConnack << 4, 3, 0, 0, 0})
+		_ = conn.Close()
+	}()
+
+	ts := newTestState(t, nil)
+	_, err = ts.runtime.RunOnEventLoop(`(async () => {
+		const client = new mqtt.Client()
+		const events = []
+		const closed = new Promise((resolve) => {
+			client.onerror = (e) => events.push(e.type)
+			client.onclose = (e) => { events.push(e.type); resolve() }
+		})
+		await client.connect("mqtt://` + l.Addr().String() + `")
+		await closed
+		if (events.join(",") !== "error,close") {
+			throw new Error("unexpected events " + events.join(","))
+		}
+	})()`)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, metricCounts(metrics.GetBufferedSamples(ts.samples))["mqtt_errors"])
+}
+
+func TestTLS(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	clientConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig //nolint:forcetypeassert
+
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: srv.TLS.Certificates,
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	newBroker(t, l)
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+
+	t.Run("connect", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestState(t, clientConfig)
+		_, err := ts.runtime.RunOnEventLoop(`(async () => {
+			const client = new mqtt.Client({ tls: { serverName: "example.com" } })
+			await client.connect("mqtts://broker.k6.io:` + port + `")
+			client.close()
+		})()`)
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong server name", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestState(t, clientConfig)
+		_, err := ts.runtime.RunOnEventLoop(`(async () => {
+			const client = new mqtt.Client()
+			await client.connect("mqtts://broker.k6.io:` + port + `")
+		})()`)
+		require.ErrorContains(t, err, "TLS handshake failed")
+	})
+}
+
+func TestInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`new mqtt.Client({ version: 3 })`:                      `unsupported MQTT version "3"`,
+		`new mqtt.Client({ keepAlive: "-1s" })`:                "keepAlive can't be negative",
+		`new mqtt.Client({ keepAlive: "24h" })`:                "keepAlive can be at most",
+		`new mqtt.Client({ connectTimeout: 0 })`:               "connectTimeout should be positive",
+		`new mqtt.Client({ will: {} })`:                        "unknown client option will",
+		`new mqtt.Client({ tls: { cert: "x", key: "y" } })`:    "invalid TLS client certificate",
+		`new mqtt.Client().addEventListener("pong", () => {})`: "unknown event type pong",
+		`new mqtt.Client().connect("http://broker.k6.io")`:     `invalid broker URL scheme "http"`,
+	}
+
+	for code, expected := range tests {
+		t.Run(code, func(t *testing.T) {
+			t.Parallel()
+
+			ts := newTestState(t, nil)
+			_, err := ts.runtime.RunOnEventLoop(`(async () => { await ` + code + ` })()`)
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// The control packet types, as defined in section 2.1.2 of both the 3.1.1 and
+// 5.0 specifications.
+const (
+	packetConnect     byte = 1
+	packetConnack     byte = 2
+	packetPublish     byte = 3
+	packetPuback      byte = 4
+	packetPubrec      byte = 5
+	packetPubrel      byte = 6
+	packetPubcomp     byte = 7
+	packetSubscribe   byte = 8
+	packetSuback      byte = 9
+	packetUnsubscribe byte = 10
+	packetUnsuback    byte = 11
+	packetPingreq     byte = 12
+	packetPingresp    byte = 13
+	packetDisconnect  byte = 14
+	packetAuth        byte = 15
+)
+
+// The protocol levels sent in the CONNECT packet.
+const (
+	protocolV311 byte = 4
+	protocolV5   byte = 5
+)
+
+// The MQTT 5 property identifiers, only the user property and the reason
+// string are used, all the others are only decoded to be skipped.
+const (
+	propReasonString byte = 0x1F
+	propUserProperty byte = 0x26
+)
+
+// maxRemainingLength is the biggest value that can be encoded in the four bytes
+// of the remaining length field.
+const maxRemainingLength = 268435455
+
+var errMalformedPacket = errors.New("malformed MQTT packet")
+
+// packet is a control packet with its fixed header decoded.
+type packet struct {
+	kind  byte
+	flags byte
+	body  []byte
+}
+
+func readPacket(r *bufio.Reader) (packet, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return packet{}, err
+	}
+	length, err := readVarint(r)
+	if err != nil {
+		return packet{}, err
+	}
+	body := make([]byte, length)
+	if _, err = io.ReadFull(r, body); err != nil {
+		return packet{}, err
+	}
+	return packet{kind: header >> 4, flags: header & 0x0F, body: body}, nil
+}
+
+// encode returns the wire format of the packet, including its fixed header.
+func (p packet) encode() ([]byte, error) {
+	if len(p.body) > maxRemainingLength {
+		return nil, fmt.Errorf("the MQTT packet is too big, it has %d bytes", len(p.body))
+	}
+	b := make([]byte, 0, len(p.body)+5)
+	b = append(b, p.kind<<4|p.flags)
+	b = appendVarint(b, len(p.body))
+	return append(b, p.body...), nil
+}
+
+func readVarint(r io.ByteReader) (int, error) {
+	var value, multiplier int = 0, 1
+	for i := 0; i < 4; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		value += int(b&0x7F) * multiplier
+		if b&0x80 == 0 {
+			return value, nil
+		}
+		multiplier *= 128
+	}
+	return 0, errMalformedPacket
+}
+
+func appendVarint(b []byte, v int) []byte {
+	for {
+		digit := byte(v % 128) //nolint:gosec // it's always smaller than 128
+		v /= 128
+		if v > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return binary.BigEndian.AppendUint16(b, v)
+}
+
+func appendString(b []byte, s string) []byte {
+	return appendBinary(b, []byte(s))
+}
+
+func appendBinary(b []byte, data []byte) []byte {
+	b = appendUint16(b, uint16(len(data))) //nolint:gosec // the length is validated by the callers
+	return append(b, data...)
+}
+
+// appendProperties appends the MQTT 5 properties section with the provided
+// user properties.
+func appendProperties(b []byte, userProperties map[string]string) []byte {
+	var props []byte
+	for k, v := range userProperties {
+		props = append(props, propUserProperty)
+		props = appendString(props, k)
+		props = appendString(props, v)
+	}
+	b = appendVarint(b, len(props))
+	return append(b, props...)
+}
+
+// decoder reads the fields of a packet's body.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) remaining() int {
+	return len(d.b)
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil || len(d.b) < 1 {
+		d.err = errMalformedPacket
+		return 0
+	}
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *decoder) uint16() uint16 {
+	if d.err != nil || len(d.b) < 2 {
+		d.err = errMalformedPacket
+		return 0
+	}
+	v := binary.BigEndian.Uint16(d.b)
+	d.b = d.b[2:]
+	return v
+}
+
+func (d *decoder) skip(n int) {
+	if d.err != nil || len(d.b) < n {
+		d.err = errMalformedPacket
+		return
+	}
+	d.b = d.b[n:]
+}
+
+func (d *decoder) binary() []byte {
+	n := int(d.uint16())
+	if d.err != nil || len(d.b) < n {
+		d.err = errMalformedPacket
+		return nil
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) string() string {
+	return string(d.binary())
+}
+
+func (d *decoder) varint() int {
+	if d.err != nil {
+		return 0
+	}
+	r := &byteReader{d: d}
+	v, err := readVarint(r)
+	if err != nil {
+		d.err = errMalformedPacket
+	}
+	return v
+}
+
+// rest returns all the bytes which weren't read yet.
+func (d *decoder) rest() []byte {
+	v := d.b
+	d.b = nil
+	return v
+}
+
+type byteReader struct {
+	d *decoder
+}
+
+func (r *byteReader) ReadByte() (byte, error) {
+	b := r.d.byte()
+	return b, r.d.err
+}
+
+// properties are the MQTT 5 properties the client cares about.
+type properties struct {
+	reasonString   string
+	userProperties map[string]string
+}
+
+// properties decodes an MQTT 5 properties section.
+func (d *decoder) properties() properties {
+	var props properties
+	length := d.varint()
+	if d.err != nil || len(d.b) < length {
+		d.err = errMalformedPacket
+		return props
+	}
+	pd := &decoder{b: d.b[:length]}
+	d.b = d.b[length:]
+
+	for pd.remaining() > 0 && pd.err == nil {
+		switch id := pd.byte(); id {
+		case 0x01, 0x17, 0x19, 0x24, 0x25, 0x28, 0x29, 0x2A:
+			pd.skip(1)
+		case 0x13, 0x21, 0x22, 0x23:
+			pd.skip(2)
+		case 0x02, 0x11, 0x18, 0x27:
+			pd.skip(4)
+		case 0x0B:
+			pd.varint()
+		case 0x03, 0x08, 0x09, 0x12, 0x15, 0x16, 0x1A, 0x1C:
+			pd.binary()
+		case propReasonString:
+			props.reasonString = pd.string()
+		case propUserProperty:
+			if props.userProperties == nil {
+				props.userProperties = make(map[string]string)
+			}
+			k := pd.string()
+			props.userProperties[k] = pd.string()
+		default:
+			pd.err = fmt.Errorf("%w: unknown property 0x%02x", errMalformedPacket, id)
+		}
+	}
+	if pd.err != nil {
+		d.err = pd.err
+	}
+	return props
+}
+
+// connectPacket returns the CONNECT packet for the client's parameters.
+func connectPacket(p *clientParams) packet {
+	var flags byte
+	if p.cleanSession {
+		flags |= 0x02
+	}
+	if p.username != "" {
+		flags |= 0x80
+	}
+	if p.password != "" {
+		flags |= 0x40
+	}
+
+	b := appendString(nil, "MQTT")
+	b = append(b, p.version, flags)
+	b = appendUint16(b, uint16(p.keepAlive.Seconds()))
+	if p.version == protocolV5 {
+		b = appendProperties(b, nil)
+	}
+	b = appendString(b, p.clientID)
+	if p.username != "" {
+		b = appendString(b, p.username)
+	}
+	if p.password != "" {
+		b = appendString(b, p.password)
+	}
+
+	return packet{kind: packetConnect, body: b}
+}
+
+// publishPacket returns the PUBLISH packet for the message. The packet ID is
+// only included for QoS 1 and 2.
+func publishPacket(version byte, m *outgoingMessage, id uint16) packet {
+	var flags byte
+	if m.retain {
+		flags |= 0x01
+	}
+	flags |= m.qos << 1
+
+	b := appendString(nil, m.topic)
+	if m.qos > 0 {
+		b = appendUint16(b, id)
+	}
+	if version == protocolV5 {
+		b = appendProperties(b, m.userProperties)
+	}
+	b = append(b, m.payload...)
+
+	return packet{kind: packetPublish, flags: flags, body: b}
+}
+
+// ackPacket returns one of the PUBACK, PUBREC, PUBREL and PUBCOMP packets, all
+// of them only have the packet ID when they are successful.
+func ackPacket(kind byte, id uint16) packet {
+	var flags byte
+	if kind == packetPubrel {
+		flags = 0x02
+	}
+	return packet{kind: kind, flags: flags, body: appendUint16(nil, id)}
+}
+
+func subscribePacket(version byte, id uint16, topic string, qos byte) packet {
+	b := appendUint16(nil, id)
+	if version == protocolV5 {
+		b = appendProperties(b, nil)
+	}
+	b = appendString(b, topic)
+	b = append(b, qos)
+	return packet{kind: packetSubscribe, flags: 0x02, body: b}
+}
+
+func unsubscribePacket(version byte, id uint16, topic string) packet {
+	b := appendUint16(nil, id)
+	if version == protocolV5 {
+		b = appendProperties(b, nil)
+	}
+	b = appendString(b, topic)
+	return packet{kind: packetUnsubscribe, flags: 0x02, body: b}
+}
+
+// incomingMessage is a decoded PUBLISH packet.
+type incomingMessage struct {
+	id             uint16
+	topic          string
+	qos            byte
+	retain         bool
+	duplicate      bool
+	payload        []byte
+	userProperties map[string]string
+}
+
+func decodePublish(version byte, p packet) (*incomingMessage, error) {
+	m := &incomingMessage{
+		qos:       (p.flags >> 1) & 0x03,
+		retain:    p.flags&0x01 != 0,
+		duplicate: p.flags&0x08 != 0,
+	}
+	if m.qos > 2 {
+		return nil, fmt.Errorf("%w: invalid QoS %d", errMalformedPacket, m.qos)
+	}
+
+	d := &decoder{b: p.body}
+	m.topic = d.string()
+	if m.qos > 0 {
+		m.id = d.uint16()
+	}
+	if version == protocolV5 {
+		m.userProperties = d.properties().userProperties
+	}
+	m.payload = d.rest()
+
+	return m, d.err
+}
+
+// decodeAck decodes the packet ID and the reason code of the acknowledgement
+// packets, which can all be decoded in the same way. The reason codes of MQTT
+// 3.1.1 SUBACK packets are returned too, as they have the same meaning.
+func decodeAck(version byte, p packet) (uint16, byte, string, error) {
+	d := &decoder{b: p.body}
+	id := d.uint16()
+
+	var reason byte
+	var props properties
+	switch p.kind {
+	case packetSuback, packetUnsuback:
+		if version == protocolV5 {
+			props = d.properties()
+		}
+		if d.remaining() > 0 {
+			reason = d.byte()
+		}
+	default:
+		if version == protocolV5 && d.remaining() > 0 {
+			reason = d.byte()
+			if d.remaining() > 0 {
+				props = d.properties()
+			}
+		}
+	}
+
+	return id, reason, props.reasonString, d.err
+}
+
+// decodeConnack returns an error if the connection was refused.
+func decodeConnack(version byte, p packet) error {
+	if p.kind != packetConnack {
+		return fmt.Errorf("expected a CONNACK packet, but got a packet of type %d", p.kind)
+	}
+
+	d := &decoder{b: p.body}
+	d.byte() // the session present flag
+	code := d.byte()
+	var props properties
+	if version == protocolV5 && d.err == nil {
+		props = d.properties()
+	}
+	if d.err != nil {
+		return d.err
+	}
+
+	if code == 0 {
+		return nil
+	}
+	if version == protocolV5 {
+		return reasonError("connection refused", code, props.reasonString)
+	}
+	return fmt.Errorf("connection refused: %s", connectReturnCodes(code))
+}
+
+func connectReturnCodes(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	default:
+		return fmt.Sprintf("unknown return code %d", code)
+	}
+}
+
+func reasonError(msg string, code byte, reason string) error {
+	if reason != "" {
+		return fmt.Errorf("%s with reason code 0x%02x: %s", msg, code, reason)
+	}
+	return fmt.Errorf("%s with reason code 0x%02x", msg, code)
+}
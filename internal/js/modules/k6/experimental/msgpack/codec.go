@@ -0,0 +1,476 @@
+package msgpack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/grafana/sobek"
+)
+
+// maxDepth is the maximum nesting of arrays and maps, protecting against
+// cyclic objects and maliciously deep data.
+const maxDepth = 1000
+
+// timestampExtType is the extension type of the timestamps, as defined by
+// the [specification].
+//
+// [specification]: https://github.com/msgpack/msgpack/blob/master/spec.md#timestamp-extension-type
+const timestampExtType = -1
+
+var (
+	errMaxDepth = fmt.Errorf("maximum nesting depth of %d exceeded", maxDepth)
+
+	typeBytes       = reflect.TypeOf([]byte(nil))         //nolint:gochecknoglobals
+	typeArrayBuffer = reflect.TypeOf(sobek.ArrayBuffer{}) //nolint:gochecknoglobals
+)
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) encode(v sobek.Value, depth int) error {
+	if depth > maxDepth {
+		return errMaxDepth
+	}
+
+	if v == nil || sobek.IsUndefined(v) || sobek.IsNull(v) {
+		e.buf = append(e.buf, 0xc0)
+		return nil
+	}
+
+	if obj, ok := v.(*sobek.Object); ok {
+		return e.encodeObject(obj, depth)
+	}
+
+	switch x := v.Export().(type) {
+	case bool:
+		if x {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case int64:
+		e.encodeInt(x)
+	case float64:
+		// integral numbers are encoded in the most compact integer format,
+		// except the negative zero which only exists as a float
+		switch {
+		case x != math.Trunc(x) || x < math.MinInt64 || x >= math.MaxUint64 || (x == 0 && math.Signbit(x)):
+			e.buf = append(e.buf, 0xcb)
+			e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(x))
+		case x < 0:
+			e.encodeInt(int64(x))
+		default:
+			e.encodeUint(uint64(x))
+		}
+	case string:
+		e.encodeString(x)
+	case *big.Int:
+		switch {
+		case x.IsInt64():
+			e.encodeInt(x.Int64())
+		case x.IsUint64():
+			e.encodeUint(x.Uint64())
+		default:
+			return fmt.Errorf("the BigInt %s is out of the range of the MessagePack integers", x)
+		}
+	default:
+		return fmt.Errorf("unsupported value of type %T", x)
+	}
+
+	return nil
+}
+
+func (e *encoder) encodeObject(obj *sobek.Object, depth int) error {
+	switch {
+	case obj.ClassName() == "Array":
+		length := obj.Get("length").ToInteger()
+		e.encodeHeader(int(length), 0x90, 0xdc, 0xdd)
+		for i := range length {
+			if err := e.encode(obj.Get(fmt.Sprint(i)), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	case obj.ClassName() == "Date":
+		t, ok := obj.Export().(time.Time)
+		if !ok {
+			return errors.New("invalid Date object")
+		}
+		e.encodeTimestamp(t)
+		return nil
+	case obj.ExportType() == typeBytes:
+		b, _ := obj.Export().([]byte)
+		e.encodeBinary(b)
+		return nil
+	case obj.ExportType() == typeArrayBuffer:
+		ab, _ := obj.Export().(sobek.ArrayBuffer)
+		e.encodeBinary(ab.Bytes())
+		return nil
+	case obj.ClassName() == "Function":
+		return errors.New("unsupported value of type function")
+	}
+
+	// like JSON, the properties of undefined values and functions are omitted
+	keys := make([]string, 0, len(obj.Keys()))
+	for _, key := range obj.Keys() {
+		value := obj.Get(key)
+		if sobek.IsUndefined(value) {
+			continue
+		}
+		if o, ok := value.(*sobek.Object); ok && o.ClassName() == "Function" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	e.encodeHeader(len(keys), 0x80, 0xde, 0xdf)
+	for _, key := range keys {
+		e.encodeString(key)
+		if err := e.encode(obj.Get(key), depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeHeader encodes the length of an array or a map, using the fix, 16 or
+// 32 bits format.
+func (e *encoder) encodeHeader(n int, fix, b16, b32 byte) {
+	switch {
+	case n < 16:
+		e.buf = append(e.buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, b16)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, b32)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n)) //nolint:gosec
+	}
+}
+
+func (e *encoder) encodeInt(i int64) {
+	if i >= 0 {
+		e.encodeUint(uint64(i))
+		return
+	}
+
+	switch {
+	case i >= -32:
+		e.buf = append(e.buf, byte(i))
+	case i >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		e.buf = append(e.buf, 0xd1)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(i)) //nolint:gosec
+	case i >= math.MinInt32:
+		e.buf = append(e.buf, 0xd2)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(i)) //nolint:gosec
+	default:
+		e.buf = append(e.buf, 0xd3)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(i)) //nolint:gosec
+	}
+}
+
+func (e *encoder) encodeUint(u uint64) {
+	switch {
+	case u <= 0x7f:
+		e.buf = append(e.buf, byte(u))
+	case u <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		e.buf = append(e.buf, 0xcd)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(u))
+	case u <= math.MaxUint32:
+		e.buf = append(e.buf, 0xce)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(u))
+	default:
+		e.buf = append(e.buf, 0xcf)
+		e.buf = binary.BigEndian.AppendUint64(e.buf, u)
+	}
+}
+
+func (e *encoder) encodeString(s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		e.buf = append(e.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xda)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdb)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n)) //nolint:gosec
+	}
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) encodeBinary(b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xc5)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xc6)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n)) //nolint:gosec
+	}
+	e.buf = append(e.buf, b...)
+}
+
+// encodeTimestamp encodes the time with the timestamp extension type, in the
+// most compact of its formats.
+func (e *encoder) encodeTimestamp(t time.Time) {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond()) //nolint:gosec
+	switch {
+	case sec >= 0 && sec <= math.MaxUint32 && nsec == 0:
+		e.buf = append(e.buf, 0xd6, byte(0xff))
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(sec))
+	case sec >= 0 && sec < 1<<34:
+		e.buf = append(e.buf, 0xd7, byte(0xff))
+		e.buf = binary.BigEndian.AppendUint64(e.buf, nsec<<34|uint64(sec))
+	default:
+		e.buf = append(e.buf, 0xc7, 12, byte(0xff))
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(nsec))
+		e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(sec)) //nolint:gosec
+	}
+}
+
+type decoder struct {
+	rt   *sobek.Runtime
+	data []byte
+	pos  int
+}
+
+var errUnexpectedEnd = errors.New("unexpected end of the MessagePack data")
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errUnexpectedEnd
+	}
+
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readUint reads a big-endian unsigned integer of the given size in bytes.
+func (d *decoder) readUint(size int) (uint64, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+//nolint:cyclop,funlen,gocognit
+func (d *decoder) decode(depth int) (sobek.Value, error) {
+	if depth > maxDepth {
+		return nil, errMaxDepth
+	}
+
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return d.rt.ToValue(int64(c)), nil
+	case c >= 0xe0:
+		return d.rt.ToValue(int64(int8(c))), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c&0x0f), depth)
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c&0x0f), depth)
+	case c&0xe0 == 0xa0:
+		return d.decodeString(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return sobek.Null(), nil
+	case 0xc2:
+		return d.rt.ToValue(false), nil
+	case 0xc3:
+		return d.rt.ToValue(true), nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readUint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		data, err := d.read(int(n)) //nolint:gosec
+		if err != nil {
+			return nil, err
+		}
+		return d.rt.ToValue(d.rt.NewArrayBuffer(append([]byte(nil), data...))), nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.readUint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeExt(int(n)) //nolint:gosec
+	case 0xca:
+		u, err := d.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+		return d.rt.ToValue(float64(math.Float32frombits(uint32(u)))), nil
+	case 0xcb:
+		u, err := d.readUint(8)
+		if err != nil {
+			return nil, err
+		}
+		return d.rt.ToValue(math.Float64frombits(u)), nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.readUint(1 << (c - 0xcc))
+		if err != nil {
+			return nil, err
+		}
+		return d.intValue(new(big.Int).SetUint64(u)), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		u, err := d.readUint(size)
+		if err != nil {
+			return nil, err
+		}
+		// sign-extend the integer to 64 bits
+		shift := 64 - 8*size
+		i := int64(u<<shift) >> shift //nolint:gosec
+		return d.intValue(big.NewInt(i)), nil
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.decodeExt(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readUint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(int(n)) //nolint:gosec
+	case 0xdc, 0xdd:
+		n, err := d.readUint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(n), depth) //nolint:gosec
+	case 0xde, 0xdf:
+		n, err := d.readUint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(n), depth) //nolint:gosec
+	default:
+		return nil, fmt.Errorf("invalid MessagePack format byte 0x%02x", c)
+	}
+}
+
+// intValue returns the integer as a number, or as a BigInt when it can't be
+// represented exactly by a number.
+func (d *decoder) intValue(i *big.Int) sobek.Value {
+	const maxSafeInteger = 1<<53 - 1
+	if i.IsInt64() && i.Int64() <= maxSafeInteger && i.Int64() >= -maxSafeInteger {
+		return d.rt.ToValue(i.Int64())
+	}
+
+	return d.rt.ToValue(i)
+}
+
+func (d *decoder) decodeString(n int) (sobek.Value, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.rt.ToValue(string(b)), nil
+}
+
+func (d *decoder) decodeArray(n int, depth int) (sobek.Value, error) {
+	// every item takes at least one byte
+	if n > len(d.data)-d.pos {
+		return nil, errUnexpectedEnd
+	}
+
+	items := make([]any, 0, n)
+	for range n {
+		item, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return d.rt.NewArray(items...), nil
+}
+
+func (d *decoder) decodeMap(n int, depth int) (sobek.Value, error) {
+	// every entry takes at least two bytes
+	if n > (len(d.data)-d.pos)/2 {
+		return nil, errUnexpectedEnd
+	}
+
+	obj := d.rt.NewObject()
+	for range n {
+		key, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		value, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if err := obj.Set(key.String(), value); err != nil {
+			return nil, err
+		}
+	}
+
+	return obj, nil
+}
+
+// decodeExt decodes the extension value of n bytes. Only the timestamp
+// extension type is supported, which is decoded as a Date.
+func (d *decoder) decodeExt(n int) (sobek.Value, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+
+	extType := int8(b[0]) //nolint:gosec
+	data, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if extType != timestampExtType {
+		return nil, fmt.Errorf("unsupported MessagePack extension type %d", extType)
+	}
+
+	var t time.Time
+	switch n {
+	case 4:
+		t = time.Unix(int64(binary.BigEndian.Uint32(data)), 0)
+	case 8:
+		u := binary.BigEndian.Uint64(data)
+		t = time.Unix(int64(u&(1<<34-1)), int64(u>>34)) //nolint:gosec
+	case 12:
+		t = time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data[:4]))) //nolint:gosec
+	default:
+		return nil, fmt.Errorf("invalid MessagePack timestamp of %d bytes", n)
+	}
+
+	return d.rt.New(d.rt.Get("Date"), d.rt.ToValue(t.UnixMilli()))
+}
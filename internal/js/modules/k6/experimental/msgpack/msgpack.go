@@ -0,0 +1,88 @@
+// Package msgpack provides the encoding and decoding of MessagePack data
+// for the k6.
+package msgpack
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+)
+
+type (
+	// RootModule is the global module instance that will create module
+	// instances for each VU.
+	RootModule struct{}
+
+	// MessagePack represents an instance of the msgpack module.
+	MessagePack struct {
+		vu modules.VU
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &MessagePack{}
+)
+
+// New returns a pointer to a new RootModule instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface to return
+// a new instance for each VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &MessagePack{vu: vu}
+}
+
+// Exports returns the exports of the msgpack module.
+func (m *MessagePack) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"encode": m.encode,
+			"decode": m.decode,
+		},
+	}
+}
+
+// encode returns the MessagePack representation of the given value, as an
+// ArrayBuffer.
+func (m *MessagePack) encode(value sobek.Value) (*sobek.ArrayBuffer, error) {
+	rt := m.vu.Runtime()
+
+	e := &encoder{}
+	if err := e.encode(value, 0); err != nil {
+		return nil, err
+	}
+
+	ab := rt.NewArrayBuffer(e.buf)
+	return &ab, nil
+}
+
+// decode returns the value represented by the given MessagePack data.
+func (m *MessagePack) decode(input sobek.Value) (sobek.Value, error) {
+	if common.IsNullish(input) {
+		return nil, errors.New("the data to decode is required")
+	}
+
+	data, err := common.ToBytes(input.Export())
+	if err != nil {
+		return nil, err
+	}
+
+	d := &decoder{rt: m.vu.Runtime(), data: data}
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("unexpected %d trailing bytes after the MessagePack value", len(d.data)-d.pos)
+	}
+
+	return value, nil
+}
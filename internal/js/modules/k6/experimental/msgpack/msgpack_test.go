@@ -0,0 +1,168 @@
+package msgpack
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modulestest"
+)
+
+func makeRuntime(t *testing.T) *sobek.Runtime {
+	rt := sobek.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+	m, ok := New().NewModuleInstance(
+		&modulestest.VU{
+			CtxField:     context.Background(),
+			RuntimeField: rt,
+			InitEnvField: &common.InitEnvironment{},
+		},
+	).(*MessagePack)
+	require.True(t, ok)
+	require.NoError(t, rt.Set("msgpack", m.Exports().Named))
+	_, err := rt.RunString(`
+	function hex(buffer) {
+		return Array.from(new Uint8Array(buffer), (b) => b.toString(16).padStart(2, "0")).join("");
+	}
+	function unhex(s) {
+		return new Uint8Array(s.match(/../g).map((b) => parseInt(b, 16))).buffer;
+	}`)
+	require.NoError(t, err)
+
+	return rt
+}
+
+func TestMessagePack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Encode", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			value    string
+			expected string
+		}{
+			{value: `null`, expected: "c0"},
+			{value: `undefined`, expected: "c0"},
+			{value: `true`, expected: "c3"},
+			{value: `false`, expected: "c2"},
+			{value: `1`, expected: "01"},
+			{value: `-1`, expected: "ff"},
+			{value: `200`, expected: "ccc8"},
+			{value: `-200`, expected: "d1ff38"},
+			{value: `70000`, expected: "ce00011170"},
+			{value: `2 ** 40`, expected: "cf0000010000000000"},
+			{value: `1.5`, expected: "cb3ff8000000000000"},
+			{value: `-0`, expected: "cb8000000000000000"},
+			{value: `18446744073709551615n`, expected: "cfffffffffffffffff"},
+			{value: `"abc"`, expected: "a3616263"},
+			{value: `[1, "a"]`, expected: "9201a161"},
+			{value: `{ b: 1, a: [], c: undefined }`, expected: "82a16201a16190"},
+			{value: `new Uint8Array([1, 2])`, expected: "c4020102"},
+			{value: `new Uint8Array([1, 2]).buffer`, expected: "c4020102"},
+			{value: `new Date(1000)`, expected: "d6ff00000001"},
+			{value: `new Date(1500)`, expected: "d7ff7735940000000001"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.value, func(t *testing.T) {
+				t.Parallel()
+
+				rt := makeRuntime(t)
+				v, err := rt.RunString(`hex(msgpack.encode(` + tc.value + `))`)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, v.String())
+			})
+		}
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			data     string
+			expected string
+		}{
+			{data: "c0", expected: `null`},
+			{data: "c3", expected: `true`},
+			{data: "ff", expected: `-1`},
+			{data: "d1ff38", expected: `-200`},
+			{data: "ce00011170", expected: `70000`},
+			{data: "ca3fc00000", expected: `1.5`},
+			{data: "cfffffffffffffffff", expected: `18446744073709551615n`},
+			{data: "d3ffffffffffffffff", expected: `-1`},
+			{data: "d9036162 63", expected: `"abc"`},
+			{data: "dc00020102", expected: `[1,2]`},
+			{data: "82a16201a16190", expected: `{"b":1,"a":[]}`},
+			{data: "8101a161", expected: `{"1":"a"}`},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.data, func(t *testing.T) {
+				t.Parallel()
+
+				rt := makeRuntime(t)
+				v, err := rt.RunString(`
+				var decoded = msgpack.decode(unhex("` + tc.data + `".replace(/ /g, "")));
+				typeof decoded === "bigint" ? decoded + "n" : JSON.stringify(decoded)`)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, v.String())
+			})
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		rt := makeRuntime(t)
+		_, err := rt.RunString(`
+		const value = {
+			id: 12345,
+			name: "k6",
+			ratio: 0.25,
+			tags: ["a", "b", null],
+			nested: { ok: true, date: new Date(1700000000123) },
+			payload: new Uint8Array([0xde, 0xad, 0xbe, 0xef]),
+		};
+		const decoded = msgpack.decode(msgpack.encode(value));
+		if (decoded.id !== 12345 || decoded.name !== "k6" || decoded.ratio !== 0.25
+			|| JSON.stringify(decoded.tags) !== '["a","b",null]' || decoded.nested.ok !== true
+			|| decoded.nested.date.getTime() !== 1700000000123 || hex(decoded.payload) !== "deadbeef") {
+			throw new Error("unexpected decoded value: " + JSON.stringify(decoded));
+		}
+		`)
+		require.NoError(t, err)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []struct {
+			script   string
+			expected string
+		}{
+			{script: `msgpack.encode(() => {})`, expected: "unsupported value of type function"},
+			{script: `msgpack.encode(2n ** 64n)`, expected: "out of the range of the MessagePack integers"},
+			{script: `var o = {}; o.self = o; msgpack.encode(o)`, expected: "maximum nesting depth"},
+			{script: `msgpack.decode(unhex("92"))`, expected: "unexpected end of the MessagePack data"},
+			{script: `msgpack.decode(unhex("c1"))`, expected: "invalid MessagePack format byte 0xc1"},
+			{script: `msgpack.decode(unhex("0101"))`, expected: "unexpected 1 trailing bytes"},
+			{script: `msgpack.decode(unhex("d40101"))`, expected: "unsupported MessagePack extension type 1"},
+			{script: `msgpack.decode(null)`, expected: "the data to decode is required"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.script, func(t *testing.T) {
+				t.Parallel()
+
+				rt := makeRuntime(t)
+				_, err := rt.RunString(tc.script)
+				require.ErrorContains(t, err, tc.expected)
+			})
+		}
+	})
+}
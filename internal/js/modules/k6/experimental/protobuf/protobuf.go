@@ -0,0 +1,215 @@
+// Package protobuf provides the encoding and decoding of Protocol Buffers
+// messages for the k6, independently of gRPC.
+package protobuf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/sobek"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"go.k6.io/k6/v2/internal/lib/netext/grpcext"
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+)
+
+type (
+	// RootModule is the global module instance that will create module
+	// instances for each VU.
+	RootModule struct{}
+
+	// Protobuf represents an instance of the protobuf module.
+	Protobuf struct {
+		vu modules.VU
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &Protobuf{}
+)
+
+// New returns a pointer to a new RootModule instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface to return
+// a new instance for each VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	return &Protobuf{vu: vu}
+}
+
+// Exports returns the exports of the protobuf module.
+func (p *Protobuf) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"load":         p.load,
+			"loadProtoset": p.loadProtoset,
+		},
+	}
+}
+
+// load parses the given proto files, and returns the schema of the messages
+// they define.
+func (p *Protobuf) load(importPaths []string, filenames ...string) (*Schema, error) {
+	fdset, err := grpcext.LoadProtos(p.vu, importPaths, filenames...)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.schema(fdset)
+}
+
+// loadProtoset parses the given protoset file (serialized FileDescriptorSet),
+// and returns the schema of the messages it defines.
+func (p *Protobuf) loadProtoset(protosetPath string) (*Schema, error) {
+	fdset, err := grpcext.LoadProtoset(p.vu, protosetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.schema(fdset)
+}
+
+func (p *Protobuf) schema(fdset *descriptorpb.FileDescriptorSet) (*Schema, error) {
+	files, err := protodesc.NewFiles(fdset)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSchema(p.vu.Runtime(), files)
+}
+
+// Schema holds the message types loaded from proto or protoset files.
+type Schema struct {
+	rt    *sobek.Runtime
+	types *protoregistry.Types
+}
+
+func newSchema(rt *sobek.Runtime, files *protoregistry.Files) (*Schema, error) {
+	types := new(protoregistry.Types)
+
+	var err error
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		stack := make([]protoreflect.MessageDescriptor, 0, fd.Messages().Len())
+		for i := 0; i < fd.Messages().Len(); i++ {
+			stack = append(stack, fd.Messages().Get(i))
+		}
+
+		for len(stack) > 0 {
+			message := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if err = types.RegisterMessage(dynamicpb.NewMessageType(message)); err != nil {
+				return false
+			}
+
+			for i := 0; i < message.Messages().Len(); i++ {
+				stack = append(stack, message.Messages().Get(i))
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{rt: rt, types: types}, nil
+}
+
+// MessageTypes returns the full names of the message types of the schema.
+func (s *Schema) MessageTypes() []string {
+	var names []string
+	s.types.RangeMessages(func(mt protoreflect.MessageType) bool {
+		names = append(names, string(mt.Descriptor().FullName()))
+		return true
+	})
+	sort.Strings(names)
+
+	return names
+}
+
+// Encode returns the message of the given type, built from the object, in
+// the Protocol Buffers binary wire format.
+//
+// The object follows the canonical JSON mapping of the message.
+func (s *Schema) Encode(messageType string, object sobek.Value) (*sobek.ArrayBuffer, error) {
+	if common.IsNullish(object) {
+		return nil, errors.New("a message object is required")
+	}
+
+	msg, err := s.newMessage(messageType)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := object.ToObject(s.rt).MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("unable to serialise the message object: %w", err)
+	}
+
+	if err := (protojson.UnmarshalOptions{Resolver: s.types}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("unable to build a %s message: %w", messageType, err)
+	}
+
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the %s message: %w", messageType, err)
+	}
+
+	ab := s.rt.NewArrayBuffer(b)
+	return &ab, nil
+}
+
+// Decode returns the object of the message of the given type, encoded in the
+// Protocol Buffers binary wire format.
+//
+// The object follows the canonical JSON mapping of the message, with the
+// fields having their default value included.
+func (s *Schema) Decode(messageType string, input sobek.Value) (any, error) {
+	data, err := common.ToBytes(input.Export())
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.newMessage(messageType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := (proto.UnmarshalOptions{Resolver: s.types}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("unable to decode the %s message: %w", messageType, err)
+	}
+
+	raw, err := (protojson.MarshalOptions{Resolver: s.types, EmitUnpopulated: true}).Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the message: %w", err)
+	}
+
+	var object any
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the message: %w", err)
+	}
+
+	return object, nil
+}
+
+func (s *Schema) newMessage(messageType string) (*dynamicpb.Message, error) {
+	mt, err := s.types.FindMessageByName(protoreflect.FullName(messageType))
+	if err != nil {
+		return nil, fmt.Errorf("message type %q not found in the loaded definitions", messageType)
+	}
+
+	return dynamicpb.NewMessage(mt.Descriptor()), nil
+}
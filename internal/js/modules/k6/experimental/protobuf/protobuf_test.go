@@ -0,0 +1,136 @@
+package protobuf
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/grafana/sobek"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modulestest"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/fsext"
+)
+
+const testProto = `syntax = "proto3";
+
+package test;
+
+import "google/protobuf/timestamp.proto";
+
+message User {
+  string name = 1;
+  int32 age = 2;
+  repeated string tags = 3;
+  Address address = 4;
+  google.protobuf.Timestamp created_at = 5;
+
+  message Address {
+    string city = 1;
+  }
+}
+`
+
+func makeRuntime(t *testing.T) (*sobek.Runtime, *modulestest.VU) {
+	t.Helper()
+
+	fs := fsext.NewMemMapFs()
+	require.NoError(t, fsext.WriteFile(fs, "/test.proto", []byte(testProto), 0o600))
+
+	rt := sobek.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+	vu := &modulestest.VU{
+		CtxField:     context.Background(),
+		RuntimeField: rt,
+		InitEnvField: &common.InitEnvironment{
+			CWD:         &url.URL{Scheme: "file", Path: "/"},
+			FileSystems: map[string]fsext.Fs{"file": fs},
+		},
+	}
+	m, ok := New().NewModuleInstance(vu).(*Protobuf)
+	require.True(t, ok)
+	require.NoError(t, rt.Set("protobuf", m.Exports().Named))
+
+	return rt, vu
+}
+
+func TestProtobuf(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MessageTypes", func(t *testing.T) {
+		t.Parallel()
+
+		rt, _ := makeRuntime(t)
+		v, err := rt.RunString(`protobuf.load([], "test.proto").messageTypes().join(",")`)
+		require.NoError(t, err)
+		assert.Contains(t, v.String(), "test.User,test.User.Address")
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		t.Parallel()
+
+		rt, _ := makeRuntime(t)
+		_, err := rt.RunString(`
+		const schema = protobuf.load([], "test.proto");
+		const encoded = schema.encode("test.User", {
+			name: "k6",
+			age: 7,
+			tags: ["load", "testing"],
+			address: { city: "Stockholm" },
+			createdAt: "2024-01-02T03:04:05Z",
+		});
+		if (!(encoded instanceof ArrayBuffer) || encoded.byteLength === 0) {
+			throw new Error("unexpected encoded value: " + encoded);
+		}
+
+		const decoded = schema.decode("test.User", encoded);
+		if (decoded.name !== "k6" || decoded.age !== 7 || decoded.tags.join() !== "load,testing"
+			|| decoded.address.city !== "Stockholm" || decoded.createdAt !== "2024-01-02T03:04:05Z") {
+			throw new Error("unexpected decoded value: " + JSON.stringify(decoded));
+		}
+
+		const empty = schema.decode("test.User", new Uint8Array(0).buffer);
+		if (empty.name !== "" || empty.age !== 0 || empty.tags.length !== 0) {
+			throw new Error("unexpected default values: " + JSON.stringify(empty));
+		}
+		`)
+		require.NoError(t, err)
+	})
+
+	t.Run("UnknownMessageType", func(t *testing.T) {
+		t.Parallel()
+
+		rt, _ := makeRuntime(t)
+		_, err := rt.RunString(`protobuf.load([], "test.proto").encode("test.Unknown", {})`)
+		require.ErrorContains(t, err, `message type "test.Unknown" not found`)
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		t.Parallel()
+
+		rt, _ := makeRuntime(t)
+		_, err := rt.RunString(`protobuf.load([], "test.proto").encode("test.User", { unknown: 1 })`)
+		require.ErrorContains(t, err, "unable to build a test.User message")
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		t.Parallel()
+
+		rt, _ := makeRuntime(t)
+		_, err := rt.RunString(`protobuf.load([], "test.proto").decode("test.User", new Uint8Array([0x0a, 0x05]).buffer)`)
+		require.ErrorContains(t, err, "unable to decode the test.User message")
+	})
+
+	t.Run("LoadOutsideInitContext", func(t *testing.T) {
+		t.Parallel()
+
+		rt, vu := makeRuntime(t)
+		vu.InitEnvField = nil
+		vu.StateField = &lib.State{}
+		_, err := rt.RunString(`protobuf.load([], "test.proto")`)
+		require.ErrorContains(t, err, "load must be called in the init context")
+	})
+}
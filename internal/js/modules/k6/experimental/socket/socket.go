@@ -0,0 +1,344 @@
+// Package socket contains the connection object shared by the
+// k6/experimental/tcp and k6/experimental/udp modules.
+//
+// The connections are always established through the VU's dialer, so they
+// respect the blacklistIPs, blockHostnames, hosts and localIPs options, and the
+// transferred bytes are accounted in the data_sent and data_received metrics.
+package socket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib/types"
+	"go.k6.io/k6/v2/metrics"
+)
+
+// defaultReadSize is the maximum amount of bytes returned by a single read,
+// unless a different one is requested.
+const defaultReadSize = 64 * 1024
+
+// Metrics contains the metrics emitted by a Socket. All of them are optional.
+type Metrics struct {
+	// Errors is incremented on every failed operation.
+	Errors *metrics.Metric
+	// SessionDuration tracks the time between the connection and its closing.
+	SessionDuration *metrics.Metric
+	// MessagesSent is incremented on every successful write.
+	MessagesSent *metrics.Metric
+	// MessagesReceived is incremented on every successful read.
+	MessagesReceived *metrics.Metric
+}
+
+// UpgradeFunc parses the parameters provided from the script for upgrading the
+// connection, for example to TLS. It is called on the event loop, and returns
+// the function doing the actual upgrade outside of it.
+type UpgradeFunc func(params sobek.Value) (func(ctx context.Context, conn net.Conn) (net.Conn, error), error)
+
+// Socket wraps a connection established by the VU's dialer, and exposes it to
+// the script with promise based methods.
+type Socket struct {
+	vu          modules.VU
+	tagsAndMeta *metrics.TagsAndMeta
+	metrics     Metrics
+	upgrade     UpgradeFunc
+	started     time.Time
+
+	m      sync.Mutex
+	conn   net.Conn
+	closed bool
+	done   chan struct{}
+}
+
+// New returns a Socket for the provided connection, which is closed if the VU
+// context is done. The upgrade function is optional, and the startTLS method is
+// only available when one is provided.
+func New(
+	vu modules.VU, conn net.Conn, tagsAndMeta *metrics.TagsAndMeta, m Metrics, upgrade UpgradeFunc,
+) *Socket {
+	s := &Socket{
+		vu:          vu,
+		tagsAndMeta: tagsAndMeta,
+		metrics:     m,
+		upgrade:     upgrade,
+		started:     time.Now(),
+		conn:        conn,
+		done:        make(chan struct{}),
+	}
+
+	go func() {
+		select {
+		case <-vu.Context().Done():
+			_ = s.Close()
+		case <-s.done:
+		}
+	}()
+
+	return s
+}
+
+// Object returns the JS object to interact with the socket.
+func (s *Socket) Object() *sobek.Object {
+	rt := s.vu.Runtime()
+	obj := rt.NewObject()
+
+	must(rt, obj.Set("read", s.Read))
+	must(rt, obj.Set("write", s.Write))
+	must(rt, obj.Set("close", s.Close))
+	must(rt, obj.Set("remoteAddress", s.getConn().RemoteAddr().String()))
+	must(rt, obj.Set("localAddress", s.getConn().LocalAddr().String()))
+	if s.upgrade != nil {
+		must(rt, obj.Set("startTLS", s.StartTLS))
+	}
+
+	return obj
+}
+
+// Read reads from the connection, and resolves with an ArrayBuffer holding at
+// most `size` bytes, or null if the connection was closed.
+//
+// The returned promise is rejected if nothing is received before the optional
+// `timeout` passes.
+func (s *Socket) Read(params sobek.Value) *sobek.Promise {
+	rt := s.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	opts, err := parseReadParams(rt, params)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+
+	conn := s.getConn()
+	callback := s.vu.RegisterCallback()
+	go func() {
+		var deadline time.Time
+		if opts.timeout > 0 {
+			deadline = time.Now().Add(opts.timeout)
+		}
+		buf := make([]byte, opts.size)
+		n, readErr := func() (int, error) {
+			if err := conn.SetReadDeadline(deadline); err != nil {
+				return 0, err
+			}
+			return conn.Read(buf)
+		}()
+
+		if readErr == nil || n > 0 {
+			s.emitCount(s.metrics.MessagesReceived)
+		} else if !s.isClosed() && !errors.Is(readErr, io.EOF) {
+			s.emitCount(s.metrics.Errors)
+		}
+
+		callback(func() error {
+			switch {
+			case n > 0:
+				return resolve(rt.NewArrayBuffer(buf[:n]))
+			case readErr == nil:
+				return resolve(rt.NewArrayBuffer(nil))
+			case errors.Is(readErr, io.EOF), s.isClosed():
+				return resolve(sobek.Null())
+			case errors.Is(readErr, os.ErrDeadlineExceeded):
+				return reject(fmt.Errorf("read timed out after %s", opts.timeout))
+			default:
+				return reject(readErr)
+			}
+		})
+	}()
+
+	return promise
+}
+
+// Write writes the provided string, ArrayBuffer or ArrayBuffer view to the
+// connection, and resolves with the number of written bytes.
+func (s *Socket) Write(data sobek.Value) *sobek.Promise {
+	rt := s.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	b, err := ToBytes(rt, data)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+
+	conn := s.getConn()
+	callback := s.vu.RegisterCallback()
+	go func() {
+		n, writeErr := conn.Write(b)
+		if writeErr != nil {
+			s.emitCount(s.metrics.Errors)
+		} else {
+			s.emitCount(s.metrics.MessagesSent)
+		}
+
+		callback(func() error {
+			if writeErr != nil {
+				return reject(writeErr)
+			}
+			return resolve(n)
+		})
+	}()
+
+	return promise
+}
+
+// StartTLS upgrades the connection with the socket's upgrade function.
+func (s *Socket) StartTLS(params sobek.Value) *sobek.Promise {
+	rt := s.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	upgrade, err := s.upgrade(params)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+
+	ctx := s.vu.Context()
+	conn := s.getConn()
+	callback := s.vu.RegisterCallback()
+	go func() {
+		upgraded, err := upgrade(ctx, conn)
+		if err != nil {
+			s.emitCount(s.metrics.Errors)
+		} else {
+			s.m.Lock()
+			s.conn = upgraded
+			s.m.Unlock()
+		}
+
+		callback(func() error {
+			if err != nil {
+				return reject(err)
+			}
+			return resolve(sobek.Undefined())
+		})
+	}()
+
+	return promise
+}
+
+// Close closes the connection, pending reads are resolved with null.
+func (s *Socket) Close() error {
+	s.m.Lock()
+	if s.closed {
+		s.m.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	err := s.conn.Close()
+	s.m.Unlock()
+
+	if s.metrics.SessionDuration != nil {
+		now := time.Now()
+		metrics.PushIfNotDone(s.vu.Context(), s.vu.State().Samples, metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: s.metrics.SessionDuration, Tags: s.tagsAndMeta.Tags},
+			Time:       now,
+			Metadata:   s.tagsAndMeta.Metadata,
+			Value:      metrics.D(now.Sub(s.started)),
+		})
+	}
+
+	return err
+}
+
+func (s *Socket) getConn() net.Conn {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.conn
+}
+
+func (s *Socket) isClosed() bool {
+	s.m.Lock()
+	defer s.m.Unlock()
+	return s.closed
+}
+
+func (s *Socket) emitCount(metric *metrics.Metric) {
+	if metric == nil {
+		return
+	}
+	metrics.PushIfNotDone(s.vu.Context(), s.vu.State().Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: metric, Tags: s.tagsAndMeta.Tags},
+		Time:       time.Now(),
+		Metadata:   s.tagsAndMeta.Metadata,
+		Value:      1,
+	})
+}
+
+type readParams struct {
+	size    int
+	timeout time.Duration
+}
+
+func parseReadParams(rt *sobek.Runtime, raw sobek.Value) (readParams, error) {
+	params := readParams{size: defaultReadSize}
+	if common.IsNullish(raw) {
+		return params, nil
+	}
+
+	obj := raw.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "size":
+			size := v.ToInteger()
+			if size <= 0 {
+				return params, fmt.Errorf("read size should be positive, but got %d", size)
+			}
+			params.size = int(size)
+		case "timeout":
+			timeout, err := types.GetDurationValue(v.Export())
+			if err != nil {
+				return params, fmt.Errorf("invalid read timeout: %w", err)
+			}
+			params.timeout = timeout
+		default:
+			return params, fmt.Errorf("unknown read option %s", k)
+		}
+	}
+
+	return params, nil
+}
+
+// ToBytes returns the bytes of a string, ArrayBuffer or ArrayBuffer view.
+func ToBytes(rt *sobek.Runtime, data sobek.Value) ([]byte, error) {
+	if common.IsNullish(data) {
+		return nil, errors.New("data can't be null or undefined")
+	}
+
+	switch v := data.Export().(type) {
+	case string:
+		return []byte(v), nil
+	case sobek.ArrayBuffer:
+		return v.Bytes(), nil
+	case *sobek.ArrayBuffer:
+		return v.Bytes(), nil
+	}
+
+	var b []byte
+	if obj, ok := data.(*sobek.Object); ok && obj.Get("buffer") != nil {
+		if err := rt.ExportTo(data, &b); err == nil {
+			return b, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unsupported data type %s, expected a string, ArrayBuffer or ArrayBuffer view", data.ExportType())
+}
+
+// must is a small helper that will panic if err is not nil.
+func must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
@@ -0,0 +1,35 @@
+package tcp
+
+import "go.k6.io/k6/v2/metrics"
+
+// instanceMetrics contains the metrics for the tcp module.
+type instanceMetrics struct {
+	Connecting      *metrics.Metric
+	TLSHandshaking  *metrics.Metric
+	SessionDuration *metrics.Metric
+	Errors          *metrics.Metric
+}
+
+// registerMetrics registers and returns the metrics in the provided registry
+func registerMetrics(registry *metrics.Registry) (*instanceMetrics, error) {
+	var err error
+	m := &instanceMetrics{}
+
+	if m.Connecting, err = registry.NewMetric("tcp_connecting", metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	if m.TLSHandshaking, err = registry.NewMetric("tcp_tls_handshaking", metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	if m.SessionDuration, err = registry.NewMetric("tcp_session_duration", metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	if m.Errors, err = registry.NewMetric("tcp_errors", metrics.Counter); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
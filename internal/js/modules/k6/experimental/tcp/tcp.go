@@ -0,0 +1,286 @@
+// Package tcp implements the k6/experimental/tcp module, which allows scripts
+// to open raw TCP connections, optionally upgraded to TLS.
+package tcp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/socket"
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/types"
+	"go.k6.io/k6/v2/metrics"
+)
+
+const defaultConnectTimeout = 60 * time.Second
+
+type (
+	// RootModule is the global module instance that will create module
+	// instances for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the tcp module for every VU.
+	ModuleInstance struct {
+		vu      modules.VU
+		metrics *instanceMetrics
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// New returns a pointer to a new RootModule instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface to return
+// a new instance for each VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	metrics, err := registerMetrics(vu.InitEnv().Registry)
+	if err != nil {
+		common.Throw(vu.Runtime(), fmt.Errorf("failed to register tcp module metrics: %w", err))
+	}
+
+	return &ModuleInstance{vu: vu, metrics: metrics}
+}
+
+// Exports returns the exports of the tcp module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"connect": mi.connect,
+		},
+	}
+}
+
+type connectParams struct {
+	timeout     time.Duration
+	tls         bool
+	tlsParams   tlsParams
+	tagsAndMeta *metrics.TagsAndMeta
+}
+
+type tlsParams struct {
+	serverName string
+}
+
+// connect dials the provided address and resolves with a socket object.
+func (mi *ModuleInstance) connect(addr string, params sobek.Value) *sobek.Promise {
+	rt := mi.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	state := mi.vu.State()
+	if state == nil {
+		must(rt, reject(errors.New("connecting is only allowed in the VU context")))
+		return promise
+	}
+
+	opts, err := mi.parseConnectParams(state, params)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+
+	ctx := mi.vu.Context()
+	callback := mi.vu.RegisterCallback()
+	go func() {
+		conn, err := mi.dial(ctx, state, addr, opts)
+		callback(func() error {
+			if err != nil {
+				return reject(err)
+			}
+			s := socket.New(mi.vu, conn, opts.tagsAndMeta, socket.Metrics{
+				Errors:          mi.metrics.Errors,
+				SessionDuration: mi.metrics.SessionDuration,
+			}, mi.upgradeFunc(state, addr, opts.tagsAndMeta))
+			return resolve(s.Object())
+		})
+	}()
+
+	return promise
+}
+
+func (mi *ModuleInstance) dial(
+	ctx context.Context, state *lib.State, addr string, opts *connectParams,
+) (net.Conn, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := state.Dialer.DialContext(dialCtx, "tcp", addr)
+	end := time.Now()
+
+	if err == nil {
+		if ip, _, splitErr := net.SplitHostPort(conn.RemoteAddr().String()); splitErr == nil {
+			opts.tagsAndMeta.SetSystemTagOrMetaIfEnabled(state.Options.SystemTags, metrics.TagIP, ip)
+		}
+	}
+	mi.emit(ctx, state, mi.metrics.Connecting, opts.tagsAndMeta, end, metrics.D(end.Sub(start)))
+	if err != nil {
+		mi.emit(ctx, state, mi.metrics.Errors, opts.tagsAndMeta, end, 1)
+		return nil, err
+	}
+
+	if !opts.tls {
+		return conn, nil
+	}
+
+	tlsConn, err := mi.handshake(ctx, state, conn, addr, opts.tlsParams, opts.tagsAndMeta)
+	if err != nil {
+		mi.emit(ctx, state, mi.metrics.Errors, opts.tagsAndMeta, time.Now(), 1)
+		_ = conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+func (mi *ModuleInstance) upgradeFunc(
+	state *lib.State, addr string, tagsAndMeta *metrics.TagsAndMeta,
+) socket.UpgradeFunc {
+	return func(raw sobek.Value) (func(context.Context, net.Conn) (net.Conn, error), error) {
+		params, err := mi.parseTLSParams(raw)
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, conn net.Conn) (net.Conn, error) {
+			return mi.handshake(ctx, state, conn, addr, params, tagsAndMeta)
+		}, nil
+	}
+}
+
+// handshake upgrades the connection to TLS, using the VU's TLS configuration.
+// The server name defaults to the host of the dialed address, and it can be
+// overwritten with the `serverName` parameter.
+func (mi *ModuleInstance) handshake(
+	ctx context.Context, state *lib.State, conn net.Conn, addr string, params tlsParams,
+	tagsAndMeta *metrics.TagsAndMeta,
+) (net.Conn, error) {
+	var config *tls.Config
+	if state.TLSConfig != nil {
+		config = state.TLSConfig.Clone()
+	} else {
+		config = &tls.Config{} //nolint:gosec // the defaults from the options are used when set
+	}
+	config.NextProtos = nil
+
+	config.ServerName = params.serverName
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		config.ServerName = host
+	}
+
+	tlsConn := tls.Client(conn, config)
+	start := time.Now()
+	err := tlsConn.HandshakeContext(ctx)
+	end := time.Now()
+	if err != nil {
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	mi.emit(ctx, state, mi.metrics.TLSHandshaking, tagsAndMeta, end, metrics.D(end.Sub(start)))
+
+	return tlsConn, nil
+}
+
+func (mi *ModuleInstance) parseTLSParams(raw sobek.Value) (tlsParams, error) {
+	var params tlsParams
+	if common.IsNullish(raw) {
+		return params, nil
+	}
+	if _, isBool := raw.Export().(bool); isBool {
+		return params, nil
+	}
+
+	obj := raw.ToObject(mi.vu.Runtime())
+	for _, k := range obj.Keys() {
+		switch k {
+		case "serverName":
+			params.serverName = obj.Get(k).String()
+		default:
+			return params, fmt.Errorf("unknown TLS option %s", k)
+		}
+	}
+	return params, nil
+}
+
+func (mi *ModuleInstance) parseConnectParams(state *lib.State, raw sobek.Value) (*connectParams, error) {
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	params := &connectParams{
+		timeout:     defaultConnectTimeout,
+		tagsAndMeta: &tagsAndMeta,
+	}
+	if common.IsNullish(raw) {
+		return params, nil
+	}
+
+	rt := mi.vu.Runtime()
+	obj := raw.ToObject(rt)
+	for _, k := range obj.Keys() {
+		v := obj.Get(k)
+		switch k {
+		case "timeout":
+			timeout, err := types.GetDurationValue(v.Export())
+			if err != nil {
+				return nil, fmt.Errorf("invalid connect timeout: %w", err)
+			}
+			if timeout <= 0 {
+				return nil, fmt.Errorf("the connect timeout should be positive, but got %s", timeout)
+			}
+			params.timeout = timeout
+		case "tls":
+			if common.IsNullish(v) {
+				continue
+			}
+			if b, isBool := v.Export().(bool); isBool {
+				params.tls = b
+				continue
+			}
+			tlsParams, err := mi.parseTLSParams(v)
+			if err != nil {
+				return nil, err
+			}
+			params.tls = true
+			params.tlsParams = tlsParams
+		case "tags":
+			if err := common.ApplyCustomUserTags(rt, params.tagsAndMeta, v); err != nil {
+				return nil, fmt.Errorf("invalid connect tags option: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown connect option %s", k)
+		}
+	}
+
+	return params, nil
+}
+
+func (mi *ModuleInstance) emit(
+	ctx context.Context, state *lib.State, metric *metrics.Metric, tagsAndMeta *metrics.TagsAndMeta,
+	t time.Time, value float64,
+) {
+	metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: metric, Tags: tagsAndMeta.Tags},
+		Time:       t,
+		Metadata:   tagsAndMeta.Metadata,
+		Value:      value,
+	})
+}
+
+// must is a small helper that will panic if err is not nil.
+func must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
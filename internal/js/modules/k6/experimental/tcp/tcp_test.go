@@ -0,0 +1,312 @@
+package tcp
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/internal/lib/testutils/mockresolver"
+	"go.k6.io/k6/v2/js/modulestest"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/netext"
+	"go.k6.io/k6/v2/metrics"
+)
+
+type testState struct {
+	runtime *modulestest.Runtime
+	samples chan metrics.SampleContainer
+	dialer  *netext.Dialer
+}
+
+func newTestState(t *testing.T, tlsConfig *tls.Config) *testState {
+	t.Helper()
+
+	runtime := modulestest.NewRuntime(t)
+	samples := make(chan metrics.SampleContainer, 1000)
+	dialer := netext.NewDialer(net.Dialer{}, mockresolver.New(map[string][]net.IP{
+		"example.com": {net.ParseIP("127.0.0.1")},
+	}))
+
+	m := new(RootModule).NewModuleInstance(runtime.VU)
+	require.NoError(t, runtime.VU.Runtime().Set("tcp", m.Exports().Named))
+
+	runtime.MoveToVUContext(&lib.State{
+		Dialer:    dialer,
+		TLSConfig: tlsConfig,
+		Options: lib.Options{
+			SystemTags: metrics.NewSystemTagSet(metrics.TagIP),
+			UserAgent:  null.StringFrom("TestUserAgent"),
+		},
+		Samples:        samples,
+		BuiltinMetrics: runtime.BuiltinMetrics,
+		Tags:           lib.NewVUStateTags(runtime.VU.InitEnvField.Registry.RootTagSet()),
+	})
+
+	return &testState{runtime: runtime, samples: samples, dialer: dialer}
+}
+
+// echo accepts connections on the listener, and echoes every line it receives.
+func echo(t *testing.T, l net.Listener) {
+	t.Helper()
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadBytes('\n')
+					if err != nil {
+						return
+					}
+					if _, err = conn.Write(line); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+}
+
+func metricNames(sampleContainers []metrics.SampleContainer) map[string]int {
+	names := make(map[string]int)
+	for _, sampleContainer := range sampleContainers {
+		for _, sample := range sampleContainer.GetSamples() {
+			names[sample.Metric.Name]++
+		}
+	}
+	return names
+}
+
+func TestConnect(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	echo(t, l)
+
+	ts := newTestState(t, nil)
+	_, err = ts.runtime.RunOnEventLoop(`(async () => {
+		const socket = await tcp.connect("` + l.Addr().String() + `", { tags: { tag: "value" } })
+		const written = await socket.write("hello\n")
+		if (written !== 6) {
+			throw new Error("unexpected written bytes " + written)
+		}
+		const data = await socket.read({ timeout: "5s" })
+		const received = String.fromCharCode(...new Uint8Array(data))
+		if (received !== "hello\n") {
+			throw new Error("unexpected received data " + received)
+		}
+		if (socket.startTLS === undefined) {
+			throw new Error("startTLS should be defined for TCP sockets")
+		}
+		socket.close()
+	})()`)
+	require.NoError(t, err)
+
+	samples := metrics.GetBufferedSamples(ts.samples)
+	names := metricNames(samples)
+	assert.Equal(t, 1, names["tcp_connecting"])
+	assert.Equal(t, 1, names["tcp_session_duration"])
+	assert.Zero(t, names["tcp_errors"])
+
+	for _, sampleContainer := range samples {
+		for _, sample := range sampleContainer.GetSamples() {
+			assert.Equal(t, map[string]string{"ip": "127.0.0.1", "tag": "value"}, sample.Tags.Map())
+		}
+	}
+}
+
+func TestConnectBlacklisted(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	echo(t, l)
+
+	ts := newTestState(t, nil)
+	ipNet, err := lib.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+	ts.dialer.Blacklist = []*lib.IPNet{ipNet}
+
+	_, err = ts.runtime.RunOnEventLoop(`(async () => {
+		try {
+			await tcp.connect("` + l.Addr().String() + `")
+		} catch (e) {
+			if (!e.toString().includes("blacklisted")) {
+				throw e
+			}
+			return
+		}
+		throw new Error("connect should have failed")
+	})()`)
+	require.NoError(t, err)
+
+	names := metricNames(metrics.GetBufferedSamples(ts.samples))
+	assert.Equal(t, 1, names["tcp_errors"])
+}
+
+func TestReadTimeout(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	echo(t, l)
+
+	ts := newTestState(t, nil)
+	_, err = ts.runtime.RunOnEventLoop(`(async () => {
+		const socket = await tcp.connect("` + l.Addr().String() + `")
+		try {
+			await socket.read({ timeout: "50ms" })
+		} catch (e) {
+			if (!e.toString().includes("read timed out after 50ms")) {
+				throw e
+			}
+			socket.close()
+			return
+		}
+		throw new Error("read should have timed out")
+	})()`)
+	require.NoError(t, err)
+
+	names := metricNames(metrics.GetBufferedSamples(ts.samples))
+	assert.Equal(t, 1, names["tcp_errors"])
+}
+
+func TestReadAfterRemoteClose(t *testing.T) {
+	t.Parallel()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		_, _ = io.WriteString(conn, "bye")
+		_ = conn.Close()
+	}()
+
+	ts := newTestState(t, nil)
+	_, err = ts.runtime.RunOnEventLoop(`(async () => {
+		const socket = await tcp.connect("` + l.Addr().String() + `")
+		let received = ""
+		for (;;) {
+			const data = await socket.read()
+			if (data === null) {
+				break
+			}
+			received += String.fromCharCode(...new Uint8Array(data))
+		}
+		if (received !== "bye") {
+			throw new Error("unexpected received data " + received)
+		}
+		socket.close()
+	})()`)
+	require.NoError(t, err)
+}
+
+func TestTLS(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	clientConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig //nolint:forcetypeassert
+
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: srv.TLS.Certificates,
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	echo(t, l)
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	require.NoError(t, err)
+
+	t.Run("connect", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestState(t, clientConfig)
+		_, err := ts.runtime.RunOnEventLoop(`(async () => {
+			const socket = await tcp.connect("example.com:` + port + `", { tls: true })
+			await socket.write("secure\n")
+			const data = await socket.read({ timeout: "5s" })
+			const received = String.fromCharCode(...new Uint8Array(data))
+			if (received !== "secure\n") {
+				throw new Error("unexpected received data " + received)
+			}
+			socket.close()
+		})()`)
+		require.NoError(t, err)
+
+		names := metricNames(metrics.GetBufferedSamples(ts.samples))
+		assert.Equal(t, 1, names["tcp_tls_handshaking"])
+	})
+
+	t.Run("start TLS", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestState(t, clientConfig)
+		_, err := ts.runtime.RunOnEventLoop(`(async () => {
+			const socket = await tcp.connect("127.0.0.1:` + port + `")
+			await socket.startTLS({ serverName: "example.com" })
+			await socket.write("upgraded\n")
+			const data = await socket.read({ timeout: "5s" })
+			const received = String.fromCharCode(...new Uint8Array(data))
+			if (received !== "upgraded\n") {
+				throw new Error("unexpected received data " + received)
+			}
+			socket.close()
+		})()`)
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong server name", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newTestState(t, clientConfig)
+		_, err := ts.runtime.RunOnEventLoop(`(async () => {
+			try {
+				await tcp.connect("127.0.0.1:` + port + `", { tls: { serverName: "k6.io" } })
+			} catch (e) {
+				if (!e.toString().includes("TLS handshake failed")) {
+					throw e
+				}
+				return
+			}
+			throw new Error("connect should have failed")
+		})()`)
+		require.NoError(t, err)
+	})
+}
+
+func TestConnectInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestState(t, nil)
+	_, err := ts.runtime.RunOnEventLoop(`(async () => {
+		try {
+			await tcp.connect("127.0.0.1:1", { something: true })
+		} catch (e) {
+			if (!e.toString().includes("unknown connect option something")) {
+				throw e
+			}
+			return
+		}
+		throw new Error("connect should have failed")
+	})()`)
+	require.NoError(t, err)
+}
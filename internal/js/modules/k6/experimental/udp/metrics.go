@@ -0,0 +1,30 @@
+package udp
+
+import "go.k6.io/k6/v2/metrics"
+
+// instanceMetrics contains the metrics for the udp module.
+type instanceMetrics struct {
+	PacketsSent     *metrics.Metric
+	PacketsReceived *metrics.Metric
+	Errors          *metrics.Metric
+}
+
+// registerMetrics registers and returns the metrics in the provided registry
+func registerMetrics(registry *metrics.Registry) (*instanceMetrics, error) {
+	var err error
+	m := &instanceMetrics{}
+
+	if m.PacketsSent, err = registry.NewMetric("udp_packets_sent", metrics.Counter); err != nil {
+		return nil, err
+	}
+
+	if m.PacketsReceived, err = registry.NewMetric("udp_packets_received", metrics.Counter); err != nil {
+		return nil, err
+	}
+
+	if m.Errors, err = registry.NewMetric("udp_errors", metrics.Counter); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
@@ -0,0 +1,139 @@
+// Package udp implements the k6/experimental/udp module, which allows scripts
+// to send and receive UDP datagrams.
+package udp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/socket"
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/metrics"
+)
+
+type (
+	// RootModule is the global module instance that will create module
+	// instances for each VU.
+	RootModule struct{}
+
+	// ModuleInstance represents an instance of the udp module for every VU.
+	ModuleInstance struct {
+		vu      modules.VU
+		metrics *instanceMetrics
+	}
+)
+
+var (
+	_ modules.Module   = &RootModule{}
+	_ modules.Instance = &ModuleInstance{}
+)
+
+// New returns a pointer to a new RootModule instance.
+func New() *RootModule {
+	return &RootModule{}
+}
+
+// NewModuleInstance implements the modules.Module interface to return
+// a new instance for each VU.
+func (*RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	metrics, err := registerMetrics(vu.InitEnv().Registry)
+	if err != nil {
+		common.Throw(vu.Runtime(), fmt.Errorf("failed to register udp module metrics: %w", err))
+	}
+
+	return &ModuleInstance{vu: vu, metrics: metrics}
+}
+
+// Exports returns the exports of the udp module.
+func (mi *ModuleInstance) Exports() modules.Exports {
+	return modules.Exports{
+		Named: map[string]any{
+			"connect": mi.connect,
+		},
+	}
+}
+
+// connect associates a UDP socket with the provided address, and resolves with
+// a socket object. Every write sends a single datagram, and every read
+// receives a single one.
+func (mi *ModuleInstance) connect(addr string, params sobek.Value) *sobek.Promise {
+	rt := mi.vu.Runtime()
+	promise, resolve, reject := rt.NewPromise()
+
+	state := mi.vu.State()
+	if state == nil {
+		must(rt, reject(errors.New("connecting is only allowed in the VU context")))
+		return promise
+	}
+
+	tagsAndMeta, err := mi.parseConnectParams(state, params)
+	if err != nil {
+		must(rt, reject(err))
+		return promise
+	}
+
+	ctx := mi.vu.Context()
+	callback := mi.vu.RegisterCallback()
+	go func() {
+		conn, err := state.Dialer.DialContext(ctx, "udp", addr)
+		if err != nil {
+			metrics.PushIfNotDone(ctx, state.Samples, metrics.Sample{
+				TimeSeries: metrics.TimeSeries{Metric: mi.metrics.Errors, Tags: tagsAndMeta.Tags},
+				Time:       time.Now(),
+				Metadata:   tagsAndMeta.Metadata,
+				Value:      1,
+			})
+		} else if ip, _, splitErr := net.SplitHostPort(conn.RemoteAddr().String()); splitErr == nil {
+			tagsAndMeta.SetSystemTagOrMetaIfEnabled(state.Options.SystemTags, metrics.TagIP, ip)
+		}
+
+		callback(func() error {
+			if err != nil {
+				return reject(err)
+			}
+			s := socket.New(mi.vu, conn, tagsAndMeta, socket.Metrics{
+				Errors:           mi.metrics.Errors,
+				MessagesSent:     mi.metrics.PacketsSent,
+				MessagesReceived: mi.metrics.PacketsReceived,
+			}, nil)
+			return resolve(s.Object())
+		})
+	}()
+
+	return promise
+}
+
+func (mi *ModuleInstance) parseConnectParams(state *lib.State, raw sobek.Value) (*metrics.TagsAndMeta, error) {
+	tagsAndMeta := state.Tags.GetCurrentValues()
+	if common.IsNullish(raw) {
+		return &tagsAndMeta, nil
+	}
+
+	rt := mi.vu.Runtime()
+	obj := raw.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "tags":
+			if err := common.ApplyCustomUserTags(rt, &tagsAndMeta, obj.Get(k)); err != nil {
+				return nil, fmt.Errorf("invalid connect tags option: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown connect option %s", k)
+		}
+	}
+
+	return &tagsAndMeta, nil
+}
+
+// must is a small helper that will panic if err is not nil.
+func must(rt *sobek.Runtime, err error) {
+	if err != nil {
+		common.Throw(rt, err)
+	}
+}
@@ -0,0 +1,117 @@
+package udp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils/mockresolver"
+	"go.k6.io/k6/v2/js/modulestest"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/netext"
+	"go.k6.io/k6/v2/lib/types"
+	"go.k6.io/k6/v2/metrics"
+)
+
+func newTestRuntime(t *testing.T, dialer *netext.Dialer, samples chan metrics.SampleContainer) *modulestest.Runtime {
+	t.Helper()
+
+	runtime := modulestest.NewRuntime(t)
+	m := new(RootModule).NewModuleInstance(runtime.VU)
+	require.NoError(t, runtime.VU.Runtime().Set("udp", m.Exports().Named))
+
+	runtime.MoveToVUContext(&lib.State{
+		Dialer:         dialer,
+		Samples:        samples,
+		BuiltinMetrics: runtime.BuiltinMetrics,
+		Tags:           lib.NewVUStateTags(runtime.VU.InitEnvField.Registry.RootTagSet()),
+	})
+
+	return runtime
+}
+
+// echo echoes every datagram received on the packet connection back to its sender.
+func echo(t *testing.T) net.PacketConn {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = pc.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if _, err = pc.WriteTo(buf[:n], addr); err != nil {
+				return
+			}
+		}
+	}()
+
+	return pc
+}
+
+func TestConnect(t *testing.T) {
+	t.Parallel()
+
+	pc := echo(t)
+	samples := make(chan metrics.SampleContainer, 1000)
+	dialer := netext.NewDialer(net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1")}}, mockresolver.New(nil))
+	runtime := newTestRuntime(t, dialer, samples)
+
+	_, err := runtime.RunOnEventLoop(`(async () => {
+		const socket = await udp.connect("` + pc.LocalAddr().String() + `")
+		if (socket.startTLS !== undefined) {
+			throw new Error("startTLS shouldn't be defined for UDP sockets")
+		}
+		for (const payload of ["first", "second"]) {
+			await socket.write(new Uint8Array([...payload].map((c) => c.charCodeAt(0))))
+			const data = await socket.read({ timeout: "5s", size: 16 })
+			const received = String.fromCharCode(...new Uint8Array(data))
+			if (received !== payload) {
+				throw new Error("unexpected received data " + received)
+			}
+		}
+		socket.close()
+	})()`)
+	require.NoError(t, err)
+
+	counts := make(map[string]float64)
+	for _, sampleContainer := range metrics.GetBufferedSamples(samples) {
+		for _, sample := range sampleContainer.GetSamples() {
+			counts[sample.Metric.Name] += sample.Value
+		}
+	}
+	assert.Equal(t, map[string]float64{"udp_packets_sent": 2, "udp_packets_received": 2}, counts)
+	assert.Equal(t, int64(len("first")+len("second")), dialer.BytesWritten)
+}
+
+func TestConnectBlockedHostname(t *testing.T) {
+	t.Parallel()
+
+	samples := make(chan metrics.SampleContainer, 1000)
+	dialer := netext.NewDialer(net.Dialer{}, mockresolver.New(nil))
+	runtime := newTestRuntime(t, dialer, samples)
+
+	var err error
+	dialer.BlockedHostnames, err = types.NewHostnameTrie([]string{"*.k6.io"})
+	require.NoError(t, err)
+
+	_, err = runtime.RunOnEventLoop(`(async () => {
+		try {
+			await udp.connect("test.k6.io:53")
+		} catch (e) {
+			if (!e.toString().includes("blocked pattern")) {
+				throw e
+			}
+			return
+		}
+		throw new Error("connect should have failed")
+	})()`)
+	require.NoError(t, err)
+}
@@ -7,7 +7,6 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"io"
 	"math"
 	"reflect"
 	"strings"
@@ -18,13 +17,11 @@ import (
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/js/modules"
 
-	"github.com/bufbuild/protocompile"
 	"github.com/grafana/sobek"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
@@ -45,79 +42,20 @@ type Client struct {
 
 // Load will parse the given proto files and make the file descriptors available to request.
 func (c *Client) Load(importPaths []string, filenames ...string) ([]MethodInfo, error) {
-	if c.vu.State() != nil {
-		return nil, errors.New("load must be called in the init context")
-	}
-
-	initEnv := c.vu.InitEnv()
-	if initEnv == nil {
-		return nil, errors.New("missing init environment")
-	}
-
-	// If no import paths are specified, use the current working directory
-	if len(importPaths) == 0 {
-		importPaths = append(importPaths, initEnv.CWD.Path)
-	}
-
-	for i, s := range importPaths {
-		// Clean file scheme as it is the only supported scheme and the following APIs do not support them
-		importPaths[i] = strings.TrimPrefix(s, "file://")
-	}
-
-	resolver := protocompile.WithStandardImports(&protocompile.SourceResolver{
-		ImportPaths: importPaths,
-		Accessor: func(filename string) (io.ReadCloser, error) {
-			absFilePath := initEnv.GetAbsFilePath(filename)
-			return initEnv.FileSystems["file"].Open(absFilePath)
-		},
-	})
-
-	compiler := protocompile.Compiler{
-		Resolver: resolver,
-	}
-
-	fds, err := compiler.Compile(c.vu.Context(), filenames...)
+	fdset, err := grpcext.LoadProtos(c.vu, importPaths, filenames...)
 	if err != nil {
 		return nil, err
 	}
-
-	fdset := &descriptorpb.FileDescriptorSet{}
-	seen := make(map[string]struct{})
-	for _, fd := range fds {
-		fdset.File = append(fdset.File, walkFileDescriptors(seen, fd)...)
-	}
 	return c.convertToMethodInfo(fdset)
 }
 
 // LoadProtoset will parse the given protoset file (serialized FileDescriptorSet) and make the file
 // descriptors available to request.
 func (c *Client) LoadProtoset(protosetPath string) ([]MethodInfo, error) {
-	if c.vu.State() != nil {
-		return nil, errors.New("load must be called in the init context")
-	}
-
-	initEnv := c.vu.InitEnv()
-	if initEnv == nil {
-		return nil, errors.New("missing init environment")
-	}
-
-	absFilePath := initEnv.GetAbsFilePath(protosetPath)
-	fdsetFile, err := initEnv.FileSystems["file"].Open(absFilePath)
-	if err != nil {
-		return nil, fmt.Errorf("couldn't open protoset: %w", err)
-	}
-
-	defer func() { _ = fdsetFile.Close() }()
-	fdsetBytes, err := io.ReadAll(fdsetFile)
+	fdset, err := grpcext.LoadProtoset(c.vu, protosetPath)
 	if err != nil {
-		return nil, fmt.Errorf("couldn't read protoset: %w", err)
-	}
-
-	fdset := &descriptorpb.FileDescriptorSet{}
-	if err = proto.Unmarshal(fdsetBytes, fdset); err != nil {
-		return nil, fmt.Errorf("couldn't unmarshal protoset file %s: %w", protosetPath, err)
+		return nil, err
 	}
-
 	return c.convertToMethodInfo(fdset)
 }
 
@@ -394,6 +332,7 @@ func (c *Client) buildInvokeRequest(
 	}
 
 	p.SetSystemTags(state, c.addr, method)
+	p.SetTraceContext(state)
 
 	return grpcext.InvokeRequest{
 		Method:                 method,
@@ -571,27 +510,6 @@ func (c *Client) convertToMethodInfo(fdset *descriptorpb.FileDescriptorSet) ([]M
 	return rtn, nil
 }
 
-// walkFileDescriptors recursively walks through a file descriptor and its dependencies,
-// converting them to FileDescriptorProto.
-// This ensures that all dependencies are included in the FileDescriptorSet.
-func walkFileDescriptors(seen map[string]struct{}, fd protoreflect.FileDescriptor) []*descriptorpb.FileDescriptorProto {
-	fds := []*descriptorpb.FileDescriptorProto{}
-
-	if _, ok := seen[fd.Path()]; ok {
-		return fds
-	}
-	seen[fd.Path()] = struct{}{}
-	fds = append(fds, protodesc.ToFileDescriptorProto(fd))
-
-	imports := fd.Imports()
-	for i := 0; i < imports.Len(); i++ {
-		dep := imports.Get(i).FileDescriptor
-		deps := walkFileDescriptors(seen, dep)
-		fds = append(fds, deps...)
-	}
-	return fds
-}
-
 // sanitizeMethodName
 func sanitizeMethodName(name string) string {
 	if name == "" {
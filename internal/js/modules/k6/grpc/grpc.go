@@ -129,6 +129,7 @@ func (mi *ModuleInstance) stream(c sobek.ConstructorCall) *sobek.Object {
 	}
 
 	p.SetSystemTags(mi.vu.State(), client.addr, methodName)
+	p.SetTraceContext(mi.vu.State())
 
 	logger := mi.vu.State().Logger.WithField("streamMethod", methodName)
 
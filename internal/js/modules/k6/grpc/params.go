@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/sobek"
+	"go.k6.io/k6/v2/internal/lib/trace"
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/js/modules"
 	"go.k6.io/k6/v2/lib"
@@ -122,6 +123,18 @@ func (p *callParams) SetSystemTags(state *lib.State, addr string, methodName str
 	}
 }
 
+// SetTraceContext starts the span of the call and propagates it in the
+// metadata, if the traceRequests option is enabled.
+func (p *callParams) SetTraceContext(state *lib.State) {
+	if !state.Options.TraceRequests.Bool {
+		return
+	}
+	if spanID := trace.StartSpan(&p.TagsAndMeta); spanID != "" {
+		p.Metadata.Set(trace.TraceparentHeader,
+			trace.Traceparent(p.TagsAndMeta.Metadata[trace.MetadataTraceID], spanID))
+	}
+}
+
 // connectParams is the parameters that can be passed to a gRPC connect call.
 type connectParams struct {
 	IsPlaintext           bool
@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/sobek"
 
+	"go.k6.io/k6/v2/internal/lib/trace"
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/js/modules"
 	"go.k6.io/k6/v2/lib"
@@ -124,6 +125,20 @@ func (mi *K6) Group(name string, val sobek.Value) (sobek.Value, error) {
 		}
 	}()
 
+	if state.Options.TraceRequests.Bool {
+		var spanID, parentSpanID string
+		started := false
+		state.Tags.Modify(func(tagsAndMeta *metrics.TagsAndMeta) {
+			spanID, parentSpanID = tagsAndMeta.Metadata[trace.MetadataSpanID], tagsAndMeta.Metadata[trace.MetadataParentSpanID]
+			started = trace.StartSpan(tagsAndMeta) != ""
+		})
+		if started {
+			defer state.Tags.Modify(func(tagsAndMeta *metrics.TagsAndMeta) {
+				trace.SetSpan(tagsAndMeta, spanID, parentSpanID)
+			})
+		}
+	}
+
 	startTime := time.Now()
 	ret, err := fn(sobek.Undefined())
 	t := time.Now()
@@ -7,7 +7,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
 
+	"go.k6.io/k6/v2/internal/lib/trace"
 	"go.k6.io/k6/v2/js/modulestest"
 	"go.k6.io/k6/v2/lib"
 	"go.k6.io/k6/v2/metrics"
@@ -103,6 +105,28 @@ func TestGroup(t *testing.T) {
 		assert.Equal(t, "", groupTag)
 	})
 
+	t.Run("trace requests", func(t *testing.T) {
+		t.Parallel()
+		tc := testCaseRuntime(t)
+		state := tc.testRuntime.VU.State()
+		state.Options.TraceRequests = null.BoolFrom(true)
+		state.Tags.Modify(trace.StartIteration)
+		iteration := state.Tags.GetCurrentValues().Metadata
+
+		_, err := tc.testRuntime.RunOnEventLoop(`k6.group("outer", () => k6.group("inner", () => {}))`)
+		require.NoError(t, err)
+		assert.Equal(t, iteration, state.Tags.GetCurrentValues().Metadata)
+
+		bufSamples := metrics.GetBufferedSamples(tc.samples)
+		require.Len(t, bufSamples, 2)
+		inner := bufSamples[0].GetSamples()[0].Metadata
+		outer := bufSamples[1].GetSamples()[0].Metadata
+		assert.Equal(t, iteration[trace.MetadataTraceID], inner[trace.MetadataTraceID])
+		assert.Equal(t, outer[trace.MetadataSpanID], inner[trace.MetadataParentSpanID])
+		assert.Equal(t, iteration[trace.MetadataSpanID], outer[trace.MetadataParentSpanID])
+		assert.NotEqual(t, iteration[trace.MetadataSpanID], outer[trace.MetadataSpanID])
+	})
+
 	t.Run("Invalid", func(t *testing.T) {
 		t.Parallel()
 		tc := testCaseRuntime(t)
@@ -55,6 +55,15 @@ const (
 
 	// PBKDF2 represents the PBKDF2 algorithm
 	PBKDF2 = "PBKDF2"
+
+	// HKDF represents the HKDF algorithm.
+	HKDF = "HKDF"
+
+	// Ed25519 represents the Ed25519 algorithm.
+	Ed25519 = "Ed25519"
+
+	// X25519 represents the X25519 algorithm.
+	X25519 = "X25519"
 )
 
 // HashAlgorithmIdentifier represents the name of a hash algorithm.
@@ -170,10 +179,13 @@ func normalizeAlgorithmName(name string) string {
 	// be considered valid.
 	name = strings.ToUpper(name)
 
-	// exception is made for RSASSA-PKCS1-v1_5
+	// exception is made for RSASSA-PKCS1-v1_5 and Ed25519
 	if name == strings.ToUpper(RSASsaPkcs1v15) {
 		return RSASsaPkcs1v15
 	}
+	if name == strings.ToUpper(Ed25519) {
+		return Ed25519
+	}
 
 	return name
 }
@@ -193,24 +205,32 @@ func isRegisteredAlgorithm(algorithmName string, forOperation string) bool {
 			isHashAlgorithm(algorithmName) ||
 			isHMACAlgorithm(algorithmName) ||
 			isEllipticCurve(algorithmName) ||
-			isRSAAlgorithm(algorithmName)
+			isRSAAlgorithm(algorithmName) ||
+			isOKPAlgorithm(algorithmName)
 	case OperationIdentifierExportKey, OperationIdentifierImportKey:
 		return isAesAlgorithm(algorithmName) ||
 			isHMACAlgorithm(algorithmName) ||
 			isEllipticCurve(algorithmName) ||
 			isRSAAlgorithm(algorithmName) ||
-			isPBKDF2Algorithm(algorithmName)
+			isPBKDF2Algorithm(algorithmName) ||
+			isHKDFAlgorithm(algorithmName) ||
+			isOKPAlgorithm(algorithmName)
 	case OperationIdentifierEncrypt, OperationIdentifierDecrypt:
 		return isAesAlgorithm(algorithmName) || algorithmName == RSAOaep
 	case OperationIdentifierSign, OperationIdentifierVerify:
 		return isHMACAlgorithm(algorithmName) ||
 			algorithmName == ECDSA ||
 			algorithmName == RSAPss ||
-			algorithmName == RSASsaPkcs1v15
+			algorithmName == RSASsaPkcs1v15 ||
+			algorithmName == Ed25519
 	case OperationIdentifierDeriveBits:
-		return isHashAlgorithm(algorithmName) || isPBKDF2Algorithm(algorithmName) || isECDHAlgorithm(algorithmName)
+		return isHashAlgorithm(algorithmName) ||
+			isPBKDF2Algorithm(algorithmName) ||
+			isHKDFAlgorithm(algorithmName) ||
+			isECDHAlgorithm(algorithmName) ||
+			algorithmName == X25519
 	case OperationIdentifierDeriveKey:
-		return isHashAlgorithm(algorithmName) || isPBKDF2Algorithm(algorithmName)
+		return isHashAlgorithm(algorithmName) || isPBKDF2Algorithm(algorithmName) || isHKDFAlgorithm(algorithmName)
 	case OperationIdentifierGetKeyLength:
 		return isAesAlgorithm(algorithmName)
 	default:
@@ -234,6 +254,16 @@ func isPBKDF2Algorithm(algorithmName string) bool {
 	return algorithmName == PBKDF2
 }
 
+func isHKDFAlgorithm(algorithmName string) bool {
+	return algorithmName == HKDF
+}
+
+// isOKPAlgorithm returns true for the algorithms using octet key pairs, as
+// named by the JWK specification, which are the Ed25519 and X25519 ones.
+func isOKPAlgorithm(algorithmName string) bool {
+	return algorithmName == Ed25519 || algorithmName == X25519
+}
+
 func isECDHAlgorithm(algorithmName string) bool {
 	return algorithmName == ECDH
 }
@@ -16,6 +16,10 @@ func newBitsDeriver(rt *sobek.Runtime, normalized Algorithm, algorithm sobek.Val
 		deriver, err = newECDHKeyDeriveParams(rt, normalized, algorithm)
 	case PBKDF2:
 		deriver, err = newPBKDF2DeriveParams(rt, normalized, algorithm)
+	case HKDF:
+		deriver, err = newHKDFParams(rt, normalized, algorithm)
+	case X25519:
+		deriver, err = newX25519KeyDeriveParams(rt, normalized, algorithm)
 	default:
 		return nil, NewError(NotSupportedError, "unsupported algorithm for derive bits: "+normalized.Name)
 	}
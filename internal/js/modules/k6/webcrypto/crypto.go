@@ -78,9 +78,9 @@ func (c *Crypto) GetRandomValues(typedArray sobek.Value) sobek.Value {
 	// We use crypto/rand.Read() here as it will use /dev/urandom or
 	// an equivalent on Unix-like systems, and CryptGenRandom()
 	// on Windows. This is the recommended way to generate random
-	// by the specification.
+	// by the specification, unless the seed option is set.
 	randomValues := make([]byte, objLength)
-	_, err := rand.Read(randomValues)
+	_, err := c.readRandom(randomValues)
 	if err != nil {
 		common.Throw(c.vu.Runtime(), err)
 	}
@@ -109,5 +109,23 @@ const maxRandomValuesLength = 65536
 // [RFC4122]: https://tools.ietf.org/html/rfc4122
 // [Web Crypto API's specification]: https://w3c.github.io/webcrypto/#Crypto-method-randomUUID
 func (c *Crypto) RandomUUID() string {
+	if state := c.vu.State(); state != nil && state.Rand != nil {
+		id, err := uuid.NewRandomFromReader(state.Rand)
+		if err != nil {
+			common.Throw(c.vu.Runtime(), err)
+		}
+		return id.String()
+	}
+
 	return uuid.New().String()
 }
+
+// readRandom fills b with random bytes, from the seeded random generator of
+// the VU if the seed option is set, for the test run to be reproducible.
+func (c *Crypto) readRandom(b []byte) (int, error) {
+	if state := c.vu.State(); state != nil && state.Rand != nil {
+		return state.Rand.Read(b)
+	}
+
+	return rand.Read(b)
+}
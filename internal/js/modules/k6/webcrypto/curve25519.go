@@ -0,0 +1,384 @@
+package webcrypto
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+	"slices"
+
+	"github.com/grafana/sobek"
+)
+
+// OKPKeyGenParams represents the object that should be passed as the algorithm
+// parameter into `SubtleCrypto.GenerateKey`, when generating an Ed25519 or an
+// X25519 key pair. As the curve is implied by the algorithm, it only holds
+// the algorithm's name.
+type OKPKeyGenParams struct {
+	Algorithm
+}
+
+var _ KeyGenerator = &OKPKeyGenParams{}
+
+func newOKPKeyGenParams(normalized Algorithm) *OKPKeyGenParams {
+	return &OKPKeyGenParams{
+		Algorithm: normalized,
+	}
+}
+
+// GenerateKey generates a new Ed25519/X25519 key pair, according to the
+// algorithm described in the [specification].
+//
+// [specification]: https://w3c.github.io/webcrypto/#ed25519-operations
+func (okpgp *OKPKeyGenParams) GenerateKey(
+	extractable bool,
+	keyUsages []CryptoKeyUsage,
+) (CryptoKeyGenerationResult, error) {
+	privateKeyUsages, publicKeyUsages := okpKeyUsages(okpgp.Name)
+
+	for _, usage := range keyUsages {
+		if !slices.Contains(privateKeyUsages, usage) && !slices.Contains(publicKeyUsages, usage) {
+			return nil, NewError(SyntaxError, "invalid key usage: "+usage)
+		}
+	}
+
+	var privateHandle, publicHandle any
+	switch okpgp.Name {
+	case Ed25519:
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, NewError(OperationError, "unable to generate a Ed25519 key pair")
+		}
+		privateHandle, publicHandle = privateKey, publicKey
+	case X25519:
+		privateKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, NewError(OperationError, "unable to generate a X25519 key pair")
+		}
+		privateHandle, publicHandle = privateKey, privateKey.PublicKey()
+	default:
+		return nil, NewError(NotSupportedError, "unsupported algorithm: "+okpgp.Name)
+	}
+
+	alg := KeyAlgorithm{Algorithm: okpgp.Algorithm}
+
+	privateKey := &CryptoKey{
+		Type:        PrivateCryptoKeyType,
+		Extractable: extractable,
+		Algorithm:   alg,
+		Usages:      UsageIntersection(keyUsages, privateKeyUsages),
+		handle:      privateHandle,
+	}
+
+	publicKey := &CryptoKey{
+		Type:        PublicCryptoKeyType,
+		Extractable: true,
+		Algorithm:   alg,
+		Usages:      UsageIntersection(keyUsages, publicKeyUsages),
+		handle:      publicHandle,
+	}
+
+	if len(privateKey.Usages) == 0 {
+		return nil, NewError(SyntaxError, "usages cannot not be empty for a private CryptoKey")
+	}
+
+	return &CryptoKeyPair{
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+	}, nil
+}
+
+// okpKeyUsages returns the usages allowed for the private and the public keys
+// of the algorithm.
+func okpKeyUsages(algorithm AlgorithmIdentifier) (private, public []CryptoKeyUsage) {
+	if algorithm == Ed25519 {
+		return []CryptoKeyUsage{SignCryptoKeyUsage}, []CryptoKeyUsage{VerifyCryptoKeyUsage}
+	}
+
+	return []CryptoKeyUsage{DeriveKeyCryptoKeyUsage, DeriveBitsCryptoKeyUsage}, []CryptoKeyUsage{}
+}
+
+// OKPKeyImportParams represents the object that should be passed as the algorithm
+// parameter into `SubtleCrypto.ImportKey` or `SubtleCrypto.UnwrapKey`, when
+// importing an Ed25519 or an X25519 key.
+type OKPKeyImportParams struct {
+	Algorithm
+}
+
+var _ KeyImporter = &OKPKeyImportParams{}
+
+func newOKPKeyImportParams(normalized Algorithm) *OKPKeyImportParams {
+	return &OKPKeyImportParams{
+		Algorithm: normalized,
+	}
+}
+
+// ImportKey imports a key according to the algorithm described in the [specification].
+//
+// [specification]: https://w3c.github.io/webcrypto/#ed25519-operations
+func (okpip *OKPKeyImportParams) ImportKey(
+	format KeyFormat,
+	keyData []byte,
+	_ bool,
+	keyUsages []CryptoKeyUsage,
+) (*CryptoKey, error) {
+	var (
+		handle  any
+		keyType CryptoKeyType
+		err     error
+	)
+
+	switch format {
+	case RawKeyFormat:
+		handle, keyType, err = importOKPPublicKey(okpip.Name, keyData)
+	case SpkiKeyFormat:
+		handle, keyType, err = importOKPSPKIPublicKey(okpip.Name, keyData)
+	case Pkcs8KeyFormat:
+		handle, keyType, err = importOKPPrivateKey(okpip.Name, keyData)
+	case JwkKeyFormat:
+		handle, keyType, err = importOKPJWK(okpip.Name, keyData)
+	default:
+		return nil, NewError(NotSupportedError, unsupportedKeyFormatErrorMsg+" "+format+" for algorithm "+okpip.Name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	privateKeyUsages, publicKeyUsages := okpKeyUsages(okpip.Name)
+	allowedUsages := publicKeyUsages
+	if keyType == PrivateCryptoKeyType {
+		allowedUsages = privateKeyUsages
+	}
+	for _, usage := range keyUsages {
+		if !slices.Contains(allowedUsages, usage) {
+			return nil, NewError(SyntaxError, "invalid key usage for a "+keyType+" "+okpip.Name+" key: "+usage)
+		}
+	}
+
+	return &CryptoKey{
+		Algorithm: KeyAlgorithm{Algorithm: okpip.Algorithm},
+		Type:      keyType,
+		handle:    handle,
+	}, nil
+}
+
+func importOKPPublicKey(algorithm AlgorithmIdentifier, keyData []byte) (any, CryptoKeyType, error) {
+	if algorithm == Ed25519 {
+		if len(keyData) != ed25519.PublicKeySize {
+			return nil, UnknownCryptoKeyType, NewError(DataError, "invalid Ed25519 public key length")
+		}
+
+		return ed25519.PublicKey(keyData), PublicCryptoKeyType, nil
+	}
+
+	key, err := ecdh.X25519().NewPublicKey(keyData)
+	if err != nil {
+		return nil, UnknownCryptoKeyType, NewError(DataError, "unable to import X25519 public key data: "+err.Error())
+	}
+
+	return key, PublicCryptoKeyType, nil
+}
+
+func importOKPSPKIPublicKey(algorithm AlgorithmIdentifier, keyData []byte) (any, CryptoKeyType, error) {
+	pk, err := x509.ParsePKIXPublicKey(keyData)
+	if err != nil {
+		return nil, UnknownCryptoKeyType, NewError(DataError, "unable to import "+algorithm+" public key data: "+err.Error())
+	}
+
+	if !isOKPKey(algorithm, pk) {
+		return nil, UnknownCryptoKeyType, NewError(DataError, fmt.Sprintf(errMsgNotExpectedPublicKey, algorithm, pk))
+	}
+
+	return pk, PublicCryptoKeyType, nil
+}
+
+func importOKPPrivateKey(algorithm AlgorithmIdentifier, keyData []byte) (any, CryptoKeyType, error) {
+	pk, err := x509.ParsePKCS8PrivateKey(keyData)
+	if err != nil {
+		return nil, UnknownCryptoKeyType, NewError(DataError, "unable to import "+algorithm+" private key data: "+err.Error())
+	}
+
+	if !isOKPKey(algorithm, pk) {
+		return nil, UnknownCryptoKeyType, NewError(DataError, fmt.Sprintf(errMsgNotExpectedPrivateKey, algorithm, pk))
+	}
+
+	return pk, PrivateCryptoKeyType, nil
+}
+
+// isOKPKey returns true if the key is a key of the algorithm, as parsed by the
+// x509 package.
+func isOKPKey(algorithm AlgorithmIdentifier, key any) bool {
+	switch k := key.(type) {
+	case ed25519.PublicKey, ed25519.PrivateKey:
+		return algorithm == Ed25519
+	case *ecdh.PublicKey:
+		return algorithm == X25519 && k.Curve() == ecdh.X25519()
+	case *ecdh.PrivateKey:
+		return algorithm == X25519 && k.Curve() == ecdh.X25519()
+	default:
+		return false
+	}
+}
+
+func exportOKPKey(ck *CryptoKey, format KeyFormat) (any, error) {
+	if ck.handle == nil {
+		return nil, NewError(OperationError, "key data is not accessible")
+	}
+
+	alg, ok := ck.Algorithm.(KeyAlgorithm)
+	if !ok {
+		return nil, NewError(InvalidAccessError, "key algorithm is not a valid Ed25519/X25519 algorithm")
+	}
+
+	switch format {
+	case RawKeyFormat:
+		if ck.Type != PublicCryptoKeyType {
+			return nil, NewError(InvalidAccessError, fmt.Sprintf(errMsgNotExpectedPublicKey, alg.Name, ck.handle))
+		}
+
+		return okpPublicKeyBytes(ck.handle)
+	case SpkiKeyFormat:
+		if ck.Type != PublicCryptoKeyType {
+			return nil, NewError(InvalidAccessError, fmt.Sprintf(errMsgNotExpectedPublicKey, alg.Name, ck.handle))
+		}
+
+		bytes, err := x509.MarshalPKIXPublicKey(ck.handle)
+		if err != nil {
+			return nil, NewError(OperationError, "unable to marshal key to SPKI format: "+err.Error())
+		}
+
+		return bytes, nil
+	case Pkcs8KeyFormat:
+		if ck.Type != PrivateCryptoKeyType {
+			return nil, NewError(InvalidAccessError, fmt.Sprintf(errMsgNotExpectedPrivateKey, alg.Name, ck.handle))
+		}
+
+		bytes, err := x509.MarshalPKCS8PrivateKey(ck.handle)
+		if err != nil {
+			return nil, NewError(OperationError, "unable to marshal key to PKCS8 format: "+err.Error())
+		}
+
+		return bytes, nil
+	case JwkKeyFormat:
+		return exportOKPJWK(alg.Name, ck.handle)
+	default:
+		return nil, NewError(NotSupportedError, unsupportedKeyFormatErrorMsg+" "+format)
+	}
+}
+
+func okpPublicKeyBytes(handle any) ([]byte, error) {
+	switch k := handle.(type) {
+	case ed25519.PublicKey:
+		return []byte(k), nil
+	case ed25519.PrivateKey:
+		pub, _ := k.Public().(ed25519.PublicKey)
+		return []byte(pub), nil
+	case *ecdh.PublicKey:
+		return k.Bytes(), nil
+	case *ecdh.PrivateKey:
+		return k.PublicKey().Bytes(), nil
+	default:
+		return nil, NewError(OperationError, "key data isn't a valid Ed25519/X25519 key")
+	}
+}
+
+// ed25519SignerVerifier signs and verifies data using the Ed25519 algorithm,
+// which doesn't take any parameter.
+type ed25519SignerVerifier struct{}
+
+var _ SignerVerifier = &ed25519SignerVerifier{}
+
+// Sign .
+func (edsv *ed25519SignerVerifier) Sign(key CryptoKey, data []byte) ([]byte, error) {
+	if key.Type != PrivateCryptoKeyType {
+		return nil, NewError(InvalidAccessError, "key is not a valid Ed25519 private key")
+	}
+
+	k, ok := key.handle.(ed25519.PrivateKey)
+	if !ok {
+		return nil, NewError(InvalidAccessError, "key is not a valid Ed25519 private key")
+	}
+
+	return ed25519.Sign(k, data), nil
+}
+
+// Verify .
+func (edsv *ed25519SignerVerifier) Verify(key CryptoKey, signature []byte, data []byte) (bool, error) {
+	if key.Type != PublicCryptoKeyType {
+		return false, NewError(InvalidAccessError, "key is not a valid Ed25519 public key")
+	}
+
+	k, ok := key.handle.(ed25519.PublicKey)
+	if !ok {
+		return false, NewError(InvalidAccessError, "key is not a valid Ed25519 public key")
+	}
+
+	if len(signature) != ed25519.SignatureSize {
+		return false, nil
+	}
+
+	return ed25519.Verify(k, data, signature), nil
+}
+
+// X25519KeyDeriveParams represents the object that should be passed as the algorithm
+// parameter into `SubtleCrypto.DeriveBits`, when the algorithm is identified as X25519.
+type X25519KeyDeriveParams struct {
+	Algorithm
+	Public *CryptoKey
+}
+
+var _ BitsDeriver = &X25519KeyDeriveParams{}
+
+func newX25519KeyDeriveParams(
+	rt *sobek.Runtime, normalized Algorithm, params sobek.Value,
+) (*X25519KeyDeriveParams, error) {
+	// The parameters have the same shape as the ECDH ones.
+	ecdhParams, err := newECDHKeyDeriveParams(rt, normalized, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &X25519KeyDeriveParams{
+		Algorithm: ecdhParams.Algorithm,
+		Public:    ecdhParams.Public,
+	}, nil
+}
+
+// DeriveBits derives the shared secret of the private key and the public key
+// of the parameters.
+func (keyParams X25519KeyDeriveParams) DeriveBits(privateKey *CryptoKey, length int) ([]byte, error) {
+	if err := privateKey.Validate(); err != nil {
+		return nil, NewError(InvalidAccessError, "provided baseKey is not a valid CryptoKey: "+err.Error())
+	}
+
+	if privateKey.Type != PrivateCryptoKeyType {
+		return nil, NewError(InvalidAccessError, "provided baseKey is not a private key")
+	}
+
+	if !privateKey.ContainsUsage(DeriveBitsCryptoKeyUsage) {
+		return nil, NewError(InvalidAccessError, "provided baseKey does not contain the 'deriveBits' usage")
+	}
+
+	pk, ok := privateKey.handle.(*ecdh.PrivateKey)
+	if !ok || pk.Curve() != ecdh.X25519() {
+		return nil, NewError(InvalidAccessError, "key is not a valid X25519 private key")
+	}
+	pc, ok := keyParams.Public.handle.(*ecdh.PublicKey)
+	if !ok || pc.Curve() != ecdh.X25519() {
+		return nil, NewError(InvalidAccessError, "key is not a valid X25519 public key")
+	}
+
+	// As required by the specification, the shared secret of a small order
+	// public key, which is all zeros, is rejected by ECDH.
+	b, err := pk.ECDH(pc)
+	if err != nil {
+		return nil, NewError(OperationError, "unable to derive the shared secret: "+err.Error())
+	}
+
+	if len(b) < length/8 {
+		return nil, NewError(OperationError, "length is too large")
+	}
+
+	return b[:length/8], nil
+}
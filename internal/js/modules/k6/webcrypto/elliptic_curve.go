@@ -116,7 +116,7 @@ func importECDHPublicKey(curve EllipticCurveKind, keyData []byte) (any, CryptoKe
 	return handle, PublicCryptoKeyType, nil
 }
 
-func importECDHSPKIPublicKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKeyType, error) {
+func importECDHSPKIPublicKey(curve EllipticCurveKind, keyData []byte) (any, CryptoKeyType, error) {
 	pk, err := x509.ParsePKIXPublicKey(keyData)
 	if err != nil {
 		return nil, UnknownCryptoKeyType, NewError(DataError, "unable to import ECDH public key data: "+err.Error())
@@ -127,6 +127,10 @@ func importECDHSPKIPublicKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKe
 		return nil, UnknownCryptoKeyType, NewError(DataError, "a public key is not an ECDSA key")
 	}
 
+	if err := checkEllipticCurve(curve, ecdsaKey.Curve); err != nil {
+		return nil, UnknownCryptoKeyType, err
+	}
+
 	// try to restore the ECDH key
 	key, err := ecdsaKey.ECDH()
 	if err != nil {
@@ -136,7 +140,7 @@ func importECDHSPKIPublicKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKe
 	return key, PublicCryptoKeyType, nil
 }
 
-func importECDSASPKIPublicKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKeyType, error) {
+func importECDSASPKIPublicKey(curve EllipticCurveKind, keyData []byte) (any, CryptoKeyType, error) {
 	pk, err := x509.ParsePKIXPublicKey(keyData)
 	if err != nil {
 		return nil, UnknownCryptoKeyType, NewError(DataError, "unable to import ECDH public key data: "+err.Error())
@@ -147,6 +151,10 @@ func importECDSASPKIPublicKey(_ EllipticCurveKind, keyData []byte) (any, CryptoK
 		return nil, UnknownCryptoKeyType, NewError(DataError, "a public key is not an ECDSA key")
 	}
 
+	if err := checkEllipticCurve(curve, ecdsaKey.Curve); err != nil {
+		return nil, UnknownCryptoKeyType, err
+	}
+
 	// try to restore the ECDH key
 	return ecdsaKey, PublicCryptoKeyType, nil
 }
@@ -184,7 +192,7 @@ func IsEllipticCurve(name string) bool {
 	}
 }
 
-func importECDHPrivateKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKeyType, error) {
+func importECDHPrivateKey(curve EllipticCurveKind, keyData []byte) (any, CryptoKeyType, error) {
 	parsedKey, err := x509.ParsePKCS8PrivateKey(keyData)
 	if err != nil {
 		return nil, UnknownCryptoKeyType, NewError(DataError, "unable to import ECDH private key data: "+err.Error())
@@ -196,6 +204,10 @@ func importECDHPrivateKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKeyTy
 		return nil, UnknownCryptoKeyType, NewError(DataError, "a private key is not an ECDSA key")
 	}
 
+	if err := checkEllipticCurve(curve, ecdsaKey.Curve); err != nil {
+		return nil, UnknownCryptoKeyType, err
+	}
+
 	// try to restore the ECDH key
 	handle, err := ecdsaKey.ECDH()
 	if err != nil {
@@ -205,7 +217,7 @@ func importECDHPrivateKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKeyTy
 	return handle, PrivateCryptoKeyType, nil
 }
 
-func importECDSAPrivateKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKeyType, error) {
+func importECDSAPrivateKey(curve EllipticCurveKind, keyData []byte) (any, CryptoKeyType, error) {
 	parsedKey, err := x509.ParsePKCS8PrivateKey(keyData)
 	if err != nil {
 		return nil, UnknownCryptoKeyType, NewError(DataError, "unable to import ECDSA private key data: "+err.Error())
@@ -216,6 +228,10 @@ func importECDSAPrivateKey(_ EllipticCurveKind, keyData []byte) (any, CryptoKeyT
 		return nil, UnknownCryptoKeyType, NewError(DataError, "a private key is not an ECDSA key")
 	}
 
+	if err := checkEllipticCurve(curve, ecdsaKey.Curve); err != nil {
+		return nil, UnknownCryptoKeyType, err
+	}
+
 	return ecdsaKey, PrivateCryptoKeyType, nil
 }
 
@@ -400,6 +416,16 @@ func pickECDHCurve(k string) (ecdh.Curve, error) {
 	}
 }
 
+// checkEllipticCurve returns a DataError if the curve of an imported key
+// doesn't match the namedCurve the import was requested for.
+func checkEllipticCurve(expected EllipticCurveKind, c elliptic.Curve) error {
+	if c == nil || c.Params().Name != expected.String() {
+		return NewError(DataError, "key's elliptic curve doesn't match the requested namedCurve "+expected.String())
+	}
+
+	return nil
+}
+
 func pickEllipticCurve(k string) (elliptic.Curve, error) {
 	switch k {
 	case p256Canonical:
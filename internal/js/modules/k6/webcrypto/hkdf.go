@@ -0,0 +1,177 @@
+package webcrypto
+
+import (
+	"crypto/hkdf"
+
+	"github.com/grafana/sobek"
+	"go.k6.io/k6/v2/js/common"
+)
+
+// HKDFKeyImportParams represents the object that should be passed as the algorithm parameter
+// into `SubtleCrypto.ImportKey`, when importing the input key material of an HKDF
+// derivation: that is, when the algorithm is identified as HKDF.
+type HKDFKeyImportParams struct {
+	Algorithm
+}
+
+func newHKDFImportParams(normalized Algorithm) *HKDFKeyImportParams {
+	return &HKDFKeyImportParams{
+		Algorithm: normalized,
+	}
+}
+
+// HKDFKeyAlgorithm is the algorithm for HKDF keys as defined in the [specification].
+//
+// [specification]: https://www.w3.org/TR/WebCryptoAPI/#hkdf
+type HKDFKeyAlgorithm struct {
+	Algorithm
+}
+
+// Ensure that HKDF types implement the expected interfaces.
+var (
+	_ KeyImporter = &HKDFKeyImportParams{}
+	_ BitsDeriver = &HKDFParams{}
+	_ KeyDeriver  = &HKDFParams{}
+)
+
+// ImportKey imports the input key material of an HKDF derivation as a secret CryptoKey.
+func (keyParams HKDFKeyImportParams) ImportKey(
+	format KeyFormat,
+	keyData []byte,
+	extractable bool,
+	keyUsages []CryptoKeyUsage,
+) (*CryptoKey, error) {
+	if format != RawKeyFormat {
+		return nil, NewError(NotSupportedError, "invalid format: "+format)
+	}
+
+	for _, usage := range keyUsages {
+		switch usage {
+		case DeriveBitsCryptoKeyUsage, DeriveKeyCryptoKeyUsage:
+			continue
+		default:
+			return nil, NewError(SyntaxError, "invalid key usage: "+usage)
+		}
+	}
+
+	if extractable {
+		return nil, NewError(SyntaxError, "invalid value for param extractable ")
+	}
+
+	return &CryptoKey{
+		Algorithm: HKDFKeyAlgorithm(keyParams),
+		Type:      SecretCryptoKeyType,
+		handle:    keyData,
+	}, nil
+}
+
+func newHKDFParams(rt *sobek.Runtime, normalized Algorithm, params sobek.Value) (*HKDFParams, error) {
+	hashValue, err := traverseObject(rt, params, "hash")
+	if err != nil {
+		return nil, NewError(SyntaxError, "could not get hash from algorithm parameter")
+	}
+
+	normalizedHash, err := normalizeAlgorithm(rt, hashValue, OperationIdentifierDeriveBits)
+	if err != nil {
+		return nil, err
+	}
+
+	salt, err := traverseObject(rt, params, "salt")
+	if err != nil {
+		return nil, err
+	}
+
+	byteSalt, err := common.ToBytes(salt.Export())
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := traverseObject(rt, params, "info")
+	if err != nil {
+		return nil, err
+	}
+
+	byteInfo, err := common.ToBytes(info.Export())
+	if err != nil {
+		return nil, err
+	}
+
+	return &HKDFParams{
+		Name: normalized.Name,
+		Hash: normalizedHash.Name,
+		Salt: byteSalt,
+		Info: byteInfo,
+	}, nil
+}
+
+// DeriveBits derives length bits from the HKDF base key.
+func (keyParams HKDFParams) DeriveBits(
+	baseKey *CryptoKey,
+	length int,
+) ([]byte, error) {
+	ikm, err := keyParams.validate(baseKey, OperationIdentifierDeriveBits)
+	if err != nil {
+		return nil, err
+	}
+
+	return keyParams.derive(ikm, length)
+}
+
+// DeriveKey derives a key of the length expected by the given importer from the HKDF base key.
+func (keyParams HKDFParams) DeriveKey(
+	baseKey *CryptoKey,
+	ki KeyImporter,
+	kgl KeyGetLengther,
+	keyUsages []CryptoKeyUsage,
+	extractable bool,
+) (*CryptoKey, error) {
+	ikm, err := keyParams.validate(baseKey, OperationIdentifierDeriveKey)
+	if err != nil {
+		return nil, err
+	}
+
+	dk, err := keyParams.derive(ikm, kgl.GetKeyLength())
+	if err != nil {
+		return nil, err
+	}
+
+	return ki.ImportKey(RawKeyFormat, dk, extractable, keyUsages)
+}
+
+func (keyParams HKDFParams) validate(baseKey *CryptoKey, usage CryptoKeyUsage) ([]byte, error) {
+	ikm, err := validateBaseKey(baseKey, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	alg, ok := baseKey.Algorithm.(HKDFKeyAlgorithm)
+	if !ok {
+		return nil, NewError(OperationError, "provided baseKey is not a valid algorithm")
+	}
+
+	if alg.Name != keyParams.Name {
+		return nil, NewError(OperationError,
+			"provided basekey algorithm and deriveKey algorithm name dont match "+alg.Name+"!="+keyParams.Name,
+		)
+	}
+
+	return ikm, nil
+}
+
+func (keyParams HKDFParams) derive(ikm []byte, length int) ([]byte, error) {
+	if length == 0 || length%8 != 0 {
+		return nil, NewError(OperationError, "provided length of key must be a non-zero multiple of 8")
+	}
+
+	hashFn, ok := getHashFn(keyParams.Hash)
+	if !ok {
+		return nil, NewError(NotSupportedError, "hash function not supported")
+	}
+
+	dk, err := hkdf.Key(hashFn, ikm, keyParams.Salt, string(keyParams.Info), length/8)
+	if err != nil {
+		return nil, NewError(OperationError, err.Error())
+	}
+
+	return dk, nil
+}
@@ -1,8 +1,10 @@
 package webcrypto
 
 import (
+	"bytes"
 	"crypto/ecdh"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rsa"
 	"encoding/json"
@@ -17,6 +19,10 @@ const (
 
 	// JWKOctKeyType represents the symmetric key type.
 	JWKOctKeyType = "oct"
+
+	// JWKOKPKeyType represents the octet key pair key type, of the Ed25519
+	// and X25519 keys.
+	JWKOKPKeyType = "OKP"
 )
 
 // JsonWebKey represents a JSON Web Key (JsonWebKey) key.
@@ -292,6 +298,108 @@ func importECDHJWK(_ EllipticCurveKind, jsonKeyData []byte) (any, CryptoKeyType,
 	}
 }
 
+// okpJWK represents an OKP JWK key, as defined by [RFC 8037].
+// It is used to unmarshal Ed25519 and X25519 keys to and from JWK format.
+//
+// [RFC 8037]: https://www.rfc-editor.org/rfc/rfc8037
+type okpJWK struct {
+	// Key type
+	Kty string `json:"kty"`
+	// Subtype of the key, the name of the algorithm
+	Crv string `json:"crv"`
+	// Public key
+	X string `json:"x"`
+	// Private key
+	D string `json:"d"`
+}
+
+func (jwk *okpJWK) validate(algorithm AlgorithmIdentifier) error {
+	if jwk.Kty != JWKOKPKeyType {
+		return fmt.Errorf("invalid key type: %s", jwk.Kty)
+	}
+
+	if jwk.Crv != algorithm {
+		return fmt.Errorf("invalid curve %q for algorithm %s", jwk.Crv, algorithm)
+	}
+
+	if jwk.X == "" {
+		return errors.New("public key X is required")
+	}
+
+	return nil
+}
+
+func importOKPJWK(algorithm AlgorithmIdentifier, jsonKeyData []byte) (any, CryptoKeyType, error) {
+	var jwkKey okpJWK
+	if err := json.Unmarshal(jsonKeyData, &jwkKey); err != nil {
+		return nil, UnknownCryptoKeyType, NewError(DataError, "failed to parse input as OKP JWK key: "+err.Error())
+	}
+
+	if err := jwkKey.validate(algorithm); err != nil {
+		return nil, UnknownCryptoKeyType, NewError(DataError, "invalid OKP JWK key: "+err.Error())
+	}
+
+	x, err := base64URLDecode(jwkKey.X)
+	if err != nil {
+		return nil, UnknownCryptoKeyType, NewError(DataError, "failed to decode X: "+err.Error())
+	}
+
+	if jwkKey.D == "" {
+		return importOKPPublicKey(algorithm, x)
+	}
+
+	d, err := base64URLDecode(jwkKey.D)
+	if err != nil {
+		return nil, UnknownCryptoKeyType, NewError(DataError, "failed to decode D: "+err.Error())
+	}
+
+	var (
+		key       any
+		publicKey []byte
+	)
+	switch algorithm {
+	case Ed25519:
+		if len(d) != ed25519.SeedSize {
+			return nil, UnknownCryptoKeyType, NewError(DataError, "invalid Ed25519 private key length")
+		}
+		privateKey := ed25519.NewKeyFromSeed(d)
+		key, publicKey = privateKey, privateKey.Public().(ed25519.PublicKey) //nolint:forcetypeassert
+	default:
+		privateKey, err := ecdh.X25519().NewPrivateKey(d)
+		if err != nil {
+			return nil, UnknownCryptoKeyType, NewError(DataError, "unable to import X25519 private key: "+err.Error())
+		}
+		key, publicKey = privateKey, privateKey.PublicKey().Bytes()
+	}
+
+	if !bytes.Equal(publicKey, x) {
+		return nil, UnknownCryptoKeyType, NewError(DataError, "the public key X doesn't match the private key D")
+	}
+
+	return key, PrivateCryptoKeyType, nil
+}
+
+func exportOKPJWK(algorithm AlgorithmIdentifier, handle any) (any, error) {
+	exported := &JsonWebKey{}
+	exported.Set("kty", JWKOKPKeyType)
+	exported.Set("crv", algorithm)
+
+	x, err := okpPublicKeyBytes(handle)
+	if err != nil {
+		return nil, err
+	}
+	exported.Set("x", base64URLEncode(x))
+
+	switch k := handle.(type) {
+	case ed25519.PrivateKey:
+		exported.Set("d", base64URLEncode(k.Seed()))
+	case *ecdh.PrivateKey:
+		exported.Set("d", base64URLEncode(k.Bytes()))
+	}
+
+	return exported, nil
+}
+
 type rsaJWK struct {
 	Kty string `json:"kty"`          // Key Type
 	N   string `json:"n"`            // Modulus
@@ -426,3 +534,50 @@ func exportRSAJWK(key *CryptoKey) (any, error) {
 
 	return exported, nil
 }
+
+// ImportJWK imports the key held by the given JSON Web Key, whatever its key
+// type, so that other modules can accept the same keys as `SubtleCrypto.ImportKey`.
+//
+// It returns the raw bytes of the "oct" keys, and the *rsa.PublicKey, *rsa.PrivateKey,
+// *ecdsa.PublicKey, *ecdsa.PrivateKey, ed25519.PublicKey, ed25519.PrivateKey,
+// *ecdh.PublicKey or *ecdh.PrivateKey of the "RSA", "EC" and "OKP" ones.
+func ImportJWK(jsonKeyData []byte) (any, CryptoKeyType, error) {
+	var header struct {
+		Kty string `json:"kty"`
+		Crv string `json:"crv"`
+	}
+	if err := json.Unmarshal(jsonKeyData, &header); err != nil {
+		return nil, UnknownCryptoKeyType, fmt.Errorf("failed to parse input as JWK key: %w", err)
+	}
+
+	switch header.Kty {
+	case JWKOctKeyType:
+		key, err := extractSymmetricJWK(jsonKeyData)
+		if err != nil {
+			return nil, UnknownCryptoKeyType, err
+		}
+
+		return key, SecretCryptoKeyType, nil
+	case JWKECKeyType:
+		return importECDSAJWK(EllipticCurveKind(header.Crv), jsonKeyData)
+	case JWKOKPKeyType:
+		if header.Crv != Ed25519 && header.Crv != X25519 {
+			return nil, UnknownCryptoKeyType, fmt.Errorf("unsupported OKP JWK curve: %q", header.Crv)
+		}
+
+		return importOKPJWK(header.Crv, jsonKeyData)
+	case "RSA":
+		key, keyType, _, err := importRSAJWK(jsonKeyData)
+		if err != nil {
+			return nil, UnknownCryptoKeyType, err
+		}
+
+		if pk, ok := key.(rsa.PublicKey); ok {
+			return &pk, keyType, nil
+		}
+
+		return key, keyType, nil
+	default:
+		return nil, UnknownCryptoKeyType, fmt.Errorf("unsupported JWK key type: %q", header.Kty)
+	}
+}
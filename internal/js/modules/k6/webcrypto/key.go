@@ -192,8 +192,12 @@ func newKeyGenerator(rt *sobek.Runtime, normalized Algorithm, params sobek.Value
 		kg, err = newECKeyGenParams(rt, normalized, params)
 	case RSASsaPkcs1v15, RSAPss, RSAOaep:
 		kg, err = newRsaHashedKeyGenParams(rt, normalized, params)
+	case Ed25519, X25519:
+		kg = newOKPKeyGenParams(normalized)
 	default:
-		validAlgorithms := []string{AESCbc, AESCtr, AESGcm, AESKw, HMAC, ECDH, ECDSA, RSASsaPkcs1v15, RSAPss, RSAOaep}
+		validAlgorithms := []string{
+			AESCbc, AESCtr, AESGcm, AESKw, HMAC, ECDH, ECDSA, RSASsaPkcs1v15, RSAPss, RSAOaep, Ed25519, X25519,
+		}
 		return nil, NewError(
 			NotImplemented,
 			"unsupported key generation algorithm '"+normalized.Name+"', "+
@@ -226,6 +230,8 @@ func newKeyDeriver(rt *sobek.Runtime, normalized Algorithm, params sobek.Value)
 	switch normalized.Name {
 	case PBKDF2:
 		kd, err = newPBKDF2DeriveParams(rt, normalized, params)
+	case HKDF:
+		kd, err = newHKDFParams(rt, normalized, params)
 	default:
 		return nil, errors.New("key derivation not implemented for algorithm " + normalized.Name)
 	}
@@ -282,6 +288,10 @@ func newKeyImporter(rt *sobek.Runtime, normalized Algorithm, params sobek.Value)
 		ki, err = newRsaHashedImportParams(rt, normalized, params)
 	case PBKDF2:
 		ki = newPBKDF2ImportParams(normalized)
+	case HKDF:
+		ki = newHKDFImportParams(normalized)
+	case Ed25519, X25519:
+		ki = newOKPKeyImportParams(normalized)
 	default:
 		return nil, errors.New("key import not implemented for algorithm " + normalized.Name)
 	}
@@ -280,7 +280,7 @@ func importRSAPrivateKey(keyData []byte) (any, CryptoKeyType, int, error) {
 
 	privateKey, ok := parsedKey.(*rsa.PrivateKey)
 	if !ok {
-		return nil, UnknownCryptoKeyType, 0, NewError(DataError, fmt.Sprintf(errMsgNotExpectedPrivateKey, "RSA", privateKey))
+		return nil, UnknownCryptoKeyType, 0, NewError(DataError, fmt.Sprintf(errMsgNotExpectedPrivateKey, "RSA", parsedKey))
 	}
 
 	return privateKey, PrivateCryptoKeyType, privateKey.N.BitLen(), nil
@@ -294,7 +294,7 @@ func importRSAPublicKey(keyData []byte) (any, CryptoKeyType, int, error) {
 
 	publicKey, ok := parsedKey.(*rsa.PublicKey)
 	if !ok {
-		return nil, UnknownCryptoKeyType, 0, NewError(DataError, fmt.Sprintf(errMsgNotExpectedPublicKey, "RSA", publicKey))
+		return nil, UnknownCryptoKeyType, 0, NewError(DataError, fmt.Sprintf(errMsgNotExpectedPublicKey, "RSA", parsedKey))
 	}
 
 	return publicKey, PublicCryptoKeyType, publicKey.N.BitLen(), nil
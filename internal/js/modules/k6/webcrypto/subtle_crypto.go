@@ -212,6 +212,7 @@ func (sc *SubtleCrypto) Decrypt( //nolint:dupl // we have two similar methods
 //   - an `SubtleCrypto.RSAPssParams` object
 //   - an `SubtleCrypto.EcdsaParams` object
 //   - the string "HMAC" or an object of the form `{ "name": "HMAC" }`
+//   - the string "Ed25519" or an object of the form `{ "name": "Ed25519" }`
 //
 // The `key` parameter should be a `CryptoKey` to be used for signing. Note that if
 // `algorithm` identifies a public-key cryptosystem, this is the private key.
@@ -306,6 +307,7 @@ func (sc *SubtleCrypto) Sign(algorithm, key, data sobek.Value) (*sobek.Promise,
 //   - an `SubtleCrypto.RSAPssParams` object
 //   - an `SubtleCrypto.EcdsaParams` object
 //   - the string "HMAC" or an object of the form `{ "name": "HMAC" }`
+//   - the string "Ed25519" or an object of the form `{ "name": "Ed25519" }`
 //
 // The `key` parameter should be a `CryptoKey` to be used for verification. Note that it
 // is the secret key for a symmetric algorithm and the public key for a public-key system.
@@ -483,6 +485,7 @@ func (sc *SubtleCrypto) Digest(algorithm sobek.Value, data sobek.Value) (*sobek.
 //   - for ECDSA or ECDH: pass an `SubtleCrypto.ECKeyGenParams` object
 //   - an `SubtleCrypto.HMACKeyGenParams` object
 //   - for AES-CTR, AES-CBC, AES-GCM, AES-KW: pass an `SubtleCrypto.AESKeyGenParams`
+//   - for Ed25519 or X25519: pass the string identifying the algorithm
 //
 // The `extractable` parameter indicates whether it will be possible to export the key
 // using `SubtleCrypto.ExportKey` or `SubtleCrypto.WrapKey`.
@@ -721,12 +724,12 @@ func getKeyInteractors(
 // be a password, imported as a `CryptoKey` using `SubtleCrypto.ImportKey`.
 //
 // The `algorithm` parameter should be one of:
-//   - an `SubtleCrypto.ECDHKeyDeriveParams` object
+//   - an `SubtleCrypto.ECDHKeyDeriveParams` object, also used for X25519
 //   - an `SubtleCrypto.HKDFParams` object
 //   - an `SubtleCrypto.PBKDF2Params` object
 //
 // The `baseKey` parameter should be a `CryptoKey` object representing the input to the derivation algorithm.
-// If `algorithm` is ECDH, then this will be the ECDH private key. Otherwise it will be the initial key material
+// If `algorithm` is ECDH or X25519, then this will be the matching private key. Otherwise it will be the initial key material
 // for the derivation function: for example, for PBKDF2 it might be a password, imported as a `CryptoKey`
 // using `SubtleCrypto.ImportKey`.
 //
@@ -818,6 +821,7 @@ func (sc *SubtleCrypto) DeriveBits(
 //     `ALGORITHM` is the name of the algorithm.
 //   - for PBKDF2: pass the string "PBKDF2"
 //   - for HKDF: pass the string "HKDF"
+//   - for Ed25519 or X25519: pass the string identifying the algorithm
 func (sc *SubtleCrypto) ImportKey( //nolint:funlen // we have a lot of error handling
 	format KeyFormat,
 	keyData sobek.Value,
@@ -963,6 +967,8 @@ func (sc *SubtleCrypto) ExportKey( //nolint:funlen // we have a lot of error han
 			keyExporter = exportECKey
 		case RSASsaPkcs1v15, RSAOaep, RSAPss:
 			keyExporter = exportRSAKey
+		case Ed25519, X25519:
+			keyExporter = exportOKPKey
 		default:
 			return NewError(NotSupportedError, "unsupported algorithm "+algorithm.Name)
 		}
@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vectorHelpers converts the hexadecimal test vectors, and prefixes the
+// private keys with the PKCS #8 header of their algorithm (RFC 8410).
+const vectorHelpers = `
+	function hex(s) {
+		return new Uint8Array((s.match(/../g) || []).map((b) => parseInt(b, 16)));
+	}
+	function toHex(buf) {
+		return Array.from(new Uint8Array(buf), (b) => b.toString(16).padStart(2, "0")).join("");
+	}
+	function assertEqual(actual, expected, message) {
+		if (actual !== expected) {
+			throw new Error(message + ": expected " + expected + ", got " + actual);
+		}
+	}
+	const pkcs8Prefixes = {
+		Ed25519: "302e020100300506032b657004220420",
+		X25519: "302e020100300506032b656e04220420",
+	};
+	function importPrivateKey(name, key, usages) {
+		return crypto.subtle.importKey("pkcs8", hex(pkcs8Prefixes[name] + key), { name }, true, usages);
+	}
+`
+
+func runVectors(t *testing.T, script string) {
+	t.Helper()
+
+	ts := newConfiguredRuntime(t)
+	_, err := ts.RunOnEventLoop(vectorHelpers + `(async function () {` + script + `})()`)
+	require.NoError(t, err)
+}
+
+func TestEd25519Vectors(t *testing.T) {
+	t.Parallel()
+
+	// https://www.rfc-editor.org/rfc/rfc8032#section-7.1, TEST 1 and TEST 3
+	runVectors(t, `
+		const vectors = [
+			{
+				secretKey: "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60",
+				publicKey: "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+				message: "",
+				signature: "e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b",
+			},
+			{
+				secretKey: "c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7",
+				publicKey: "fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025",
+				message: "af82",
+				signature: "6291d657deec24024827e69c3abe01a30ce548a284743a445e3680d7db5ac3ac18ff9b538d16f290ae67f760984dc6594a7c15e9716ed28dc027beceea1ec40a",
+			},
+		];
+
+		for (const v of vectors) {
+			const privateKey = await importPrivateKey("Ed25519", v.secretKey, ["sign"]);
+			const signature = await crypto.subtle.sign({ name: "Ed25519" }, privateKey, hex(v.message));
+			assertEqual(toHex(signature), v.signature, "signature");
+
+			const jwk = await crypto.subtle.exportKey("jwk", privateKey);
+			assertEqual(jwk.x, btoa(hex(v.publicKey).buffer, "rawurl"), "public key of the private key");
+
+			const publicKey = await crypto.subtle.importKey("raw", hex(v.publicKey), { name: "Ed25519" }, true, ["verify"]);
+			assertEqual(await crypto.subtle.verify({ name: "Ed25519" }, publicKey, hex(v.signature), hex(v.message)),
+				true, "verification");
+
+			const tampered = hex(v.signature);
+			tampered[0] ^= 1;
+			assertEqual(await crypto.subtle.verify({ name: "Ed25519" }, publicKey, tampered, hex(v.message)),
+				false, "verification of a tampered signature");
+		}
+	`)
+}
+
+func TestX25519Vectors(t *testing.T) {
+	t.Parallel()
+
+	runVectors(t, `
+		// https://www.rfc-editor.org/rfc/rfc7748#section-6.1
+		const alice = await importPrivateKey("X25519",
+			"77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a", ["deriveBits"]);
+		const bob = await importPrivateKey("X25519",
+			"5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb", ["deriveBits"]);
+		const alicePublic = "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a";
+		const bobPublic = "de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f";
+		const shared = "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742";
+
+		assertEqual((await crypto.subtle.exportKey("jwk", alice)).x, btoa(hex(alicePublic).buffer, "rawurl"),
+			"Alice's public key");
+		assertEqual((await crypto.subtle.exportKey("jwk", bob)).x, btoa(hex(bobPublic).buffer, "rawurl"),
+			"Bob's public key");
+
+		for (const [privateKey, publicKey] of [[alice, bobPublic], [bob, alicePublic]]) {
+			const public = await crypto.subtle.importKey("raw", hex(publicKey), { name: "X25519" }, true, []);
+			const bits = await crypto.subtle.deriveBits({ name: "X25519", public }, privateKey, 256);
+			assertEqual(toHex(bits), shared, "shared secret");
+		}
+
+		// https://www.rfc-editor.org/rfc/rfc7748#section-5.2, the first vector
+		const scalar = await importPrivateKey("X25519",
+			"a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4", ["deriveBits"]);
+		const u = await crypto.subtle.importKey("raw",
+			hex("e6db6867583030db3594c1a424b15f7c726624ec26b3353b10a903a6d0ab1c4c"), { name: "X25519" }, true, []);
+		const output = await crypto.subtle.deriveBits({ name: "X25519", public: u }, scalar, 256);
+		assertEqual(toHex(output), "c3da55379de9c6908e94ea4df28d084f32eccf03491c71f754b4075577a28552", "scalar multiplication");
+	`)
+}
+
+func TestHKDFVectors(t *testing.T) {
+	t.Parallel()
+
+	// https://www.rfc-editor.org/rfc/rfc5869#appendix-A, test cases 1, 3 and 4
+	runVectors(t, `
+		const vectors = [
+			{
+				hash: "SHA-256",
+				ikm: "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+				salt: "000102030405060708090a0b0c",
+				info: "f0f1f2f3f4f5f6f7f8f9",
+				okm: "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+			},
+			{
+				hash: "SHA-256",
+				ikm: "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b",
+				salt: "",
+				info: "",
+				okm: "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d9d201395faa4b61a96c8",
+			},
+			{
+				hash: "SHA-1",
+				ikm: "0b0b0b0b0b0b0b0b0b0b0b",
+				salt: "000102030405060708090a0b0c",
+				info: "f0f1f2f3f4f5f6f7f8f9",
+				okm: "085a01ea1b10f36933068b56efa5ad81a4f14b822f5b091568a9cdd4f155fda2c22e422478d305f3f896",
+			},
+		];
+
+		for (const v of vectors) {
+			const key = await crypto.subtle.importKey("raw", hex(v.ikm), "HKDF", false, ["deriveBits"]);
+			const bits = await crypto.subtle.deriveBits(
+				{ name: "HKDF", hash: v.hash, salt: hex(v.salt), info: hex(v.info) }, key, v.okm.length * 4);
+			assertEqual(toHex(bits), v.okm, "output keying material with " + v.hash);
+		}
+	`)
+}
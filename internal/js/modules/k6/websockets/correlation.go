@@ -0,0 +1,259 @@
+package websockets
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/grafana/sobek"
+	"github.com/tidwall/gjson"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/lib/types"
+	"go.k6.io/k6/v2/metrics"
+)
+
+const defaultCorrelationTimeout = 60 * time.Second
+
+// correlator matches sent and received application messages by a key
+// extracted from each of them, in order to measure their round-trip time.
+//
+// The key extraction runs on the event loop, as it might call into js, while
+// the timestamps are taken in the read and write pumps, so the measured time
+// isn't affected by how busy the event loop is.
+type correlator struct {
+	extract func(*message) (string, bool, error)
+	timeout time.Duration
+
+	// m guards the pending messages, as the write pump updates their sent time
+	m       sync.Mutex
+	pending map[string]*pendingMessage
+}
+
+type pendingMessage struct {
+	sent  time.Time
+	timer *time.Timer
+}
+
+// correlate registers, or removes if the extractor is nullish, the correlation
+// of the sent and received messages.
+//
+// The extractor is either a JSON path, applied to the messages' data, or a
+// function receiving the messages' data and returning the correlation key.
+// Messages for which no key is extracted aren't tracked.
+func (w *webSocket) correlate(extractor sobek.Value, options sobek.Value) {
+	rt := w.vu.Runtime()
+
+	if common.IsNullish(extractor) {
+		w.stopCorrelation(false)
+		w.correlator = nil
+		return
+	}
+
+	c := &correlator{
+		timeout: defaultCorrelationTimeout,
+		pending: make(map[string]*pendingMessage),
+	}
+
+	if fn, ok := sobek.AssertFunction(extractor); ok {
+		c.extract = func(msg *message) (string, bool, error) {
+			var data sobek.Value
+			if msg.mtype == websocket.BinaryMessage {
+				data = rt.ToValue(rt.NewArrayBuffer(msg.data))
+			} else {
+				data = rt.ToValue(string(msg.data))
+			}
+			key, err := fn(sobek.Undefined(), data)
+			if err != nil {
+				return "", false, err
+			}
+			if common.IsNullish(key) {
+				return "", false, nil
+			}
+			return key.String(), true, nil
+		}
+	} else {
+		path := extractor.String()
+		if path == "" {
+			common.Throw(rt, errors.New("the correlation JSON path can't be empty"))
+		}
+		c.extract = func(msg *message) (string, bool, error) {
+			result := gjson.GetBytes(msg.data, path)
+			if !result.Exists() {
+				return "", false, nil
+			}
+			return result.String(), true, nil
+		}
+	}
+
+	if !common.IsNullish(options) {
+		params := options.ToObject(rt)
+		for _, k := range params.Keys() {
+			switch k {
+			case "timeout":
+				timeout, err := types.GetDurationValue(params.Get(k).Export())
+				if err != nil {
+					common.Throw(rt, fmt.Errorf("invalid correlation timeout: %w", err))
+				}
+				if timeout <= 0 {
+					common.Throw(rt, fmt.Errorf("the correlation timeout should be positive, but got %s", timeout))
+				}
+				c.timeout = timeout
+			default:
+				common.Throw(rt, fmt.Errorf("unknown correlation option %s", k))
+			}
+		}
+	}
+
+	w.stopCorrelation(false)
+	w.correlator = c
+}
+
+// trackSent starts tracking a message that is about to be sent and sets its
+// correlation key. It needs to be called on the event loop.
+func (w *webSocket) trackSent(msg *message) {
+	c := w.correlator
+	if c == nil || (msg.mtype != websocket.TextMessage && msg.mtype != websocket.BinaryMessage) {
+		return
+	}
+
+	key, ok, err := c.extract(msg)
+	if err != nil {
+		common.Throw(w.vu.Runtime(), fmt.Errorf("failed to extract the correlation key of a sent message: %w", err))
+	}
+	if !ok {
+		return
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, inFlight := c.pending[key]; inFlight {
+		w.vu.State().Logger.Warnf("a message with correlation key %q is already waiting for a response", key)
+		return
+	}
+
+	pending := &pendingMessage{sent: msg.t}
+	pending.timer = time.AfterFunc(c.timeout, func() {
+		w.tq.Queue(func() error {
+			w.correlationTimedOut(c, key, pending)
+			return nil
+		})
+	})
+	c.pending[key] = pending
+	msg.correlator = c
+	msg.correlationKey = key
+}
+
+// markSent updates the sent time of a tracked message with the time it was
+// actually written to the connection.
+func (c *correlator) markSent(key string, t time.Time) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if pending, ok := c.pending[key]; ok {
+		pending.sent = t
+	}
+}
+
+// trackReceived matches a received message with a sent one and emits its
+// round-trip time. It needs to be called on the event loop.
+func (w *webSocket) trackReceived(msg *message) error {
+	c := w.correlator
+	if c == nil {
+		return nil
+	}
+
+	key, ok, err := c.extract(msg)
+	if err != nil {
+		return fmt.Errorf("failed to extract the correlation key of a received message: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	c.m.Lock()
+	pending, ok := c.pending[key]
+	if ok {
+		delete(c.pending, key)
+		pending.timer.Stop()
+	}
+	c.m.Unlock()
+	if !ok {
+		return nil
+	}
+
+	ctx := w.vu.Context()
+	metrics.PushIfNotDone(ctx, w.vu.State().Samples, metrics.ConnectedSamples{
+		Samples: []metrics.Sample{
+			{
+				TimeSeries: metrics.TimeSeries{Metric: w.metrics.MessageRTT, Tags: w.tagsAndMeta.Tags},
+				Time:       msg.t,
+				Metadata:   w.tagsAndMeta.Metadata,
+				Value:      metrics.D(msg.t.Sub(pending.sent)),
+			},
+			{
+				TimeSeries: metrics.TimeSeries{Metric: w.metrics.MessageTimeouts, Tags: w.tagsAndMeta.Tags},
+				Time:       msg.t,
+				Metadata:   w.tagsAndMeta.Metadata,
+				Value:      0,
+			},
+		},
+		Tags: w.tagsAndMeta.Tags,
+		Time: msg.t,
+	})
+	return nil
+}
+
+// correlationTimedOut is run on the event loop when a tracked message didn't
+// receive a response in time.
+func (w *webSocket) correlationTimedOut(c *correlator, key string, pending *pendingMessage) {
+	c.m.Lock()
+	current, ok := c.pending[key]
+	if ok && current == pending {
+		delete(c.pending, key)
+	}
+	c.m.Unlock()
+	if !ok || current != pending {
+		return
+	}
+
+	w.emitCorrelationTimeout(time.Now())
+}
+
+// stopCorrelation stops tracking all pending messages and if requested emits
+// them as unmatched. It needs to be called on the event loop.
+func (w *webSocket) stopCorrelation(emitUnmatched bool) {
+	c := w.correlator
+	if c == nil {
+		return
+	}
+
+	c.m.Lock()
+	unmatched := len(c.pending)
+	for key, pending := range c.pending {
+		pending.timer.Stop()
+		delete(c.pending, key)
+	}
+	c.m.Unlock()
+
+	if !emitUnmatched {
+		return
+	}
+	now := time.Now()
+	for range unmatched {
+		w.emitCorrelationTimeout(now)
+	}
+}
+
+func (w *webSocket) emitCorrelationTimeout(t time.Time) {
+	metrics.PushIfNotDone(w.vu.Context(), w.vu.State().Samples, metrics.Sample{
+		TimeSeries: metrics.TimeSeries{
+			Metric: w.metrics.MessageTimeouts,
+			Tags:   w.tagsAndMeta.Tags,
+		},
+		Time:     t,
+		Metadata: w.tagsAndMeta.Metadata,
+		Value:    1,
+	})
+}
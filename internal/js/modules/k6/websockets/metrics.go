@@ -0,0 +1,35 @@
+package websockets
+
+import "go.k6.io/k6/v2/metrics"
+
+const (
+	// messageRTTName is the name of the metric tracking the round-trip time
+	// of correlated application messages.
+	messageRTTName = "ws_msg_rtt"
+	// messageTimeoutsName is the name of the metric tracking the rate of
+	// correlated messages that didn't receive a response in time.
+	messageTimeoutsName = "ws_msg_timeouts"
+)
+
+// instanceMetrics contains the metrics for the websockets module
+// which aren't part of the builtin ones.
+type instanceMetrics struct {
+	MessageRTT      *metrics.Metric
+	MessageTimeouts *metrics.Metric
+}
+
+// registerMetrics registers and returns the metrics in the provided registry
+func registerMetrics(registry *metrics.Registry) (*instanceMetrics, error) {
+	var err error
+	m := &instanceMetrics{}
+
+	if m.MessageRTT, err = registry.NewMetric(messageRTTName, metrics.Trend, metrics.Time); err != nil {
+		return nil, err
+	}
+
+	if m.MessageTimeouts, err = registry.NewMetric(messageTimeoutsName, metrics.Rate); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
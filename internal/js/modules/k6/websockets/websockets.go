@@ -31,6 +31,7 @@ type RootModule struct{}
 type WebSocketsAPI struct { //nolint:revive
 	vu              modules.VU
 	blobConstructor sobek.Value
+	metrics         *instanceMetrics
 }
 
 var _ modules.Module = &RootModule{}
@@ -42,8 +43,14 @@ func New() *RootModule {
 
 // NewModuleInstance returns a new instance of the module
 func (r *RootModule) NewModuleInstance(vu modules.VU) modules.Instance {
+	metrics, err := registerMetrics(vu.InitEnv().Registry)
+	if err != nil {
+		common.Throw(vu.Runtime(), fmt.Errorf("failed to register websockets module metrics: %w", err))
+	}
+
 	return &WebSocketsAPI{
-		vu: vu,
+		vu:      vu,
+		metrics: metrics,
 	}
 }
 
@@ -81,6 +88,7 @@ type webSocket struct {
 	tagsAndMeta    *metrics.TagsAndMeta
 	tq             *taskqueue.TaskQueue
 	builtinMetrics *metrics.BuiltinMetrics
+	metrics        *instanceMetrics
 	obj            *sobek.Object // the object that is given to js to interact with the WebSocket
 	started        time.Time
 
@@ -91,6 +99,9 @@ type webSocket struct {
 
 	sendPings ping
 
+	// correlator is only accessed on the event loop, it is nil unless correlate was called
+	correlator *correlator
+
 	// fields that should be seen by js only be updated on the event loop
 	readyState     ReadyState
 	bufferedAmount int
@@ -141,6 +152,7 @@ func (r *WebSocketsAPI) websocket(c sobek.ConstructorCall) *sobek.Object {
 		tq:              taskqueue.New(r.vu.RegisterCallback),
 		readyState:      CONNECTING,
 		builtinMetrics:  r.vu.State().BuiltinMetrics,
+		metrics:         r.metrics,
 		done:            make(chan struct{}),
 		writeQueueCh:    make(chan message),
 		eventListeners:  newEventListeners(),
@@ -194,6 +206,8 @@ func defineWebsocket(rt *sobek.Runtime, w *webSocket) {
 		"ping", rt.ToValue(w.ping), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
 	must(rt, w.obj.DefineDataProperty(
 		"close", rt.ToValue(w.close), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
+	must(rt, w.obj.DefineDataProperty(
+		"correlate", rt.ToValue(w.correlate), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
 	must(rt, w.obj.DefineDataProperty(
 		"url", rt.ToValue(w.url.String()), sobek.FLAG_FALSE, sobek.FLAG_FALSE, sobek.FLAG_TRUE))
 	must(rt, w.obj.DefineAccessorProperty( // this needs to be with an accessor as we change the value
@@ -262,6 +276,10 @@ type message struct {
 	mtype int // message type consts as defined in gorilla/websocket/conn.go
 	data  []byte
 	t     time.Time
+
+	// set only for sent messages tracked by a correlator
+	correlator     *correlator
+	correlationKey string
 }
 
 // documented https://websockets.spec.whatwg.org/#concept-websocket-establish
@@ -465,6 +483,12 @@ func (w *webSocket) queueMessage(msg *message) {
 			Value:    1,
 		})
 
+		if err := w.trackReceived(msg); err != nil {
+			_ = w.conn.Close()                   // TODO log it?
+			_ = w.connectionClosedWithError(err) // TODO log it?
+			return err
+		}
+
 		rt := w.vu.Runtime()
 		ev := w.newEvent(events.MESSAGE, msg.t)
 
@@ -575,6 +599,9 @@ func (w *webSocket) writePump(wg *sync.WaitGroup) {
 					})
 					return
 				}
+				if msg.correlator != nil {
+					msg.correlator.markSent(msg.correlationKey, time.Now())
+				}
 				// This from the specification needs to happen like that instead of with
 				// atomics or locks outside of the event loop
 				w.tq.Queue(func() error {
@@ -624,12 +651,11 @@ func (w *webSocket) send(msg sobek.Value) {
 
 	switch o := msg.Export().(type) {
 	case string:
-		w.bufferedAmount += len(o)
-		w.writeQueueCh <- message{
+		w.sendMessage(message{
 			mtype: websocket.TextMessage,
 			data:  []byte(o),
 			t:     time.Now(),
-		}
+		})
 	case *sobek.ArrayBuffer:
 		w.sendArrayBuffer(*o)
 	case sobek.ArrayBuffer:
@@ -642,12 +668,11 @@ func (w *webSocket) send(msg sobek.Value) {
 		}
 
 		b := extractBytes(obj, rt)
-		w.bufferedAmount += len(b)
-		w.writeQueueCh <- message{
+		w.sendMessage(message{
 			mtype: websocket.BinaryMessage,
 			data:  b,
 			t:     time.Now(),
-		}
+		})
 	default:
 		rt := w.vu.Runtime()
 		isView, err := isArrayBufferView(rt, msg)
@@ -665,23 +690,28 @@ func (w *webSocket) send(msg sobek.Value) {
 			common.Throw(rt,
 				fmt.Errorf("got error while trying to export ArrayBufferView to bytes: %w", err))
 		}
-		w.bufferedAmount += len(b)
-		w.writeQueueCh <- message{
+		w.sendMessage(message{
 			mtype: websocket.BinaryMessage,
 			data:  b,
 			t:     time.Now(),
-		}
+		})
 	}
 }
 
 func (w *webSocket) sendArrayBuffer(o sobek.ArrayBuffer) {
 	b := o.Bytes()
-	w.bufferedAmount += len(b)
-	w.writeQueueCh <- message{
+	w.sendMessage(message{
 		mtype: websocket.BinaryMessage,
 		data:  b,
 		t:     time.Now(),
-	}
+	})
+}
+
+// sendMessage queues a data message to be written, tracking it if a correlator is set.
+func (w *webSocket) sendMessage(msg message) {
+	w.trackSent(&msg)
+	w.bufferedAmount += len(msg.data)
+	w.writeQueueCh <- msg
 }
 
 func isArrayBufferView(rt *sobek.Runtime, v sobek.Value) (bool, error) {
@@ -822,6 +852,7 @@ func (w *webSocket) connectionClosedWithError(err error) error {
 	}
 	w.readyState = CLOSED
 	close(w.done)
+	w.stopCorrelation(true)
 
 	if err != nil {
 		var closeError *websocket.CloseError
@@ -1758,3 +1758,125 @@ func TestPingHandlerDeadlock(t *testing.T) {
 	`))
 	assert.NoError(t, err)
 }
+
+func metricValues(sampleContainers []metrics.SampleContainer, metricName string) []float64 {
+	var values []float64
+	for _, sampleContainer := range sampleContainers {
+		for _, sample := range sampleContainer.GetSamples() {
+			if sample.Metric.Name == metricName {
+				values = append(values, sample.Value)
+			}
+		}
+	}
+	return values
+}
+
+func TestCorrelateJSONPath(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+
+	ts.tb.Mux.HandleFunc("/ws-echo-all", func(w http.ResponseWriter, req *http.Request) {
+		conn, upgErr := (&websocket.Upgrader{}).Upgrade(w, req, w.Header())
+		if !assert.NoError(t, upgErr) {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err = conn.WriteMessage(messageType, data); err != nil {
+				return
+			}
+		}
+	})
+
+	sr := ts.tb.Replacer.Replace
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var ws = new WebSocket("WSBIN_URL/ws-echo-all")
+		ws.correlate("meta.id")
+		var received = 0
+		ws.onopen = () => {
+			ws.send(JSON.stringify({ meta: { id: 1 } }))
+			ws.send(JSON.stringify({ meta: { id: 2 } }))
+			ws.send("not correlated")
+		}
+		ws.onmessage = () => {
+			if (++received == 3) {
+				ws.close()
+			}
+		}
+		ws.onerror = (e) => { throw JSON.stringify(e) }
+	`))
+	require.NoError(t, err)
+
+	samples := metrics.GetBufferedSamples(ts.samples)
+	assertMetricEmittedCount(t, messageRTTName, samples, sr("WSBIN_URL/ws-echo-all"), 2)
+	assert.Equal(t, []float64{0, 0}, metricValues(samples, messageTimeoutsName))
+}
+
+func TestCorrelateFunctionTimeout(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+
+	ts.tb.Mux.HandleFunc("/ws-late", func(w http.ResponseWriter, req *http.Request) {
+		conn, upgErr := (&websocket.Upgrader{}).Upgrade(w, req, w.Header())
+		if !assert.NoError(t, upgErr) {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		for i := range 2 {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if i == 1 {
+				// the response to the second message is later than the timeout
+				time.Sleep(300 * time.Millisecond)
+			}
+			if err = conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		}
+		_, _, _ = conn.ReadMessage()
+	})
+
+	sr := ts.tb.Replacer.Replace
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var ws = new WebSocket("WSBIN_URL/ws-late")
+		ws.correlate((data) => data.split(":")[0], { timeout: "100ms" })
+		ws.onopen = () => {
+			ws.send("first:ping")
+		}
+		ws.onmessage = (e) => {
+			if (e.data == "first:ping") {
+				ws.send("second:ping")
+				return
+			}
+			ws.close()
+		}
+		ws.onerror = (e) => { throw JSON.stringify(e) }
+	`))
+	require.NoError(t, err)
+
+	samples := metrics.GetBufferedSamples(ts.samples)
+	assertMetricEmittedCount(t, messageRTTName, samples, sr("WSBIN_URL/ws-late"), 1)
+	assert.Equal(t, []float64{0, 1}, metricValues(samples, messageTimeoutsName))
+}
+
+func TestCorrelateInvalidOptions(t *testing.T) {
+	t.Parallel()
+	ts := newTestState(t)
+	sr := ts.tb.Replacer.Replace
+	_, err := ts.runtime.RunOnEventLoop(sr(`
+		var ws = new WebSocket("WSBIN_URL/ws-echo")
+		ws.onopen = () => {
+			ws.close()
+		}
+		ws.correlate("id", { something: 1 })
+	`))
+	require.ErrorContains(t, err, "unknown correlation option something")
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"maps"
+	"math/rand" // nosemgrep: math-random-used // used for the seeded random values
 	"net"
 	"net/http"
 	"net/http/cookiejar"
@@ -21,6 +22,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/http2"
 	"golang.org/x/time/rate"
+	"gopkg.in/guregu/null.v3"
 
 	"go.k6.io/k6/v2/errext"
 	"go.k6.io/k6/v2/errext/exitcodes"
@@ -28,6 +30,7 @@ import (
 	"go.k6.io/k6/v2/internal/js/eventloop"
 	"go.k6.io/k6/v2/internal/lib/consts"
 	"go.k6.io/k6/v2/internal/lib/summary"
+	"go.k6.io/k6/v2/internal/lib/trace"
 	"go.k6.io/k6/v2/internal/loader"
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/js/modules"
@@ -250,7 +253,12 @@ func (r *Runner) newVU(
 		Usage:          r.preInitState.Usage,
 		TestStatus:     r.preInitState.TestStatus,
 	}
+	if seed := r.Bundle.Options.Seed; seed.Valid {
+		vu.state.Rand = rand.New(rand.NewSource(lib.DeriveSeed(seed.Int64, idGlobal))) //nolint:gosec
+		vu.Runtime.SetRandSource(vu.state.Rand.Float64)
+	}
 	vu.moduleVUImpl.state = vu.state
+	vu.Console = vu.Console.withVU(vu.moduleVUImpl)
 	_ = vu.Runtime.Set("console", vu.Console)
 
 	return vu, nil
@@ -570,7 +578,7 @@ func (r *Runner) SetOptions(opts lib.Options) error {
 	// (it needs the actual resolver, not the config), and it would
 	// require an additional field on Bundle to pass the config through,
 	// which is arguably worse than this.
-	if err := r.setResolver(opts.DNS); err != nil {
+	if err := r.setResolver(opts.DNS, opts.Seed); err != nil {
 		return err
 	}
 
@@ -580,7 +588,7 @@ func (r *Runner) SetOptions(opts lib.Options) error {
 	return nil
 }
 
-func (r *Runner) setResolver(dns types.DNSConfig) error {
+func (r *Runner) setResolver(dns types.DNSConfig, seed null.Int) error {
 	ttl, err := parseTTL(dns.TTL.String)
 	if err != nil {
 		return err
@@ -594,8 +602,13 @@ func (r *Runner) setResolver(dns types.DNSConfig) error {
 	if !dnsPol.Valid {
 		dnsPol = types.DefaultDNSConfig().Policy
 	}
-	r.Resolver = netext.NewResolver(
-		r.ActualResolver, ttl, dnsSel.DNSSelect, dnsPol.DNSPolicy)
+	if seed.Valid {
+		r.Resolver = netext.NewSeededResolver(
+			r.ActualResolver, ttl, dnsSel.DNSSelect, dnsPol.DNSPolicy, lib.DeriveSeed(seed.Int64))
+	} else {
+		r.Resolver = netext.NewResolver(
+			r.ActualResolver, ttl, dnsSel.DNSSelect, dnsPol.DNSPolicy)
+	}
 
 	return nil
 }
@@ -854,6 +867,11 @@ func (u *ActiveVU) RunOnce() error {
 	if err := u.Runtime.Set("__ITER", u.iteration); err != nil {
 		panic(fmt.Errorf("error setting __ITER in Sobek runtime: %w", err))
 	}
+	if u.state.Rand != nil {
+		// Each iteration has its own sequence of random values, so a failing
+		// iteration can be reproduced without the ones before it.
+		u.state.Rand.Seed(lib.DeriveSeed(u.Runner.Bundle.Options.Seed.Int64, u.IDGlobal, uint64(u.iteration))) //nolint:gosec
+	}
 
 	ctx, cancel := context.WithCancel(u.RunContext)
 	defer cancel()
@@ -929,6 +947,9 @@ func (u *VU) runFn(
 			tagsAndMeta.SetSystemTagOrMeta(metrics.TagIter, strconv.FormatInt(u.state.Iteration, 10))
 		})
 	}
+	if opts.TraceRequests.Bool {
+		u.state.Tags.Modify(trace.StartIteration)
+	}
 
 	startTime := time.Now()
 
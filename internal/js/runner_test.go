@@ -618,6 +618,51 @@ func TestVURunContext(t *testing.T) {
 	}
 }
 
+func TestVURunSeed(t *testing.T) {
+	t.Parallel()
+
+	script := `
+		var crypto = require("k6/crypto");
+		exports.options = { seed: 42 };
+		exports.default = function() {
+			fn([
+				Math.random(),
+				crypto.hexEncode(crypto.randomBytes(8)),
+				globalThis.crypto.getRandomValues(new Uint8Array(8)).join("-"),
+			].join());
+		}
+	`
+
+	runIterations := func(t *testing.T, vuID uint64) []string {
+		r, err := getSimpleRunner(t, "/script.js", script)
+		require.NoError(t, err)
+		assert.Equal(t, null.IntFrom(42), r.GetOptions().Seed)
+
+		ctx := t.Context()
+		vu, err := r.newVU(ctx, vuID, vuID, newDevNullSampleChannel())
+		require.NoError(t, err)
+
+		var values []string
+		require.NoError(t, vu.Runtime.Set("fn", func(v string) {
+			values = append(values, v)
+		}))
+
+		activeVU := vu.Activate(&lib.VUActivationParams{RunContext: ctx})
+		for range 3 {
+			require.NoError(t, activeVU.RunOnce())
+		}
+		return values
+	}
+
+	values := runIterations(t, 1)
+	require.Len(t, values, 3)
+	assert.NotEqual(t, values[0], values[1])
+	assert.NotEqual(t, values[1], values[2])
+
+	assert.Equal(t, values, runIterations(t, 1))
+	assert.NotEqual(t, values, runIterations(t, 2))
+}
+
 func TestVURunInterrupt(t *testing.T) {
 	t.Parallel()
 	r1, err := getSimpleRunner(t, "/script.js", `
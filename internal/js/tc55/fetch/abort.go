@@ -0,0 +1,196 @@
+package fetch
+
+import (
+	"time"
+
+	"github.com/grafana/sobek"
+)
+
+// AbortController is the implementation of the AbortController class.
+// https://dom.spec.whatwg.org/#interface-abortcontroller
+type AbortController struct {
+	Signal *sobek.Object `js:"signal"`
+
+	signal *AbortSignal
+}
+
+func (f *fetchAPI) abortController(call sobek.ConstructorCall) *sobek.Object {
+	signal := f.newAbortSignal()
+	return f.wrap(&AbortController{Signal: signal.obj, signal: signal}, call.This.Prototype())
+}
+
+// Abort aborts the signal of the controller with the given reason,
+// or with an AbortError if it is undefined.
+func (c *AbortController) Abort(reason sobek.Value) error {
+	return c.signal.abort(reason)
+}
+
+// AbortSignal is the implementation of the AbortSignal class.
+// https://dom.spec.whatwg.org/#interface-AbortSignal
+//
+// Its aborted and reason properties are accessors defined on its prototype,
+// as the signals returned by AbortSignal.timeout() abort when they are
+// observed after their deadline, rather than keeping the iteration running.
+type AbortSignal struct {
+	Onabort sobek.Value `js:"onabort"`
+
+	rt  *sobek.Runtime
+	obj *sobek.Object
+
+	aborted bool
+	reason  sobek.Value
+	// deadline is only set for the signals returned by AbortSignal.timeout()
+	deadline time.Time
+
+	listeners []sobek.Value
+	// algorithms are run when the signal is aborted, e.g. to cancel the in-flight requests using it
+	algorithms map[uint64]func()
+	nextID     uint64
+}
+
+func (f *fetchAPI) abortSignal(sobek.ConstructorCall) *sobek.Object {
+	panic(f.vu.Runtime().NewTypeError("Illegal constructor"))
+}
+
+func (f *fetchAPI) newAbortSignal() *AbortSignal {
+	s := &AbortSignal{
+		rt:         f.vu.Runtime(),
+		reason:     sobek.Undefined(),
+		algorithms: make(map[uint64]func()),
+	}
+	s.obj = f.wrap(s, f.signalPrototype)
+	return s
+}
+
+// abortSignalAbort implements AbortSignal.abort(), returning an already aborted signal.
+func (f *fetchAPI) abortSignalAbort(reason sobek.Value) (*sobek.Object, error) {
+	s := f.newAbortSignal()
+	if err := s.abort(reason); err != nil {
+		return nil, err
+	}
+	return s.obj, nil
+}
+
+// abortSignalTimeout implements AbortSignal.timeout(), returning a signal
+// aborted with a TimeoutError after the given number of milliseconds.
+func (f *fetchAPI) abortSignalTimeout(ms float64) *sobek.Object {
+	if ms < 0 {
+		panic(f.vu.Runtime().NewTypeError("the timeout must be a positive number, got %v", ms))
+	}
+	s := f.newAbortSignal()
+	s.deadline = time.Now().Add(time.Duration(ms * float64(time.Millisecond)))
+	return s.obj
+}
+
+// ThrowIfAborted throws the abort reason of the signal if it has been aborted.
+func (s *AbortSignal) ThrowIfAborted() {
+	if s.isAborted() {
+		panic(s.reason)
+	}
+}
+
+// AddEventListener registers a listener of the abort event.
+func (s *AbortSignal) AddEventListener(event string, listener sobek.Value) {
+	if event != "abort" {
+		return
+	}
+	if _, ok := sobek.AssertFunction(listener); !ok {
+		return
+	}
+	for _, l := range s.listeners {
+		if l.SameAs(listener) {
+			return
+		}
+	}
+	s.listeners = append(s.listeners, listener)
+}
+
+// RemoveEventListener unregisters a listener of the abort event.
+func (s *AbortSignal) RemoveEventListener(event string, listener sobek.Value) {
+	if event != "abort" {
+		return
+	}
+	for i, l := range s.listeners {
+		if l.SameAs(listener) {
+			s.listeners = append(s.listeners[:i], s.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// isAborted returns whether the signal has been aborted, aborting it first if its deadline has passed.
+func (s *AbortSignal) isAborted() bool {
+	if err := s.checkDeadline(); err != nil {
+		panic(err)
+	}
+	return s.aborted
+}
+
+// checkDeadline aborts the signal with a TimeoutError if its deadline has passed.
+func (s *AbortSignal) checkDeadline() error {
+	if s.aborted || s.deadline.IsZero() || time.Now().Before(s.deadline) {
+		return nil
+	}
+	return s.abort(newDOMException(s.rt, "TimeoutError", "signal timed out"))
+}
+
+// abort runs the abort steps of the signal and dispatches its abort event.
+// https://dom.spec.whatwg.org/#abortsignal-signal-abort
+func (s *AbortSignal) abort(reason sobek.Value) error {
+	if s.aborted {
+		return nil
+	}
+	if reason == nil || sobek.IsUndefined(reason) {
+		reason = newDOMException(s.rt, "AbortError", "signal is aborted without reason")
+	}
+	s.aborted = true
+	s.reason = reason
+
+	for _, algorithm := range s.algorithms {
+		algorithm()
+	}
+	clear(s.algorithms)
+
+	event := s.rt.NewObject()
+	if err := event.Set("type", "abort"); err != nil {
+		return err
+	}
+	if err := event.Set("target", s.obj); err != nil {
+		return err
+	}
+
+	listeners := s.listeners
+	if s.Onabort != nil {
+		listeners = append([]sobek.Value{s.Onabort}, listeners...)
+	}
+	for _, listener := range listeners {
+		if fn, ok := sobek.AssertFunction(listener); ok {
+			if _, err := fn(s.obj, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addAlgorithm registers a function to run when the signal is aborted,
+// and returns a function unregistering it.
+func (s *AbortSignal) addAlgorithm(algorithm func()) func() {
+	id := s.nextID
+	s.nextID++
+	s.algorithms[id] = algorithm
+	return func() { delete(s.algorithms, id) }
+}
+
+// newDOMException returns an Error with the given name, standing in for
+// the DOMException the specifications use, which k6 doesn't implement.
+func newDOMException(rt *sobek.Runtime, name, message string) *sobek.Object {
+	exc, err := rt.New(rt.Get("Error"), rt.ToValue(message))
+	if err != nil {
+		panic(err)
+	}
+	if err := exc.Set("name", name); err != nil {
+		panic(err)
+	}
+	return exc
+}
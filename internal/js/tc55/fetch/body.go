@@ -0,0 +1,121 @@
+package fetch
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+)
+
+// body is the body of a request or a response, which can be read only once.
+// https://fetch.spec.whatwg.org/#body-mixin
+type body struct {
+	Used bool `js:"bodyUsed"`
+
+	// data is nil for null bodies
+	data []byte
+}
+
+// extractBody returns the content of a body init value, along with the
+// Content-Type it implies, if any.
+// https://fetch.spec.whatwg.org/#concept-bodyinit-extract
+func extractBody(rt *sobek.Runtime, v sobek.Value) (data []byte, contentType string) {
+	if common.IsNullish(v) {
+		return nil, ""
+	}
+	if b, ok := bufferSourceBytes(rt, v); ok {
+		return b, ""
+	}
+	return []byte(v.String()), "text/plain;charset=UTF-8"
+}
+
+// bufferSourceBytes returns a copy of the bytes of an ArrayBuffer, a TypedArray or a DataView.
+func bufferSourceBytes(rt *sobek.Runtime, v sobek.Value) ([]byte, bool) {
+	if ab, ok := v.Export().(sobek.ArrayBuffer); ok {
+		return bytes.Clone(ab.Bytes()), true
+	}
+
+	isView, ok := sobek.AssertFunction(rt.Get("ArrayBuffer").ToObject(rt).Get("isView"))
+	if !ok {
+		return nil, false
+	}
+	if res, err := isView(sobek.Undefined(), v); err != nil || !res.ToBoolean() {
+		return nil, false
+	}
+
+	view := v.ToObject(rt)
+	ab, ok := view.Get("buffer").Export().(sobek.ArrayBuffer)
+	if !ok {
+		return nil, false
+	}
+	offset := view.Get("byteOffset").ToInteger()
+	length := view.Get("byteLength").ToInteger()
+	return bytes.Clone(ab.Bytes()[offset : offset+length]), true
+}
+
+// clone returns a copy of the body, which must not have been used.
+func (b *body) clone(rt *sobek.Runtime) body {
+	if b.Used {
+		panic(rt.NewTypeError("the body has already been read"))
+	}
+	return body{data: b.data}
+}
+
+// read consumes the body and returns a promise resolved with the result of convert.
+func (b *body) read(rt *sobek.Runtime, convert func([]byte) (sobek.Value, error)) *sobek.Promise {
+	p, resolve, reject := rt.NewPromise()
+	if b.Used {
+		_ = reject(rt.NewTypeError("the body has already been read"))
+		return p
+	}
+	if b.data != nil {
+		b.Used = true
+	}
+
+	v, err := convert(b.data)
+	if err != nil {
+		var exc *sobek.Exception
+		if errors.As(err, &exc) {
+			_ = reject(exc.Value())
+		} else {
+			_ = reject(err)
+		}
+		return p
+	}
+	_ = resolve(v)
+	return p
+}
+
+func (b *body) text(rt *sobek.Runtime) *sobek.Promise {
+	return b.read(rt, func(data []byte) (sobek.Value, error) {
+		return rt.ToValue(decodeUTF8(data)), nil
+	})
+}
+
+func (b *body) json(rt *sobek.Runtime) *sobek.Promise {
+	return b.read(rt, func(data []byte) (sobek.Value, error) {
+		parse, _ := sobek.AssertFunction(rt.Get("JSON").ToObject(rt).Get("parse"))
+		return parse(sobek.Undefined(), rt.ToValue(decodeUTF8(data)))
+	})
+}
+
+func (b *body) arrayBuffer(rt *sobek.Runtime) *sobek.Promise {
+	return b.read(rt, func(data []byte) (sobek.Value, error) {
+		return rt.ToValue(rt.NewArrayBuffer(bytes.Clone(data))), nil
+	})
+}
+
+func (b *body) bytes(rt *sobek.Runtime) *sobek.Promise {
+	return b.read(rt, func(data []byte) (sobek.Value, error) {
+		return rt.New(rt.Get("Uint8Array"), rt.ToValue(rt.NewArrayBuffer(bytes.Clone(data))))
+	})
+}
+
+// decodeUTF8 decodes data as UTF-8, dropping the byte order mark
+// and replacing invalid sequences with U+FFFD.
+func decodeUTF8(data []byte) string {
+	return strings.ToValidUTF8(string(bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))), "\uFFFD")
+}
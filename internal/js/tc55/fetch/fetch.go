@@ -0,0 +1,273 @@
+// Package fetch implements the fetch() function (https://fetch.spec.whatwg.org) along with the
+// Headers, Request, Response, AbortController and AbortSignal classes, on top of the k6 HTTP client.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/sobek"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/netext/httpext"
+)
+
+// ErrFetchForbiddenInInitContext is used when fetch is called in the init context
+var ErrFetchForbiddenInInitContext = common.NewInitContextError(
+	"Using fetch in the init context is not supported")
+
+// requestTimeout is the timeout of the requests, as for the ones made with the k6/http module.
+// A shorter one can be used with an AbortSignal.
+const requestTimeout = 60 * time.Second
+
+type fetchAPI struct {
+	vu modules.VU
+
+	headersPrototype  *sobek.Object
+	requestPrototype  *sobek.Object
+	responsePrototype *sobek.Object
+	signalPrototype   *sobek.Object
+}
+
+// SetupGlobally setups implementations of fetch, Headers, Request, Response, AbortController
+// and AbortSignal to be accessible globally by setting them on globalThis.
+func SetupGlobally(vu modules.VU) error {
+	return (&fetchAPI{vu: vu}).setupGlobally()
+}
+
+func (f *fetchAPI) setupGlobally() error {
+	rt := f.vu.Runtime()
+
+	headers := rt.ToValue(f.headers).ToObject(rt)
+	request := rt.ToValue(f.request).ToObject(rt)
+	response := rt.ToValue(f.response).ToObject(rt)
+	abortController := rt.ToValue(f.abortController).ToObject(rt)
+	abortSignal := rt.ToValue(f.abortSignal).ToObject(rt)
+
+	f.headersPrototype = headers.Get("prototype").ToObject(rt)
+	f.requestPrototype = request.Get("prototype").ToObject(rt)
+	f.responsePrototype = response.Get("prototype").ToObject(rt)
+	f.signalPrototype = abortSignal.Get("prototype").ToObject(rt)
+
+	err := f.headersPrototype.SetSymbol(sobek.SymIterator, func(call sobek.FunctionCall) sobek.Value {
+		return f.thisHeaders(call.This).Entries()
+	})
+	if err != nil {
+		return err
+	}
+	if err = f.defineSignalAccessors(); err != nil {
+		return err
+	}
+
+	statics := []struct {
+		class   *sobek.Object
+		methods map[string]any
+	}{
+		{response, map[string]any{"error": f.responseError, "json": f.responseJSON, "redirect": f.responseRedirect}},
+		{abortSignal, map[string]any{"abort": f.abortSignalAbort, "timeout": f.abortSignalTimeout}},
+	}
+	for _, s := range statics {
+		for name, method := range s.methods {
+			if err = s.class.Set(name, method); err != nil {
+				return err
+			}
+		}
+	}
+
+	mapping := map[string]any{
+		"fetch":           f.fetch,
+		"Headers":         headers,
+		"Request":         request,
+		"Response":        response,
+		"AbortController": abortController,
+		"AbortSignal":     abortSignal,
+	}
+	for k, v := range mapping {
+		if err = rt.Set(k, v); err != nil {
+			return fmt.Errorf("error setting up %q globally: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// defineSignalAccessors defines the aborted and reason accessors of the AbortSignal prototype.
+func (f *fetchAPI) defineSignalAccessors() error {
+	rt := f.vu.Runtime()
+	accessors := map[string]func(*AbortSignal) sobek.Value{
+		"aborted": func(s *AbortSignal) sobek.Value { return rt.ToValue(s.isAborted()) },
+		"reason": func(s *AbortSignal) sobek.Value {
+			s.isAborted()
+			return s.reason
+		},
+	}
+	for name, get := range accessors {
+		getter := rt.ToValue(func(call sobek.FunctionCall) sobek.Value {
+			s, ok := call.This.Export().(*AbortSignal)
+			if !ok {
+				panic(rt.NewTypeError("Illegal invocation"))
+			}
+			return get(s)
+		})
+		if err := f.signalPrototype.DefineAccessorProperty(name, getter, nil, sobek.FLAG_TRUE, sobek.FLAG_TRUE); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// wrap returns the JS object for v, with the given prototype.
+func (f *fetchAPI) wrap(v any, prototype *sobek.Object) *sobek.Object {
+	rt := f.vu.Runtime()
+	obj := rt.ToValue(v).ToObject(rt)
+	if err := obj.SetPrototype(prototype); err != nil {
+		common.Throw(rt, err)
+	}
+	return obj
+}
+
+func (f *fetchAPI) thisHeaders(this sobek.Value) *Headers {
+	h, ok := this.Export().(*Headers)
+	if !ok {
+		panic(f.vu.Runtime().NewTypeError("Illegal invocation"))
+	}
+	return h
+}
+
+// fetch makes a request and returns a promise resolved with its response once its body has been read.
+// All the networking is done off the event loop, and the request emits the same metrics as the ones
+// made with the k6/http module. The promise is rejected with a TypeError on network errors, and with
+// the abort reason if the signal of the request is aborted.
+// https://fetch.spec.whatwg.org/#fetch-method
+func (f *fetchAPI) fetch(input, init sobek.Value) (*sobek.Promise, error) {
+	state := f.vu.State()
+	if state == nil {
+		return nil, ErrFetchForbiddenInInitContext
+	}
+
+	rt := f.vu.Runtime()
+	p, resolve, reject := rt.NewPromise()
+
+	var req *Request
+	if exc := rt.Try(func() { req = f.newRequest(input, init) }); exc != nil {
+		return p, reject(exc.Value())
+	}
+	signal := req.signal
+	if signal.isAborted() {
+		return p, reject(signal.reason)
+	}
+
+	preq, err := f.parseRequest(state, req)
+	if err != nil {
+		return p, reject(rt.NewTypeError("fetch failed: %s", err))
+	}
+
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if signal.deadline.IsZero() {
+		ctx, cancel = context.WithCancel(f.vu.Context())
+	} else {
+		ctx, cancel = context.WithDeadline(f.vu.Context(), signal.deadline)
+	}
+	removeAlgorithm := signal.addAlgorithm(cancel)
+	callback := f.vu.RegisterCallback()
+
+	go func() {
+		resp, err := httpext.MakeRequest(ctx, state, preq)
+		callback(func() error {
+			removeAlgorithm()
+			cancel()
+
+			if abortErr := signal.checkDeadline(); abortErr != nil {
+				return abortErr
+			}
+			if signal.aborted {
+				return reject(signal.reason)
+			}
+			if err != nil {
+				return reject(rt.NewTypeError("fetch failed: %s", err))
+			}
+			if req.Redirect == redirectError && isRedirectStatus(resp.Status) {
+				return reject(rt.NewTypeError("fetch failed: unexpected redirect to %s", resp.Headers["Location"]))
+			}
+			if req.Redirect == redirectManual && isRedirectStatus(resp.Status) {
+				return resolve(f.responseOpaqueRedirect(req.URL))
+			}
+			return resolve(f.responseFromHTTPext(resp, req.URL))
+		})
+	}()
+
+	return p, nil
+}
+
+// parseRequest returns the httpext request for req, following the k6 options.
+func (f *fetchAPI) parseRequest(state *lib.State, req *Request) (*httpext.ParsedHTTPRequest, error) {
+	u, err := httpext.NewURL(req.URL, req.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &httpext.ParsedHTTPRequest{
+		URL: &u,
+		Req: &http.Request{
+			Method: req.Method,
+			URL:    u.GetURL(),
+			Header: req.headers.httpHeader(),
+		},
+		Timeout:          requestTimeout,
+		Throw:            true,
+		Redirects:        state.Options.MaxRedirects,
+		Cookies:          make(map[string]*httpext.HTTPRequestCookie),
+		ResponseCallback: expectedStatus,
+		TagsAndMeta:      state.Tags.GetCurrentValues(),
+	}
+
+	if req.Redirect != redirectFollow {
+		result.Redirects = null.IntFrom(0)
+	}
+	if state.Options.DiscardResponseBodies.Bool {
+		result.ResponseType = httpext.ResponseTypeNone
+	} else {
+		result.ResponseType = httpext.ResponseTypeBinary
+	}
+	if req.data != nil {
+		result.Body = bytes.NewBuffer(req.data)
+	}
+	if host := result.Req.Header.Get("Host"); host != "" {
+		result.Req.Host = host
+	}
+	if result.Req.Header.Get("User-Agent") == "" {
+		result.Req.Header.Set("User-Agent", state.Options.UserAgent.String)
+	}
+
+	if state.CookieJar != nil && req.Credentials != credentialsOmit {
+		result.ActiveJar = state.CookieJar
+		httpext.SetRequestCookies(result.Req, result.ActiveJar, result.Cookies)
+	}
+
+	return result, nil
+}
+
+// expectedStatus matches the statuses expected by default by the k6/http module,
+// for the http_req_failed metric and the expected_response tag.
+func expectedStatus(status int) bool {
+	return status >= 200 && status < 400
+}
+
+// iterate returns an iterator over the given values.
+func iterate(rt *sobek.Runtime, values []any) sobek.Value {
+	arr := rt.NewArray(values...)
+	iterator, _ := sobek.AssertFunction(arr.Get("values"))
+	it, err := iterator(arr)
+	if err != nil {
+		panic(err)
+	}
+	return it
+}
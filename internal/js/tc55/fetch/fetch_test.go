@@ -0,0 +1,307 @@
+package fetch_test
+
+import (
+	"net/http/cookiejar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/internal/lib/testutils/httpmultibin"
+	"go.k6.io/k6/v2/js/modulestest"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/metrics"
+)
+
+type fetchTestCase struct {
+	tb      *httpmultibin.HTTPMultiBin
+	runtime *modulestest.Runtime
+	samples chan metrics.SampleContainer
+}
+
+func newTestCase(t *testing.T) *fetchTestCase {
+	t.Helper()
+
+	tb := httpmultibin.NewHTTPMultiBin(t)
+	runtime := modulestest.NewRuntime(t)
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	registry := runtime.VU.InitEnv().Registry
+	samples := make(chan metrics.SampleContainer, 1000)
+	runtime.MoveToVUContext(&lib.State{
+		Options: lib.Options{
+			MaxRedirects: null.IntFrom(10),
+			UserAgent:    null.StringFrom("TestUserAgent"),
+			SystemTags:   &metrics.DefaultSystemTagSet,
+		},
+		Logger:         testutils.NewLogger(t),
+		TLSConfig:      tb.TLSClientConfig,
+		Transport:      tb.HTTPTransport,
+		BufferPool:     lib.NewBufferPool(),
+		Samples:        samples,
+		CookieJar:      jar,
+		Tags:           lib.NewVUStateTags(registry.RootTagSet().With("group", lib.RootGroupPath)),
+		BuiltinMetrics: runtime.BuiltinMetrics,
+	})
+
+	return &fetchTestCase{tb: tb, runtime: runtime, samples: samples}
+}
+
+func (tc *fetchTestCase) run(t *testing.T, script string) {
+	t.Helper()
+
+	_, err := tc.runtime.RunOnEventLoop(tc.tb.Replacer.Replace(`(async function () {` + script + `})()`))
+	require.NoError(t, err)
+}
+
+func TestFetch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GET", func(t *testing.T) {
+		t.Parallel()
+
+		tc := newTestCase(t)
+		tc.run(t, `
+			const res = await fetch("HTTPBIN_URL/get?a=1", { headers: { "X-Test": "value" } });
+			if (!(res instanceof Response) || !(res.headers instanceof Headers)) {
+				throw new Error("unexpected response types");
+			}
+			if (res.status !== 200 || !res.ok || res.statusText !== "OK" || res.type !== "basic" || res.redirected) {
+				throw new Error("unexpected response " + JSON.stringify(res));
+			}
+			if (res.url !== "HTTPBIN_URL/get?a=1" || !res.headers.get("Content-Type").startsWith("application/json")) {
+				throw new Error("unexpected response " + res.url + " " + res.headers.get("Content-Type"));
+			}
+
+			const body = await res.json();
+			if (body.headers["X-Test"][0] !== "value" || body.headers["User-Agent"][0] !== "TestUserAgent") {
+				throw new Error("unexpected request headers " + JSON.stringify(body.headers));
+			}
+			if (!res.bodyUsed) {
+				throw new Error("expected the body to be used");
+			}
+			try {
+				await res.text();
+				throw new Error("expected text to fail");
+			} catch (err) {
+				if (!(err instanceof TypeError)) {
+					throw err;
+				}
+			}
+		`)
+
+		var found bool
+		for _, sample := range metrics.GetBufferedSamples(tc.samples) {
+			for _, s := range sample.GetSamples() {
+				if s.Metric.Name != metrics.HTTPReqsName {
+					continue
+				}
+				found = true
+				tags := s.Tags.Map()
+				assert.Equal(t, tc.tb.Replacer.Replace("HTTPBIN_URL/get?a=1"), tags["url"])
+				assert.Equal(t, "GET", tags["method"])
+				assert.Equal(t, "200", tags["status"])
+				assert.Equal(t, "true", tags["expected_response"])
+			}
+		}
+		assert.True(t, found, "expected a http_reqs sample")
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		t.Parallel()
+
+		tc := newTestCase(t)
+		tc.run(t, `
+			let body = await (await fetch("HTTPBIN_URL/post", { method: "post", body: "hello" })).json();
+			if (body.data !== "hello" || body.headers["Content-Type"][0] !== "text/plain;charset=UTF-8") {
+				throw new Error("unexpected text body " + JSON.stringify(body));
+			}
+
+			const request = new Request("HTTPBIN_URL/put", {
+				method: "PUT",
+				body: new Uint8Array([104, 105, 33]).subarray(0, 2),
+				headers: [["Content-Type", "application/octet-stream"]],
+			});
+			body = await (await fetch(request)).json();
+			if (body.data !== "data:application/octet-stream;base64,aGk=") {
+				throw new Error("unexpected binary body " + JSON.stringify(body));
+			}
+		`)
+	})
+
+	t.Run("redirects", func(t *testing.T) {
+		t.Parallel()
+
+		tc := newTestCase(t)
+		tc.run(t, `
+			let res = await fetch("HTTPBIN_URL/redirect/2");
+			if (res.status !== 200 || !res.redirected || res.url !== "HTTPBIN_URL/get") {
+				throw new Error("unexpected followed redirect " + res.status + " " + res.url);
+			}
+
+			res = await fetch("HTTPBIN_URL/redirect/2", { redirect: "manual" });
+			if (res.type !== "opaqueredirect" || res.status !== 0 || res.ok || res.redirected ||
+				res.url !== "HTTPBIN_URL/redirect/2" || [...res.headers].length !== 0 || (await res.text()) !== "") {
+				throw new Error("unexpected manual redirect " + res.type + " " + res.status + " " + res.url);
+			}
+
+			try {
+				await fetch("HTTPBIN_URL/redirect/2", { redirect: "error" });
+				throw new Error("expected fetch to fail");
+			} catch (err) {
+				if (!(err instanceof TypeError)) {
+					throw err;
+				}
+			}
+		`)
+	})
+
+	t.Run("cookies", func(t *testing.T) {
+		t.Parallel()
+
+		tc := newTestCase(t)
+		tc.run(t, `
+			await fetch("HTTPBIN_URL/cookies/set?k6=fetch");
+
+			let body = await (await fetch("HTTPBIN_URL/cookies")).json();
+			if (body.cookies.k6 !== "fetch") {
+				throw new Error("expected the cookie to be sent " + JSON.stringify(body));
+			}
+
+			body = await (await fetch("HTTPBIN_URL/cookies", { credentials: "omit" })).json();
+			if (body.cookies.k6 !== undefined) {
+				throw new Error("expected the cookie to be omitted " + JSON.stringify(body));
+			}
+		`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tc := newTestCase(t)
+		tc.run(t, `
+			for (const [input, init] of [
+				["not a url"],
+				["HTTPBIN_URL/get", { body: "data" }],
+				["HTTPBIN_URL/get", { method: "CONNECT" }],
+				["HTTPBIN_URL/get", { redirect: "none" }],
+				["HTTPBIN_URL/get", { signal: {} }],
+				["http://127.0.0.1:1/"],
+			]) {
+				try {
+					await fetch(input, init);
+					throw new Error("expected fetch to fail for " + input + " " + JSON.stringify(init));
+				} catch (err) {
+					if (!(err instanceof TypeError)) {
+						throw err;
+					}
+				}
+			}
+
+			const res = await fetch("HTTPBIN_URL/status/404");
+			if (res.ok || res.status !== 404) {
+				throw new Error("unexpected response " + res.status);
+			}
+		`)
+	})
+
+	t.Run("in the init context", func(t *testing.T) {
+		t.Parallel()
+
+		runtime := modulestest.NewRuntime(t)
+		_, err := runtime.VU.Runtime().RunString(`fetch("https://k6.io")`)
+		require.ErrorContains(t, err, "Using fetch in the init context is not supported")
+	})
+}
+
+func TestFetchAbort(t *testing.T) {
+	t.Parallel()
+
+	t.Run("in-flight request", func(t *testing.T) {
+		t.Parallel()
+
+		tc := newTestCase(t)
+		tc.run(t, `
+			const controller = new AbortController();
+			let events = 0;
+			controller.signal.addEventListener("abort", (event) => {
+				if (event.type === "abort" && event.target === controller.signal) {
+					events++;
+				}
+			});
+
+			const promise = fetch("HTTPBIN_URL/delay/10", { signal: controller.signal });
+			setTimeout(() => controller.abort(), 100);
+
+			try {
+				await promise;
+				throw new Error("expected fetch to be aborted");
+			} catch (err) {
+				if (err.name !== "AbortError" || err !== controller.signal.reason) {
+					throw err;
+				}
+			}
+			if (events !== 1 || !controller.signal.aborted) {
+				throw new Error("unexpected signal state " + events + " " + controller.signal.aborted);
+			}
+		`)
+	})
+
+	t.Run("already aborted signal", func(t *testing.T) {
+		t.Parallel()
+
+		tc := newTestCase(t)
+		tc.run(t, `
+			try {
+				await fetch("HTTPBIN_URL/get", { signal: AbortSignal.abort("canceled") });
+				throw new Error("expected fetch to be aborted");
+			} catch (err) {
+				if (err !== "canceled") {
+					throw err;
+				}
+			}
+		`)
+
+		assert.Empty(t, metrics.GetBufferedSamples(tc.samples))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		tc := newTestCase(t)
+		tc.run(t, `
+			const signal = AbortSignal.timeout(100);
+			let aborted = false;
+			signal.onabort = () => { aborted = true; };
+
+			try {
+				await fetch("HTTPBIN_URL/delay/10", { signal });
+				throw new Error("expected fetch to time out");
+			} catch (err) {
+				if (err.name !== "TimeoutError" || !aborted || !signal.aborted) {
+					throw err;
+				}
+			}
+			try {
+				signal.throwIfAborted();
+				throw new Error("expected throwIfAborted to throw");
+			} catch (err) {
+				if (err !== signal.reason) {
+					throw err;
+				}
+			}
+		`)
+	})
+
+	t.Run("illegal constructor", func(t *testing.T) {
+		t.Parallel()
+
+		runtime := modulestest.NewRuntime(t)
+		_, err := runtime.VU.Runtime().RunString(`new AbortSignal()`)
+		require.ErrorContains(t, err, "TypeError: Illegal constructor")
+	})
+}
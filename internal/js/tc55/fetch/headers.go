@@ -0,0 +1,260 @@
+package fetch
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+)
+
+// Headers is the implementation of the Headers class.
+// https://fetch.spec.whatwg.org/#headers-class
+type Headers struct {
+	rt *sobek.Runtime
+
+	// list holds the headers in insertion order, with lowercased names
+	list []header
+	// immutable is set for the headers of the responses returned by fetch
+	immutable bool
+}
+
+type header struct {
+	name, value string
+}
+
+func (f *fetchAPI) headers(call sobek.ConstructorCall) *sobek.Object {
+	h := &Headers{rt: f.vu.Runtime()}
+	h.fill(call.Argument(0))
+
+	return f.wrap(h, call.This.Prototype())
+}
+
+// fill appends the headers of init, which can be another Headers instance,
+// an iterable of name-value pairs or a record.
+func (h *Headers) fill(init sobek.Value) {
+	if common.IsNullish(init) {
+		return
+	}
+
+	if other, ok := init.Export().(*Headers); ok {
+		for _, e := range other.list {
+			h.Append(e.name, e.value)
+		}
+		return
+	}
+
+	obj := init.ToObject(h.rt)
+	if iter := obj.GetSymbol(sobek.SymIterator); !common.IsNullish(iter) {
+		h.rt.ForOf(init, func(pair sobek.Value) bool {
+			var values []sobek.Value
+			if !common.IsNullish(pair) {
+				h.rt.ForOf(pair, func(v sobek.Value) bool {
+					values = append(values, v)
+					return true
+				})
+			}
+			if len(values) != 2 {
+				panic(h.rt.NewTypeError("each header must be a name-value pair, got %d elements", len(values)))
+			}
+			h.Append(values[0].String(), values[1].String())
+			return true
+		})
+		return
+	}
+
+	for _, key := range obj.Keys() {
+		h.Append(key, obj.Get(key).String())
+	}
+}
+
+// Append adds a value to the header with the given name, keeping its existing values.
+func (h *Headers) Append(name, value string) {
+	name, value = h.validate(name, value)
+	h.list = append(h.list, header{name: name, value: value})
+}
+
+// Delete removes all the values of the header with the given name.
+func (h *Headers) Delete(name string) {
+	name, _ = h.validate(name, "")
+	h.list = slices.DeleteFunc(h.list, func(e header) bool { return e.name == name })
+}
+
+// Get returns the values of the header with the given name joined by ", ", or null.
+func (h *Headers) Get(name string) sobek.Value {
+	name = h.validateName(name)
+	value, ok := h.get(name)
+	if !ok {
+		return sobek.Null()
+	}
+	return h.rt.ToValue(value)
+}
+
+// GetSetCookie returns the values of the Set-Cookie headers, which can't be combined.
+func (h *Headers) GetSetCookie() []string {
+	values := make([]string, 0)
+	for _, e := range h.list {
+		if e.name == "set-cookie" {
+			values = append(values, e.value)
+		}
+	}
+	return values
+}
+
+// Has returns whether a header with the given name exists.
+func (h *Headers) Has(name string) bool {
+	_, ok := h.get(h.validateName(name))
+	return ok
+}
+
+// Set replaces all the values of the header with the given name.
+func (h *Headers) Set(name, value string) {
+	name, value = h.validate(name, value)
+	i := slices.IndexFunc(h.list, func(e header) bool { return e.name == name })
+	if i < 0 {
+		h.list = append(h.list, header{name: name, value: value})
+		return
+	}
+	h.list[i].value = value
+	rest := slices.DeleteFunc(h.list[i+1:], func(e header) bool { return e.name == name })
+	h.list = h.list[:i+1+len(rest)]
+}
+
+// ForEach calls the callback with the value, the name and the Headers
+// object itself for each of the sorted and combined headers.
+func (h *Headers) ForEach(call sobek.FunctionCall) sobek.Value {
+	callback, ok := sobek.AssertFunction(call.Argument(0))
+	if !ok {
+		panic(h.rt.NewTypeError("the callback must be a function"))
+	}
+	for _, e := range h.sorted() {
+		if _, err := callback(call.Argument(1), h.rt.ToValue(e.value), h.rt.ToValue(e.name), call.This); err != nil {
+			panic(err)
+		}
+	}
+	return sobek.Undefined()
+}
+
+// Entries returns an iterator over the name-value pairs of the sorted and combined headers.
+func (h *Headers) Entries() sobek.Value {
+	sorted := h.sorted()
+	entries := make([]any, len(sorted))
+	for i, e := range sorted {
+		entries[i] = h.rt.NewArray(e.name, e.value)
+	}
+	return iterate(h.rt, entries)
+}
+
+// Keys returns an iterator over the names of the sorted and combined headers.
+func (h *Headers) Keys() sobek.Value {
+	sorted := h.sorted()
+	keys := make([]any, len(sorted))
+	for i, e := range sorted {
+		keys[i] = e.name
+	}
+	return iterate(h.rt, keys)
+}
+
+// Values returns an iterator over the values of the sorted and combined headers.
+func (h *Headers) Values() sobek.Value {
+	sorted := h.sorted()
+	values := make([]any, len(sorted))
+	for i, e := range sorted {
+		values[i] = e.value
+	}
+	return iterate(h.rt, values)
+}
+
+func (h *Headers) get(name string) (string, bool) {
+	var values []string
+	for _, e := range h.list {
+		if e.name == name {
+			values = append(values, e.value)
+		}
+	}
+	return strings.Join(values, ", "), values != nil
+}
+
+// sorted returns the headers sorted by name with the values of headers
+// sharing the same name combined, except for Set-Cookie.
+// https://fetch.spec.whatwg.org/#concept-header-list-sort-and-combine
+func (h *Headers) sorted() []header {
+	names := make([]string, 0, len(h.list))
+	for _, e := range h.list {
+		if !slices.Contains(names, e.name) {
+			names = append(names, e.name)
+		}
+	}
+	slices.Sort(names)
+
+	result := make([]header, 0, len(names))
+	for _, name := range names {
+		if name == "set-cookie" {
+			for _, value := range h.GetSetCookie() {
+				result = append(result, header{name: name, value: value})
+			}
+			continue
+		}
+		value, _ := h.get(name)
+		result = append(result, header{name: name, value: value})
+	}
+	return result
+}
+
+func (h *Headers) validate(name, value string) (string, string) {
+	if h.immutable {
+		panic(h.rt.NewTypeError("the headers are immutable"))
+	}
+	name = h.validateName(name)
+	value = strings.Trim(value, "\t\n\r ")
+	if strings.ContainsAny(value, "\x00\r\n") {
+		panic(h.rt.NewTypeError("invalid value for the header %q", name))
+	}
+	return name, value
+}
+
+func (h *Headers) validateName(name string) string {
+	if !isToken(name) {
+		panic(h.rt.NewTypeError("invalid header name %q", name))
+	}
+	return strings.ToLower(name)
+}
+
+// clone returns a mutable copy of the headers.
+func (h *Headers) clone() *Headers {
+	return &Headers{rt: h.rt, list: slices.Clone(h.list)}
+}
+
+// httpHeader returns the headers in the form used by net/http.
+func (h *Headers) httpHeader() http.Header {
+	result := make(http.Header, len(h.list))
+	for _, e := range h.list {
+		result.Add(e.name, e.value)
+	}
+	return result
+}
+
+// isToken returns whether s is a valid token, as required for header names and methods.
+// https://httpwg.org/specs/rfc9110.html#tokens
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range []byte(s) {
+		if !isTokenChar(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTokenChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	default:
+		return strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+	}
+}
@@ -0,0 +1,91 @@
+package fetch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/js/modulestest"
+)
+
+func TestHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("names are case-insensitive", func(t *testing.T) {
+		t.Parallel()
+
+		rt := modulestest.NewRuntime(t).VU.Runtime()
+		v, err := rt.RunString(`
+			const headers = new Headers({ "Content-Type": "text/plain", "X-Values": "a" });
+			headers.append("x-values", " b ");
+			headers.set("X-Other", "c");
+			headers.delete("X-OTHER");
+			[headers.get("content-type"), headers.get("X-Values"), headers.has("x-other"), headers.get("missing")]
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"text/plain", "a, b", false, nil}, v.Export())
+	})
+
+	t.Run("iteration is sorted and combined", func(t *testing.T) {
+		t.Parallel()
+
+		rt := modulestest.NewRuntime(t).VU.Runtime()
+		v, err := rt.RunString(`
+			const headers = new Headers([["b", "1"], ["Set-Cookie", "a=1"], ["a", "2"], ["B", "3"], ["set-cookie", "b=2"]]);
+			const entries = [...headers].map(([name, value]) => name + "=" + value);
+			const forEach = [];
+			headers.forEach(function (value, name, h) {
+				if (h !== headers || this.marker !== 1) {
+					throw new Error("unexpected forEach arguments");
+				}
+				forEach.push(name);
+			}, { marker: 1 });
+			[entries.join(";"), [...headers.keys()].join(","), [...headers.values()].join(","),
+				headers.getSetCookie().join(","), forEach.join(",")]
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, []any{
+			"a=2;b=1, 3;set-cookie=a=1;set-cookie=b=2",
+			"a,b,set-cookie,set-cookie",
+			"2,1, 3,a=1,b=2",
+			"a=1,b=2",
+			"a,b,set-cookie,set-cookie",
+		}, v.Export())
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		t.Parallel()
+
+		rt := modulestest.NewRuntime(t).VU.Runtime()
+		v, err := rt.RunString(`
+			const original = new Headers({ a: "1" });
+			const copy = new Headers(original);
+			copy.set("a", "2");
+			[original.get("a"), copy.get("a"), copy instanceof Headers]
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, []any{"1", "2", true}, v.Export())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := []string{
+			`new Headers({ "invalid name": "a" })`,
+			`new Headers([["a"]])`,
+			`new Headers().append("a", "b\nc")`,
+			`new Headers().get("")`,
+			`Response.error().headers.set("a", "b")`,
+		}
+		for _, script := range testCases {
+			t.Run(script, func(t *testing.T) {
+				t.Parallel()
+
+				rt := modulestest.NewRuntime(t).VU.Runtime()
+				_, err := rt.RunString(script)
+				require.ErrorContains(t, err, "TypeError")
+			})
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package fetch
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+)
+
+// Request is the implementation of the Request class.
+// https://fetch.spec.whatwg.org/#request-class
+type Request struct {
+	body
+
+	Method      string        `js:"method"`
+	URL         string        `js:"url"`
+	Headers     *sobek.Object `js:"headers"`
+	Signal      *sobek.Object `js:"signal"`
+	Redirect    string        `js:"redirect"`
+	Credentials string        `js:"credentials"`
+
+	api     *fetchAPI
+	headers *Headers
+	signal  *AbortSignal
+}
+
+const (
+	redirectFollow = "follow"
+	redirectError  = "error"
+	redirectManual = "manual"
+
+	credentialsOmit = "omit"
+)
+
+func (f *fetchAPI) request(call sobek.ConstructorCall) *sobek.Object {
+	r := f.newRequest(call.Argument(0), call.Argument(1))
+	return f.wrap(r, call.This.Prototype())
+}
+
+// newRequest creates a request from an URL or another Request, and the options of init.
+// It panics with a TypeError if any of them is invalid.
+//
+//nolint:cyclop,funlen
+func (f *fetchAPI) newRequest(input, init sobek.Value) *Request {
+	rt := f.vu.Runtime()
+
+	r := &Request{
+		api:         f,
+		Method:      http.MethodGet,
+		Redirect:    redirectFollow,
+		Credentials: "same-origin",
+	}
+
+	var headers *Headers
+	if other, ok := input.Export().(*Request); ok {
+		r.URL = other.URL
+		r.Method = other.Method
+		r.Redirect = other.Redirect
+		r.Credentials = other.Credentials
+		r.signal = other.signal
+		r.body = other.clone(rt)
+		headers = other.headers.clone()
+	} else {
+		r.URL = parseURL(rt, input)
+		headers = &Headers{rt: rt}
+	}
+
+	var options *sobek.Object
+	if !common.IsNullish(init) {
+		options = init.ToObject(rt)
+	}
+	option := func(name string) (sobek.Value, bool) {
+		if options == nil {
+			return nil, false
+		}
+		v := options.Get(name)
+		return v, v != nil && !sobek.IsUndefined(v)
+	}
+
+	if v, ok := option("method"); ok {
+		r.Method = normalizeMethod(rt, v.String())
+	}
+	if v, ok := option("headers"); ok {
+		headers = &Headers{rt: rt}
+		headers.fill(v)
+	}
+	if v, ok := option("signal"); ok {
+		r.signal = nil
+		if !sobek.IsNull(v) {
+			signal, isSignal := v.Export().(*AbortSignal)
+			if !isSignal {
+				panic(rt.NewTypeError("the signal option must be an AbortSignal"))
+			}
+			r.signal = signal
+		}
+	}
+	if v, ok := option("redirect"); ok {
+		r.Redirect = v.String()
+		if !slices.Contains([]string{redirectFollow, redirectError, redirectManual}, r.Redirect) {
+			panic(rt.NewTypeError("invalid redirect mode %q", r.Redirect))
+		}
+	}
+	if v, ok := option("credentials"); ok {
+		r.Credentials = v.String()
+		if !slices.Contains([]string{credentialsOmit, "same-origin", "include"}, r.Credentials) {
+			panic(rt.NewTypeError("invalid credentials mode %q", r.Credentials))
+		}
+	}
+	if v, ok := option("body"); ok {
+		data, contentType := extractBody(rt, v)
+		r.body = body{data: data}
+		if contentType != "" && !headers.Has("content-type") {
+			headers.Append("content-type", contentType)
+		}
+	}
+
+	if r.data != nil && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		panic(rt.NewTypeError("a request with the %s method cannot have a body", r.Method))
+	}
+
+	if r.signal == nil {
+		r.signal = f.newAbortSignal()
+	}
+	r.Signal = r.signal.obj
+	r.headers = headers
+	r.Headers = f.wrap(headers, f.headersPrototype)
+
+	return r
+}
+
+// parseURL returns the serialization of an absolute URL, given as a string or an URL object.
+func parseURL(rt *sobek.Runtime, v sobek.Value) string {
+	if common.IsNullish(v) {
+		panic(rt.NewTypeError("a URL is required"))
+	}
+	u, err := url.Parse(v.String())
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		panic(rt.NewTypeError("invalid URL %q", v.String()))
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	u.Fragment = ""
+	return u.String()
+}
+
+// normalizeMethod validates a method, and uppercases it if it is one of the standard ones.
+// https://fetch.spec.whatwg.org/#concept-method-normalize
+func normalizeMethod(rt *sobek.Runtime, method string) string {
+	if !isToken(method) {
+		panic(rt.NewTypeError("invalid method %q", method))
+	}
+	upper := strings.ToUpper(method)
+	switch upper {
+	case http.MethodConnect, http.MethodTrace, "TRACK":
+		panic(rt.NewTypeError("the %s method is forbidden", upper))
+	case http.MethodDelete, http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPost, http.MethodPut:
+		return upper
+	default:
+		return method
+	}
+}
+
+// Text returns a promise resolved with the body decoded as UTF-8.
+func (r *Request) Text() *sobek.Promise {
+	return r.text(r.api.vu.Runtime())
+}
+
+// JSON returns a promise resolved with the body parsed as JSON.
+func (r *Request) JSON() *sobek.Promise {
+	return r.json(r.api.vu.Runtime())
+}
+
+// ArrayBuffer returns a promise resolved with the body as an ArrayBuffer.
+func (r *Request) ArrayBuffer() *sobek.Promise {
+	return r.arrayBuffer(r.api.vu.Runtime())
+}
+
+// Bytes returns a promise resolved with the body as an Uint8Array.
+func (r *Request) Bytes() *sobek.Promise {
+	return r.bytes(r.api.vu.Runtime())
+}
+
+// Clone returns a copy of the request, which must not have had its body read.
+func (r *Request) Clone() *sobek.Object {
+	rt := r.api.vu.Runtime()
+	c := *r
+	c.body = r.clone(rt)
+	c.headers = r.headers.clone()
+	c.Headers = r.api.wrap(c.headers, r.api.headersPrototype)
+	return r.api.wrap(&c, r.api.requestPrototype)
+}
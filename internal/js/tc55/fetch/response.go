@@ -0,0 +1,208 @@
+package fetch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/lib/netext/httpext"
+)
+
+// Response is the implementation of the Response class.
+// https://fetch.spec.whatwg.org/#response-class
+type Response struct {
+	body
+
+	Type       string        `js:"type"`
+	URL        string        `js:"url"`
+	Redirected bool          `js:"redirected"`
+	Status     int           `js:"status"`
+	OK         bool          `js:"ok"`
+	StatusText string        `js:"statusText"`
+	Headers    *sobek.Object `js:"headers"`
+
+	api     *fetchAPI
+	headers *Headers
+}
+
+func (f *fetchAPI) response(call sobek.ConstructorCall) *sobek.Object {
+	r := f.newResponse(call.Argument(0), call.Argument(1))
+	return f.wrap(r, call.This.Prototype())
+}
+
+// newResponse creates a response with the given body and the status, statusText and headers of init.
+// https://fetch.spec.whatwg.org/#dom-response
+func (f *fetchAPI) newResponse(bodyInit, init sobek.Value) *Response {
+	rt := f.vu.Runtime()
+
+	r := &Response{api: f, Type: "default", Status: 200, headers: &Headers{rt: rt}}
+	if !common.IsNullish(init) {
+		options := init.ToObject(rt)
+		if v := options.Get("status"); v != nil && !sobek.IsUndefined(v) {
+			r.Status = int(v.ToInteger())
+			if r.Status < 200 || r.Status > 599 {
+				panic(newRangeError(rt, "the status must be in the range 200 to 599, got %d", r.Status))
+			}
+		}
+		if v := options.Get("statusText"); v != nil && !sobek.IsUndefined(v) {
+			r.StatusText = v.String()
+		}
+		if v := options.Get("headers"); v != nil && !sobek.IsUndefined(v) {
+			r.headers.fill(v)
+		}
+	}
+
+	data, contentType := extractBody(rt, bodyInit)
+	if data != nil {
+		if isNullBodyStatus(r.Status) {
+			panic(rt.NewTypeError("a response with the status %d cannot have a body", r.Status))
+		}
+		r.data = data
+		if contentType != "" && !r.headers.Has("content-type") {
+			r.headers.Append("content-type", contentType)
+		}
+	}
+
+	r.OK = r.Status >= 200 && r.Status <= 299
+	r.Headers = f.wrap(r.headers, f.headersPrototype)
+	return r
+}
+
+// responseFromHTTPext creates the response returned by fetch from the one of httpext.
+func (f *fetchAPI) responseFromHTTPext(resp *httpext.Response, requestURL string) *sobek.Object {
+	rt := f.vu.Runtime()
+
+	r := &Response{
+		api:        f,
+		Type:       "basic",
+		URL:        resp.URL,
+		Redirected: resp.URL != requestURL,
+		Status:     resp.Status,
+		OK:         resp.Status >= 200 && resp.Status <= 299,
+		StatusText: strings.TrimPrefix(resp.StatusText, strconv.Itoa(resp.Status)+" "),
+		headers:    &Headers{rt: rt},
+	}
+	for name, value := range resp.Headers {
+		r.headers.Append(name, value)
+	}
+	r.headers.immutable = true
+
+	if data, ok := resp.Body.([]byte); ok {
+		r.data = data
+	} else if !isNullBodyStatus(resp.Status) {
+		r.data = []byte{}
+	}
+
+	r.Headers = f.wrap(r.headers, f.headersPrototype)
+	return f.wrap(r, f.responsePrototype)
+}
+
+// responseError implements Response.error(), returning a network error.
+func (f *fetchAPI) responseError() *sobek.Object {
+	rt := f.vu.Runtime()
+	r := &Response{api: f, Type: "error", headers: &Headers{rt: rt, immutable: true}}
+	r.Headers = f.wrap(r.headers, f.headersPrototype)
+	return f.wrap(r, f.responsePrototype)
+}
+
+// responseOpaqueRedirect returns the opaque-redirect filtered response of a
+// redirection, for the requests with the "manual" redirect mode.
+func (f *fetchAPI) responseOpaqueRedirect(requestURL string) *sobek.Object {
+	rt := f.vu.Runtime()
+	r := &Response{api: f, Type: "opaqueredirect", URL: requestURL, headers: &Headers{rt: rt, immutable: true}}
+	r.Headers = f.wrap(r.headers, f.headersPrototype)
+	return f.wrap(r, f.responsePrototype)
+}
+
+// responseJSON implements Response.json(), returning a response with data serialized as JSON.
+func (f *fetchAPI) responseJSON(data, init sobek.Value) (*sobek.Object, error) {
+	rt := f.vu.Runtime()
+	stringify, _ := sobek.AssertFunction(rt.Get("JSON").ToObject(rt).Get("stringify"))
+	serialized, err := stringify(sobek.Undefined(), data)
+	if err != nil {
+		return nil, err
+	}
+	if sobek.IsUndefined(serialized) {
+		panic(rt.NewTypeError("the data can't be serialized as JSON"))
+	}
+
+	r := f.newResponse(sobek.Undefined(), init)
+	if isNullBodyStatus(r.Status) {
+		panic(rt.NewTypeError("a response with the status %d cannot have a body", r.Status))
+	}
+	r.data = []byte(serialized.String())
+	if !r.headers.Has("content-type") {
+		r.headers.Append("content-type", "application/json")
+	}
+	return f.wrap(r, f.responsePrototype), nil
+}
+
+// responseRedirect implements Response.redirect(), returning a redirection to url.
+func (f *fetchAPI) responseRedirect(location sobek.Value, status sobek.Value) *sobek.Object {
+	rt := f.vu.Runtime()
+	u := parseURL(rt, location)
+
+	code := 302
+	if !sobek.IsUndefined(status) {
+		code = int(status.ToInteger())
+	}
+	if !isRedirectStatus(code) {
+		panic(newRangeError(rt, "invalid redirect status %d", code))
+	}
+
+	r := &Response{api: f, Type: "default", Status: code, headers: &Headers{rt: rt}}
+	r.headers.Append("location", u)
+	r.headers.immutable = true
+	r.Headers = f.wrap(r.headers, f.headersPrototype)
+	return f.wrap(r, f.responsePrototype)
+}
+
+// Text returns a promise resolved with the body decoded as UTF-8.
+func (r *Response) Text() *sobek.Promise {
+	return r.text(r.api.vu.Runtime())
+}
+
+// JSON returns a promise resolved with the body parsed as JSON.
+func (r *Response) JSON() *sobek.Promise {
+	return r.json(r.api.vu.Runtime())
+}
+
+// ArrayBuffer returns a promise resolved with the body as an ArrayBuffer.
+func (r *Response) ArrayBuffer() *sobek.Promise {
+	return r.arrayBuffer(r.api.vu.Runtime())
+}
+
+// Bytes returns a promise resolved with the body as an Uint8Array.
+func (r *Response) Bytes() *sobek.Promise {
+	return r.bytes(r.api.vu.Runtime())
+}
+
+// Clone returns a copy of the response, which must not have had its body read.
+func (r *Response) Clone() *sobek.Object {
+	rt := r.api.vu.Runtime()
+	c := *r
+	c.body = r.clone(rt)
+	c.headers = r.headers.clone()
+	c.headers.immutable = r.headers.immutable
+	c.Headers = r.api.wrap(c.headers, r.api.headersPrototype)
+	return r.api.wrap(&c, r.api.responsePrototype)
+}
+
+func isNullBodyStatus(status int) bool {
+	return status == 101 || status == 103 || status == 204 || status == 205 || status == 304
+}
+
+func isRedirectStatus(status int) bool {
+	return status == 301 || status == 302 || status == 303 || status == 307 || status == 308
+}
+
+func newRangeError(rt *sobek.Runtime, format string, args ...any) *sobek.Object {
+	exc, err := rt.New(rt.Get("RangeError"), rt.ToValue(fmt.Sprintf(format, args...)))
+	if err != nil {
+		panic(err)
+	}
+	return exc
+}
@@ -0,0 +1,127 @@
+package fetch_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/js/modulestest"
+)
+
+func TestRequest(t *testing.T) {
+	t.Parallel()
+
+	runtime := modulestest.NewRuntime(t)
+	_, err := runtime.RunOnEventLoop(`(async function () {
+		const request = new Request("https://example.com", {
+			method: "post",
+			headers: { "X-Test": "a" },
+			body: JSON.stringify({ a: 1 }),
+		});
+		if (request.method !== "POST" || request.url !== "https://example.com/" || request.redirect !== "follow") {
+			throw new Error("unexpected request " + JSON.stringify(request));
+		}
+		if (request.headers.get("content-type") !== "text/plain;charset=UTF-8" || request.signal.aborted) {
+			throw new Error("unexpected request headers or signal");
+		}
+
+		const copy = new Request(request, { method: "Patch" });
+		if (copy.method !== "Patch" || copy.headers.get("x-test") !== "a") {
+			throw new Error("unexpected copy " + JSON.stringify(copy));
+		}
+
+		const clone = request.clone();
+		if ((await request.json()).a !== 1 || (await clone.text()) !== '{"a":1}' || !request.bodyUsed) {
+			throw new Error("unexpected bodies");
+		}
+		try {
+			request.clone();
+			throw new Error("expected clone to fail");
+		} catch (err) {
+			if (!(err instanceof TypeError)) {
+				throw err;
+			}
+		}
+	})()`)
+	require.NoError(t, err)
+}
+
+func TestResponse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("constructor", func(t *testing.T) {
+		t.Parallel()
+
+		runtime := modulestest.NewRuntime(t)
+		_, err := runtime.RunOnEventLoop(`(async function () {
+			let res = new Response(new Uint8Array([0xEF, 0xBB, 0xBF, 0x68, 0x69, 0xFF]), {
+				status: 201,
+				statusText: "Created",
+				headers: { "X-Test": "a" },
+			});
+			if (!(res instanceof Response) || res.status !== 201 || !res.ok || res.statusText !== "Created"
+				|| res.type !== "default" || res.headers.get("x-test") !== "a" || res.headers.has("content-type")) {
+				throw new Error("unexpected response " + JSON.stringify(res));
+			}
+			if (await res.clone().text() !== "hi\uFFFD") {
+				throw new Error("unexpected text");
+			}
+			const bytes = await res.bytes();
+			if (!(bytes instanceof Uint8Array) || bytes.length !== 6) {
+				throw new Error("unexpected bytes");
+			}
+
+			res = new Response(null, { status: 204 });
+			if (res.bodyUsed || await res.text() !== "" || res.bodyUsed) {
+				throw new Error("unexpected null body");
+			}
+
+			res = Response.json({ a: [1] }, { status: 400 });
+			if (res.ok || res.headers.get("content-type") !== "application/json" || (await res.json()).a[0] !== 1) {
+				throw new Error("unexpected JSON response");
+			}
+
+			res = Response.redirect("https://example.com/a", 301);
+			if (res.status !== 301 || res.headers.get("location") !== "https://example.com/a") {
+				throw new Error("unexpected redirect response");
+			}
+
+			res = Response.error();
+			if (res.type !== "error" || res.status !== 0) {
+				throw new Error("unexpected error response");
+			}
+
+			try {
+				await new Response("{").json();
+				throw new Error("expected json to fail");
+			} catch (err) {
+				if (!(err instanceof SyntaxError)) {
+					throw err;
+				}
+			}
+		})()`)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		testCases := map[string]string{
+			`new Response("", { status: 600 })`:          "RangeError",
+			`new Response("body", { status: 204 })`:      "TypeError",
+			`Response.redirect("https://k6.io", 200)`:    "RangeError",
+			`Response.redirect("/relative")`:             "TypeError",
+			`Response.json(undefined)`:                   "TypeError",
+			`new Request("https://k6.io", { body: "" })`: "TypeError",
+		}
+		for script, expected := range testCases {
+			t.Run(script, func(t *testing.T) {
+				t.Parallel()
+
+				rt := modulestest.NewRuntime(t).VU.Runtime()
+				_, err := rt.RunString(script)
+				require.ErrorContains(t, err, expected)
+			})
+		}
+	})
+}
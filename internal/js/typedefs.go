@@ -0,0 +1,385 @@
+package js
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/grafana/sobek"
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/v2/internal/event"
+	"go.k6.io/k6/v2/internal/js/eventloop"
+	"go.k6.io/k6/v2/internal/usage"
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/metrics"
+)
+
+// TypeDefinitions returns the TypeScript declarations of the built-in and the
+// extension JS modules, generated from the exports of their Go implementation.
+//
+// They are meant for the editors to complete the imports, not for type checking:
+// most of the functions take sobek.Value arguments or return JS objects built at
+// runtime, so their parameters and results are declared as any.
+//
+// The modules which can't be instantiated outside of a test run, e.g. the
+// removed ones, are skipped.
+func TypeDefinitions(logger logrus.FieldLogger) string {
+	jsModules := getJSModules()
+
+	names := make([]string, 0, len(jsModules))
+	for name := range jsModules {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	b.WriteString("// Type definitions of the k6 JS modules, generated from their implementation.\n")
+	b.WriteString("// Most of the parameters and results are declared as any, so they aren't type checked.\n")
+
+	for _, name := range names {
+		exports, ok := moduleExports(logger, jsModules[name])
+		if !ok {
+			logger.Debugf("Skipping the type definitions of %q, it can't be instantiated", name)
+			continue
+		}
+
+		fmt.Fprintf(&b, "\ndeclare module %q {\n", name)
+		writeExports(&b, exports)
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+// moduleExports returns the exports of a new instance of the module, created
+// for a VU in the init context.
+func moduleExports(logger logrus.FieldLogger, module any) (exports modules.Exports, ok bool) {
+	m, isModule := module.(modules.Module)
+	if !isModule {
+		return modules.Exports{}, false
+	}
+
+	registry := metrics.NewRegistry()
+	vu := &moduleVUImpl{
+		ctx:     context.Background(),
+		runtime: sobek.New(),
+		initEnv: &common.InitEnvironment{
+			TestPreInitState: &lib.TestPreInitState{
+				Logger:         logger,
+				Registry:       registry,
+				BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
+				Usage:          usage.New(),
+			},
+		},
+		events: events{
+			global: event.NewEventSystem(0, logger),
+			local:  event.NewEventSystem(0, logger),
+		},
+	}
+	vu.eventLoop = eventloop.New(vu)
+	vu.runtime.SetFieldNameMapper(common.FieldNameMapper{})
+
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+
+	instance := m.NewModuleInstance(vu)
+	if instance == nil {
+		return modules.Exports{}, false
+	}
+
+	return instance.Exports(), true
+}
+
+var (
+	typeConstructorCall = reflect.TypeFor[sobek.ConstructorCall]()
+	typeFunctionCall    = reflect.TypeFor[sobek.FunctionCall]()
+	typeRuntime         = reflect.TypeFor[*sobek.Runtime]()
+	typeValue           = reflect.TypeFor[sobek.Value]()
+	typePromise         = reflect.TypeFor[*sobek.Promise]()
+	typeObject          = reflect.TypeFor[*sobek.Object]()
+	typeError           = reflect.TypeFor[error]()
+)
+
+func writeExports(b *strings.Builder, exports modules.Exports) {
+	names := make([]string, 0, len(exports.Named))
+	for name := range exports.Named {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		value := reflect.ValueOf(exports.Named[name])
+		if !value.IsValid() {
+			fmt.Fprintf(b, "  export const %s: any;\n", name)
+			continue
+		}
+
+		switch t := value.Type(); {
+		case isConstructor(t):
+			fmt.Fprintf(b, "  export class %s {\n    constructor(...args: any[]);\n    [key: string]: any;\n  }\n", name)
+		case t.Kind() == reflect.Func:
+			fmt.Fprintf(b, "  export function %s%s;\n", name, tsSignature(t))
+		default:
+			fmt.Fprintf(b, "  export const %s: %s;\n", name, tsValueType(value))
+		}
+	}
+
+	if exports.Default == nil {
+		return
+	}
+
+	fmt.Fprintf(b, "  const _default: %s;\n  export default _default;\n", tsValueType(reflect.ValueOf(exports.Default)))
+}
+
+// isConstructor returns true for the functions sobek calls as constructors.
+func isConstructor(t reflect.Type) bool {
+	return t.Kind() == reflect.Func && t.NumIn() > 0 && t.In(0) == typeConstructorCall
+}
+
+// tsSignature returns the TypeScript signature of the function, e.g.
+// "(arg0: string, arg1?: any): number".
+func tsSignature(t reflect.Type) string {
+	params, result := tsFunc(t)
+	return "(" + params + "): " + result
+}
+
+// tsFunc returns the TypeScript parameters and result of the function.
+func tsFunc(t reflect.Type) (params string, result string) {
+	var in []string
+
+	// The parameters following an optional one must be optional too.
+	optional := false
+	for i := range t.NumIn() {
+		param := t.In(i)
+
+		switch {
+		case param == typeFunctionCall:
+			in = append(in, "...args: any[]")
+		case param == typeRuntime:
+		case t.IsVariadic() && i == t.NumIn()-1:
+			in = append(in, fmt.Sprintf("...arg%d: %s[]", i, tsType(param.Elem())))
+		case param == typeValue || param.Kind() == reflect.Interface || optional:
+			optional = true
+			in = append(in, fmt.Sprintf("arg%d?: %s", i, tsType(param)))
+		default:
+			in = append(in, fmt.Sprintf("arg%d: %s", i, tsType(param)))
+		}
+	}
+
+	var out []reflect.Type
+	for i := range t.NumOut() {
+		if o := t.Out(i); o != typeError {
+			out = append(out, o)
+		}
+	}
+
+	switch len(out) {
+	case 0:
+		result = "void"
+	case 1:
+		result = tsType(out[0])
+	default:
+		result = "any"
+	}
+
+	return strings.Join(in, ", "), result
+}
+
+// tsValueType returns the TypeScript type of the exported value, which is
+// the shape of the objects whose methods and fields are exposed to JS.
+func tsValueType(value reflect.Value) string {
+	t := value.Type()
+	if t == typeObject {
+		return tsJSObjectType(value.Interface().(*sobek.Object)) //nolint:forcetypeassert
+	}
+	if t.Kind() == reflect.Map && t.Key().Kind() == reflect.String {
+		return tsMapType(value)
+	}
+
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct ||
+		t.Kind() == reflect.Struct {
+		return tsObjectType(t)
+	}
+
+	return tsType(t)
+}
+
+func tsMapType(value reflect.Value) string {
+	keys := value.MapKeys()
+	if len(keys) == 0 {
+		return tsType(value.Type())
+	}
+
+	members := make([]string, 0, len(keys))
+	for _, key := range keys {
+		member := value.MapIndex(key)
+		if member.Kind() == reflect.Interface {
+			member = member.Elem()
+		}
+
+		switch {
+		case !member.IsValid():
+			members = append(members, fmt.Sprintf("%q: any", key.String()))
+		case member.Kind() == reflect.Func && !isConstructor(member.Type()):
+			members = append(members, fmt.Sprintf("%q%s", key.String(), tsSignature(member.Type())))
+		case member.Kind() == reflect.Func:
+			members = append(members, fmt.Sprintf("%q: new (...args: any[]) => any", key.String()))
+		default:
+			members = append(members, fmt.Sprintf("%q: %s", key.String(), tsType(member.Type())))
+		}
+	}
+	slices.Sort(members)
+
+	return "{ " + strings.Join(members, "; ") + " }"
+}
+
+// tsJSObjectType returns the shape of the JS object, from its properties.
+func tsJSObjectType(obj *sobek.Object) string {
+	if obj == nil {
+		return "any"
+	}
+
+	keys := obj.Keys()
+	if len(keys) == 0 {
+		return "object"
+	}
+
+	members := make([]string, 0, len(keys))
+	for _, key := range keys {
+		value, ok := getProperty(obj, key)
+		if !ok {
+			members = append(members, fmt.Sprintf("readonly %q: any", key))
+			continue
+		}
+		if _, isFunc := sobek.AssertFunction(value); isFunc {
+			members = append(members, fmt.Sprintf("%q(...args: any[]): any", key))
+			continue
+		}
+		if nested, isObject := value.(*sobek.Object); isObject {
+			members = append(members, fmt.Sprintf("%q: %s", key, tsJSObjectType(nested)))
+			continue
+		}
+
+		exported := reflect.ValueOf(value.Export())
+		if !exported.IsValid() {
+			members = append(members, fmt.Sprintf("%q: any", key))
+			continue
+		}
+		members = append(members, fmt.Sprintf("%q: %s", key, tsType(exported.Type())))
+	}
+	slices.Sort(members)
+
+	return "{ " + strings.Join(members, "; ") + " }"
+}
+
+// getProperty returns the value of the object's property, or false if its
+// getter throws, e.g. because it's not supported in the init context.
+func getProperty(obj *sobek.Object, key string) (value sobek.Value, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+
+	return obj.Get(key), true
+}
+
+// tsObjectType returns the shape of the struct, with the methods and the
+// fields named as they are in JS.
+func tsObjectType(t reflect.Type) string {
+	var members []string
+
+	structType := t
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		if name := common.FieldName(structType, field); name != "" {
+			members = append(members, fmt.Sprintf("%s: %s", name, tsType(field.Type)))
+		}
+	}
+
+	for i := range t.NumMethod() {
+		method := t.Method(i)
+		name := common.MethodName(t, method)
+		if name == "" {
+			continue
+		}
+
+		// The method's type has the receiver as its first argument.
+		in := make([]reflect.Type, 0, method.Type.NumIn()-1)
+		for j := 1; j < method.Type.NumIn(); j++ {
+			in = append(in, method.Type.In(j))
+		}
+		out := make([]reflect.Type, 0, method.Type.NumOut())
+		for j := range method.Type.NumOut() {
+			out = append(out, method.Type.Out(j))
+		}
+
+		members = append(members, name+tsSignature(reflect.FuncOf(in, out, method.Type.IsVariadic())))
+	}
+
+	if len(members) == 0 {
+		return "any"
+	}
+
+	slices.Sort(members)
+
+	return "{ " + strings.Join(members, "; ") + " }"
+}
+
+// tsType returns the TypeScript type of the values of the Go type, once they
+// are converted by sobek.
+//
+//nolint:exhaustive
+func tsType(t reflect.Type) string {
+	switch t {
+	case typeValue:
+		return "any"
+	case typePromise:
+		return "Promise<any>"
+	case typeObject:
+		return "object"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "ArrayBuffer"
+		}
+		return tsType(t.Elem()) + "[]"
+	case reflect.Map:
+		return "Record<string, " + tsType(t.Elem()) + ">"
+	case reflect.Func:
+		if isConstructor(t) {
+			return "new (...args: any[]) => any"
+		}
+		params, result := tsFunc(t)
+		return "((" + params + ") => " + result + ")"
+	case reflect.Pointer:
+		return tsType(t.Elem())
+	default:
+		return "any"
+	}
+}
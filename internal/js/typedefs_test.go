@@ -0,0 +1,25 @@
+package js
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+)
+
+func TestTypeDefinitions(t *testing.T) {
+	t.Parallel()
+
+	defs := TypeDefinitions(testutils.NewLogger(t))
+
+	assert.Contains(t, defs, `declare module "k6" {`)
+	assert.Contains(t, defs, `export function check(`)
+	assert.Contains(t, defs, `declare module "k6/data" {`)
+	assert.Contains(t, defs, `export class SharedArray {`)
+	assert.Contains(t, defs, `declare module "k6/experimental/fs" {`)
+	assert.Contains(t, defs, `export const SeekMode: { "Current": number; "End": number; "Start": number };`)
+	assert.Contains(t, defs, `declare module "k6/execution" {`)
+	assert.Contains(t, defs, `export default _default;`)
+	assert.NotContains(t, defs, "className()")
+}
@@ -0,0 +1,123 @@
+package grpcext
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/js/modules"
+)
+
+// LoadProtos parses the given proto files, looked up in the import paths or in
+// the working directory if there are none, and returns their file descriptors
+// and the ones of their imports. It must be called in the init context.
+func LoadProtos(vu modules.VU, importPaths []string, filenames ...string) (*descriptorpb.FileDescriptorSet, error) {
+	initEnv, err := initEnvironment(vu)
+	if err != nil {
+		return nil, err
+	}
+
+	// If no import paths are specified, use the current working directory
+	if len(importPaths) == 0 {
+		importPaths = append(importPaths, initEnv.CWD.Path)
+	}
+
+	for i, s := range importPaths {
+		// Clean file scheme as it is the only supported scheme and the following APIs do not support them
+		importPaths[i] = strings.TrimPrefix(s, "file://")
+	}
+
+	resolver := protocompile.WithStandardImports(&protocompile.SourceResolver{
+		ImportPaths: importPaths,
+		Accessor: func(filename string) (io.ReadCloser, error) {
+			absFilePath := initEnv.GetAbsFilePath(filename)
+			return initEnv.FileSystems["file"].Open(absFilePath)
+		},
+	})
+
+	compiler := protocompile.Compiler{
+		Resolver: resolver,
+	}
+
+	fds, err := compiler.Compile(vu.Context(), filenames...)
+	if err != nil {
+		return nil, err
+	}
+
+	fdset := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]struct{})
+	for _, fd := range fds {
+		fdset.File = append(fdset.File, walkFileDescriptors(seen, fd)...)
+	}
+	return fdset, nil
+}
+
+// LoadProtoset parses the given protoset file (serialized FileDescriptorSet).
+// It must be called in the init context.
+func LoadProtoset(vu modules.VU, protosetPath string) (*descriptorpb.FileDescriptorSet, error) {
+	initEnv, err := initEnvironment(vu)
+	if err != nil {
+		return nil, err
+	}
+
+	absFilePath := initEnv.GetAbsFilePath(protosetPath)
+	fdsetFile, err := initEnv.FileSystems["file"].Open(absFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open protoset: %w", err)
+	}
+
+	defer func() { _ = fdsetFile.Close() }()
+	fdsetBytes, err := io.ReadAll(fdsetFile)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read protoset: %w", err)
+	}
+
+	fdset := &descriptorpb.FileDescriptorSet{}
+	if err = proto.Unmarshal(fdsetBytes, fdset); err != nil {
+		return nil, fmt.Errorf("couldn't unmarshal protoset file %s: %w", protosetPath, err)
+	}
+
+	return fdset, nil
+}
+
+func initEnvironment(vu modules.VU) (*common.InitEnvironment, error) {
+	if vu.State() != nil {
+		return nil, errors.New("load must be called in the init context")
+	}
+
+	initEnv := vu.InitEnv()
+	if initEnv == nil {
+		return nil, errors.New("missing init environment")
+	}
+
+	return initEnv, nil
+}
+
+// walkFileDescriptors recursively walks through a file descriptor and its dependencies,
+// converting them to FileDescriptorProto.
+// This ensures that all dependencies are included in the FileDescriptorSet.
+func walkFileDescriptors(seen map[string]struct{}, fd protoreflect.FileDescriptor) []*descriptorpb.FileDescriptorProto {
+	fds := []*descriptorpb.FileDescriptorProto{}
+
+	if _, ok := seen[fd.Path()]; ok {
+		return fds
+	}
+	seen[fd.Path()] = struct{}{}
+	fds = append(fds, protodesc.ToFileDescriptorProto(fd))
+
+	imports := fd.Imports()
+	for i := 0; i < imports.Len(); i++ {
+		dep := imports.Get(i).FileDescriptor
+		deps := walkFileDescriptors(seen, dep)
+		fds = append(fds, deps...)
+	}
+	return fds
+}
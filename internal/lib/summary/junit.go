@@ -0,0 +1,153 @@
+package summary
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// reportTestCase is a threshold or a check of the summary, as a test case of
+// the JUnit XML and TAP reports.
+type reportTestCase struct {
+	suite  string
+	name   string
+	ok     bool
+	values [][2]string
+}
+
+// ToJUnit returns the JUnit XML report of the summary, with a test suite of
+// the thresholds, a test case with the observed value for each of them, and a
+// test suite of the checks, a test case with the passes and the fails for each
+// of them.
+func ToJUnit(s *Summary) ([]byte, error) {
+	suites := junitTestSuites{
+		Name: "k6",
+		Time: strconv.FormatFloat(s.TestRunDuration.Seconds(), 'f', 3, 64),
+	}
+
+	testCases := reportTestCases(s)
+	for _, suite := range []string{"thresholds", "checks"} {
+		junitSuite := junitTestSuite{Name: suite}
+
+		for _, tc := range testCases {
+			if tc.suite != suite {
+				continue
+			}
+
+			values := make([]string, 0, len(tc.values))
+			for _, v := range tc.values {
+				values = append(values, v[0]+"="+v[1])
+			}
+
+			junitCase := junitTestCase{
+				Name:      tc.name,
+				ClassName: suite,
+				SystemOut: strings.Join(values, " "),
+			}
+
+			if !tc.ok {
+				junitCase.Failure = &junitFailure{Message: fmt.Sprintf("%s failed with %s", tc.name, junitCase.SystemOut)}
+				junitSuite.Failures++
+			}
+
+			junitSuite.TestCases = append(junitSuite.TestCases, junitCase)
+			junitSuite.Tests++
+		}
+
+		suites.Suites = append(suites.Suites, junitSuite)
+		suites.Tests += junitSuite.Tests
+		suites.Failures += junitSuite.Failures
+	}
+
+	out, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(out, '\n')...), nil
+}
+
+// reportTestCases returns the test cases of the thresholds, sorted by their
+// metric name, and the ones of the root group's checks, in their order.
+func reportTestCases(s *Summary) []reportTestCase {
+	var testCases []reportTestCase
+
+	names := make([]string, 0, len(s.Thresholds))
+	for name := range s.Thresholds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		mt := s.Thresholds[name]
+		for _, threshold := range mt.Thresholds {
+			tc := reportTestCase{
+				suite: "thresholds",
+				name:  name + ": " + threshold.Source,
+				ok:    threshold.Ok,
+			}
+
+			agg := thresholdAggregation(threshold.Source)
+			if value, ok := mt.Metric.Values[agg]; ok {
+				tc.values = [][2]string{{agg, strconv.FormatFloat(value, 'f', -1, 64)}}
+			}
+
+			testCases = append(testCases, tc)
+		}
+	}
+
+	if s.Checks != nil {
+		for _, check := range s.Checks.OrderedChecks {
+			testCases = append(testCases, reportTestCase{
+				suite: "checks",
+				name:  check.Name,
+				ok:    check.Fails == 0,
+				values: [][2]string{
+					{"passes", strconv.FormatInt(check.Passes, 10)},
+					{"fails", strconv.FormatInt(check.Fails, 10)},
+				},
+			})
+		}
+	}
+
+	return testCases
+}
+
+// thresholdAggregation returns the aggregation method of the threshold's
+// source, e.g. "p(95)" for "p(95)<200".
+func thresholdAggregation(source string) string {
+	if idx := strings.IndexAny(source, "<>=!"); idx >= 0 {
+		source = source[:idx]
+	}
+
+	return strings.TrimSpace(source)
+}
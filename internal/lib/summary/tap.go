@@ -0,0 +1,35 @@
+package summary
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ToTAP returns the TAP (version 13) report of the summary, with a test point
+// for each threshold and each check, and their values as YAML diagnostics.
+func ToTAP(s *Summary) []byte {
+	testCases := reportTestCases(s)
+
+	var buf bytes.Buffer
+	buf.WriteString("TAP version 13\n")
+	fmt.Fprintf(&buf, "1..%d\n", len(testCases))
+
+	for i, tc := range testCases {
+		status := "ok"
+		if !tc.ok {
+			status = "not ok"
+		}
+		fmt.Fprintf(&buf, "%s %d - %s %s\n", status, i+1, tc.suite, tc.name)
+
+		if len(tc.values) == 0 {
+			continue
+		}
+		buf.WriteString("  ---\n")
+		for _, v := range tc.values {
+			fmt.Fprintf(&buf, "  %s: %s\n", v[0], v[1])
+		}
+		buf.WriteString("  ...\n")
+	}
+
+	return buf.Bytes()
+}
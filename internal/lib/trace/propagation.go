@@ -0,0 +1,74 @@
+package trace
+
+import (
+	"encoding/binary"
+	"math/rand/v2"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"go.k6.io/k6/v2/metrics"
+)
+
+// The metadata keys of the samples emitted with the traceRequests option. The
+// spans of the iterations and of the groups are the parents of the spans of
+// the requests, and the samples emitted inside of them carry their span_id.
+const (
+	MetadataTraceID      = "trace_id"
+	MetadataSpanID       = "span_id"
+	MetadataParentSpanID = "parent_span_id"
+)
+
+// TraceparentHeader is the header propagating the trace context, as defined
+// by the W3C Trace Context specification.
+const TraceparentHeader = "traceparent"
+
+// NewTraceID returns a random trace ID, as a hex string.
+func NewTraceID() string {
+	var id trace.TraceID
+	binary.BigEndian.PutUint64(id[:8], rand.Uint64()) //nolint:gosec
+	binary.BigEndian.PutUint64(id[8:], rand.Uint64()) //nolint:gosec
+	return id.String()
+}
+
+// NewSpanID returns a random span ID, as a hex string.
+func NewSpanID() string {
+	var id trace.SpanID
+	binary.BigEndian.PutUint64(id[:], rand.Uint64()) //nolint:gosec
+	return id.String()
+}
+
+// Traceparent returns the value of the traceparent header for the span, with
+// the sampled flag set.
+func Traceparent(traceID, spanID string) string {
+	return "00-" + traceID + "-" + spanID + "-01"
+}
+
+// StartIteration sets a new trace and the span of the iteration in the metadata.
+func StartIteration(tm *metrics.TagsAndMeta) {
+	tm.SetMetadata(MetadataTraceID, NewTraceID())
+	tm.SetMetadata(MetadataSpanID, NewSpanID())
+	tm.DeleteMetadata(MetadataParentSpanID)
+}
+
+// StartSpan sets a new span, child of the current one, in the metadata. It
+// returns the new span's ID, or an empty string if there isn't a trace.
+func StartSpan(tm *metrics.TagsAndMeta) string {
+	if tm.Metadata[MetadataTraceID] == "" {
+		return ""
+	}
+	spanID := NewSpanID()
+	tm.SetMetadata(MetadataParentSpanID, tm.Metadata[MetadataSpanID])
+	tm.SetMetadata(MetadataSpanID, spanID)
+	return spanID
+}
+
+// SetSpan sets the current span in the metadata, e.g. to restore the span of
+// the parent group when a group ends.
+func SetSpan(tm *metrics.TagsAndMeta, spanID, parentSpanID string) {
+	tm.SetMetadata(MetadataSpanID, spanID)
+	if parentSpanID == "" {
+		tm.DeleteMetadata(MetadataParentSpanID)
+		return
+	}
+	tm.SetMetadata(MetadataParentSpanID, parentSpanID)
+}
@@ -0,0 +1,36 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/metrics"
+)
+
+func TestPropagation(t *testing.T) {
+	t.Parallel()
+
+	tm := metrics.TagsAndMeta{}
+	assert.Empty(t, StartSpan(&tm), "no span without a trace")
+
+	StartIteration(&tm)
+	traceID, iterationID := tm.Metadata[MetadataTraceID], tm.Metadata[MetadataSpanID]
+	require.Len(t, traceID, 32)
+	require.Len(t, iterationID, 16)
+	assert.NotContains(t, tm.Metadata, MetadataParentSpanID)
+
+	groupID := StartSpan(&tm)
+	require.Len(t, groupID, 16)
+	assert.Equal(t, groupID, tm.Metadata[MetadataSpanID])
+	assert.Equal(t, iterationID, tm.Metadata[MetadataParentSpanID])
+	assert.Equal(t, "00-"+traceID+"-"+groupID+"-01", Traceparent(traceID, groupID))
+
+	SetSpan(&tm, iterationID, "")
+	assert.Equal(t, iterationID, tm.Metadata[MetadataSpanID])
+	assert.NotContains(t, tm.Metadata, MetadataParentSpanID)
+
+	StartIteration(&tm)
+	assert.NotEqual(t, traceID, tm.Metadata[MetadataTraceID])
+}
@@ -0,0 +1,28 @@
+package log
+
+import "context"
+
+type attributesKey struct{}
+
+// WithAttributes returns a copy of the context with the function returning the
+// attributes of the log entries made with it, e.g. the VU and the iteration of
+// the console's ones. They aren't formatted, but the hooks exporting the entries
+// can add them. The function is only called by Attributes, so they aren't
+// computed when no hook needs them.
+func WithAttributes(ctx context.Context, attrs func() map[string]string) context.Context {
+	return context.WithValue(ctx, attributesKey{}, attrs)
+}
+
+// Attributes returns the attributes set in the context with WithAttributes.
+// The hooks have to call it when they are fired, as the attributes are the
+// ones at the time it's called, e.g. the VU's current iteration.
+func Attributes(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(attributesKey{}).(func() map[string]string)
+	if attrs == nil {
+		return nil
+	}
+	return attrs()
+}
@@ -0,0 +1,301 @@
+// Package aggregation implements the pre-aggregation of the samples in time
+// buckets, shared by the outputs that can send aggregates instead of the raw samples.
+//
+// The samples are grouped per time series in buckets of a fixed period, and
+// each bucket is emitted once its end is older than the wait period, to give
+// the late samples a chance to be aggregated. A sample arriving after its
+// bucket was emitted is dropped with a warning, as a second aggregate of the
+// same bucket would overwrite the first one in the outputs identifying the
+// points by their time series and time, like InfluxDB.
+//
+// The values of an aggregate depend on the metric's type:
+//
+//   - counter: count and sum.
+//   - gauge: count, min, max and last.
+//   - rate: count, passes (the non-zero values) and rate.
+//   - trend: count, sum, min, max, avg and the configured percentiles, like p(95).
+package aggregation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/v2/metrics"
+)
+
+// DefaultWaitPeriod is the default time waited after the end of a bucket before emitting it.
+const DefaultWaitPeriod = 2 * time.Second
+
+// DefaultPercentiles are the percentiles computed for the trends by default.
+//
+//nolint:gochecknoglobals
+var DefaultPercentiles = []float64{50, 90, 95, 99}
+
+// Value is one of the values of an aggregate.
+type Value struct {
+	Name  string
+	Value float64
+}
+
+// Aggregate holds the values of a time series in a time bucket.
+type Aggregate struct {
+	metrics.TimeSeries
+
+	// Time is the start of the bucket.
+	Time   time.Time
+	Values []Value
+}
+
+// Aggregator groups the samples per time series in time buckets. It isn't
+// safe for concurrent use, it's expected to be called by the output's flusher.
+type Aggregator struct {
+	period      time.Duration
+	waitPeriod  time.Duration
+	percentiles []float64
+	nowFunc     func() time.Time
+	logger      logrus.FieldLogger
+
+	buckets map[int64]map[metrics.TimeSeries]*series
+
+	// emitted is the id of the first bucket which wasn't emitted by Expired,
+	// the samples of the previous ones are dropped.
+	emitted int64
+	dropped uint64
+}
+
+// New returns an Aggregator with buckets of the given period, the trends
+// computing the given percentiles, or the default ones if there is none.
+// The dropped late samples are reported to the logger.
+func New(
+	period, waitPeriod time.Duration, percentiles []float64, logger logrus.FieldLogger,
+) (*Aggregator, error) {
+	if period <= 0 {
+		return nil, errors.New("the aggregation period must be positive")
+	}
+	if waitPeriod < 0 {
+		return nil, errors.New("the aggregation wait period can't be negative")
+	}
+	if len(percentiles) == 0 {
+		percentiles = DefaultPercentiles
+	}
+	for _, p := range percentiles {
+		if p < 0 || p > 100 {
+			return nil, fmt.Errorf("the aggregation percentile %v isn't between 0 and 100", p)
+		}
+	}
+
+	return &Aggregator{
+		period:      period,
+		waitPeriod:  waitPeriod,
+		percentiles: slices.Clone(percentiles),
+		nowFunc:     time.Now,
+		logger:      logger,
+		buckets:     make(map[int64]map[metrics.TimeSeries]*series),
+	}, nil
+}
+
+// ParsePercentiles parses a list of percentiles separated by semicolons, as the
+// commas separate the options in the outputs' arguments.
+func ParsePercentiles(s string) ([]float64, error) {
+	parts := strings.Split(s, ";")
+	percentiles := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		p, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregation percentile %q", part)
+		}
+		percentiles = append(percentiles, p)
+	}
+	return percentiles, nil
+}
+
+// Period returns the duration of the buckets.
+func (a *Aggregator) Period() time.Duration {
+	return a.period
+}
+
+// AddSamples aggregates the samples in their buckets, and drops the ones of
+// the buckets already emitted.
+func (a *Aggregator) AddSamples(containers []metrics.SampleContainer) {
+	var dropped uint64
+	for _, container := range containers {
+		for _, sample := range container.GetSamples() {
+			id := sample.Time.UnixNano() / int64(a.period)
+			if id < a.emitted {
+				dropped++
+				continue
+			}
+			bucket, ok := a.buckets[id]
+			if !ok {
+				bucket = make(map[metrics.TimeSeries]*series)
+				a.buckets[id] = bucket
+			}
+			s, ok := bucket[sample.TimeSeries]
+			if !ok {
+				s = newSeries(sample.Metric.Type)
+				bucket[sample.TimeSeries] = s
+			}
+			s.add(sample)
+		}
+	}
+
+	if dropped == 0 {
+		return
+	}
+	if a.dropped == 0 {
+		a.logger.Warnf("Dropped %d samples which arrived after their aggregation bucket was emitted, "+
+			"increase the aggregation wait period to aggregate them", dropped)
+	} else {
+		a.logger.Debugf("Dropped %d samples which arrived after their aggregation bucket was emitted", dropped)
+	}
+	a.dropped += dropped
+}
+
+// Expired returns the aggregates of the buckets ended before the wait period,
+// and removes them from the Aggregator.
+func (a *Aggregator) Expired() []Aggregate {
+	cutoff := a.nowFunc().Add(-a.waitPeriod).UnixNano()
+	a.emitted = max(a.emitted, cutoff/int64(a.period))
+	return a.pop(func(id int64) bool {
+		return id < a.emitted
+	})
+}
+
+// All returns the aggregates of all of the buckets, even the ones still open,
+// and removes them from the Aggregator. It's expected to be called when the
+// output is stopped, reporting the total of the dropped samples.
+func (a *Aggregator) All() []Aggregate {
+	if a.dropped > 0 {
+		a.logger.Warnf("Dropped %d samples in total which arrived after their aggregation bucket was emitted", a.dropped)
+	}
+	return a.pop(func(int64) bool { return true })
+}
+
+// pop returns the aggregates of the selected buckets, sorted by time,
+// metric's name and tags.
+func (a *Aggregator) pop(selected func(id int64) bool) []Aggregate {
+	var result []Aggregate
+	for id, bucket := range a.buckets {
+		if !selected(id) {
+			continue
+		}
+		t := time.Unix(0, id*int64(a.period))
+		for ts, s := range bucket {
+			result = append(result, Aggregate{
+				TimeSeries: ts,
+				Time:       t,
+				Values:     s.values(ts.Metric.Type, a.percentiles),
+			})
+		}
+		delete(a.buckets, id)
+	}
+
+	slices.SortFunc(result, func(x, y Aggregate) int {
+		if c := x.Time.Compare(y.Time); c != 0 {
+			return c
+		}
+		if c := strings.Compare(x.Metric.Name, y.Metric.Name); c != 0 {
+			return c
+		}
+		return compareTags(x.Tags, y.Tags)
+	})
+	return result
+}
+
+// compareTags orders the tag sets by their sorted tags, a missing tag
+// sorting after any value.
+func compareTags(x, y *metrics.TagSet) int {
+	if x == y {
+		return 0
+	}
+	xs, ys := x.Map(), y.Map()
+	keys := make([]string, 0, len(xs)+len(ys))
+	for k := range xs {
+		keys = append(keys, k)
+	}
+	for k := range ys {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range slices.Compact(keys) {
+		xv, xok := xs[k]
+		yv, yok := ys[k]
+		switch {
+		case xok != yok && xok:
+			return -1
+		case xok != yok:
+			return 1
+		case xv < yv:
+			return -1
+		case xv > yv:
+			return 1
+		}
+	}
+	return 0
+}
+
+// series holds the aggregated samples of a time series in a bucket.
+type series struct {
+	count         uint64
+	passes        uint64
+	sum, min, max float64
+	last          float64
+	lastTime      time.Time
+	trend         *metrics.TrendSink
+}
+
+func newSeries(mt metrics.MetricType) *series {
+	s := &series{min: math.Inf(1), max: math.Inf(-1)}
+	if mt == metrics.Trend {
+		s.trend = metrics.NewTrendSink()
+	}
+	return s
+}
+
+func (s *series) add(sample metrics.Sample) {
+	s.count++
+	s.sum += sample.Value
+	s.min = math.Min(s.min, sample.Value)
+	s.max = math.Max(s.max, sample.Value)
+	if sample.Value != 0 {
+		s.passes++
+	}
+	if !sample.Time.Before(s.lastTime) {
+		s.last, s.lastTime = sample.Value, sample.Time
+	}
+	if s.trend != nil {
+		s.trend.Add(sample)
+	}
+}
+
+func (s *series) values(mt metrics.MetricType, percentiles []float64) []Value {
+	count := float64(s.count)
+	switch mt {
+	case metrics.Counter:
+		return []Value{{"count", count}, {"sum", s.sum}}
+	case metrics.Gauge:
+		return []Value{{"count", count}, {"min", s.min}, {"max", s.max}, {"last", s.last}}
+	case metrics.Rate:
+		return []Value{{"count", count}, {"passes", float64(s.passes)}, {"rate", float64(s.passes) / count}}
+	default:
+		values := make([]Value, 0, 5+len(percentiles))
+		values = append(values,
+			Value{"count", count}, Value{"sum", s.sum}, Value{"min", s.min}, Value{"max", s.max},
+			Value{"avg", s.sum / count},
+		)
+		for _, p := range percentiles {
+			values = append(values, Value{
+				Name:  "p(" + strconv.FormatFloat(p, 'f', -1, 64) + ")",
+				Value: s.trend.P(p / 100),
+			})
+		}
+		return values
+	}
+}
@@ -0,0 +1,166 @@
+package aggregation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/metrics"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(0, time.Second, nil, testutils.NewLogger(t))
+	require.Error(t, err)
+	_, err = New(time.Second, -time.Second, nil, testutils.NewLogger(t))
+	require.Error(t, err)
+	_, err = New(time.Second, time.Second, []float64{50, 101}, testutils.NewLogger(t))
+	require.Error(t, err)
+
+	a, err := New(time.Second, time.Second, nil, testutils.NewLogger(t))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPercentiles, a.percentiles)
+}
+
+func TestParsePercentiles(t *testing.T) {
+	t.Parallel()
+
+	percentiles, err := ParsePercentiles("50;99.9")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{50, 99.9}, percentiles)
+
+	_, err = ParsePercentiles("50;p99")
+	require.Error(t, err)
+}
+
+func TestAggregator(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	counter, err := registry.NewMetric("counter", metrics.Counter)
+	require.NoError(t, err)
+	gauge, err := registry.NewMetric("gauge", metrics.Gauge)
+	require.NoError(t, err)
+	rate, err := registry.NewMetric("rate", metrics.Rate)
+	require.NoError(t, err)
+	trend, err := registry.NewMetric("trend", metrics.Trend)
+	require.NoError(t, err)
+
+	tagsA := registry.RootTagSet().With("name", "a")
+	tagsB := registry.RootTagSet().With("name", "b")
+	start := time.Unix(100, 0)
+	sample := func(m *metrics.Metric, tags *metrics.TagSet, offset time.Duration, value float64) metrics.Sample {
+		return metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: m, Tags: tags},
+			Time:       start.Add(offset),
+			Value:      value,
+		}
+	}
+
+	a, err := New(10*time.Second, 2*time.Second, []float64{50, 100}, testutils.NewLogger(t))
+	require.NoError(t, err)
+	now := start.Add(11 * time.Second)
+	a.nowFunc = func() time.Time { return now }
+
+	a.AddSamples([]metrics.SampleContainer{metrics.Samples{
+		sample(counter, tagsB, 0, 1),
+		sample(counter, tagsA, time.Second, 2),
+		sample(counter, tagsA, 2*time.Second, 3),
+		sample(gauge, tagsA, 3*time.Second, 7),
+		sample(gauge, tagsA, time.Second, 9),
+		sample(rate, tagsA, 0, 1),
+		sample(rate, tagsA, time.Second, 0),
+		sample(trend, tagsA, 0, 1),
+		sample(trend, tagsA, time.Second, 2),
+		sample(trend, tagsA, 2*time.Second, 6),
+		sample(counter, tagsA, 10*time.Second, 4),
+	}})
+
+	// the first bucket ends before the wait period
+	assert.Empty(t, a.Expired())
+
+	now = start.Add(12 * time.Second)
+	expired := a.Expired()
+	assert.Equal(t, []Aggregate{
+		{
+			TimeSeries: metrics.TimeSeries{Metric: counter, Tags: tagsA},
+			Time:       start,
+			Values:     []Value{{"count", 2}, {"sum", 5}},
+		},
+		{
+			TimeSeries: metrics.TimeSeries{Metric: counter, Tags: tagsB},
+			Time:       start,
+			Values:     []Value{{"count", 1}, {"sum", 1}},
+		},
+		{
+			TimeSeries: metrics.TimeSeries{Metric: gauge, Tags: tagsA},
+			Time:       start,
+			Values:     []Value{{"count", 2}, {"min", 7}, {"max", 9}, {"last", 7}},
+		},
+		{
+			TimeSeries: metrics.TimeSeries{Metric: rate, Tags: tagsA},
+			Time:       start,
+			Values:     []Value{{"count", 2}, {"passes", 1}, {"rate", 0.5}},
+		},
+		{
+			TimeSeries: metrics.TimeSeries{Metric: trend, Tags: tagsA},
+			Time:       start,
+			Values: []Value{
+				{"count", 3}, {"sum", 9}, {"min", 1}, {"max", 6}, {"avg", 3}, {"p(50)", 2}, {"p(100)", 6},
+			},
+		},
+	}, expired)
+
+	assert.Equal(t, []Aggregate{{
+		TimeSeries: metrics.TimeSeries{Metric: counter, Tags: tagsA},
+		Time:       start.Add(10 * time.Second),
+		Values:     []Value{{"count", 1}, {"sum", 4}},
+	}}, a.All())
+	assert.Empty(t, a.All())
+}
+
+func TestLateSamples(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	counter, err := registry.NewMetric("counter", metrics.Counter)
+	require.NoError(t, err)
+	start := time.Unix(100, 0)
+	sample := func(offset time.Duration) metrics.Sample {
+		return metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: counter, Tags: registry.RootTagSet()},
+			Time:       start.Add(offset),
+			Value:      1,
+		}
+	}
+
+	logger, hook := testutils.NewLoggerWithHook(t, logrus.WarnLevel)
+	a, err := New(10*time.Second, 2*time.Second, nil, logger)
+	require.NoError(t, err)
+	now := start.Add(12 * time.Second)
+	a.nowFunc = func() time.Time { return now }
+
+	a.AddSamples([]metrics.SampleContainer{metrics.Samples{sample(0)}})
+	require.Len(t, a.Expired(), 1)
+
+	// the first bucket was emitted, its late samples are dropped
+	a.AddSamples([]metrics.SampleContainer{metrics.Samples{sample(time.Second), sample(2 * time.Second)}})
+	a.AddSamples([]metrics.SampleContainer{metrics.Samples{sample(time.Second), sample(10 * time.Second)}})
+	assert.Empty(t, a.Expired())
+
+	assert.Equal(t, []Aggregate{{
+		TimeSeries: metrics.TimeSeries{Metric: counter, Tags: registry.RootTagSet()},
+		Time:       start.Add(10 * time.Second),
+		Values:     []Value{{"count", 1}, {"sum", 1}},
+	}}, a.All())
+
+	lines := hook.Lines()
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "Dropped 2 samples which arrived after their aggregation bucket was emitted")
+	assert.Contains(t, lines[1], "Dropped 3 samples in total")
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/mstoykov/envconfig"
 
+	"go.k6.io/k6/v2/internal/output/aggregation"
 	"go.k6.io/k6/v2/lib/types"
 )
 
@@ -19,6 +20,11 @@ type Config struct {
 	FileName     null.String        `json:"file_name" envconfig:"K6_CSV_FILENAME"`
 	SaveInterval types.NullDuration `json:"save_interval" envconfig:"K6_CSV_SAVE_INTERVAL"`
 	TimeFormat   null.String        `json:"time_format" envconfig:"K6_CSV_TIME_FORMAT"`
+
+	// Aggregation, disabled without a period.
+	AggregationPeriod      types.NullDuration `json:"aggregation_period" envconfig:"K6_CSV_AGGREGATION_PERIOD"`
+	AggregationWaitPeriod  types.NullDuration `json:"aggregation_wait_period" envconfig:"K6_CSV_AGGREGATION_WAIT_PERIOD"`
+	AggregationPercentiles []float64          `json:"aggregation_percentiles" envconfig:"K6_CSV_AGGREGATION_PERCENTILES"` //nolint:lll
 }
 
 // TimeFormat custom enum type
@@ -42,6 +48,8 @@ func NewConfig() Config {
 		FileName:     null.NewString("file.csv", false),
 		SaveInterval: types.NewNullDuration(1*time.Second, false),
 		TimeFormat:   null.NewString("unix", false),
+
+		AggregationWaitPeriod: types.NewNullDuration(aggregation.DefaultWaitPeriod, false),
 	}
 }
 
@@ -56,6 +64,15 @@ func (c Config) Apply(cfg Config) Config {
 	if cfg.TimeFormat.Valid {
 		c.TimeFormat = cfg.TimeFormat
 	}
+	if cfg.AggregationPeriod.Valid {
+		c.AggregationPeriod = cfg.AggregationPeriod
+	}
+	if cfg.AggregationWaitPeriod.Valid {
+		c.AggregationWaitPeriod = cfg.AggregationWaitPeriod
+	}
+	if len(cfg.AggregationPercentiles) > 0 {
+		c.AggregationPercentiles = cfg.AggregationPercentiles
+	}
 	return c
 }
 
@@ -84,6 +101,20 @@ func ParseArg(arg string) (Config, error) {
 			c.FileName = null.StringFrom(v)
 		case "timeFormat":
 			c.TimeFormat = null.StringFrom(v)
+		case "aggregationPeriod":
+			if err := c.AggregationPeriod.UnmarshalText([]byte(v)); err != nil {
+				return c, err
+			}
+		case "aggregationWaitPeriod":
+			if err := c.AggregationWaitPeriod.UnmarshalText([]byte(v)); err != nil {
+				return c, err
+			}
+		case "aggregationPercentiles":
+			percentiles, err := aggregation.ParsePercentiles(v)
+			if err != nil {
+				return c, err
+			}
+			c.AggregationPercentiles = percentiles
 		default:
 			return c, fmt.Errorf("unknown key %q as argument for csv output", k)
 		}
@@ -19,6 +19,8 @@ func TestNewConfig(t *testing.T) {
 	assert.Equal(t, "file.csv", config.FileName.String)
 	assert.Equal(t, "1s", config.SaveInterval.String())
 	assert.Equal(t, "unix", config.TimeFormat.String)
+	assert.False(t, config.AggregationPeriod.Valid)
+	assert.Equal(t, "2s", config.AggregationWaitPeriod.String())
 }
 
 func TestApply(t *testing.T) {
@@ -83,6 +85,8 @@ func TestParseArg(t *testing.T) {
 				FileName:     null.StringFrom("test_file.csv"),
 				SaveInterval: types.NewNullDuration(1*time.Second, false),
 				TimeFormat:   null.NewString("unix", false),
+
+				AggregationWaitPeriod: types.NewNullDuration(2*time.Second, false),
 			},
 		},
 		"saveInterval=5s": {
@@ -90,6 +94,8 @@ func TestParseArg(t *testing.T) {
 				FileName:     null.NewString("file.csv", false),
 				SaveInterval: types.NullDurationFrom(5 * time.Second),
 				TimeFormat:   null.NewString("unix", false),
+
+				AggregationWaitPeriod: types.NewNullDuration(2*time.Second, false),
 			},
 		},
 		"filename=test.csv,saveInterval=5s": {
@@ -100,8 +106,24 @@ func TestParseArg(t *testing.T) {
 				FileName:     null.StringFrom("test.csv"),
 				SaveInterval: types.NewNullDuration(1*time.Second, false),
 				TimeFormat:   null.StringFrom("rfc3339"),
+
+				AggregationWaitPeriod: types.NewNullDuration(2*time.Second, false),
+			},
+		},
+		"aggregationPeriod=10s,aggregationWaitPeriod=1s,aggregationPercentiles=90;99.9": {
+			config: Config{
+				FileName:     null.NewString("file.csv", false),
+				SaveInterval: types.NewNullDuration(1*time.Second, false),
+				TimeFormat:   null.NewString("unix", false),
+
+				AggregationPeriod:      types.NullDurationFrom(10 * time.Second),
+				AggregationWaitPeriod:  types.NullDurationFrom(1 * time.Second),
+				AggregationPercentiles: []float64{90, 99.9},
 			},
 		},
+		"aggregationPercentiles=90;p99": {
+			expectedErr: true,
+		},
 	}
 
 	for arg, testCase := range cases {
@@ -13,6 +13,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"go.k6.io/k6/v2/internal/output/aggregation"
 	"go.k6.io/k6/v2/metrics"
 	"go.k6.io/k6/v2/output"
 )
@@ -35,6 +36,10 @@ type Output struct {
 	row          []string
 	saveInterval time.Duration
 	timeFormat   TimeFormat
+
+	// aggregator is nil if the aggregation is disabled
+	aggregator *aggregation.Aggregator
+	aggRow     []string
 }
 
 // New Creates new instance of CSV output
@@ -61,6 +66,19 @@ func newOutput(params output.Params) (*Output, error) {
 		return nil, err
 	}
 
+	var aggregator *aggregation.Aggregator
+	if config.AggregationPeriod.Valid {
+		aggregator, err = aggregation.New(
+			config.AggregationPeriod.TimeDuration(),
+			config.AggregationWaitPeriod.TimeDuration(),
+			config.AggregationPercentiles,
+			logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	saveInterval := config.SaveInterval.TimeDuration()
 	fname := config.FileName.String
 
@@ -77,6 +95,8 @@ func newOutput(params output.Params) (*Output, error) {
 			closeFn:      func() error { return nil },
 			logger:       logger,
 			params:       params,
+			aggregator:   aggregator,
+			aggRow:       make([]string, 4+len(resTags)+1),
 		}, nil
 	}
 
@@ -94,6 +114,8 @@ func newOutput(params output.Params) (*Output, error) {
 		timeFormat:   timeFormat,
 		logger:       logger,
 		params:       params,
+		aggregator:   aggregator,
+		aggRow:       make([]string, 4+len(resTags)+1),
 	}
 
 	if strings.HasSuffix(fname, ".gz") {
@@ -160,6 +182,9 @@ func (o *Output) Start() error {
 	o.logger.Debug("Starting...")
 
 	header := MakeHeader(o.resTags)
+	if o.aggregator != nil {
+		header = MakeAggregationHeader(o.resTags)
+	}
 	err := o.csvWriter.Write(header)
 	if err != nil {
 		o.logger.WithField("filename", o.fname).Error("CSV: Error writing column names to file")
@@ -181,6 +206,9 @@ func (o *Output) Stop() error {
 	o.logger.Debug("Stopping...")
 	defer o.logger.Debug("Stopped!")
 	o.periodicFlusher.Stop()
+	if o.aggregator != nil {
+		o.writeAggregates(o.aggregator.All())
+	}
 	return o.closeFn()
 }
 
@@ -188,6 +216,12 @@ func (o *Output) Stop() error {
 func (o *Output) flushMetrics() {
 	samples := o.GetBufferedSamples()
 
+	if o.aggregator != nil {
+		o.aggregator.AddSamples(samples)
+		o.writeAggregates(o.aggregator.Expired())
+		return
+	}
+
 	if len(samples) > 0 {
 		o.csvLock.Lock()
 		defer o.csvLock.Unlock()
@@ -204,6 +238,26 @@ func (o *Output) flushMetrics() {
 	}
 }
 
+// writeAggregates writes a row for each value of the aggregates.
+func (o *Output) writeAggregates(aggregates []aggregation.Aggregate) {
+	if len(aggregates) == 0 {
+		return
+	}
+
+	o.csvLock.Lock()
+	defer o.csvLock.Unlock()
+	for _, agg := range aggregates {
+		sample := metrics.Sample{TimeSeries: agg.TimeSeries, Time: agg.Time}
+		for _, v := range agg.Values {
+			row := AggregateToRow(&sample, v, o.resTags, o.ignoredTags, o.row, o.aggRow, o.timeFormat)
+			if err := o.csvWriter.Write(row); err != nil {
+				o.logger.WithField("filename", o.fname).Error("CSV: Error writing to file")
+			}
+		}
+	}
+	o.csvWriter.Flush()
+}
+
 // MakeHeader creates list of column names for csv file
 func MakeHeader(tags []string) []string {
 	tags = append(tags, "extra_tags")
@@ -211,6 +265,26 @@ func MakeHeader(tags []string) []string {
 	return append([]string{"metric_name", "timestamp", "metric_value"}, tags...)
 }
 
+// MakeAggregationHeader creates list of column names for csv file with the aggregation enabled
+func MakeAggregationHeader(tags []string) []string {
+	tags = append(tags, "extra_tags")
+	return append([]string{"metric_name", "timestamp", "aggregation", "metric_value"}, tags...)
+}
+
+// AggregateToRow converts a value of an aggregate into array of strings, the sample
+// holding the aggregate's time series and the start of its bucket
+func AggregateToRow(sample *metrics.Sample, value aggregation.Value, resTags []string, ignoredTags []string,
+	row []string, aggRow []string, timeFormat TimeFormat,
+) []string {
+	row = SampleToRow(sample, resTags, ignoredTags, row, timeFormat)
+	aggRow[0] = row[0]
+	aggRow[1] = row[1]
+	aggRow[2] = value.Name
+	aggRow[3] = fmt.Sprintf("%f", value.Value)
+	copy(aggRow[4:], row[3:len(row)-1])
+	return aggRow
+}
+
 // SampleToRow converts sample into array of strings
 func SampleToRow(sample *metrics.Sample, resTags []string, ignoredTags []string, row []string,
 	timeFormat TimeFormat,
@@ -48,6 +48,13 @@ func TestMakeHeader(t *testing.T) {
 	}
 }
 
+func TestMakeAggregationHeader(t *testing.T) {
+	t.Parallel()
+
+	header := MakeAggregationHeader([]string{"tag1", "tag2"})
+	assert.Equal(t, []string{"metric_name", "timestamp", "aggregation", "metric_value", "tag1", "tag2", "extra_tags"}, header)
+}
+
 func TestSampleToRow(t *testing.T) {
 	t.Parallel()
 
@@ -528,3 +535,51 @@ func sortExtraTagsForTest(t *testing.T, input string) string {
 	w.Flush()
 	return b.String()
 }
+
+func TestRunAggregation(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	trend, err := registry.NewMetric("my_trend", metrics.Trend)
+	require.NoError(t, err)
+	counter, err := registry.NewMetric("my_counter", metrics.Counter)
+	require.NoError(t, err)
+
+	tags := registry.RootTagSet().WithTagsFromMap(map[string]string{"check": "val1", "url": "val2"})
+	start := time.Unix(1562324640, 0)
+	samples := metrics.Samples{
+		{TimeSeries: metrics.TimeSeries{Metric: trend, Tags: tags}, Time: start, Value: 1},
+		{TimeSeries: metrics.TimeSeries{Metric: trend, Tags: tags}, Time: start.Add(2 * time.Second), Value: 3},
+		{TimeSeries: metrics.TimeSeries{Metric: counter, Tags: tags}, Time: start.Add(time.Second), Value: 2},
+		{TimeSeries: metrics.TimeSeries{Metric: counter, Tags: tags}, Time: start.Add(12 * time.Second), Value: 5},
+	}
+
+	mem := fsext.NewMemMapFs()
+	output, err := newOutput(output.Params{
+		Logger:         testutils.NewLogger(t),
+		FS:             mem,
+		Environment:    map[string]string{},
+		ConfigArgument: "fileName=test.csv,aggregationPeriod=10s,aggregationPercentiles=50",
+		ScriptOptions: lib.Options{
+			SystemTags: metrics.NewSystemTagSet(metrics.TagCheck),
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, output.Start())
+	output.AddMetricSamples([]metrics.SampleContainer{samples})
+	require.NoError(t, output.Stop())
+
+	assert.Equal(t, "metric_name,timestamp,aggregation,metric_value,check,extra_tags\n"+
+		"my_counter,1562324640,count,1.000000,val1,url=val2\n"+
+		"my_counter,1562324640,sum,2.000000,val1,url=val2\n"+
+		"my_trend,1562324640,count,2.000000,val1,url=val2\n"+
+		"my_trend,1562324640,sum,4.000000,val1,url=val2\n"+
+		"my_trend,1562324640,min,1.000000,val1,url=val2\n"+
+		"my_trend,1562324640,max,3.000000,val1,url=val2\n"+
+		"my_trend,1562324640,avg,2.000000,val1,url=val2\n"+
+		"my_trend,1562324640,p(50),2.000000,val1,url=val2\n"+
+		"my_counter,1562324650,count,1.000000,val1,url=val2\n"+
+		"my_counter,1562324650,sum,5.000000,val1,url=val2\n",
+		readUnCompressedFile("test.csv", mem))
+}
@@ -0,0 +1,245 @@
+package influxdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	client "github.com/influxdata/influxdb1-client/v2"
+)
+
+// maxRetryInterval caps the exponential backoff between the retries.
+const maxRetryInterval = 30 * time.Second
+
+// apiClient implements client.Client on top of the write endpoints of the
+// InfluxDB v2 and v3 HTTP APIs. The points are sent gzip compressed, and the
+// writes rejected with 429 or 503 are retried with an exponential backoff.
+type apiClient struct {
+	httpClient *http.Client
+	addr       *url.URL
+	writeURL   string
+	token      string
+	version    int64
+
+	// pointPrecision is the precision in the notation used for formatting the
+	// points, which can be different from the one used by the API.
+	pointPrecision string
+
+	maxRetries    int
+	retryInterval time.Duration
+}
+
+var _ client.Client = &apiClient{}
+
+// apiPrecisions maps the supported precisions to the ones for formatting the
+// points, and to the names used by the v2 and v3 APIs.
+//
+//nolint:gochecknoglobals
+var apiPrecisions = map[string]struct{ point, v2, v3 string }{
+	"":   {"n", "ns", "nanosecond"},
+	"n":  {"n", "ns", "nanosecond"},
+	"ns": {"n", "ns", "nanosecond"},
+	"u":  {"u", "us", "microsecond"},
+	"us": {"u", "us", "microsecond"},
+	"ms": {"ms", "ms", "millisecond"},
+	"s":  {"s", "s", "second"},
+}
+
+func newAPIClient(conf Config) (*apiClient, error) {
+	addr, err := url.Parse(conf.Addr.String)
+	if err != nil {
+		return nil, fmt.Errorf("invalid InfluxDB address: %w", err)
+	}
+	if addr.Scheme != "http" && addr.Scheme != "https" {
+		return nil, fmt.Errorf("the InfluxDB v%d API requires an http or https address, but got %q",
+			conf.APIVersion.Int64, conf.Addr.String)
+	}
+
+	precision, ok := apiPrecisions[conf.Precision.String]
+	if !ok {
+		return nil, fmt.Errorf("the InfluxDB v%d API doesn't support the %q precision, the supported ones are ns, us, ms and s",
+			conf.APIVersion.Int64, conf.Precision.String)
+	}
+
+	bucket := conf.Bucket.String
+	if bucket == "" {
+		bucket = conf.DB.String
+	}
+	if bucket == "" {
+		bucket = "k6"
+	}
+
+	params := url.Values{}
+	writeURL := *addr
+	switch conf.APIVersion.Int64 {
+	case 2:
+		if conf.Organization.String == "" {
+			return nil, errors.New("the InfluxDB v2 API requires an organization")
+		}
+		writeURL.Path = strings.TrimSuffix(writeURL.Path, "/") + "/api/v2/write"
+		params.Set("org", conf.Organization.String)
+		params.Set("bucket", bucket)
+		params.Set("precision", precision.v2)
+	case 3:
+		writeURL.Path = strings.TrimSuffix(writeURL.Path, "/") + "/api/v3/write_lp"
+		params.Set("db", bucket)
+		params.Set("precision", precision.v3)
+	default:
+		return nil, fmt.Errorf("unsupported InfluxDB API version %d", conf.APIVersion.Int64)
+	}
+	writeURL.RawQuery = params.Encode()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: conf.Insecure.Bool, //nolint:gosec
+	}
+	if conf.Proxy.Valid {
+		proxyURL, err := url.Parse(conf.Proxy.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the http proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &apiClient{
+		httpClient:     &http.Client{Transport: transport, Timeout: 30 * time.Second},
+		addr:           addr,
+		writeURL:       writeURL.String(),
+		token:          conf.Token.String,
+		version:        conf.APIVersion.Int64,
+		pointPrecision: precision.point,
+		maxRetries:     int(conf.MaxRetries.Int64),
+		retryInterval:  conf.RetryInterval.TimeDuration(),
+	}, nil
+}
+
+// Write writes the points in the line protocol format, retrying if InfluxDB is
+// overloaded or temporarily unavailable.
+func (c *apiClient) Write(bp client.BatchPoints) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, p := range bp.Points() {
+		if p == nil {
+			continue
+		}
+		if _, err := io.WriteString(gz, p.PrecisionString(c.pointPrecision)); err != nil {
+			return err
+		}
+		if _, err := gz.Write([]byte{'\n'}); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	body := buf.Bytes()
+
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := c.write(body)
+		if retryAfter < 0 || attempt >= c.maxRetries {
+			return err
+		}
+
+		wait := c.retryInterval << attempt
+		if wait <= 0 || wait > maxRetryInterval {
+			wait = maxRetryInterval
+		}
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		time.Sleep(wait)
+	}
+}
+
+// write sends a single write request. The returned duration is negative if the
+// request shouldn't be retried, and otherwise the time requested by InfluxDB
+// with the Retry-After header, if it had one.
+func (c *apiClient) write(body []byte) (time.Duration, error) {
+	req, err := http.NewRequest(http.MethodPost, c.writeURL, bytes.NewReader(body)) //nolint:noctx
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("User-Agent", "k6")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Token "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return -1, nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	err = fmt.Errorf("the InfluxDB v%d API responded with status %d: %s",
+		c.version, resp.StatusCode, strings.TrimSpace(string(msg)))
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return -1, err
+	}
+
+	var retryAfter time.Duration
+	if seconds, parseErr := strconv.Atoi(resp.Header.Get("Retry-After")); parseErr == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	return retryAfter, err
+}
+
+// Ping checks that InfluxDB is available.
+func (c *apiClient) Ping(timeout time.Duration) (time.Duration, string, error) {
+	pingURL := *c.addr
+	pingURL.Path = strings.TrimSuffix(pingURL.Path, "/") + "/ping"
+
+	start := time.Now()
+	req, err := http.NewRequest(http.MethodGet, pingURL.String(), nil) //nolint:noctx
+	if err != nil {
+		return 0, "", err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Token "+c.token)
+	}
+	httpClient := *c.httpClient
+	if timeout > 0 {
+		httpClient.Timeout = timeout
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return 0, "", fmt.Errorf("the InfluxDB ping responded with status %d", resp.StatusCode)
+	}
+	return time.Since(start), resp.Header.Get("X-Influxdb-Version"), nil
+}
+
+// Query isn't supported, the output only needs to write.
+func (c *apiClient) Query(client.Query) (*client.Response, error) {
+	return nil, fmt.Errorf("queries aren't supported by the InfluxDB v%d client", c.version)
+}
+
+// QueryAsChunk isn't supported, the output only needs to write.
+func (c *apiClient) QueryAsChunk(client.Query) (*client.ChunkedResponse, error) {
+	return nil, fmt.Errorf("queries aren't supported by the InfluxDB v%d client", c.version)
+}
+
+// Close releases the idle connections.
+func (c *apiClient) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,218 @@
+package influxdb
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	client "github.com/influxdata/influxdb1-client/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/lib/types"
+	"go.k6.io/k6/v2/metrics"
+	"go.k6.io/k6/v2/output"
+)
+
+func testBatch(t *testing.T) client.BatchPoints {
+	t.Helper()
+
+	batch, err := client.NewBatchPoints(client.BatchPointsConfig{})
+	require.NoError(t, err)
+	p, err := client.NewPoint("http_reqs", map[string]string{"status": "200"}, map[string]any{"value": 1.0},
+		time.Unix(1700000000, 123456789))
+	require.NoError(t, err)
+	batch.AddPoint(p)
+	return batch
+}
+
+func readGzipBody(t *testing.T, r *http.Request) string {
+	t.Helper()
+
+	assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+	gz, err := gzip.NewReader(r.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestAPIClientWrite(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		conf  Config
+		path  string
+		query string
+		line  string
+	}{
+		"v2": {
+			conf: Config{
+				APIVersion:   null.IntFrom(2),
+				Organization: null.StringFrom("grafana"),
+				Bucket:       null.StringFrom("perf"),
+				Precision:    null.StringFrom("ms"),
+			},
+			path:  "/api/v2/write",
+			query: "bucket=perf&org=grafana&precision=ms",
+			line:  "http_reqs,status=200 value=1 1700000000123\n",
+		},
+		"v3 with the bucket from the db": {
+			conf: Config{
+				APIVersion: null.IntFrom(3),
+				DB:         null.StringFrom("perf"),
+			},
+			path:  "/api/v3/write_lp",
+			query: "db=perf&precision=nanosecond",
+			line:  "http_reqs,status=200 value=1 1700000000123456789\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.path, r.URL.Path)
+				assert.Equal(t, tc.query, r.URL.RawQuery)
+				assert.Equal(t, "Token secret", r.Header.Get("Authorization"))
+				assert.Equal(t, tc.line, readGzipBody(t, r))
+				rw.WriteHeader(http.StatusNoContent)
+			}))
+			t.Cleanup(srv.Close)
+
+			conf := NewConfig().Apply(tc.conf)
+			conf.Addr = null.StringFrom(srv.URL)
+			conf.Token = null.StringFrom("secret")
+			c, err := MakeClient(conf)
+			require.NoError(t, err)
+			require.NoError(t, c.Write(testBatch(t)))
+		})
+	}
+}
+
+func TestAPIClientRetries(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		statuses []int
+		requests int32
+		err      string
+	}{
+		"retried until successful": {
+			statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent},
+			requests: 3,
+		},
+		"retries exhausted": {
+			statuses: []int{
+				http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+				http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+			},
+			requests: 3,
+			err:      "the InfluxDB v2 API responded with status 503: overloaded",
+		},
+		"not retried": {
+			statuses: []int{http.StatusBadRequest, http.StatusNoContent},
+			requests: 1,
+			err:      "the InfluxDB v2 API responded with status 400",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int32
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+				status := tc.statuses[atomic.AddInt32(&requests, 1)-1]
+				rw.WriteHeader(status)
+				if status != http.StatusNoContent {
+					_, _ = io.WriteString(rw, "overloaded")
+				}
+			}))
+			t.Cleanup(srv.Close)
+
+			c, err := MakeClient(NewConfig().Apply(Config{
+				Addr:          null.StringFrom(srv.URL),
+				APIVersion:    null.IntFrom(2),
+				Organization:  null.StringFrom("grafana"),
+				MaxRetries:    null.IntFrom(2),
+				RetryInterval: types.NullDurationFrom(time.Millisecond),
+			}))
+			require.NoError(t, err)
+
+			err = c.Write(testBatch(t))
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.requests, atomic.LoadInt32(&requests))
+		})
+	}
+}
+
+func TestAPIClientInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"?apiVersion=2":                           "the InfluxDB v2 API requires an organization",
+		"?apiVersion=4":                           "unsupported InfluxDB API version 4",
+		"?apiVersion=3&precision=h":               `the InfluxDB v3 API doesn't support the "h" precision`,
+		"udp://127.0.0.1:8089?apiVersion=3":       `the InfluxDB v3 API requires an http or https address`,
+		"?apiVersion=3&maxRetries=-1":             "influxdb's MaxRetries can't be negative",
+		"?apiVersion=2&organization=o&bucket=b&x": "unknown query parameter: x",
+	}
+
+	for url, expected := range tests {
+		t.Run(url, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(output.Params{
+				Logger:         testutils.NewLogger(t),
+				ConfigArgument: url,
+			})
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
+
+func TestOutputAPIv3(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	metric, err := registry.NewMetric("test_counter", metrics.Counter)
+	require.NoError(t, err)
+
+	var lines atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body := readGzipBody(t, r)
+		lines.Add(int32(strings.Count(body, "\n"))) //nolint:gosec
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	o, err := New(output.Params{
+		Logger:         testutils.NewLogger(t),
+		ConfigArgument: srv.URL + "/perf?apiVersion=3",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "InfluxDBv3 ("+srv.URL+")", o.Description())
+
+	require.NoError(t, o.Start())
+	o.AddMetricSamples([]metrics.SampleContainer{metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: metric, Tags: registry.RootTagSet()},
+		Time:       time.Now(),
+		Value:      1,
+	}})
+	require.NoError(t, o.Stop())
+
+	assert.Equal(t, int32(1), lines.Load())
+}
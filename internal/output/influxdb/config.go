@@ -11,6 +11,7 @@ import (
 	"github.com/mstoykov/envconfig"
 	"gopkg.in/guregu/null.v3"
 
+	"go.k6.io/k6/v2/internal/output/aggregation"
 	"go.k6.io/k6/v2/lib/types"
 )
 
@@ -26,12 +27,25 @@ type Config struct {
 	PushInterval     types.NullDuration `json:"pushInterval" envconfig:"K6_INFLUXDB_PUSH_INTERVAL"`
 	ConcurrentWrites null.Int           `json:"concurrentWrites" envconfig:"K6_INFLUXDB_CONCURRENT_WRITES"`
 
+	// InfluxDB v2 and v3 API.
+	APIVersion    null.Int           `json:"apiVersion" envconfig:"K6_INFLUXDB_API_VERSION"`
+	Organization  null.String        `json:"organization" envconfig:"K6_INFLUXDB_ORGANIZATION"`
+	Bucket        null.String        `json:"bucket" envconfig:"K6_INFLUXDB_BUCKET"`
+	Token         null.String        `json:"token" envconfig:"K6_INFLUXDB_TOKEN"`
+	MaxRetries    null.Int           `json:"maxRetries" envconfig:"K6_INFLUXDB_MAX_RETRIES"`
+	RetryInterval types.NullDuration `json:"retryInterval" envconfig:"K6_INFLUXDB_RETRY_INTERVAL"`
+
 	// Samples.
 	DB           null.String `json:"db" envconfig:"K6_INFLUXDB_DB"`
 	Precision    null.String `json:"precision" envconfig:"K6_INFLUXDB_PRECISION"`
 	Retention    null.String `json:"retention" envconfig:"K6_INFLUXDB_RETENTION"`
 	Consistency  null.String `json:"consistency" envconfig:"K6_INFLUXDB_CONSISTENCY"`
 	TagsAsFields []string    `json:"tagsAsFields,omitempty" envconfig:"K6_INFLUXDB_TAGS_AS_FIELDS"`
+
+	// Aggregation, disabled without a period.
+	AggregationPeriod      types.NullDuration `json:"aggregationPeriod" envconfig:"K6_INFLUXDB_AGGREGATION_PERIOD"`
+	AggregationWaitPeriod  types.NullDuration `json:"aggregationWaitPeriod" envconfig:"K6_INFLUXDB_AGGREGATION_WAIT_PERIOD"`
+	AggregationPercentiles []float64          `json:"aggregationPercentiles,omitempty" envconfig:"K6_INFLUXDB_AGGREGATION_PERCENTILES"` //nolint:lll
 }
 
 // NewConfig creates a new InfluxDB output config with some default values.
@@ -51,6 +65,12 @@ func NewConfig() Config {
 		// and the user should adjust the executed script
 		// or the configuration based on the environment and rate expected.
 		ConcurrentWrites: null.NewInt(4, false),
+
+		APIVersion:    null.NewInt(1, false),
+		MaxRetries:    null.NewInt(3, false),
+		RetryInterval: types.NewNullDuration(time.Second, false),
+
+		AggregationWaitPeriod: types.NewNullDuration(aggregation.DefaultWaitPeriod, false),
 	}
 	return c
 }
@@ -97,6 +117,33 @@ func (c Config) Apply(cfg Config) Config {
 	if cfg.ConcurrentWrites.Valid {
 		c.ConcurrentWrites = cfg.ConcurrentWrites
 	}
+	if cfg.APIVersion.Valid {
+		c.APIVersion = cfg.APIVersion
+	}
+	if cfg.Organization.Valid {
+		c.Organization = cfg.Organization
+	}
+	if cfg.Bucket.Valid {
+		c.Bucket = cfg.Bucket
+	}
+	if cfg.Token.Valid {
+		c.Token = cfg.Token
+	}
+	if cfg.MaxRetries.Valid {
+		c.MaxRetries = cfg.MaxRetries
+	}
+	if cfg.RetryInterval.Valid {
+		c.RetryInterval = cfg.RetryInterval
+	}
+	if cfg.AggregationPeriod.Valid {
+		c.AggregationPeriod = cfg.AggregationPeriod
+	}
+	if cfg.AggregationWaitPeriod.Valid {
+		c.AggregationWaitPeriod = cfg.AggregationWaitPeriod
+	}
+	if len(cfg.AggregationPercentiles) > 0 {
+		c.AggregationPercentiles = cfg.AggregationPercentiles
+	}
 	return c
 }
 
@@ -165,6 +212,49 @@ func ParseURL(text string) (Config, error) {
 			c.ConcurrentWrites = null.IntFrom(int64(writes))
 		case "tagsAsFields":
 			c.TagsAsFields = vs
+		case "apiVersion":
+			var version int
+			version, err = strconv.Atoi(vs[0])
+			if err != nil {
+				return c, err
+			}
+			c.APIVersion = null.IntFrom(int64(version))
+		case "organization":
+			c.Organization = null.StringFrom(vs[0])
+		case "bucket":
+			c.Bucket = null.StringFrom(vs[0])
+		case "maxRetries":
+			var retries int
+			retries, err = strconv.Atoi(vs[0])
+			if err != nil {
+				return c, err
+			}
+			c.MaxRetries = null.IntFrom(int64(retries))
+		case "retryInterval":
+			err = c.RetryInterval.UnmarshalText([]byte(vs[0]))
+			if err != nil {
+				return c, err
+			}
+		case "aggregationPeriod":
+			err = c.AggregationPeriod.UnmarshalText([]byte(vs[0]))
+			if err != nil {
+				return c, err
+			}
+		case "aggregationWaitPeriod":
+			err = c.AggregationWaitPeriod.UnmarshalText([]byte(vs[0]))
+			if err != nil {
+				return c, err
+			}
+		case "aggregationPercentiles":
+			c.AggregationPercentiles = make([]float64, 0, len(vs))
+			for _, v := range vs {
+				var p float64
+				p, err = strconv.ParseFloat(v, 64)
+				if err != nil {
+					return c, err
+				}
+				c.AggregationPercentiles = append(c.AggregationPercentiles, p)
+			}
 		default:
 			return c, fmt.Errorf("unknown query parameter: %s", k)
 		}
@@ -2,9 +2,12 @@ package influxdb
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/lib/types"
 )
 
 func TestParseURL(t *testing.T) {
@@ -26,6 +29,14 @@ func TestParseURL(t *testing.T) {
 		"?insecure=ture":   {Config{}, "insecure must be true or false, not ture"},
 		"?payload_size=69": {Config{PayloadSize: null.IntFrom(69)}, ""},
 		"?payload_size=a":  {Config{}, "strconv.Atoi: parsing \"a\": invalid syntax"},
+		"?aggregationPeriod=10s&aggregationWaitPeriod=1s&aggregationPercentiles=90&aggregationPercentiles=99.9": {
+			Config{
+				AggregationPeriod:      types.NullDurationFrom(10 * time.Second),
+				AggregationWaitPeriod:  types.NullDurationFrom(time.Second),
+				AggregationPercentiles: []float64{90, 99.9},
+			}, "",
+		},
+		"?aggregationPercentiles=p99": {Config{}, "strconv.ParseFloat: parsing \"p99\": invalid syntax"},
 	}
 	for str, data := range testdata {
 		t.Run(str, func(t *testing.T) {
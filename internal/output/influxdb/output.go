@@ -1,7 +1,7 @@
 // Package influxdb provides an output plugin for sending results
-// directly to InfluxDB v1.
-// for the InfluxDB v2 please see an extension
-// https://github.com/grafana/xk6-output-influxdb
+// directly to InfluxDB. The v1 API is used by default, and the v2 and v3
+// ones can be enabled with the apiVersion option, authenticating with
+// a token instead of a username and password.
 package influxdb
 
 import (
@@ -16,6 +16,7 @@ import (
 	client "github.com/influxdata/influxdb1-client/v2"
 	"github.com/sirupsen/logrus"
 
+	"go.k6.io/k6/v2/internal/output/aggregation"
 	"go.k6.io/k6/v2/metrics"
 	"go.k6.io/k6/v2/output"
 )
@@ -48,6 +49,9 @@ type Output struct {
 	periodicFlusher *output.PeriodicFlusher
 	semaphoreCh     chan struct{}
 	wg              sync.WaitGroup
+
+	// aggregator is nil if the aggregation is disabled
+	aggregator *aggregation.Aggregator
 }
 
 // New returns new influxdb output
@@ -68,18 +72,35 @@ func newOutput(params output.Params) (*Output, error) {
 	if conf.ConcurrentWrites.Int64 <= 0 {
 		return nil, errors.New("influxdb's ConcurrentWrites must be a positive number")
 	}
+	if conf.MaxRetries.Int64 < 0 {
+		return nil, errors.New("influxdb's MaxRetries can't be negative")
+	}
+	logger := params.Logger.WithFields(logrus.Fields{
+		"output": fmt.Sprintf("InfluxDBv%d", conf.APIVersion.Int64),
+	})
+	var aggregator *aggregation.Aggregator
+	if conf.AggregationPeriod.Valid {
+		aggregator, err = aggregation.New(
+			conf.AggregationPeriod.TimeDuration(),
+			conf.AggregationWaitPeriod.TimeDuration(),
+			conf.AggregationPercentiles,
+			logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
 	fldKinds, err := MakeFieldKinds(conf)
 	return &Output{
-		params: params,
-		logger: params.Logger.WithFields(logrus.Fields{
-			"output": "InfluxDBv1",
-		}),
+		params:      params,
+		logger:      logger,
 		Client:      cl,
 		Config:      conf,
 		BatchConf:   batchConf,
 		fieldKinds:  fldKinds,
 		semaphoreCh: make(chan struct{}, conf.ConcurrentWrites.Int64),
 		wg:          sync.WaitGroup{},
+		aggregator:  aggregator,
 	}, err
 }
 
@@ -151,9 +172,34 @@ func (o *Output) batchFromSamples(containers []metrics.SampleContainer) (client.
 	return batch, nil
 }
 
+// batchFromAggregates makes a point for each aggregate, with the aggregate's
+// values as fields, at the start of its bucket.
+func (o *Output) batchFromAggregates(aggregates []aggregation.Aggregate) (client.BatchPoints, error) {
+	batch, err := client.NewBatchPoints(o.BatchConf)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't make a batch: %w", err)
+	}
+
+	for _, agg := range aggregates {
+		tags := agg.Tags.Map()
+		values := o.extractTagsToValues(tags, make(map[string]any, len(tags)+len(agg.Values)))
+		for _, v := range agg.Values {
+			values[v.Name] = v.Value
+		}
+		var p *client.Point
+		p, err = client.NewPoint(agg.Metric.Name, tags, values, agg.Time)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't make point from aggregate: %w", err)
+		}
+		batch.AddPoint(p)
+	}
+
+	return batch, nil
+}
+
 // Description returns a human-readable description of the output.
 func (o *Output) Description() string {
-	return fmt.Sprintf("InfluxDBv1 (%s)", o.Config.Addr.String)
+	return fmt.Sprintf("InfluxDBv%d (%s)", o.Config.APIVersion.Int64, o.Config.Addr.String)
 }
 
 // Start tries to open the specified JSON file and starts the goroutine for
@@ -162,9 +208,12 @@ func (o *Output) Start() error {
 	o.logger.Debug("Starting...")
 	// Try to create the database if it doesn't exist. Failure to do so is USUALLY harmless; it
 	// usually means we're either a non-admin user to an existing DB or connecting over UDP.
-	_, err := o.Client.Query(client.NewQuery("CREATE DATABASE "+o.BatchConf.Database, "", ""))
-	if err != nil {
-		o.logger.WithError(err).Debug("Couldn't create database; most likely harmless")
+	// The buckets of the v2 and v3 APIs have to be created in advance.
+	if o.Config.APIVersion.Int64 == 1 {
+		_, err := o.Client.Query(client.NewQuery("CREATE DATABASE "+o.BatchConf.Database, "", ""))
+		if err != nil {
+			o.logger.WithError(err).Debug("Couldn't create database; most likely harmless")
+		}
 	}
 
 	pf, err := output.NewPeriodicFlusher(o.Config.PushInterval.TimeDuration(), o.flushMetrics)
@@ -182,17 +231,45 @@ func (o *Output) Stop() error {
 	o.logger.Debug("Stopping...")
 	defer o.logger.Debug("Stopped!")
 	o.periodicFlusher.Stop()
+	if o.aggregator != nil {
+		o.flushAggregates(o.aggregator.All())
+	}
 	o.wg.Wait()
 	return nil
 }
 
 func (o *Output) flushMetrics() {
 	samples := o.GetBufferedSamples()
+	if o.aggregator != nil {
+		o.aggregator.AddSamples(samples)
+		o.flushAggregates(o.aggregator.Expired())
+		return
+	}
 	if len(samples) < 1 {
 		return
 	}
 
 	o.logger.Debug("Committing...")
+	o.commit(func() (client.BatchPoints, error) {
+		o.logger.WithField("samples", len(samples)).Debug("Writing...")
+		return o.batchFromSamples(samples)
+	})
+}
+
+func (o *Output) flushAggregates(aggregates []aggregation.Aggregate) {
+	if len(aggregates) < 1 {
+		return
+	}
+
+	o.logger.Debug("Committing...")
+	o.commit(func() (client.BatchPoints, error) {
+		o.logger.WithField("aggregates", len(aggregates)).Debug("Writing...")
+		return o.batchFromAggregates(aggregates)
+	})
+}
+
+// commit writes the batch in a new goroutine, limited by the concurrent writes.
+func (o *Output) commit(makeBatch func() (client.BatchPoints, error)) {
 	o.wg.Add(1)
 	o.semaphoreCh <- struct{}{}
 	go func() {
@@ -201,11 +278,9 @@ func (o *Output) flushMetrics() {
 			o.wg.Done()
 		}()
 
-		o.logger.WithField("samples", len(samples)).Debug("Writing...")
-
-		batch, err := o.batchFromSamples(samples)
+		batch, err := makeBatch()
 		if err != nil {
-			o.logger.WithError(err).Error("Couldn't create batch from samples")
+			o.logger.WithError(err).Error("Couldn't create batch")
 			return
 		}
 
@@ -213,8 +288,8 @@ func (o *Output) flushMetrics() {
 		startTime := time.Now()
 		if err := o.Client.Write(batch); err != nil {
 			msg := "Couldn't write stats"
-			if strings.Contains(err.Error(), "unauthorized access") {
-				msg += ", InfluxDB v2.x isn't supported by this output, if you are using it you may consider to use the extension https://github.com/grafana/xk6-output-influxdb" //nolint:lll
+			if o.Config.APIVersion.Int64 == 1 && strings.Contains(err.Error(), "unauthorized access") {
+				msg += ", if you are using InfluxDB v2.x or v3.x you should set the apiVersion option, along with the token for authenticating" //nolint:lll
 			}
 			o.logger.WithError(err).Error(msg)
 			return
@@ -7,11 +7,13 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -216,3 +218,84 @@ func TestExtractTagsToValues(t *testing.T) {
 	require.Equal(t, 3.14, values["floatField"])
 	require.Equal(t, int64(12345), values["intField"])
 }
+
+func TestBatchFromAggregates(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	metric, err := registry.NewMetric("test_counter", metrics.Counter)
+	require.NoError(t, err)
+
+	o, err := newOutput(output.Params{
+		Logger:         testutils.NewLogger(t),
+		ConfigArgument: "?tagsAsFields=vu:int&aggregationPeriod=10s",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, o.aggregator)
+
+	start := time.Unix(1700000000, 0)
+	tags := registry.RootTagSet().WithTagsFromMap(map[string]string{"vu": "1", "name": "a"})
+	o.aggregator.AddSamples([]metrics.SampleContainer{metrics.Samples{
+		{TimeSeries: metrics.TimeSeries{Metric: metric, Tags: tags}, Time: start, Value: 2},
+		{TimeSeries: metrics.TimeSeries{Metric: metric, Tags: tags}, Time: start.Add(time.Second), Value: 3},
+	}})
+
+	batch, err := o.batchFromAggregates(o.aggregator.All())
+	require.NoError(t, err)
+	require.Len(t, batch.Points(), 1)
+
+	p := batch.Points()[0]
+	assert.Equal(t, "test_counter", p.Name())
+	assert.Equal(t, map[string]string{"name": "a"}, p.Tags())
+	assert.Equal(t, start, p.Time())
+	fields, err := p.Fields()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"vu": int64(1), "count": 2.0, "sum": 5.0}, fields)
+}
+
+func TestOutputLateAggregatedSamples(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	metric, err := registry.NewMetric("test_counter", metrics.Counter)
+	require.NoError(t, err)
+
+	var (
+		mu     sync.Mutex
+		points []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		points = append(points, strings.Split(strings.TrimSpace(string(body)), "\n")...)
+		mu.Unlock()
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	logger, hook := testutils.NewLoggerWithHook(t, logrus.WarnLevel)
+	o, err := newOutput(output.Params{
+		Logger:         logger,
+		ConfigArgument: srv.URL + "?aggregationPeriod=1s&aggregationWaitPeriod=0s",
+	})
+	require.NoError(t, err)
+
+	sample := metrics.Samples{{
+		TimeSeries: metrics.TimeSeries{Metric: metric, Tags: registry.RootTagSet()},
+		Time:       time.Now().Add(-10 * time.Second),
+		Value:      1,
+	}}
+	o.AddMetricSamples([]metrics.SampleContainer{sample})
+	o.flushMetrics()
+	o.wg.Wait()
+
+	// the sample arrives after its bucket was written, it would overwrite the point
+	o.AddMetricSamples([]metrics.SampleContainer{sample})
+	o.flushMetrics()
+	o.flushAggregates(o.aggregator.All())
+	o.wg.Wait()
+
+	require.Len(t, points, 1)
+	assert.Contains(t, points[0], "count=1")
+	assert.Contains(t, hook.Lines()[0], "Dropped 1 samples which arrived after their aggregation bucket was emitted")
+}
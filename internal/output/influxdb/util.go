@@ -10,8 +10,15 @@ import (
 	"gopkg.in/guregu/null.v3"
 )
 
-// MakeClient returns a new InfluxDB client based on the given Config.
+// MakeClient returns a new InfluxDB client based on the given Config. The
+// v1 client is used unless a different API version is configured.
 func MakeClient(conf Config) (client.Client, error) {
+	if conf.APIVersion.Valid && conf.APIVersion.Int64 != 1 {
+		if conf.Addr.String == "" {
+			conf.Addr = null.StringFrom("http://localhost:8086")
+		}
+		return newAPIClient(conf)
+	}
 	if after, ok := strings.CutPrefix(conf.Addr.String, "udp://"); ok {
 		return client.NewUDPClient(client.UDPConfig{
 			Addr:        after,
@@ -0,0 +1,135 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/guregu/null.v3"
+
+	"github.com/mstoykov/envconfig"
+
+	"go.k6.io/k6/v2/internal/output/aggregation"
+	"go.k6.io/k6/v2/lib/types"
+)
+
+// Config is the config for the json output
+type Config struct {
+	FileName null.String `json:"file_name" envconfig:"K6_JSON_FILENAME"`
+
+	// Aggregation, disabled without a period.
+	AggregationPeriod      types.NullDuration `json:"aggregation_period" envconfig:"K6_JSON_AGGREGATION_PERIOD"`
+	AggregationWaitPeriod  types.NullDuration `json:"aggregation_wait_period" envconfig:"K6_JSON_AGGREGATION_WAIT_PERIOD"`
+	AggregationPercentiles []float64          `json:"aggregation_percentiles" envconfig:"K6_JSON_AGGREGATION_PERCENTILES"` //nolint:lll
+}
+
+// NewConfig creates a new Config instance with default values for some fields.
+func NewConfig() Config {
+	return Config{
+		AggregationWaitPeriod: types.NewNullDuration(aggregation.DefaultWaitPeriod, false),
+	}
+}
+
+// Apply merges two configs by overwriting properties in the old config
+func (c Config) Apply(cfg Config) Config {
+	if cfg.FileName.Valid {
+		c.FileName = cfg.FileName
+	}
+	if cfg.AggregationPeriod.Valid {
+		c.AggregationPeriod = cfg.AggregationPeriod
+	}
+	if cfg.AggregationWaitPeriod.Valid {
+		c.AggregationWaitPeriod = cfg.AggregationWaitPeriod
+	}
+	if len(cfg.AggregationPercentiles) > 0 {
+		c.AggregationPercentiles = cfg.AggregationPercentiles
+	}
+	return c
+}
+
+// ParseArg takes an arg string and converts it to a config. The whole arg is
+// the file name, unless it's a list of key=value pairs with only known keys,
+// so the existing file names containing "=" still work.
+func ParseArg(arg string) (Config, error) {
+	c := NewConfig()
+
+	if !isKeyValueArg(arg) {
+		c.FileName = null.StringFrom(arg)
+		return c, nil
+	}
+
+	for pair := range strings.SplitSeq(arg, ",") {
+		k, v, _ := strings.Cut(pair, "=")
+		switch k {
+		case "fileName":
+			c.FileName = null.StringFrom(v)
+		case "aggregationPeriod":
+			if err := c.AggregationPeriod.UnmarshalText([]byte(v)); err != nil {
+				return c, err
+			}
+		case "aggregationWaitPeriod":
+			if err := c.AggregationWaitPeriod.UnmarshalText([]byte(v)); err != nil {
+				return c, err
+			}
+		case "aggregationPercentiles":
+			percentiles, err := aggregation.ParsePercentiles(v)
+			if err != nil {
+				return c, err
+			}
+			c.AggregationPercentiles = percentiles
+		default:
+			return c, fmt.Errorf("unknown key %q as argument for json output", k)
+		}
+	}
+
+	return c, nil
+}
+
+func isKeyValueArg(arg string) bool {
+	if !strings.Contains(arg, "=") {
+		return false
+	}
+	for pair := range strings.SplitSeq(arg, ",") {
+		k, _, _ := strings.Cut(pair, "=")
+		switch k {
+		case "fileName", "aggregationPeriod", "aggregationWaitPeriod", "aggregationPercentiles":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// GetConsolidatedConfig combines {default config values + JSON config +
+// environment vars + arg config values}, and returns the final result.
+func GetConsolidatedConfig(
+	jsonRawConf json.RawMessage, env map[string]string, arg string,
+) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+
+	envConfig := Config{}
+	if err := envconfig.Process("", &envConfig, func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}); err != nil {
+		return result, err
+	}
+	result = result.Apply(envConfig)
+
+	if arg != "" {
+		argConf, err := ParseArg(arg)
+		if err != nil {
+			return result, err
+		}
+		result = result.Apply(argConf)
+	}
+
+	return result, nil
+}
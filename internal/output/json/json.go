@@ -14,6 +14,7 @@ import (
 	"github.com/klauspost/compress/gzip"
 	"github.com/sirupsen/logrus"
 
+	"go.k6.io/k6/v2/internal/output/aggregation"
 	"go.k6.io/k6/v2/metrics"
 	"go.k6.io/k6/v2/output"
 )
@@ -34,18 +35,41 @@ type Output struct {
 	closeFn     func() error
 	seenMetrics map[string]struct{}
 	thresholds  map[string]metrics.Thresholds
+
+	// aggregator is nil if the aggregation is disabled
+	aggregator *aggregation.Aggregator
 }
 
 // New returns a new JSON output.
 func New(params output.Params) (output.Output, error) {
+	config, err := GetConsolidatedConfig(params.JSONConfig, params.Environment, params.ConfigArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := params.Logger.WithFields(logrus.Fields{
+		"output":   "json",
+		"filename": config.FileName.String,
+	})
+	var aggregator *aggregation.Aggregator
+	if config.AggregationPeriod.Valid {
+		aggregator, err = aggregation.New(
+			config.AggregationPeriod.TimeDuration(),
+			config.AggregationWaitPeriod.TimeDuration(),
+			config.AggregationPercentiles,
+			logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Output{
-		params:   params,
-		filename: params.ConfigArgument,
-		logger: params.Logger.WithFields(logrus.Fields{
-			"output":   "json",
-			"filename": params.ConfigArgument,
-		}),
+		params:      params,
+		filename:    config.FileName.String,
+		logger:      logger,
 		seenMetrics: make(map[string]struct{}),
+		aggregator:  aggregator,
 	}, nil
 }
 
@@ -108,6 +132,9 @@ func (o *Output) Stop() error {
 	o.logger.Debug("Stopping...")
 	defer o.logger.Debug("Stopped!")
 	o.periodicFlusher.Stop()
+	if o.aggregator != nil {
+		o.writeAggregates(o.aggregator.All())
+	}
 	return o.closeFn()
 }
 
@@ -122,6 +149,11 @@ func (o *Output) SetThresholds(thresholds map[string]metrics.Thresholds) {
 
 func (o *Output) flushMetrics() {
 	samples := o.GetBufferedSamples()
+	if o.aggregator != nil {
+		o.aggregator.AddSamples(samples)
+		o.writeAggregates(o.aggregator.Expired())
+		return
+	}
 	start := time.Now()
 	var count int
 	var firstErr error
@@ -155,6 +187,36 @@ func (o *Output) flushMetrics() {
 	}
 }
 
+// writeAggregates writes the aggregates, each one preceded by its metric
+// the first time it's seen, like the samples.
+func (o *Output) writeAggregates(aggregates []aggregation.Aggregate) {
+	if len(aggregates) == 0 {
+		return
+	}
+
+	var firstErr error
+	var errCount int
+	enc := json.NewEncoder(o.out)
+	enc.SetEscapeHTML(false)
+	for _, agg := range aggregates {
+		if err := o.handleMetric(agg.Metric, enc); err != nil {
+			errCount++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if err := enc.Encode(wrapAggregate(agg, o.aggregator.Period())); err != nil {
+			errCount++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if firstErr != nil {
+		o.logger.WithError(firstErr).WithField("failed", errCount).Error("Aggregate(s) couldn't be marshalled to JSON")
+	}
+}
+
 func (o *Output) handleMetric(m *metrics.Metric, enc *json.Encoder) error {
 	if _, ok := o.seenMetrics[m.Name]; ok {
 		return nil
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"compress/gzip"
+	"encoding/json"
 	"io"
 	"testing"
 	"time"
@@ -13,6 +14,7 @@ import (
 
 	"go.k6.io/k6/v2/internal/lib/testutils"
 	"go.k6.io/k6/v2/lib/fsext"
+	"go.k6.io/k6/v2/lib/types"
 	"go.k6.io/k6/v2/metrics"
 	"go.k6.io/k6/v2/output"
 )
@@ -225,3 +227,63 @@ func setThresholds(t *testing.T, out output.Output) {
 	ts := metrics.NewThresholds([]string{"rate<0.01", "p(99)<250"})
 	jout.SetThresholds(map[string]metrics.Thresholds{"my_metric1": ts})
 }
+
+func TestJsonOutputAggregation(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	trend, err := registry.NewMetric("my_trend", metrics.Trend, metrics.Time)
+	require.NoError(t, err)
+	tags := registry.RootTagSet().With("tag1", "val1")
+
+	start := time.Unix(1614173830, 0)
+	samples := metrics.Samples{
+		{TimeSeries: metrics.TimeSeries{Metric: trend, Tags: tags}, Time: start, Value: 10},
+		{TimeSeries: metrics.TimeSeries{Metric: trend, Tags: tags}, Time: start.Add(time.Second), Value: 30},
+	}
+
+	stdout := new(bytes.Buffer)
+	out, err := New(output.Params{
+		Logger:         testutils.NewLogger(t),
+		StdOut:         stdout,
+		Environment:    map[string]string{"K6_JSON_AGGREGATION_PERCENTILES": "90"},
+		ConfigArgument: "aggregationPeriod=10s",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, out.Start())
+	out.AddMetricSamples([]metrics.SampleContainer{samples})
+	require.NoError(t, out.Stop())
+
+	startJSON, err := json.Marshal(start)
+	require.NoError(t, err)
+	getValidator(t, []string{
+		`{"type":"Metric","data":{"name":"my_trend","type":"trend","contains":"time","thresholds":[],"submetrics":null},"metric":"my_trend"}`,
+		`{"type":"Aggregate","data":{"time":` + string(startJSON) + `,"period":"10s","tags":{"tag1":"val1"},` +
+			`"values":{"count":2,"sum":40,"min":10,"max":30,"avg":20,"p(90)":28}},"metric":"my_trend"}`,
+	})(stdout)
+}
+
+func TestParseArg(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseArg("results.json")
+	require.NoError(t, err)
+	assert.Equal(t, "results.json", config.FileName.String)
+	assert.False(t, config.AggregationPeriod.Valid)
+
+	// a file name containing "=" and unknown keys
+	config, err = ParseArg("run=1.json")
+	require.NoError(t, err)
+	assert.Equal(t, "run=1.json", config.FileName.String)
+
+	config, err = ParseArg("fileName=results.json,aggregationPeriod=5s,aggregationWaitPeriod=1s,aggregationPercentiles=50;99")
+	require.NoError(t, err)
+	assert.Equal(t, "results.json", config.FileName.String)
+	assert.Equal(t, types.NullDurationFrom(5*time.Second), config.AggregationPeriod)
+	assert.Equal(t, types.NullDurationFrom(time.Second), config.AggregationWaitPeriod)
+	assert.Equal(t, []float64{50, 99}, config.AggregationPercentiles)
+
+	_, err = ParseArg("aggregationPeriod=5x")
+	require.Error(t, err)
+}
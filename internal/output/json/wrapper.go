@@ -3,6 +3,8 @@ package json
 import (
 	"time"
 
+	"go.k6.io/k6/v2/internal/output/aggregation"
+	"go.k6.io/k6/v2/lib/types"
 	"go.k6.io/k6/v2/metrics"
 )
 
@@ -31,6 +33,34 @@ func wrapSample(sample metrics.Sample) sampleEnvelope {
 	return s
 }
 
+type aggregateEnvelope struct {
+	Metric string `json:"metric"`
+	Type   string `json:"type"`
+	Data   struct {
+		Time   time.Time          `json:"time"`
+		Period types.Duration     `json:"period"`
+		Tags   *metrics.TagSet    `json:"tags"`
+		Values map[string]float64 `json:"values"`
+	} `json:"data"`
+}
+
+// wrapAggregate is used to package an aggregate of the samples in a time bucket,
+// its time being the start of the bucket.
+func wrapAggregate(agg aggregation.Aggregate, period time.Duration) aggregateEnvelope {
+	a := aggregateEnvelope{
+		Type:   "Aggregate",
+		Metric: agg.Metric.Name,
+	}
+	a.Data.Time = agg.Time
+	a.Data.Period = types.Duration(period)
+	a.Data.Tags = agg.Tags
+	a.Data.Values = make(map[string]float64, len(agg.Values))
+	for _, v := range agg.Values {
+		a.Data.Values[v.Name] = v.Value
+	}
+	return a
+}
+
 type metricEnvelope struct {
 	Type string `json:"type"`
 	Data struct {
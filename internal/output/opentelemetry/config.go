@@ -39,6 +39,9 @@ type Config struct {
 	ExporterProtocol null.String `json:"exporterProtocol" envconfig:"K6_OTEL_EXPORTER_PROTOCOL"`
 	// ExportInterval configures the intervening time between metrics exports
 	ExportInterval types.NullDuration `json:"exportInterval" envconfig:"K6_OTEL_EXPORT_INTERVAL"`
+	// ExportLogs enables the export of k6's logs, including the console's ones
+	// unless they are written to a file with the --console-output option
+	ExportLogs null.Bool `json:"exportLogs" envconfig:"K6_OTEL_EXPORT_LOGS"`
 
 	// Headers in W3C Correlation-Context format without additional semi-colon delimited metadata (i.e. "k1=v1,k2=v2")
 	Headers null.String `json:"headers" envconfig:"K6_OTEL_HEADERS"`
@@ -61,6 +64,12 @@ type Config struct {
 	HTTPExporterEndpoint null.String `json:"httpExporterEndpoint" envconfig:"K6_OTEL_HTTP_EXPORTER_ENDPOINT"`
 	// HTTPExporterURLPath sets the target URL path the OpenTelemetry Exporter
 	HTTPExporterURLPath null.String `json:"httpExporterURLPath" envconfig:"K6_OTEL_HTTP_EXPORTER_URL_PATH"`
+	// HTTPExporterTracesURLPath sets the target URL path of the spans, exported
+	// when the traceRequests option is enabled
+	HTTPExporterTracesURLPath null.String `json:"httpExporterTracesURLPath" envconfig:"K6_OTEL_HTTP_EXPORTER_TRACES_URL_PATH"` //nolint:lll
+	// HTTPExporterLogsURLPath sets the target URL path of the logs, exported
+	// when the exportLogs option is enabled
+	HTTPExporterLogsURLPath null.String `json:"httpExporterLogsURLPath" envconfig:"K6_OTEL_HTTP_EXPORTER_LOGS_URL_PATH"`
 
 	// GRPCExporterEndpoint sets the target endpoint the OpenTelemetry Exporter
 	// will connect to.
@@ -113,10 +122,14 @@ func newDefaultConfig() Config {
 		HTTPExporterEndpoint: null.NewString("localhost:4318", false),
 		HTTPExporterURLPath:  null.NewString("/v1/metrics", false),
 
+		HTTPExporterTracesURLPath: null.NewString("/v1/traces", false),
+		HTTPExporterLogsURLPath:   null.NewString("/v1/logs", false),
+
 		GRPCExporterInsecure: null.NewBool(false, false),
 		GRPCExporterEndpoint: null.NewString("localhost:4317", false),
 
 		ExportInterval: types.NewNullDuration(10*time.Second, false),
+		ExportLogs:     null.NewBool(false, false),
 		FlushInterval:  types.NewNullDuration(1*time.Second, false),
 	}
 }
@@ -147,6 +160,10 @@ func (cfg Config) Apply(v Config) Config {
 		cfg.ExportInterval = v.ExportInterval
 	}
 
+	if v.ExportLogs.Valid {
+		cfg.ExportLogs = v.ExportLogs
+	}
+
 	if v.HTTPExporterInsecure.Valid {
 		cfg.HTTPExporterInsecure = v.HTTPExporterInsecure
 	}
@@ -159,6 +176,14 @@ func (cfg Config) Apply(v Config) Config {
 		cfg.HTTPExporterURLPath = v.HTTPExporterURLPath
 	}
 
+	if v.HTTPExporterTracesURLPath.Valid {
+		cfg.HTTPExporterTracesURLPath = v.HTTPExporterTracesURLPath
+	}
+
+	if v.HTTPExporterLogsURLPath.Valid {
+		cfg.HTTPExporterLogsURLPath = v.HTTPExporterLogsURLPath
+	}
+
 	if v.GRPCExporterEndpoint.Valid {
 		cfg.GRPCExporterEndpoint = v.GRPCExporterEndpoint
 	}
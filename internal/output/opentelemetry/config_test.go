@@ -23,69 +23,81 @@ func TestConfig(t *testing.T) {
 	}{
 		"default": {
 			expectedConfig: Config{
-				ServiceName:          null.NewString("k6", false),
-				ServiceVersion:       null.NewString(build.Version, false),
-				ExporterProtocol:     null.NewString(grpcExporterProtocol, false),
-				HTTPExporterInsecure: null.NewBool(false, false),
-				HTTPExporterEndpoint: null.NewString("localhost:4318", false),
-				HTTPExporterURLPath:  null.NewString("/v1/metrics", false),
-				GRPCExporterInsecure: null.NewBool(false, false),
-				GRPCExporterEndpoint: null.NewString("localhost:4317", false),
-				ExportInterval:       types.NewNullDuration(10*time.Second, false),
-				FlushInterval:        types.NewNullDuration(1*time.Second, false),
+				ServiceName:               null.NewString("k6", false),
+				ServiceVersion:            null.NewString(build.Version, false),
+				ExporterProtocol:          null.NewString(grpcExporterProtocol, false),
+				HTTPExporterInsecure:      null.NewBool(false, false),
+				HTTPExporterEndpoint:      null.NewString("localhost:4318", false),
+				HTTPExporterURLPath:       null.NewString("/v1/metrics", false),
+				HTTPExporterTracesURLPath: null.NewString("/v1/traces", false),
+				HTTPExporterLogsURLPath:   null.NewString("/v1/logs", false),
+				GRPCExporterInsecure:      null.NewBool(false, false),
+				GRPCExporterEndpoint:      null.NewString("localhost:4317", false),
+				ExportInterval:            types.NewNullDuration(10*time.Second, false),
+				ExportLogs:                null.NewBool(false, false),
+				FlushInterval:             types.NewNullDuration(1*time.Second, false),
 			},
 		},
 
 		"environment success merge": {
 			env: map[string]string{"K6_OTEL_GRPC_EXPORTER_ENDPOINT": "else", "K6_OTEL_EXPORT_INTERVAL": "4ms"},
 			expectedConfig: Config{
-				ServiceName:          null.NewString("k6", false),
-				ServiceVersion:       null.NewString(build.Version, false),
-				ExporterProtocol:     null.NewString(grpcExporterProtocol, false),
-				HTTPExporterInsecure: null.NewBool(false, false),
-				HTTPExporterEndpoint: null.NewString("localhost:4318", false),
-				HTTPExporterURLPath:  null.NewString("/v1/metrics", false),
-				GRPCExporterInsecure: null.NewBool(false, false),
-				GRPCExporterEndpoint: null.NewString("else", true),
-				ExportInterval:       types.NewNullDuration(4*time.Millisecond, true),
-				FlushInterval:        types.NewNullDuration(1*time.Second, false),
+				ServiceName:               null.NewString("k6", false),
+				ServiceVersion:            null.NewString(build.Version, false),
+				ExporterProtocol:          null.NewString(grpcExporterProtocol, false),
+				HTTPExporterInsecure:      null.NewBool(false, false),
+				HTTPExporterEndpoint:      null.NewString("localhost:4318", false),
+				HTTPExporterURLPath:       null.NewString("/v1/metrics", false),
+				HTTPExporterTracesURLPath: null.NewString("/v1/traces", false),
+				HTTPExporterLogsURLPath:   null.NewString("/v1/logs", false),
+				GRPCExporterInsecure:      null.NewBool(false, false),
+				GRPCExporterEndpoint:      null.NewString("else", true),
+				ExportInterval:            types.NewNullDuration(4*time.Millisecond, true),
+				ExportLogs:                null.NewBool(false, false),
+				FlushInterval:             types.NewNullDuration(1*time.Second, false),
 			},
 		},
 
 		"environment complete overwrite": {
 			env: map[string]string{
-				"K6_OTEL_SERVICE_NAME":             "foo",
-				"K6_OTEL_SERVICE_VERSION":          "v0.0.99",
-				"K6_OTEL_EXPORTER_PROTOCOL":        "http/protobuf",
-				"K6_OTEL_EXPORT_INTERVAL":          "4ms",
-				"K6_OTEL_HTTP_EXPORTER_INSECURE":   "true",
-				"K6_OTEL_HTTP_EXPORTER_ENDPOINT":   "localhost:5555",
-				"K6_OTEL_HTTP_EXPORTER_URL_PATH":   "/foo/bar",
-				"K6_OTEL_GRPC_EXPORTER_INSECURE":   "true",
-				"K6_OTEL_GRPC_EXPORTER_ENDPOINT":   "else",
-				"K6_OTEL_FLUSH_INTERVAL":           "13s",
-				"K6_OTEL_TLS_INSECURE_SKIP_VERIFY": "true",
-				"K6_OTEL_TLS_CERTIFICATE":          "cert_path",
-				"K6_OTEL_TLS_CLIENT_CERTIFICATE":   "client_cert_path",
-				"K6_OTEL_TLS_CLIENT_KEY":           "client_key_path",
-				"K6_OTEL_HEADERS":                  "key1=value1,key2=value2",
+				"K6_OTEL_SERVICE_NAME":                  "foo",
+				"K6_OTEL_SERVICE_VERSION":               "v0.0.99",
+				"K6_OTEL_EXPORTER_PROTOCOL":             "http/protobuf",
+				"K6_OTEL_EXPORT_INTERVAL":               "4ms",
+				"K6_OTEL_HTTP_EXPORTER_INSECURE":        "true",
+				"K6_OTEL_HTTP_EXPORTER_ENDPOINT":        "localhost:5555",
+				"K6_OTEL_HTTP_EXPORTER_URL_PATH":        "/foo/bar",
+				"K6_OTEL_HTTP_EXPORTER_TRACES_URL_PATH": "/foo/traces",
+				"K6_OTEL_HTTP_EXPORTER_LOGS_URL_PATH":   "/foo/logs",
+				"K6_OTEL_EXPORT_LOGS":                   "true",
+				"K6_OTEL_GRPC_EXPORTER_INSECURE":        "true",
+				"K6_OTEL_GRPC_EXPORTER_ENDPOINT":        "else",
+				"K6_OTEL_FLUSH_INTERVAL":                "13s",
+				"K6_OTEL_TLS_INSECURE_SKIP_VERIFY":      "true",
+				"K6_OTEL_TLS_CERTIFICATE":               "cert_path",
+				"K6_OTEL_TLS_CLIENT_CERTIFICATE":        "client_cert_path",
+				"K6_OTEL_TLS_CLIENT_KEY":                "client_key_path",
+				"K6_OTEL_HEADERS":                       "key1=value1,key2=value2",
 			},
 			expectedConfig: Config{
-				ServiceName:           null.NewString("foo", true),
-				ServiceVersion:        null.NewString("v0.0.99", true),
-				ExporterProtocol:      null.NewString(httpExporterProtocol, true),
-				ExportInterval:        types.NewNullDuration(4*time.Millisecond, true),
-				HTTPExporterInsecure:  null.NewBool(true, true),
-				HTTPExporterEndpoint:  null.NewString("localhost:5555", true),
-				HTTPExporterURLPath:   null.NewString("/foo/bar", true),
-				GRPCExporterInsecure:  null.NewBool(true, true),
-				GRPCExporterEndpoint:  null.NewString("else", true),
-				FlushInterval:         types.NewNullDuration(13*time.Second, true),
-				TLSInsecureSkipVerify: null.NewBool(true, true),
-				TLSCertificate:        null.NewString("cert_path", true),
-				TLSClientCertificate:  null.NewString("client_cert_path", true),
-				TLSClientKey:          null.NewString("client_key_path", true),
-				Headers:               null.NewString("key1=value1,key2=value2", true),
+				ServiceName:               null.NewString("foo", true),
+				ServiceVersion:            null.NewString("v0.0.99", true),
+				ExporterProtocol:          null.NewString(httpExporterProtocol, true),
+				ExportInterval:            types.NewNullDuration(4*time.Millisecond, true),
+				ExportLogs:                null.NewBool(true, true),
+				HTTPExporterInsecure:      null.NewBool(true, true),
+				HTTPExporterEndpoint:      null.NewString("localhost:5555", true),
+				HTTPExporterURLPath:       null.NewString("/foo/bar", true),
+				HTTPExporterTracesURLPath: null.NewString("/foo/traces", true),
+				HTTPExporterLogsURLPath:   null.NewString("/foo/logs", true),
+				GRPCExporterInsecure:      null.NewBool(true, true),
+				GRPCExporterEndpoint:      null.NewString("else", true),
+				FlushInterval:             types.NewNullDuration(13*time.Second, true),
+				TLSInsecureSkipVerify:     null.NewBool(true, true),
+				TLSCertificate:            null.NewString("cert_path", true),
+				TLSClientCertificate:      null.NewString("client_cert_path", true),
+				TLSClientKey:              null.NewString("client_key_path", true),
+				Headers:                   null.NewString("key1=value1,key2=value2", true),
 			},
 		},
 
@@ -94,16 +106,19 @@ func TestConfig(t *testing.T) {
 				"OTEL_SERVICE_NAME": "otel-service",
 			},
 			expectedConfig: Config{
-				ServiceName:          null.NewString("otel-service", true),
-				ServiceVersion:       null.NewString(build.Version, false),
-				ExporterProtocol:     null.NewString(grpcExporterProtocol, false),
-				HTTPExporterInsecure: null.NewBool(false, false),
-				HTTPExporterEndpoint: null.NewString("localhost:4318", false),
-				HTTPExporterURLPath:  null.NewString("/v1/metrics", false),
-				GRPCExporterInsecure: null.NewBool(false, false),
-				GRPCExporterEndpoint: null.NewString("localhost:4317", false),
-				ExportInterval:       types.NewNullDuration(10*time.Second, false),
-				FlushInterval:        types.NewNullDuration(1*time.Second, false),
+				ServiceName:               null.NewString("otel-service", true),
+				ServiceVersion:            null.NewString(build.Version, false),
+				ExporterProtocol:          null.NewString(grpcExporterProtocol, false),
+				HTTPExporterInsecure:      null.NewBool(false, false),
+				HTTPExporterEndpoint:      null.NewString("localhost:4318", false),
+				HTTPExporterURLPath:       null.NewString("/v1/metrics", false),
+				HTTPExporterTracesURLPath: null.NewString("/v1/traces", false),
+				HTTPExporterLogsURLPath:   null.NewString("/v1/logs", false),
+				GRPCExporterInsecure:      null.NewBool(false, false),
+				GRPCExporterEndpoint:      null.NewString("localhost:4317", false),
+				ExportInterval:            types.NewNullDuration(10*time.Second, false),
+				ExportLogs:                null.NewBool(false, false),
+				FlushInterval:             types.NewNullDuration(1*time.Second, false),
 			},
 		},
 
@@ -117,6 +132,9 @@ func TestConfig(t *testing.T) {
 					`"httpExporterInsecure":true,` +
 					`"httpExporterEndpoint":"localhost:5555",` +
 					`"httpExporterURLPath":"/foo/bar",` +
+					`"httpExporterTracesURLPath":"/foo/traces",` +
+					`"httpExporterLogsURLPath":"/foo/logs",` +
+					`"exportLogs":true,` +
 					`"grpcExporterInsecure":true,` +
 					`"grpcExporterEndpoint":"else",` +
 					`"flushInterval":"13s",` +
@@ -128,37 +146,43 @@ func TestConfig(t *testing.T) {
 					`}`,
 			),
 			expectedConfig: Config{
-				ServiceName:           null.NewString("bar", true),
-				ServiceVersion:        null.NewString("v2.0.99", true),
-				ExporterProtocol:      null.NewString(httpExporterProtocol, true),
-				ExportInterval:        types.NewNullDuration(15*time.Millisecond, true),
-				HTTPExporterInsecure:  null.NewBool(true, true),
-				HTTPExporterEndpoint:  null.NewString("localhost:5555", true),
-				HTTPExporterURLPath:   null.NewString("/foo/bar", true),
-				GRPCExporterInsecure:  null.NewBool(true, true),
-				GRPCExporterEndpoint:  null.NewString("else", true),
-				FlushInterval:         types.NewNullDuration(13*time.Second, true),
-				TLSInsecureSkipVerify: null.NewBool(true, true),
-				TLSCertificate:        null.NewString("cert_path", true),
-				TLSClientCertificate:  null.NewString("client_cert_path", true),
-				TLSClientKey:          null.NewString("client_key_path", true),
-				Headers:               null.NewString("key1=value1,key2=value2", true),
+				ServiceName:               null.NewString("bar", true),
+				ServiceVersion:            null.NewString("v2.0.99", true),
+				ExporterProtocol:          null.NewString(httpExporterProtocol, true),
+				ExportInterval:            types.NewNullDuration(15*time.Millisecond, true),
+				ExportLogs:                null.NewBool(true, true),
+				HTTPExporterInsecure:      null.NewBool(true, true),
+				HTTPExporterEndpoint:      null.NewString("localhost:5555", true),
+				HTTPExporterURLPath:       null.NewString("/foo/bar", true),
+				HTTPExporterTracesURLPath: null.NewString("/foo/traces", true),
+				HTTPExporterLogsURLPath:   null.NewString("/foo/logs", true),
+				GRPCExporterInsecure:      null.NewBool(true, true),
+				GRPCExporterEndpoint:      null.NewString("else", true),
+				FlushInterval:             types.NewNullDuration(13*time.Second, true),
+				TLSInsecureSkipVerify:     null.NewBool(true, true),
+				TLSCertificate:            null.NewString("cert_path", true),
+				TLSClientCertificate:      null.NewString("client_cert_path", true),
+				TLSClientKey:              null.NewString("client_key_path", true),
+				Headers:                   null.NewString("key1=value1,key2=value2", true),
 			},
 		},
 
 		"JSON success merge": {
 			jsonRaw: json.RawMessage(`{"exporterProtocol":"http/protobuf","httpExporterEndpoint":"localhost:5566","httpExporterURLPath":"/lorem/ipsum","exportInterval":"15ms"}`),
 			expectedConfig: Config{
-				ServiceName:          null.NewString("k6", false),
-				ServiceVersion:       null.NewString(build.Version, false),
-				ExporterProtocol:     null.NewString(httpExporterProtocol, true),
-				HTTPExporterInsecure: null.NewBool(false, false),
-				HTTPExporterEndpoint: null.NewString("localhost:5566", true),
-				HTTPExporterURLPath:  null.NewString("/lorem/ipsum", true),
-				GRPCExporterInsecure: null.NewBool(false, false),              // default
-				GRPCExporterEndpoint: null.NewString("localhost:4317", false), // default
-				ExportInterval:       types.NewNullDuration(15*time.Millisecond, true),
-				FlushInterval:        types.NewNullDuration(1*time.Second, false),
+				ServiceName:               null.NewString("k6", false),
+				ServiceVersion:            null.NewString(build.Version, false),
+				ExporterProtocol:          null.NewString(httpExporterProtocol, true),
+				HTTPExporterInsecure:      null.NewBool(false, false),
+				HTTPExporterEndpoint:      null.NewString("localhost:5566", true),
+				HTTPExporterURLPath:       null.NewString("/lorem/ipsum", true),
+				HTTPExporterTracesURLPath: null.NewString("/v1/traces", false),
+				HTTPExporterLogsURLPath:   null.NewString("/v1/logs", false),
+				GRPCExporterInsecure:      null.NewBool(false, false),              // default
+				GRPCExporterEndpoint:      null.NewString("localhost:4317", false), // default
+				ExportInterval:            types.NewNullDuration(15*time.Millisecond, true),
+				ExportLogs:                null.NewBool(false, false),
+				FlushInterval:             types.NewNullDuration(1*time.Second, false),
 			},
 		},
 		"no scheme in http exporter protocol": {
@@ -8,9 +8,15 @@ import (
 	"net/url"
 	"strings"
 
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"google.golang.org/grpc/credentials"
 )
 
@@ -20,6 +26,113 @@ func getExporter(cfg Config) (metric.Exporter, error) {
 	// later on, it could be used for the connection timeout
 	ctx := context.Background()
 
+	tlsConfig, headers, err := getExporterSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.ExporterProtocol.String {
+	case grpcExporterProtocol:
+		return buildGRPCExporter(ctx, cfg, tlsConfig, headers)
+	case httpExporterProtocol:
+		return buildHTTPExporter(ctx, cfg, tlsConfig, headers)
+	default:
+		return nil, errors.New("unsupported exporter protocol " + cfg.ExporterProtocol.String)
+	}
+}
+
+// getTracesExporter returns an exporter of the spans, with the same settings
+// as the metrics' one, except the URL path of the HTTP exporter.
+func getTracesExporter(cfg Config) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+
+	tlsConfig, headers, err := getExporterSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.ExporterProtocol.String {
+	case grpcExporterProtocol:
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(cfg.GRPCExporterEndpoint.String),
+		}
+		if cfg.GRPCExporterInsecure.Bool {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(headers))
+		}
+		if tlsConfig != nil {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case httpExporterProtocol:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cfg.HTTPExporterEndpoint.String),
+			otlptracehttp.WithURLPath(cfg.HTTPExporterTracesURLPath.String),
+		}
+		if cfg.HTTPExporterInsecure.Bool {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(headers))
+		}
+		if tlsConfig != nil {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, errors.New("unsupported exporter protocol " + cfg.ExporterProtocol.String)
+	}
+}
+
+// getLogsExporter returns an exporter of the logs, with the same settings as
+// the metrics' one, except the URL path of the HTTP exporter.
+func getLogsExporter(cfg Config) (sdklog.Exporter, error) {
+	ctx := context.Background()
+
+	tlsConfig, headers, err := getExporterSettings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.ExporterProtocol.String {
+	case grpcExporterProtocol:
+		opts := []otlploggrpc.Option{
+			otlploggrpc.WithEndpoint(cfg.GRPCExporterEndpoint.String),
+		}
+		if cfg.GRPCExporterInsecure.Bool {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if len(headers) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(headers))
+		}
+		if tlsConfig != nil {
+			opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		}
+		return otlploggrpc.New(ctx, opts...)
+	case httpExporterProtocol:
+		opts := []otlploghttp.Option{
+			otlploghttp.WithEndpoint(cfg.HTTPExporterEndpoint.String),
+			otlploghttp.WithURLPath(cfg.HTTPExporterLogsURLPath.String),
+		}
+		if cfg.HTTPExporterInsecure.Bool {
+			opts = append(opts, otlploghttp.WithInsecure())
+		}
+		if len(headers) > 0 {
+			opts = append(opts, otlploghttp.WithHeaders(headers))
+		}
+		if tlsConfig != nil {
+			opts = append(opts, otlploghttp.WithTLSClientConfig(tlsConfig))
+		}
+		return otlploghttp.New(ctx, opts...)
+	default:
+		return nil, errors.New("unsupported exporter protocol " + cfg.ExporterProtocol.String)
+	}
+}
+
+// getExporterSettings returns the TLS config and the headers of the exporters.
+func getExporterSettings(cfg Config) (*tls.Config, map[string]string, error) {
 	tlsConfig, err := buildTLSConfig(
 		cfg.TLSInsecureSkipVerify,
 		cfg.TLSCertificate,
@@ -27,25 +140,18 @@ func getExporter(cfg Config) (metric.Exporter, error) {
 		cfg.TLSClientKey,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var headers map[string]string
 	if cfg.Headers.Valid {
 		headers, err = parseHeaders(cfg.Headers.String)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse headers: %w", err)
+			return nil, nil, fmt.Errorf("failed to parse headers: %w", err)
 		}
 	}
 
-	switch cfg.ExporterProtocol.String {
-	case grpcExporterProtocol:
-		return buildGRPCExporter(ctx, cfg, tlsConfig, headers)
-	case httpExporterProtocol:
-		return buildHTTPExporter(ctx, cfg, tlsConfig, headers)
-	default:
-		return nil, errors.New("unsupported exporter protocol " + cfg.ExporterProtocol.String)
-	}
+	return tlsConfig, headers, nil
 }
 
 func buildHTTPExporter(
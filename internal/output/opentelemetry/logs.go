@@ -0,0 +1,113 @@
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+
+	k6trace "go.k6.io/k6/v2/internal/lib/trace"
+	k6log "go.k6.io/k6/v2/internal/log"
+)
+
+// logsHook is a logrus hook emitting the log entries as OpenTelemetry log
+// records. Besides the fields, the records have the attributes set in the
+// entries' context, e.g. the VU, the iteration, the scenario and the trace of
+// the console's entries.
+type logsHook struct {
+	logger  otellog.Logger
+	stopped atomic.Bool
+}
+
+// Levels returns all the levels, the logger already filters the entries.
+func (h *logsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire emits the entry, unless the hook is stopped.
+func (h *logsHook) Fire(entry *logrus.Entry) error {
+	if h.stopped.Load() {
+		return nil
+	}
+
+	var record otellog.Record
+	record.SetTimestamp(entry.Time)
+	record.SetSeverity(severity(entry.Level))
+	record.SetSeverityText(entry.Level.String())
+	record.SetBody(otellog.StringValue(entry.Message))
+	for _, key := range slices.Sorted(maps.Keys(entry.Data)) {
+		record.AddAttributes(otellog.String(key, fmt.Sprint(entry.Data[key])))
+	}
+
+	ctx := context.Background()
+	if attrs := k6log.Attributes(entry.Context); attrs != nil {
+		for _, key := range slices.Sorted(maps.Keys(attrs)) {
+			record.AddAttributes(otellog.String(key, attrs[key]))
+		}
+		ctx = contextWithSpan(ctx, attrs)
+	}
+
+	h.logger.Emit(ctx, record)
+	return nil
+}
+
+// stop makes the hook drop the entries, as logrus can't remove a hook.
+func (h *logsHook) stop() {
+	h.stopped.Store(true)
+}
+
+// contextWithSpan returns a copy of the context with the span of the
+// attributes, if they have a valid one, so the record is correlated with it.
+func contextWithSpan(ctx context.Context, attrs map[string]string) context.Context {
+	traceID, err := trace.TraceIDFromHex(attrs[k6trace.MetadataTraceID])
+	if err != nil {
+		return ctx
+	}
+	spanID, err := trace.SpanIDFromHex(attrs[k6trace.MetadataSpanID])
+	if err != nil {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+}
+
+func severity(level logrus.Level) otellog.Severity {
+	switch level {
+	case logrus.PanicLevel:
+		return otellog.SeverityFatal2
+	case logrus.FatalLevel:
+		return otellog.SeverityFatal
+	case logrus.ErrorLevel:
+		return otellog.SeverityError
+	case logrus.WarnLevel:
+		return otellog.SeverityWarn
+	case logrus.InfoLevel:
+		return otellog.SeverityInfo
+	case logrus.DebugLevel:
+		return otellog.SeverityDebug
+	case logrus.TraceLevel:
+		return otellog.SeverityTrace
+	default:
+		return otellog.SeverityUndefined
+	}
+}
+
+// rootLogger returns the logger to add the hook to.
+func rootLogger(logger logrus.FieldLogger) (*logrus.Logger, error) {
+	switch l := logger.(type) {
+	case *logrus.Logger:
+		return l, nil
+	case *logrus.Entry:
+		return l.Logger, nil
+	default:
+		return nil, fmt.Errorf("can't add a hook to the logger of type %T", logger)
+	}
+}
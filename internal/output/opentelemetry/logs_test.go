@@ -0,0 +1,89 @@
+package opentelemetry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	k6log "go.k6.io/k6/v2/internal/log"
+)
+
+type recordingExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func TestLogsHook(t *testing.T) {
+	t.Parallel()
+
+	exp := &recordingExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	hook := &logsHook{logger: provider.Logger("k6")}
+
+	logger := logrus.New()
+	logger.AddHook(hook)
+
+	attrs := map[string]string{
+		"vu":       "1",
+		"iter":     "2",
+		"scenario": "default",
+		"trace_id": "0102030405060708090a0b0c0d0e0f10",
+		"span_id":  "0102030405060708",
+	}
+	logger.WithField("source", "console").
+		WithContext(k6log.WithAttributes(context.Background(), func() map[string]string { return attrs })).
+		Warn("hello")
+	logger.Error("without attributes")
+
+	hook.stop()
+	logger.Info("dropped")
+
+	require.Len(t, exp.records, 2)
+
+	record := exp.records[0]
+	assert.Equal(t, "hello", record.Body().AsString())
+	assert.Equal(t, otellog.SeverityWarn, record.Severity())
+	assert.Equal(t, "warning", record.SeverityText())
+	assert.WithinDuration(t, time.Now(), record.Timestamp(), time.Minute)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", record.TraceID().String())
+	assert.Equal(t, "0102030405060708", record.SpanID().String())
+
+	got := make(map[string]string)
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		got[kv.Key] = kv.Value.AsString()
+		return true
+	})
+	assert.Equal(t, map[string]string{
+		"source":   "console",
+		"vu":       "1",
+		"iter":     "2",
+		"scenario": "default",
+		"trace_id": "0102030405060708090a0b0c0d0e0f10",
+		"span_id":  "0102030405060708",
+	}, got)
+
+	record = exp.records[1]
+	assert.Equal(t, "without attributes", record.Body().AsString())
+	assert.Equal(t, otellog.SeverityError, record.Severity())
+	assert.Zero(t, record.AttributesLen())
+	assert.False(t, record.TraceID().IsValid())
+}
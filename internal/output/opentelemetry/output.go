@@ -1,4 +1,9 @@
-// Package opentelemetry performs output operations for the opentelemetry extension
+// Package opentelemetry performs output operations for the opentelemetry extension.
+// With the traceRequests option, it also exports the spans of the iterations,
+// groups and HTTP and gRPC requests, the phases of the HTTP requests as events.
+// With the exportLogs option, it exports k6's logs as well, the console's ones
+// with the VU, the iteration, the scenario and the trace as attributes. With the
+// --console-output option, the console's logs are only written to its file.
 package opentelemetry
 
 import (
@@ -9,8 +14,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel/attribute"
 	otelMetric "go.opentelemetry.io/otel/metric"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 
 	"go.k6.io/k6/v2/metrics"
@@ -27,6 +34,17 @@ type Output struct {
 
 	meterProvider   *metric.MeterProvider
 	metricsRegistry *registry
+
+	// the spans are exported only with the traceRequests option
+	traceRequests  bool
+	tracesExporter sdktrace.SpanExporter
+	spanBuilder    *spanBuilder
+
+	// the console's logs aren't exported when they are written to a file
+	consoleOutput string
+
+	loggerProvider *sdklog.LoggerProvider
+	logsHook       *logsHook
 }
 
 var _ output.WithStopWithTestError = new(Output)
@@ -39,8 +57,10 @@ func New(p output.Params) (*Output, error) {
 	}
 
 	return &Output{
-		config: conf,
-		logger: p.Logger,
+		config:        conf,
+		logger:        p.Logger,
+		traceRequests: p.ScriptOptions.TraceRequests.Bool,
+		consoleOutput: p.ScriptOptions.ConsoleOutput.String,
 	}, nil
 }
 
@@ -60,6 +80,19 @@ func (o *Output) StopWithTestError(_ error) error {
 		o.logger.WithError(err).Error("can't shutdown OpenTelemetry metric provider")
 	}
 
+	if o.tracesExporter != nil {
+		if err := o.tracesExporter.Shutdown(context.Background()); err != nil {
+			o.logger.WithError(err).Error("can't shutdown OpenTelemetry traces exporter")
+		}
+	}
+
+	if o.loggerProvider != nil {
+		o.logsHook.stop()
+		if err := o.loggerProvider.Shutdown(context.Background()); err != nil {
+			o.logger.WithError(err).Error("can't shutdown OpenTelemetry logger provider")
+		}
+	}
+
 	return nil
 }
 
@@ -96,6 +129,20 @@ func (o *Output) Start() error {
 		),
 	)
 
+	if o.traceRequests {
+		o.tracesExporter, err = getTracesExporter(o.config)
+		if err != nil {
+			return fmt.Errorf("failed to create OpenTelemetry traces exporter: %w", err)
+		}
+		o.spanBuilder = newSpanBuilder(res)
+	}
+
+	if o.config.ExportLogs.Bool {
+		if err := o.startLogsExport(res); err != nil {
+			return err
+		}
+	}
+
 	pf, err := output.NewPeriodicFlusher(o.config.FlushInterval.TimeDuration(), o.flushMetrics)
 	if err != nil {
 		return err
@@ -109,11 +156,40 @@ func (o *Output) Start() error {
 	return nil
 }
 
+// startLogsExport adds a hook exporting the entries of k6's logger.
+func (o *Output) startLogsExport(res *resource.Resource) error {
+	logger, err := rootLogger(o.logger)
+	if err != nil {
+		return err
+	}
+	exp, err := getLogsExporter(o.config)
+	if err != nil {
+		return fmt.Errorf("failed to create OpenTelemetry logs exporter: %w", err)
+	}
+
+	o.loggerProvider = sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exp)),
+	)
+	o.logsHook = &logsHook{logger: o.loggerProvider.Logger("k6")}
+	logger.AddHook(o.logsHook)
+
+	if o.consoleOutput != "" {
+		o.logger.Warnf("The console's logs are written to %q and aren't exported, "+
+			"only k6's logs are", o.consoleOutput)
+	}
+	return nil
+}
+
 func (o *Output) flushMetrics() {
 	samples := o.GetBufferedSamples()
 	start := time.Now()
 	var count, errCount int
+	var spans []sdktrace.ReadOnlySpan
 	for _, sc := range samples {
+		if o.spanBuilder != nil {
+			spans = append(spans, o.spanBuilder.spans(sc)...)
+		}
 		samples := sc.GetSamples()
 
 		for _, sample := range samples {
@@ -140,6 +216,12 @@ func (o *Output) flushMetrics() {
 			WithField("count", errCount).
 			Warn("can't flush some metrics")
 	}
+
+	if len(spans) > 0 {
+		if err := o.tracesExporter.ExportSpans(context.Background(), spans); err != nil {
+			o.logger.WithError(err).WithField("count", len(spans)).Warn("can't export some spans")
+		}
+	}
 }
 
 func (o *Output) dispatch(entry metrics.Sample) error {
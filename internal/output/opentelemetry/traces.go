@@ -0,0 +1,221 @@
+package opentelemetry
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	k6trace "go.k6.io/k6/v2/internal/lib/trace"
+	"go.k6.io/k6/v2/metrics"
+)
+
+// spanBuilder makes the spans of the iterations, groups and requests from the
+// samples carrying the trace context set by the traceRequests option.
+//
+// The spans are recorded by a tracer of the SDK, with the identifiers of the
+// samples and the timestamps of their durations. It isn't safe for concurrent
+// use, it's expected to be called by the output's flusher.
+type spanBuilder struct {
+	tracer    trace.Tracer
+	collector *spanCollector
+}
+
+func newSpanBuilder(res *resource.Resource) *spanBuilder {
+	collector := &spanCollector{}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithIDGenerator(sampleIDGenerator{}),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSpanProcessor(collector),
+	)
+	return &spanBuilder{tracer: provider.Tracer("k6"), collector: collector}
+}
+
+// spans returns the spans of the samples in the container. The samples of an
+// HTTP request are in the same container, so its phases are added as events.
+func (b *spanBuilder) spans(container metrics.SampleContainer) []sdktrace.ReadOnlySpan {
+	samples := container.GetSamples()
+	for _, sample := range samples {
+		var (
+			name    string
+			kind    = trace.SpanKindInternal
+			isError func(status int) bool
+		)
+		switch sample.Metric.Name {
+		case metrics.IterationDurationName:
+			name = "iteration"
+		case metrics.GroupDurationName:
+			group, _ := sample.Tags.Get(metrics.TagGroup.String())
+			name = "group " + group
+		case metrics.HTTPReqDurationName:
+			method, _ := sample.Tags.Get(metrics.TagMethod.String())
+			name, kind = "HTTP "+method, trace.SpanKindClient
+			isError = func(status int) bool { return status == 0 || status >= 400 }
+		case metrics.GRPCReqDurationName:
+			method, _ := sample.Tags.Get(metrics.TagName.String())
+			name, kind = "gRPC "+method, trace.SpanKindClient
+			isError = func(status int) bool { return status != 0 }
+		default:
+			continue
+		}
+		b.record(sample, samples, name, kind, isError)
+	}
+	return b.collector.take()
+}
+
+// record records the span of a duration sample, ending at the sample's time.
+// Nothing is recorded if the sample doesn't carry a trace context.
+func (b *spanBuilder) record(
+	sample metrics.Sample, samples []metrics.Sample, name string, kind trace.SpanKind, isError func(int) bool,
+) {
+	traceID, err := trace.TraceIDFromHex(sample.Metadata[k6trace.MetadataTraceID])
+	if err != nil {
+		return
+	}
+	spanID, err := trace.SpanIDFromHex(sample.Metadata[k6trace.MetadataSpanID])
+	if err != nil {
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), spanIDsKey{}, spanIDs{traceID: traceID, spanID: spanID})
+	if parentID, err := trace.SpanIDFromHex(sample.Metadata[k6trace.MetadataParentSpanID]); err == nil {
+		ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     parentID,
+			TraceFlags: trace.FlagsSampled,
+		}))
+	}
+
+	start := sample.Time.Add(-time.Duration(sample.Value * float64(time.Millisecond)))
+	var events []event
+	if sample.Metric.Name == metrics.HTTPReqDurationName {
+		start, events = httpReqPhases(start, sample, samples)
+	}
+
+	attributes := newAttributeSet(sample.Tags)
+	_, span := b.tracer.Start(ctx, name,
+		trace.WithSpanKind(kind),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attributes.ToSlice()...),
+	)
+	for _, e := range events {
+		span.AddEvent(e.name, trace.WithTimestamp(e.time),
+			trace.WithAttributes(attribute.Float64("duration_ms", metrics.D(e.duration))))
+	}
+	if isError != nil && hasErrorStatus(sample.Tags, isError) {
+		span.SetStatus(codes.Error, "")
+	}
+	span.End(trace.WithTimestamp(sample.Time))
+}
+
+// event is a phase of an HTTP request.
+type event struct {
+	name     string
+	time     time.Time
+	duration time.Duration
+}
+
+// httpReqPhases returns the start of the request, at its first phase, and its
+// phases. The blocked phase includes the connecting and TLS handshaking ones,
+// that are at its end.
+func httpReqPhases(
+	start time.Time, reqDuration metrics.Sample, samples []metrics.Sample,
+) (time.Time, []event) {
+	spanID := reqDuration.Metadata[k6trace.MetadataSpanID]
+	phases := make(map[string]time.Duration)
+	for _, sample := range samples {
+		if sample.Metadata[k6trace.MetadataSpanID] == spanID {
+			phases[sample.Metric.Name] = time.Duration(sample.Value * float64(time.Millisecond))
+		}
+	}
+	blocked, ok := phases[metrics.HTTPReqBlockedName]
+	if !ok {
+		return start, nil
+	}
+	connecting := phases[metrics.HTTPReqConnectingName]
+	tlsHandshaking := phases[metrics.HTTPReqTLSHandshakingName]
+	sending := phases[metrics.HTTPReqSendingName]
+	waiting := phases[metrics.HTTPReqWaitingName]
+	receiving := phases[metrics.HTTPReqReceivingName]
+
+	start = reqDuration.Time.Add(-(blocked + sending + waiting + receiving))
+	events := make([]event, 0, 6)
+	add := func(name string, t time.Time, d time.Duration) {
+		if t.Before(start) {
+			t = start
+		}
+		events = append(events, event{name: name, time: t, duration: d})
+	}
+	add(metrics.HTTPReqBlockedName, start, blocked)
+	add(metrics.HTTPReqConnectingName, start.Add(blocked-tlsHandshaking-connecting), connecting)
+	add(metrics.HTTPReqTLSHandshakingName, start.Add(blocked-tlsHandshaking), tlsHandshaking)
+	add(metrics.HTTPReqSendingName, start.Add(blocked), sending)
+	add(metrics.HTTPReqWaitingName, start.Add(blocked+sending), waiting)
+	add(metrics.HTTPReqReceivingName, start.Add(blocked+sending+waiting), receiving)
+	return start, events
+}
+
+// hasErrorStatus returns whether the status tag's value is an error.
+func hasErrorStatus(tags *metrics.TagSet, isError func(status int) bool) bool {
+	value, ok := tags.Get(metrics.TagStatus.String())
+	if !ok {
+		return false
+	}
+	status, err := strconv.Atoi(value)
+	return err == nil && isError(status)
+}
+
+type spanIDsKey struct{}
+
+// spanIDs are the identifiers of the span being recorded.
+type spanIDs struct {
+	traceID trace.TraceID
+	spanID  trace.SpanID
+}
+
+// sampleIDGenerator gives the spans the identifiers of their samples, set in
+// the context the spans are started with.
+type sampleIDGenerator struct{}
+
+var _ sdktrace.IDGenerator = sampleIDGenerator{}
+
+func (sampleIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	ids, _ := ctx.Value(spanIDsKey{}).(spanIDs)
+	return ids.traceID, ids.spanID
+}
+
+func (sampleIDGenerator) NewSpanID(ctx context.Context, _ trace.TraceID) trace.SpanID {
+	ids, _ := ctx.Value(spanIDsKey{}).(spanIDs)
+	return ids.spanID
+}
+
+// spanCollector is the span processor keeping the ended spans, until the
+// builder takes them.
+type spanCollector struct {
+	ended []sdktrace.ReadOnlySpan
+}
+
+var _ sdktrace.SpanProcessor = (*spanCollector)(nil)
+
+func (c *spanCollector) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (c *spanCollector) OnEnd(s sdktrace.ReadOnlySpan) {
+	c.ended = append(c.ended, s)
+}
+
+func (c *spanCollector) Shutdown(context.Context) error { return nil }
+
+func (c *spanCollector) ForceFlush(context.Context) error { return nil }
+
+// take returns the ended spans and forgets them.
+func (c *spanCollector) take() []sdktrace.ReadOnlySpan {
+	spans := c.ended
+	c.ended = nil
+	return spans
+}
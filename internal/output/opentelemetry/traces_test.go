@@ -0,0 +1,122 @@
+package opentelemetry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+
+	k6trace "go.k6.io/k6/v2/internal/lib/trace"
+	"go.k6.io/k6/v2/metrics"
+)
+
+func TestSpanBuilder(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	builtin := metrics.RegisterBuiltinMetrics(registry)
+
+	const (
+		traceID     = "0102030405060708090a0b0c0d0e0f10"
+		iterationID = "0102030405060708"
+		groupID     = "1112131415161718"
+		requestID   = "2122232425262728"
+	)
+	end := time.Unix(1700000000, 0)
+	meta := func(spanID, parentSpanID string) map[string]string {
+		m := map[string]string{k6trace.MetadataTraceID: traceID, k6trace.MetadataSpanID: spanID}
+		if parentSpanID != "" {
+			m[k6trace.MetadataParentSpanID] = parentSpanID
+		}
+		return m
+	}
+	reqTags := registry.RootTagSet().WithTagsFromMap(map[string]string{
+		"method": "GET", "status": "503", "group": "::login",
+	})
+	reqSample := func(m *metrics.Metric, value float64) metrics.Sample {
+		return metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: m, Tags: reqTags},
+			Time:       end,
+			Metadata:   meta(requestID, groupID),
+			Value:      value,
+		}
+	}
+
+	b := newSpanBuilder(resource.Empty())
+	spans := b.spans(metrics.Samples{
+		reqSample(builtin.HTTPReqs, 1),
+		reqSample(builtin.HTTPReqDuration, 60),
+		reqSample(builtin.HTTPReqBlocked, 30),
+		reqSample(builtin.HTTPReqConnecting, 10),
+		reqSample(builtin.HTTPReqTLSHandshaking, 15),
+		reqSample(builtin.HTTPReqSending, 10),
+		reqSample(builtin.HTTPReqWaiting, 40),
+		reqSample(builtin.HTTPReqReceiving, 10),
+	})
+	spans = append(spans, b.spans(metrics.Samples{{
+		TimeSeries: metrics.TimeSeries{
+			Metric: builtin.GroupDuration,
+			Tags:   registry.RootTagSet().With("group", "::login"),
+		},
+		Time:     end,
+		Metadata: meta(groupID, iterationID),
+		Value:    100,
+	}})...)
+	spans = append(spans, b.spans(metrics.Samples{
+		{
+			TimeSeries: metrics.TimeSeries{Metric: builtin.IterationDuration, Tags: registry.RootTagSet()},
+			Time:       end,
+			Metadata:   meta(iterationID, ""),
+			Value:      1000,
+		},
+		{
+			// without a trace context
+			TimeSeries: metrics.TimeSeries{Metric: builtin.IterationDuration, Tags: registry.RootTagSet()},
+			Time:       end,
+			Value:      1000,
+		},
+	})...)
+	require.Len(t, spans, 3)
+
+	req := spans[0]
+	assert.Equal(t, "HTTP GET", req.Name())
+	assert.Equal(t, trace.SpanKindClient, req.SpanKind())
+	assert.Equal(t, requestID, req.SpanContext().SpanID().String())
+	assert.Equal(t, traceID, req.SpanContext().TraceID().String())
+	assert.Equal(t, groupID, req.Parent().SpanID().String())
+	assert.Equal(t, end.Add(-90*time.Millisecond), req.StartTime())
+	assert.Equal(t, end, req.EndTime())
+	assert.Equal(t, codes.Error, req.Status().Code)
+	assert.Contains(t, req.Attributes(), attribute.String("method", "GET"))
+
+	events := req.Events()
+	require.Len(t, events, 6)
+	offsets := map[string]time.Duration{
+		metrics.HTTPReqBlockedName:        0,
+		metrics.HTTPReqConnectingName:     5 * time.Millisecond,
+		metrics.HTTPReqTLSHandshakingName: 15 * time.Millisecond,
+		metrics.HTTPReqSendingName:        30 * time.Millisecond,
+		metrics.HTTPReqWaitingName:        40 * time.Millisecond,
+		metrics.HTTPReqReceivingName:      80 * time.Millisecond,
+	}
+	for _, e := range events {
+		assert.Equal(t, req.StartTime().Add(offsets[e.Name]), e.Time, e.Name)
+	}
+
+	group := spans[1]
+	assert.Equal(t, "group ::login", group.Name())
+	assert.Equal(t, iterationID, group.Parent().SpanID().String())
+	assert.Equal(t, end.Add(-100*time.Millisecond), group.StartTime())
+
+	iteration := spans[2]
+	assert.Equal(t, "iteration", iteration.Name())
+	assert.Equal(t, trace.SpanKindInternal, iteration.SpanKind())
+	assert.False(t, iteration.Parent().IsValid())
+	assert.Equal(t, codes.Unset, iteration.Status().Code)
+	assert.Equal(t, "k6", iteration.InstrumentationScope().Name)
+}
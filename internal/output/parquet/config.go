@@ -0,0 +1,129 @@
+package parquet
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mstoykov/envconfig"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/lib/types"
+)
+
+// Config is the config for the parquet output
+type Config struct {
+	FileName     null.String        `json:"file_name" envconfig:"K6_PARQUET_FILENAME"`
+	SaveInterval types.NullDuration `json:"save_interval" envconfig:"K6_PARQUET_SAVE_INTERVAL"`
+	Compression  null.String        `json:"compression" envconfig:"K6_PARQUET_COMPRESSION"`
+	// TagColumns are the additional tags, besides the enabled system tags,
+	// with a dedicated column instead of being in the extra_tags one.
+	TagColumns []string `json:"tag_columns" envconfig:"K6_PARQUET_TAG_COLUMNS"`
+}
+
+// NewConfig creates a new Config instance with default values for some fields.
+func NewConfig() Config {
+	return Config{
+		FileName:     null.NewString("file.parquet", false),
+		SaveInterval: types.NewNullDuration(10*time.Second, false),
+		Compression:  null.NewString("snappy", false),
+	}
+}
+
+// Apply merges two configs by overwriting properties in the old config
+func (c Config) Apply(cfg Config) Config {
+	if cfg.FileName.Valid {
+		c.FileName = cfg.FileName
+	}
+	if cfg.SaveInterval.Valid {
+		c.SaveInterval = cfg.SaveInterval
+	}
+	if cfg.Compression.Valid {
+		c.Compression = cfg.Compression
+	}
+	if len(cfg.TagColumns) > 0 {
+		c.TagColumns = slices.Clone(cfg.TagColumns)
+	}
+	return c
+}
+
+// ParseArg takes an arg string and converts it to a config
+func ParseArg(arg string) (Config, error) {
+	c := NewConfig()
+
+	if !strings.Contains(arg, "=") {
+		c.FileName = null.StringFrom(arg)
+		return c, nil
+	}
+
+	for pair := range strings.SplitSeq(arg, ",") {
+		k, v, _ := strings.Cut(pair, "=")
+		if v == "" {
+			return c, fmt.Errorf("couldn't parse %q as argument for parquet output", arg)
+		}
+		switch k {
+		case "saveInterval":
+			err := c.SaveInterval.UnmarshalText([]byte(v))
+			if err != nil {
+				return c, err
+			}
+		case "fileName":
+			c.FileName = null.StringFrom(v)
+		case "compression":
+			c.Compression = null.StringFrom(v)
+		case "tagColumns":
+			// the comma is already used as the options' separator
+			c.TagColumns = strings.Split(v, ";")
+		default:
+			return c, fmt.Errorf("unknown key %q as argument for parquet output", k)
+		}
+	}
+
+	return c, nil
+}
+
+// GetConsolidatedConfig combines {default config values + JSON config +
+// environment vars + arg config values}, and returns the final result.
+func GetConsolidatedConfig(
+	jsonRawConf json.RawMessage, env map[string]string, arg string,
+) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+
+	envConfig := Config{}
+	if err := envconfig.Process("", &envConfig, func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}); err != nil {
+		return result, err
+	}
+	result = result.Apply(envConfig)
+
+	if arg != "" {
+		argConf, err := ParseArg(arg)
+		if err != nil {
+			return result, err
+		}
+		result = result.Apply(argConf)
+	}
+
+	if _, ok := compressionCodecs[result.Compression.String]; !ok {
+		return result, fmt.Errorf("unsupported compression %q, the supported ones are none, snappy, gzip and zstd",
+			result.Compression.String)
+	}
+	for _, tag := range result.TagColumns {
+		if slices.Contains(fixedColumns, tag) {
+			return result, fmt.Errorf("the tag %q can't have a column, the name is already used", tag)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,86 @@
+package parquet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/lib/types"
+)
+
+func TestGetConsolidatedConfig(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		name   string
+		json   string
+		env    map[string]string
+		arg    string
+		config Config
+		err    string
+	}{
+		{
+			name:   "defaults",
+			config: NewConfig(),
+		},
+		{
+			name: "file name only",
+			arg:  "results.parquet",
+			config: NewConfig().Apply(Config{
+				FileName: null.StringFrom("results.parquet"),
+			}),
+		},
+		{
+			name: "all the sources",
+			json: `{"file_name":"json.parquet","compression":"gzip"}`,
+			env: map[string]string{
+				"K6_PARQUET_COMPRESSION":   "zstd",
+				"K6_PARQUET_SAVE_INTERVAL": "1s",
+				"K6_PARQUET_TAG_COLUMNS":   "scenario,group",
+			},
+			arg: "saveInterval=5s,tagColumns=name;expected_response",
+			config: NewConfig().Apply(Config{
+				FileName:     null.StringFrom("json.parquet"),
+				SaveInterval: types.NullDurationFrom(5 * time.Second),
+				Compression:  null.StringFrom("zstd"),
+				TagColumns:   []string{"name", "expected_response"},
+			}),
+		},
+		{
+			name: "unknown key",
+			arg:  "fileName=results.parquet,rowGroupSize=10",
+			err:  `unknown key "rowGroupSize" as argument for parquet output`,
+		},
+		{
+			name: "unsupported compression",
+			arg:  "compression=lz4",
+			err:  `unsupported compression "lz4", the supported ones are none, snappy, gzip and zstd`,
+		},
+		{
+			name: "reserved column",
+			arg:  "tagColumns=metadata",
+			err:  `the tag "metadata" can't have a column, the name is already used`,
+		},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var jsonConf []byte
+			if tc.json != "" {
+				jsonConf = []byte(tc.json)
+			}
+			config, err := GetConsolidatedConfig(jsonConf, tc.env, tc.arg)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.config, config)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Package parquet implements an output writing the samples in a Parquet file,
+// ready to be loaded by tools like DuckDB or Spark.
+//
+// Every flush writes a row group with the following columns:
+//
+//   - metric_name: the metric's name, as a dictionary encoded string.
+//   - timestamp: the sample's time, as an UTC timestamp in microseconds.
+//   - metric_value: the sample's value, as a double.
+//   - a nullable dictionary encoded string column for each enabled indexable
+//     system tag and each tag configured with tag_columns, sorted by name.
+//   - extra_tags: the rest of the tags as a JSON object, or null.
+//   - metadata: the sample's metadata as a JSON object, or null.
+//
+// The types of the metrics and their thresholds are stored as JSON in the
+// k6.metrics key of the file's metadata. As the file's footer is written
+// at the end of the test, the file can't be read while the test is running.
+package parquet
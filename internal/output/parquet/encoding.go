@@ -0,0 +1,184 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+)
+
+// The types of the Thrift compact protocol, used for encoding the file's
+// metadata and the pages' headers.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes Thrift structs with the compact protocol. The structs
+// are written field by field, in increasing order of the fields' IDs.
+type thriftWriter struct {
+	buf []byte
+	// lastField is the stack of the last written field ID for each struct.
+	lastField []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastField: []int16{0}}
+}
+
+func (w *thriftWriter) field(id int16, kind byte) {
+	last := &w.lastField[len(w.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|kind) //nolint:gosec
+	} else {
+		w.buf = append(w.buf, kind)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) varint(v int64) {
+	w.buf = binary.AppendUvarint(w.buf, uint64((v<<1)^(v>>63))) //nolint:gosec
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(v)
+}
+
+func (w *thriftWriter) bool(id int16, v bool) {
+	if v {
+		w.field(id, thriftTrue)
+	} else {
+		w.field(id, thriftFalse)
+	}
+}
+
+func (w *thriftWriter) binary(id int16, v []byte) {
+	w.field(id, thriftBinary)
+	w.binaryValue(v)
+}
+
+func (w *thriftWriter) binaryValue(v []byte) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// list writes the header of a list field, the caller has to write its n
+// elements right after.
+func (w *thriftWriter) list(id int16, kind byte, n int) {
+	w.field(id, thriftList)
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|kind) //nolint:gosec
+		return
+	}
+	w.buf = append(w.buf, 0xF0|kind)
+	w.buf = binary.AppendUvarint(w.buf, uint64(n)) //nolint:gosec
+}
+
+// structField starts a struct field, it has to be ended with endStruct.
+func (w *thriftWriter) structField(id int16) {
+	w.field(id, thriftStruct)
+	w.beginStruct()
+}
+
+// beginStruct starts a struct that isn't a field, like a list element
+// or the top-level one.
+func (w *thriftWriter) beginStruct() {
+	w.lastField = append(w.lastField, 0)
+}
+
+func (w *thriftWriter) endStruct() {
+	w.buf = append(w.buf, 0)
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
+
+// bitWidth returns the number of bits required for encoding the values up to max.
+func bitWidth(maxValue int) int {
+	return bits.Len(uint(maxValue)) //nolint:gosec
+}
+
+// appendHybrid appends the values with the RLE/bit-packing hybrid encoding,
+// used for the definition levels and the dictionary indexes. The runs of at
+// least 8 repeated values are RLE encoded, the rest is bit-packed.
+func appendHybrid(buf []byte, values []int32, width int) []byte {
+	byteWidth := (width + 7) / 8
+	runLength := func(i int) int {
+		j := i + 1
+		for j < len(values) && values[j] == values[i] {
+			j++
+		}
+		return j - i
+	}
+
+	for i := 0; i < len(values); {
+		if n := runLength(i); n >= 8 {
+			buf = binary.AppendUvarint(buf, uint64(n)<<1) //nolint:gosec
+			v := uint32(values[i])                        //nolint:gosec
+			for b := range byteWidth {
+				buf = append(buf, byte(v>>(8*b)))
+			}
+			i += n
+			continue
+		}
+
+		// bit-pack until the next run, in groups of 8 values: if a run follows,
+		// it provides the values for completing the last group, otherwise
+		// the last group is padded with zeros.
+		j := i
+		for j < len(values) && runLength(j) < 8 {
+			j++
+		}
+		groups := (j - i + 7) / 8
+		buf = binary.AppendUvarint(buf, uint64(groups)<<1|1) //nolint:gosec
+
+		packed := make([]byte, groups*width)
+		for k := range groups * 8 {
+			if i+k >= len(values) {
+				break
+			}
+			v := uint64(values[i+k]) //nolint:gosec
+			for b := range width {
+				if v&(1<<b) != 0 {
+					bit := k*width + b
+					packed[bit/8] |= 1 << (bit % 8)
+				}
+			}
+		}
+		buf = append(buf, packed...)
+		i = min(i+groups*8, len(values))
+	}
+	return buf
+}
+
+// appendLevels appends the definition levels of an optional column, prefixed
+// by their length as required by the v1 data pages.
+func appendLevels(buf []byte, levels []int32) []byte {
+	start := len(buf)
+	buf = append(buf, 0, 0, 0, 0)
+	buf = appendHybrid(buf, levels, 1)
+	binary.LittleEndian.PutUint32(buf[start:], uint32(len(buf)-start-4)) //nolint:gosec
+	return buf
+}
+
+func appendPlainInt64(buf []byte, v int64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, uint64(v)) //nolint:gosec
+}
+
+func appendPlainDouble(buf []byte, v float64) []byte {
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+}
+
+func appendPlainByteArray(buf []byte, v string) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v))) //nolint:gosec
+	return append(buf, v...)
+}
@@ -0,0 +1,201 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendHybrid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		values   []int32
+		width    int
+		expected []byte
+	}{
+		"bit-packed": {
+			// the example in the Parquet's encodings specification
+			values:   []int32{0, 1, 2, 3, 4, 5, 6, 7},
+			width:    3,
+			expected: []byte{0x03, 0x88, 0xC6, 0xFA},
+		},
+		"padded": {
+			values:   []int32{1, 0, 1},
+			width:    1,
+			expected: []byte{0x03, 0x05},
+		},
+		"run": {
+			values:   []int32{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			width:    1,
+			expected: []byte{0x14, 0x01},
+		},
+		"bit-packed completed by a run": {
+			values:   []int32{0, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			width:    1,
+			expected: []byte{0x03, 0xFE, 0x10, 0x01},
+		},
+		"wide run": {
+			values:   []int32{300, 300, 300, 300, 300, 300, 300, 300},
+			width:    9,
+			expected: []byte{0x10, 0x2C, 0x01},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded := appendHybrid(nil, tc.values, tc.width)
+			assert.Equal(t, tc.expected, encoded)
+			assert.Equal(t, tc.values, decodeHybrid(t, encoded, tc.width, len(tc.values)))
+		})
+	}
+}
+
+func TestThriftWriter(t *testing.T) {
+	t.Parallel()
+
+	tw := newThriftWriter()
+	tw.i32(1, -1)
+	tw.i64(2, 150)
+	tw.binary(20, []byte("k6"))
+	tw.list(21, thriftI32, 2)
+	tw.varint(1)
+	tw.varint(2)
+	tw.structField(22)
+	tw.bool(1, true)
+	tw.endStruct()
+	tw.endStruct()
+
+	assert.Equal(t, []byte{
+		0x15, 0x01, // field 1, i32 -1
+		0x16, 0xAC, 0x02, // field 2, i64 150
+		0x08, 0x28, 0x02, 'k', '6', // field 20 with the long form, binary "k6"
+		0x19, 0x25, 0x02, 0x04, // field 21, list of 2 i32
+		0x1C, 0x11, 0x00, // field 22, struct with the field 1 true
+		0x00,
+	}, tw.buf)
+
+	fields := decodeThrift(t, tw.buf)
+	assert.Equal(t, int64(-1), fields[1])
+	assert.Equal(t, int64(150), fields[2])
+	assert.Equal(t, []byte("k6"), fields[20])
+	assert.Equal(t, []any{int64(1), int64(2)}, fields[21])
+	assert.Equal(t, map[int16]any{1: true}, fields[22])
+}
+
+// decodeHybrid decodes n values encoded with the RLE/bit-packing hybrid encoding.
+func decodeHybrid(t *testing.T, b []byte, width, n int) []int32 {
+	t.Helper()
+
+	var values []int32
+	for len(values) < n {
+		header, l := binary.Uvarint(b)
+		require.Positive(t, l)
+		b = b[l:]
+		if header&1 == 0 {
+			var v int32
+			for i := range (width + 7) / 8 {
+				v |= int32(b[i]) << (8 * i)
+			}
+			b = b[(width+7)/8:]
+			for range header >> 1 {
+				values = append(values, v)
+			}
+			continue
+		}
+		count := int(header>>1) * 8
+		for k := range count {
+			var v int32
+			for bit := range width {
+				pos := k*width + bit
+				if b[pos/8]&(1<<(pos%8)) != 0 {
+					v |= 1 << bit
+				}
+			}
+			values = append(values, v)
+		}
+		b = b[count*width/8:]
+	}
+	return values[:n]
+}
+
+// decodeThrift decodes a struct encoded with the Thrift compact protocol,
+// the integers are decoded as int64, the structs as maps and the lists as slices.
+func decodeThrift(t *testing.T, b []byte) map[int16]any {
+	t.Helper()
+
+	d := &thriftDecoder{t: t, b: b}
+	return d.structValue()
+}
+
+type thriftDecoder struct {
+	t *testing.T
+	b []byte
+}
+
+func (d *thriftDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.b)
+	require.Positive(d.t, n)
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *thriftDecoder) varint() int64 {
+	v := d.uvarint()
+	return int64(v>>1) ^ -int64(v&1) //nolint:gosec
+}
+
+func (d *thriftDecoder) value(kind byte) any {
+	switch kind {
+	case thriftTrue:
+		return true
+	case thriftFalse:
+		return false
+	case thriftI32, thriftI64:
+		return d.varint()
+	case thriftBinary:
+		n := d.uvarint()
+		v := d.b[:n]
+		d.b = d.b[n:]
+		return v
+	case thriftList:
+		header := d.b[0]
+		d.b = d.b[1:]
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(d.uvarint()) //nolint:gosec
+		}
+		list := make([]any, 0, n)
+		for range n {
+			list = append(list, d.value(header&0x0F))
+		}
+		return list
+	case thriftStruct:
+		return d.structValue()
+	default:
+		panic(fmt.Sprintf("unexpected Thrift type %d", kind))
+	}
+}
+
+func (d *thriftDecoder) structValue() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for {
+		header := d.b[0]
+		d.b = d.b[1:]
+		if header == 0 {
+			return fields
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			last += delta
+		} else {
+			last = int16(d.varint()) //nolint:gosec
+		}
+		fields[last] = d.value(header & 0x0F)
+	}
+}
@@ -0,0 +1,291 @@
+package parquet
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/v2/internal/build"
+	"go.k6.io/k6/v2/metrics"
+	"go.k6.io/k6/v2/output"
+)
+
+// fixedColumns are the columns always present, the tag columns
+// are between metric_value and extra_tags.
+//
+//nolint:gochecknoglobals
+var fixedColumns = []string{"metric_name", "timestamp", "metric_value", "extra_tags", "metadata"}
+
+// Output writes the samples to a Parquet file, one row group per flush.
+type Output struct {
+	output.SampleBuffer
+
+	params          output.Params
+	periodicFlusher *output.PeriodicFlusher
+
+	logger       logrus.FieldLogger
+	fname        string
+	compression  string
+	saveInterval time.Duration
+	tagColumns   []string
+	columns      []columnSpec
+
+	writer  *fileWriter
+	buf     *bufio.Writer
+	closeFn func() error
+
+	seenMetrics map[string]*metrics.Metric
+	thresholds  map[string]metrics.Thresholds
+}
+
+var _ output.WithThresholds = &Output{}
+
+// New creates a new instance of the parquet output
+func New(params output.Params) (output.Output, error) {
+	return newOutput(params)
+}
+
+func newOutput(params output.Params) (*Output, error) {
+	config, err := GetConsolidatedConfig(params.JSONConfig, params.Environment, params.ConfigArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	tagColumns, err := buildTagColumns(params, config.TagColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := []columnSpec{
+		{name: "metric_name", kind: kindString},
+		{name: "timestamp", kind: kindTimestamp},
+		{name: "metric_value", kind: kindDouble},
+	}
+	for _, tag := range tagColumns {
+		columns = append(columns, columnSpec{name: tag, kind: kindString, optional: true})
+	}
+	columns = append(columns,
+		columnSpec{name: "extra_tags", kind: kindJSON, optional: true},
+		columnSpec{name: "metadata", kind: kindJSON, optional: true},
+	)
+
+	return &Output{
+		params:       params,
+		fname:        config.FileName.String,
+		compression:  config.Compression.String,
+		saveInterval: config.SaveInterval.TimeDuration(),
+		tagColumns:   tagColumns,
+		columns:      columns,
+		logger: params.Logger.WithFields(logrus.Fields{
+			"output":   "parquet",
+			"filename": config.FileName.String,
+		}),
+		seenMetrics: make(map[string]*metrics.Metric),
+	}, nil
+}
+
+// buildTagColumns returns the sorted names of the tags with a dedicated
+// column: the enabled indexable system tags, and the configured ones.
+func buildTagColumns(params output.Params, configured []string) ([]string, error) {
+	columns := slices.Clone(configured)
+	for tag, enabled := range params.ScriptOptions.SystemTags.Map() {
+		systemTag, err := metrics.SystemTagString(tag)
+		if err != nil {
+			return nil, err
+		}
+		// the non-indexable system tags are in the metadata column
+		if enabled && !metrics.NonIndexableSystemTags.Has(systemTag) {
+			columns = append(columns, tag)
+		}
+	}
+	slices.Sort(columns)
+	return slices.Compact(columns), nil
+}
+
+// Description returns a human-readable description of the output.
+func (o *Output) Description() string {
+	return fmt.Sprintf("parquet (%s)", o.fname)
+}
+
+// SetThresholds receives the thresholds before the output is Start()-ed.
+func (o *Output) SetThresholds(thresholds map[string]metrics.Thresholds) {
+	if len(thresholds) == 0 {
+		return
+	}
+	o.thresholds = make(map[string]metrics.Thresholds, len(thresholds))
+	maps.Copy(o.thresholds, thresholds)
+}
+
+// Start creates the file and starts a new output.PeriodicFlusher.
+func (o *Output) Start() error {
+	o.logger.Debug("Starting...")
+
+	file, err := o.params.FS.Create(o.fname)
+	if err != nil {
+		return err
+	}
+	o.buf = bufio.NewWriter(file)
+	o.closeFn = file.Close
+
+	o.writer, err = newFileWriter(o.buf, o.columns, o.compression)
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	pf, err := output.NewPeriodicFlusher(o.saveInterval, o.flushMetrics)
+	if err != nil {
+		return err
+	}
+	o.logger.Debug("Started!")
+	o.periodicFlusher = pf
+
+	return nil
+}
+
+// Stop flushes any remaining metrics, writes the file's footer and closes it.
+func (o *Output) Stop() error {
+	o.logger.Debug("Stopping...")
+	defer o.logger.Debug("Stopped!")
+	o.periodicFlusher.Stop()
+
+	metricsInfo, err := o.metricsInfo()
+	if err != nil {
+		return err
+	}
+	keyValues := [][2]string{{"k6.metrics", metricsInfo}}
+	if err := o.writer.close(keyValues, "k6 version "+build.Version); err != nil {
+		_ = o.closeFn()
+		return err
+	}
+	if err := o.buf.Flush(); err != nil {
+		_ = o.closeFn()
+		return err
+	}
+	return o.closeFn()
+}
+
+// metricsInfo returns the JSON definitions of the written metrics,
+// stored in the file's key-value metadata.
+func (o *Output) metricsInfo() (string, error) {
+	type metricInfo struct {
+		Name       string             `json:"name"`
+		Type       metrics.MetricType `json:"type"`
+		Contains   metrics.ValueType  `json:"contains"`
+		Thresholds []string           `json:"thresholds,omitempty"`
+	}
+
+	info := make([]metricInfo, 0, len(o.seenMetrics))
+	for _, m := range o.seenMetrics {
+		mi := metricInfo{Name: m.Name, Type: m.Type, Contains: m.Contains}
+		for _, t := range o.thresholds[m.Name].Thresholds {
+			mi.Thresholds = append(mi.Thresholds, t.Source)
+		}
+		info = append(info, mi)
+	}
+	sort.Slice(info, func(i, j int) bool { return info[i].Name < info[j].Name })
+
+	b, err := json.Marshal(info)
+	return string(b), err
+}
+
+// flushMetrics writes the buffered samples as a new row group.
+func (o *Output) flushMetrics() {
+	samples := o.GetBufferedSamples()
+	if len(samples) == 0 {
+		return
+	}
+	start := time.Now()
+
+	var (
+		names      stringColumn
+		timestamps []int64
+		values     []float64
+		tags       = make([]stringColumn, len(o.tagColumns))
+		extraTags  stringColumn
+		metadata   stringColumn
+	)
+	for _, sc := range samples {
+		for _, sample := range sc.GetSamples() {
+			o.seenMetrics[sample.Metric.Name] = sample.Metric
+
+			names.values = append(names.values, sample.Metric.Name)
+			timestamps = append(timestamps, sample.Time.UnixMicro())
+			values = append(values, sample.Value)
+
+			sampleTags := sample.Tags.Map()
+			for i, tag := range o.tagColumns {
+				v, ok := sampleTags[tag]
+				tags[i].values = append(tags[i].values, v)
+				tags[i].defined = append(tags[i].defined, ok)
+				delete(sampleTags, tag)
+			}
+			appendJSON(&extraTags, sampleTags)
+			appendJSON(&metadata, sample.Metadata)
+		}
+	}
+
+	if len(timestamps) == 0 {
+		return
+	}
+	if err := o.writeRowGroup(names, timestamps, values, tags, extraTags, metadata); err != nil {
+		o.logger.WithError(err).Error("Parquet: Error writing to file")
+		return
+	}
+	o.logger.WithField("t", time.Since(start)).WithField("count", len(timestamps)).Debug("Wrote metrics to parquet")
+}
+
+// appendJSON appends the map as a JSON object, or a null if it's empty.
+func appendJSON(col *stringColumn, m map[string]string) {
+	if len(m) == 0 {
+		col.values = append(col.values, "")
+		col.defined = append(col.defined, false)
+		return
+	}
+	// a map of strings can't fail to be marshaled
+	b, _ := json.Marshal(m) //nolint:errchkjson
+	col.values = append(col.values, string(b))
+	col.defined = append(col.defined, true)
+}
+
+func (o *Output) writeRowGroup(
+	names stringColumn, timestamps []int64, values []float64,
+	tags []stringColumn, extraTags, metadata stringColumn,
+) error {
+	chunks := make([]columnChunk, 0, len(o.columns))
+	add := func(c columnChunk, err error) error {
+		chunks = append(chunks, c)
+		return err
+	}
+
+	if err := add(o.writer.writeStringChunk(&o.columns[0], names)); err != nil {
+		return err
+	}
+	if err := add(o.writer.writeInt64Chunk(&o.columns[1], timestamps)); err != nil {
+		return err
+	}
+	if err := add(o.writer.writeDoubleChunk(&o.columns[2], values)); err != nil {
+		return err
+	}
+	for i, tag := range tags {
+		if err := add(o.writer.writeStringChunk(&o.columns[3+i], tag)); err != nil {
+			return err
+		}
+	}
+	n := len(o.columns)
+	if err := add(o.writer.writeStringChunk(&o.columns[n-2], extraTags)); err != nil {
+		return err
+	}
+	if err := add(o.writer.writeStringChunk(&o.columns[n-1], metadata)); err != nil {
+		return err
+	}
+
+	o.writer.addRowGroup(len(timestamps), chunks)
+	return nil
+}
@@ -0,0 +1,113 @@
+package parquet
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/fsext"
+	"go.k6.io/k6/v2/metrics"
+	"go.k6.io/k6/v2/output"
+)
+
+// readFooter checks the file's structure and returns its decoded metadata.
+func readFooter(t *testing.T, fs fsext.Fs, fileName string) map[int16]any {
+	t.Helper()
+
+	data, err := fsext.ReadFile(fs, fileName)
+	require.NoError(t, err)
+	require.Equal(t, magic, string(data[:4]))
+	require.Equal(t, magic, string(data[len(data)-4:]))
+
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	return decodeThrift(t, data[len(data)-8-footerLength:len(data)-8])
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	for _, compression := range []string{"none", "snappy", "gzip", "zstd"} {
+		t.Run(compression, func(t *testing.T) {
+			t.Parallel()
+
+			fs := fsext.NewMemMapFs()
+			o, err := newOutput(output.Params{
+				Logger:         testutils.NewLogger(t),
+				FS:             fs,
+				ConfigArgument: "fileName=results.parquet,compression=" + compression + ",tagColumns=scenario",
+				ScriptOptions: lib.Options{
+					SystemTags: metrics.NewSystemTagSet(metrics.TagMethod | metrics.TagStatus | metrics.TagVU),
+				},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "parquet (results.parquet)", o.Description())
+
+			registry := metrics.NewRegistry()
+			reqs := registry.MustNewMetric("http_reqs", metrics.Counter)
+			duration := registry.MustNewMetric("http_req_duration", metrics.Trend, metrics.Time)
+			thresholds := metrics.NewThresholds([]string{"p(95)<500"})
+			o.SetThresholds(map[string]metrics.Thresholds{"http_req_duration": thresholds})
+
+			require.NoError(t, o.Start())
+			now := time.Unix(1700000000, 0)
+			for i := range 3 {
+				tags := registry.RootTagSet().With("method", "GET").With("group", "::login")
+				o.AddMetricSamples([]metrics.SampleContainer{metrics.Samples{
+					{
+						TimeSeries: metrics.TimeSeries{Metric: reqs, Tags: tags.With("status", "200")},
+						Time:       now.Add(time.Duration(i) * time.Second),
+						Value:      1,
+					},
+					{
+						TimeSeries: metrics.TimeSeries{Metric: duration, Tags: tags},
+						Time:       now.Add(time.Duration(i) * time.Second),
+						Value:      float64(100 * i),
+						Metadata:   map[string]string{"vu": "1"},
+					},
+				}})
+				o.flushMetrics()
+			}
+			require.NoError(t, o.Stop())
+
+			footer := readFooter(t, fs, "results.parquet")
+			assert.Equal(t, int64(6), footer[3])
+
+			var columns []string
+			for _, element := range footer[2].([]any)[1:] { //nolint:forcetypeassert
+				columns = append(columns, string(element.(map[int16]any)[4].([]byte))) //nolint:forcetypeassert
+			}
+			assert.Equal(t, []string{
+				"metric_name", "timestamp", "metric_value", "method", "scenario", "status", "extra_tags", "metadata",
+			}, columns)
+
+			rowGroups := footer[4].([]any) //nolint:forcetypeassert
+			require.Len(t, rowGroups, 3)
+			for _, rg := range rowGroups {
+				rowGroup := rg.(map[int16]any) //nolint:forcetypeassert
+				assert.Equal(t, int64(2), rowGroup[3])
+				chunks := rowGroup[1].([]any) //nolint:forcetypeassert
+				require.Len(t, chunks, len(columns))
+
+				nullCounts := make([]int64, 0, len(chunks))
+				for _, c := range chunks {
+					meta := c.(map[int16]any)[3].(map[int16]any) //nolint:forcetypeassert
+					assert.Equal(t, int64(compressionCodecs[compression]), meta[4])
+					nullCounts = append(nullCounts, meta[12].(map[int16]any)[3].(int64)) //nolint:forcetypeassert
+				}
+				assert.Equal(t, []int64{0, 0, 0, 0, 2, 1, 0, 1}, nullCounts)
+			}
+
+			keyValue := footer[5].([]any)[0].(map[int16]any)            //nolint:forcetypeassert
+			assert.Equal(t, "k6.metrics", string(keyValue[1].([]byte))) //nolint:forcetypeassert
+			assert.JSONEq(t, `[
+				{"name":"http_req_duration","type":"trend","contains":"time","thresholds":["p(95)<500"]},
+				{"name":"http_reqs","type":"counter","contains":"default"}
+			]`, string(keyValue[2].([]byte))) //nolint:forcetypeassert
+		})
+	}
+}
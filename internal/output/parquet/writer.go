@@ -0,0 +1,451 @@
+package parquet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// magic is at the start and at the end of every Parquet file.
+const magic = "PAR1"
+
+// The physical types, the encodings and the other enums of the Parquet format
+// used by the writer, as defined by https://github.com/apache/parquet-format.
+const (
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+	convertedJSON            = 19
+
+	encodingPlain         = 0
+	encodingRLE           = 3
+	encodingRLEDictionary = 8
+
+	pageData       = 0
+	pageDictionary = 2
+)
+
+// compressionCodecs maps the supported compressions to their codec IDs.
+//
+//nolint:gochecknoglobals
+var compressionCodecs = map[string]int32{
+	"none":   0,
+	"snappy": 1,
+	"gzip":   2,
+	"zstd":   6,
+}
+
+// columnKind is how the values of a column are stored.
+type columnKind uint8
+
+const (
+	// kindString is an UTF-8 string, always dictionary encoded.
+	kindString columnKind = iota
+	// kindJSON is a JSON document, always dictionary encoded.
+	kindJSON
+	// kindTimestamp is an UTC timestamp with microseconds precision.
+	kindTimestamp
+	// kindDouble is a double precision floating point number.
+	kindDouble
+)
+
+type columnSpec struct {
+	name     string
+	kind     columnKind
+	optional bool
+}
+
+// stringColumn is the data of a kindString or kindJSON column, defined
+// is only used for the optional columns.
+type stringColumn struct {
+	values  []string
+	defined []bool
+}
+
+// columnChunk is the metadata of a written column chunk.
+type columnChunk struct {
+	spec                 *columnSpec
+	encodings            []int32
+	numValues            int64
+	uncompressedSize     int64
+	compressedSize       int64
+	dataPageOffset       int64
+	dictionaryPageOffset int64
+	nullCount            int64
+	minValue, maxValue   []byte
+}
+
+type rowGroup struct {
+	chunks  []columnChunk
+	numRows int64
+}
+
+// fileWriter writes a Parquet file with a fixed schema, one row group at a
+// time. Every column chunk is made of a single v1 data page, preceded by a
+// dictionary page for the string columns.
+type fileWriter struct {
+	w        io.Writer
+	offset   int64
+	codec    int32
+	compress func([]byte) ([]byte, error)
+
+	columns   []columnSpec
+	rowGroups []rowGroup
+	numRows   int64
+}
+
+func newFileWriter(w io.Writer, columns []columnSpec, compression string) (*fileWriter, error) {
+	codec, ok := compressionCodecs[compression]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression %q, the supported ones are none, snappy, gzip and zstd",
+			compression)
+	}
+	fw := &fileWriter{w: w, codec: codec, columns: columns}
+
+	switch compression {
+	case "none":
+		fw.compress = func(b []byte) ([]byte, error) { return b, nil }
+	case "snappy":
+		fw.compress = func(b []byte) ([]byte, error) { return snappy.Encode(nil, b), nil }
+	case "gzip":
+		fw.compress = func(b []byte) ([]byte, error) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			if _, err := gz.Write(b); err != nil {
+				return nil, err
+			}
+			if err := gz.Close(); err != nil {
+				return nil, err
+			}
+			return buf.Bytes(), nil
+		}
+	case "zstd":
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, err
+		}
+		fw.compress = func(b []byte) ([]byte, error) { return enc.EncodeAll(b, nil), nil }
+	}
+
+	return fw, fw.write([]byte(magic))
+}
+
+func (fw *fileWriter) write(b []byte) error {
+	n, err := fw.w.Write(b)
+	fw.offset += int64(n)
+	return err
+}
+
+// writePage writes a page, the header function writes the type specific
+// header, and the returned sizes include the page's header.
+func (fw *fileWriter) writePage(
+	kind int32, body []byte, header func(*thriftWriter),
+) (uncompressed int64, compressed int64, err error) {
+	data, err := fw.compress(body)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tw := newThriftWriter()
+	tw.i32(1, kind)
+	tw.i32(2, int32(len(body))) //nolint:gosec
+	tw.i32(3, int32(len(data))) //nolint:gosec
+	header(tw)
+	tw.endStruct()
+
+	if err := fw.write(tw.buf); err != nil {
+		return 0, 0, err
+	}
+	if err := fw.write(data); err != nil {
+		return 0, 0, err
+	}
+	return int64(len(tw.buf) + len(body)), int64(len(tw.buf) + len(data)), nil
+}
+
+func (fw *fileWriter) writeDataPage(c *columnChunk, numValues int, encoding int32, body []byte) error {
+	c.dataPageOffset = fw.offset
+	uncompressed, compressed, err := fw.writePage(pageData, body, func(tw *thriftWriter) {
+		tw.structField(5)
+		tw.i32(1, int32(numValues)) //nolint:gosec
+		tw.i32(2, encoding)
+		tw.i32(3, encodingRLE)
+		tw.i32(4, encodingRLE)
+		tw.endStruct()
+	})
+	c.numValues = int64(numValues)
+	c.uncompressedSize += uncompressed
+	c.compressedSize += compressed
+	return err
+}
+
+func (fw *fileWriter) writeStringChunk(spec *columnSpec, col stringColumn) (columnChunk, error) {
+	c := columnChunk{spec: spec, encodings: []int32{encodingPlain, encodingRLE, encodingRLEDictionary}}
+
+	var (
+		dictionary []byte
+		indexes    = make(map[string]int32)
+		values     = make([]int32, 0, len(col.values))
+		levels     []int32
+	)
+	if spec.optional {
+		levels = make([]int32, len(col.values))
+	}
+	for i, v := range col.values {
+		if spec.optional {
+			if !col.defined[i] {
+				c.nullCount++
+				continue
+			}
+			levels[i] = 1
+		}
+		index, ok := indexes[v]
+		if !ok {
+			index = int32(len(indexes)) //nolint:gosec
+			indexes[v] = index
+			dictionary = appendPlainByteArray(dictionary, v)
+		}
+		values = append(values, index)
+	}
+
+	var body []byte
+	if spec.optional {
+		body = appendLevels(body, levels)
+	}
+	// a column chunk with only nulls doesn't have any value to encode
+	if len(indexes) == 0 {
+		c.encodings = []int32{encodingPlain, encodingRLE}
+		return c, fw.writeDataPage(&c, len(col.values), encodingPlain, body)
+	}
+
+	c.dictionaryPageOffset = fw.offset
+	uncompressed, compressed, err := fw.writePage(pageDictionary, dictionary, func(tw *thriftWriter) {
+		tw.structField(7)
+		tw.i32(1, int32(len(indexes))) //nolint:gosec
+		tw.i32(2, encodingPlain)
+		tw.endStruct()
+	})
+	if err != nil {
+		return c, err
+	}
+	c.uncompressedSize += uncompressed
+	c.compressedSize += compressed
+
+	width := max(1, bitWidth(len(indexes)-1))
+	body = append(body, byte(width))
+	body = appendHybrid(body, values, width)
+	return c, fw.writeDataPage(&c, len(col.values), encodingRLEDictionary, body)
+}
+
+func (fw *fileWriter) writeInt64Chunk(spec *columnSpec, values []int64) (columnChunk, error) {
+	c := columnChunk{spec: spec, encodings: []int32{encodingPlain}}
+
+	body := make([]byte, 0, 8*len(values))
+	minValue, maxValue := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, v := range values {
+		body = appendPlainInt64(body, v)
+		minValue, maxValue = min(minValue, v), max(maxValue, v)
+	}
+	if len(values) > 0 {
+		c.minValue = appendPlainInt64(nil, minValue)
+		c.maxValue = appendPlainInt64(nil, maxValue)
+	}
+	return c, fw.writeDataPage(&c, len(values), encodingPlain, body)
+}
+
+func (fw *fileWriter) writeDoubleChunk(spec *columnSpec, values []float64) (columnChunk, error) {
+	c := columnChunk{spec: spec, encodings: []int32{encodingPlain}}
+
+	body := make([]byte, 0, 8*len(values))
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		body = appendPlainDouble(body, v)
+		// NaN doesn't have an order, so it is excluded from the statistics
+		if !math.IsNaN(v) {
+			minValue, maxValue = min(minValue, v), max(maxValue, v)
+		}
+	}
+	if minValue <= maxValue {
+		c.minValue = appendPlainDouble(nil, minValue)
+		c.maxValue = appendPlainDouble(nil, maxValue)
+	}
+	return c, fw.writeDataPage(&c, len(values), encodingPlain, body)
+}
+
+// addRowGroup registers a row group made of the already written chunks.
+func (fw *fileWriter) addRowGroup(numRows int, chunks []columnChunk) {
+	fw.rowGroups = append(fw.rowGroups, rowGroup{chunks: chunks, numRows: int64(numRows)})
+	fw.numRows += int64(numRows)
+}
+
+// close writes the file's footer with the key-value metadata,
+// it doesn't close the underlying writer.
+func (fw *fileWriter) close(keyValues [][2]string, createdBy string) error {
+	tw := newThriftWriter()
+	tw.i32(1, 1)
+
+	tw.list(2, thriftStruct, len(fw.columns)+1)
+	tw.beginStruct()
+	tw.binary(4, []byte("schema"))
+	tw.i32(5, int32(len(fw.columns))) //nolint:gosec
+	tw.endStruct()
+	for _, spec := range fw.columns {
+		writeSchemaElement(tw, spec)
+	}
+
+	tw.i64(3, fw.numRows)
+	tw.list(4, thriftStruct, len(fw.rowGroups))
+	for _, rg := range fw.rowGroups {
+		fw.writeRowGroup(tw, rg)
+	}
+
+	if len(keyValues) > 0 {
+		tw.list(5, thriftStruct, len(keyValues))
+		for _, kv := range keyValues {
+			tw.beginStruct()
+			tw.binary(1, []byte(kv[0]))
+			tw.binary(2, []byte(kv[1]))
+			tw.endStruct()
+		}
+	}
+	tw.binary(6, []byte(createdBy))
+
+	// all the columns use the order defined by their type, it's required
+	// for the readers to trust the min and max statistics
+	tw.list(7, thriftStruct, len(fw.columns))
+	for range fw.columns {
+		tw.beginStruct()
+		tw.structField(1)
+		tw.endStruct()
+		tw.endStruct()
+	}
+	tw.endStruct()
+
+	footer := tw.buf
+	footer = append(footer, byte(len(footer)), byte(len(footer)>>8), byte(len(footer)>>16), byte(len(footer)>>24))
+	footer = append(footer, magic...)
+	return fw.write(footer)
+}
+
+func writeSchemaElement(tw *thriftWriter, spec columnSpec) {
+	tw.beginStruct()
+	defer tw.endStruct()
+
+	switch spec.kind {
+	case kindString, kindJSON:
+		tw.i32(1, typeByteArray)
+	case kindTimestamp:
+		tw.i32(1, typeInt64)
+	case kindDouble:
+		tw.i32(1, typeDouble)
+	}
+	if spec.optional {
+		tw.i32(3, repetitionOptional)
+	} else {
+		tw.i32(3, repetitionRequired)
+	}
+	tw.binary(4, []byte(spec.name))
+
+	switch spec.kind {
+	case kindString:
+		tw.i32(6, convertedUTF8)
+		tw.structField(10)
+		tw.structField(1) // STRING
+		tw.endStruct()
+		tw.endStruct()
+	case kindJSON:
+		tw.i32(6, convertedJSON)
+		tw.structField(10)
+		tw.structField(12) // JSON
+		tw.endStruct()
+		tw.endStruct()
+	case kindTimestamp:
+		tw.i32(6, convertedTimestampMicros)
+		tw.structField(10)
+		tw.structField(8) // TIMESTAMP
+		tw.bool(1, true)  // isAdjustedToUTC
+		tw.structField(2) // unit
+		tw.structField(2) // MICROS
+		tw.endStruct()
+		tw.endStruct()
+		tw.endStruct()
+		tw.endStruct()
+	case kindDouble:
+	}
+}
+
+func (fw *fileWriter) writeRowGroup(tw *thriftWriter, rg rowGroup) {
+	tw.beginStruct()
+	defer tw.endStruct()
+
+	var uncompressed, compressed int64
+	tw.list(1, thriftStruct, len(rg.chunks))
+	for _, c := range rg.chunks {
+		uncompressed += c.uncompressedSize
+		compressed += c.compressedSize
+		fw.writeColumnChunk(tw, c)
+	}
+	tw.i64(2, uncompressed)
+	tw.i64(3, rg.numRows)
+	tw.i64(5, rg.chunks[0].startOffset())
+	tw.i64(6, compressed)
+}
+
+// startOffset returns the offset of the chunk's first page, the dictionary
+// page offset is zero for the chunks without it, as it is the file's magic.
+func (c columnChunk) startOffset() int64 {
+	if c.dictionaryPageOffset > 0 {
+		return c.dictionaryPageOffset
+	}
+	return c.dataPageOffset
+}
+
+func (fw *fileWriter) writeColumnChunk(tw *thriftWriter, c columnChunk) {
+	tw.beginStruct()
+	defer tw.endStruct()
+
+	tw.i64(2, c.startOffset())
+	tw.structField(3)
+	defer tw.endStruct()
+
+	switch c.spec.kind {
+	case kindString, kindJSON:
+		tw.i32(1, typeByteArray)
+	case kindTimestamp:
+		tw.i32(1, typeInt64)
+	case kindDouble:
+		tw.i32(1, typeDouble)
+	}
+	tw.list(2, thriftI32, len(c.encodings))
+	for _, e := range c.encodings {
+		tw.varint(int64(e))
+	}
+	tw.list(3, thriftBinary, 1)
+	tw.binaryValue([]byte(c.spec.name))
+	tw.i32(4, fw.codec)
+	tw.i64(5, c.numValues)
+	tw.i64(6, c.uncompressedSize)
+	tw.i64(7, c.compressedSize)
+	tw.i64(9, c.dataPageOffset)
+	if c.dictionaryPageOffset > 0 {
+		tw.i64(11, c.dictionaryPageOffset)
+	}
+
+	tw.structField(12)
+	tw.i64(3, c.nullCount)
+	if c.maxValue != nil {
+		tw.binary(5, c.maxValue)
+		tw.binary(6, c.minValue)
+	}
+	tw.endStruct()
+}
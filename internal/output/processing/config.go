@@ -0,0 +1,121 @@
+package processing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// The actions of the rules.
+const (
+	ActionDrop       = "drop"
+	ActionKeep       = "keep"
+	ActionRename     = "rename"
+	ActionReplaceTag = "replaceTag"
+	ActionHashTag    = "hashTag"
+	ActionDropTag    = "dropTag"
+)
+
+// Config is the list of rules of each output type.
+type Config map[string][]Rule
+
+// Rule is a step processing the samples. The Metric and Tags matchers restrict
+// the samples it applies to, they are regular expressions matching the whole
+// metric's name and tags' values, and a sample without one of the tags doesn't match.
+type Rule struct {
+	Action string            `json:"action"`
+	Metric string            `json:"metric"`
+	Tags   map[string]string `json:"tags"`
+
+	// To is the new name of the metric for the rename action, it can refer to
+	// the submatches of the Metric matcher, like $1.
+	To string `json:"to"`
+
+	// Tag is the tag changed by the replaceTag, hashTag and dropTag actions.
+	Tag string `json:"tag"`
+
+	// Regex and Replacement are used by the replaceTag action to replace
+	// all of the matches in the tag's value, the replacement can refer to the submatches.
+	Regex       string `json:"regex"`
+	Replacement string `json:"replacement"`
+}
+
+// ParseConfig parses the outputProcessing option, an empty one results in a nil Config.
+func ParseConfig(data json.RawMessage) (Config, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var config Config
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("couldn't parse the outputProcessing option: %w", err)
+	}
+	return config, nil
+}
+
+// compiledRule is a validated Rule with its regular expressions compiled.
+type compiledRule struct {
+	Rule
+
+	metric *regexp.Regexp
+	tags   map[string]*regexp.Regexp
+	regex  *regexp.Regexp
+}
+
+func compileRule(r Rule) (*compiledRule, error) {
+	cr := &compiledRule{Rule: r}
+
+	var err error
+	if r.Metric != "" {
+		if cr.metric, err = compileMatcher(r.Metric); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.Tags) > 0 {
+		cr.tags = make(map[string]*regexp.Regexp, len(r.Tags))
+		for tag, expr := range r.Tags {
+			if cr.tags[tag], err = compileMatcher(expr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	switch r.Action {
+	case ActionDrop, ActionKeep:
+		if cr.metric == nil && cr.tags == nil {
+			return nil, fmt.Errorf("the %s action needs a metric or tags matcher", r.Action)
+		}
+	case ActionRename:
+		if cr.metric == nil || r.To == "" {
+			return nil, errors.New("the rename action needs a metric matcher and the new name in to")
+		}
+	case ActionReplaceTag:
+		if r.Tag == "" || r.Regex == "" {
+			return nil, errors.New("the replaceTag action needs a tag and a regex")
+		}
+		if cr.regex, err = regexp.Compile(r.Regex); err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", r.Regex, err)
+		}
+	case ActionHashTag, ActionDropTag:
+		if r.Tag == "" {
+			return nil, fmt.Errorf("the %s action needs a tag", r.Action)
+		}
+	default:
+		return nil, fmt.Errorf("unknown action %q, the supported ones are %s, %s, %s, %s, %s and %s", r.Action,
+			ActionDrop, ActionKeep, ActionRename, ActionReplaceTag, ActionHashTag, ActionDropTag)
+	}
+	return cr, nil
+}
+
+// compileMatcher compiles the regular expression to match the whole string.
+func compileMatcher(expr string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid matcher %q: %w", expr, err)
+	}
+	return re, nil
+}
@@ -0,0 +1,23 @@
+// Package processing implements the outputProcessing option, a list of rules
+// processing the samples before they are sent to an output, for example to
+// reduce the cardinality of the time series:
+//
+//	export const options = {
+//	  outputProcessing: {
+//	    "experimental-prometheus-rw": [
+//	      { action: "drop", metric: "http_req_(blocked|connecting|tls_handshaking)" },
+//	      { action: "keep", tags: { expected_response: "true" } },
+//	      { action: "rename", metric: "http_req_(.*)", to: "request_$1" },
+//	      { action: "replaceTag", tag: "url", regex: "/users/\\d+", replacement: "/users/{id}" },
+//	      { action: "hashTag", tag: "name" },
+//	      { action: "dropTag", tag: "vu" },
+//	    ],
+//	  },
+//	};
+//
+// The rules are applied in order, each one to the samples matching its metric
+// and tags matchers: drop removes them, keep removes the other ones, rename
+// changes the metric, replaceTag replaces the matches of a regular expression
+// in a tag's value, hashTag replaces a tag's value with the beginning of its
+// SHA-256 hash, and dropTag removes a tag.
+package processing
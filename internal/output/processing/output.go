@@ -0,0 +1,52 @@
+package processing
+
+import (
+	"go.k6.io/k6/v2/metrics"
+	"go.k6.io/k6/v2/output"
+)
+
+// Output is an output.Output sending the samples processed by a Processor to
+// the output it wraps.
+type Output struct {
+	output.Output
+	processor *Processor
+}
+
+var (
+	_ output.WithTestRunStop       = (*Output)(nil)
+	_ output.WithStopWithTestError = (*Output)(nil)
+)
+
+// NewOutput returns an Output applying the processor's rules to the samples
+// of out. The optional interfaces of out have to be used before it is wrapped,
+// except for the ones used by the output.Manager, which are forwarded.
+func NewOutput(out output.Output, processor *Processor) *Output {
+	return &Output{Output: out, processor: processor}
+}
+
+// Unwrap returns the wrapped output.
+func (o *Output) Unwrap() output.Output {
+	return o.Output
+}
+
+// AddMetricSamples sends the processed samples to the wrapped output.
+func (o *Output) AddMetricSamples(samples []metrics.SampleContainer) {
+	o.Output.AddMetricSamples(o.processor.Process(samples))
+}
+
+// SetTestRunStopCallback forwards the callback to the wrapped output, if it
+// can stop the test run.
+func (o *Output) SetTestRunStopCallback(callback func(error)) {
+	if out, ok := o.Output.(output.WithTestRunStop); ok {
+		out.SetTestRunStopCallback(callback)
+	}
+}
+
+// StopWithTestError stops the wrapped output, with the test run's error if it
+// receives it.
+func (o *Output) StopWithTestError(testRunErr error) error {
+	if out, ok := o.Output.(output.WithStopWithTestError); ok {
+		return out.StopWithTestError(testRunErr)
+	}
+	return o.Output.Stop()
+}
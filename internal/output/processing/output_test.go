@@ -0,0 +1,85 @@
+package processing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/internal/lib/testutils/mockoutput"
+	"go.k6.io/k6/v2/metrics"
+)
+
+// stoppingOutput records the test run's error it is stopped with.
+type stoppingOutput struct {
+	*mockoutput.MockOutput
+	callback func(error)
+	testErr  error
+}
+
+func (o *stoppingOutput) SetTestRunStopCallback(callback func(error)) { o.callback = callback }
+
+func (o *stoppingOutput) StopWithTestError(testErr error) error {
+	o.testErr = testErr
+	return nil
+}
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	vus := registry.MustNewMetric("vus", metrics.Gauge)
+	iterations := registry.MustNewMetric("iterations", metrics.Counter)
+	p, err := New([]Rule{{Action: "drop", Metric: "vus"}}, registry, testutils.NewLogger(t))
+	require.NoError(t, err)
+
+	t.Run("processes the samples", func(t *testing.T) {
+		t.Parallel()
+
+		mock := mockoutput.New()
+		out := NewOutput(mock, p)
+		sample := func(m *metrics.Metric) metrics.Sample {
+			return metrics.Sample{
+				TimeSeries: metrics.TimeSeries{Metric: m, Tags: registry.RootTagSet()},
+				Time:       time.Now(),
+				Value:      1,
+			}
+		}
+		out.AddMetricSamples([]metrics.SampleContainer{metrics.Samples{sample(vus), sample(iterations)}})
+
+		require.Len(t, mock.Samples, 1)
+		assert.Equal(t, iterations, mock.Samples[0].Metric)
+		assert.Same(t, mock, out.Unwrap())
+	})
+
+	t.Run("forwards the stop interfaces", func(t *testing.T) {
+		t.Parallel()
+
+		wrapped := &stoppingOutput{MockOutput: mockoutput.New()}
+		out := NewOutput(wrapped, p)
+		out.SetTestRunStopCallback(func(error) {})
+		assert.NotNil(t, wrapped.callback)
+
+		testErr := errors.New("test error")
+		require.NoError(t, out.StopWithTestError(testErr))
+		assert.Equal(t, testErr, wrapped.testErr)
+	})
+
+	t.Run("stops the outputs without the stop interfaces", func(t *testing.T) {
+		t.Parallel()
+
+		stopped := false
+		mock := mockoutput.New()
+		mock.StopFn = func() error {
+			stopped = true
+			return nil
+		}
+		out := NewOutput(mock, p)
+		out.SetTestRunStopCallback(func(error) {})
+		require.NoError(t, out.StopWithTestError(nil))
+		assert.True(t, stopped)
+	})
+}
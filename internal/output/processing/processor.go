@@ -0,0 +1,193 @@
+package processing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/v2/lib/netext/httpext"
+	"go.k6.io/k6/v2/metrics"
+)
+
+// hashLength is the number of hexadecimal characters kept from the tags' values hashes.
+const hashLength = 16
+
+// Processor applies the rules, in order, to the samples sent to an output.
+// It isn't safe for concurrent use, the output.Manager sends the samples to an
+// [Output] from a single goroutine.
+type Processor struct {
+	registry *metrics.Registry
+	logger   logrus.FieldLogger
+	rules    []*compiledRule
+
+	// the results of the metric matchers and the renamed metrics, by rule
+	metricMatches []map[*metrics.Metric]bool
+	renamed       []map[*metrics.Metric]*metrics.Metric
+}
+
+// New validates the rules and returns a Processor applying them. The renamed
+// metrics are created in the registry, with the type of the original ones.
+func New(rules []Rule, registry *metrics.Registry, logger logrus.FieldLogger) (*Processor, error) {
+	p := &Processor{
+		registry:      registry,
+		logger:        logger,
+		rules:         make([]*compiledRule, 0, len(rules)),
+		metricMatches: make([]map[*metrics.Metric]bool, len(rules)),
+		renamed:       make([]map[*metrics.Metric]*metrics.Metric, len(rules)),
+	}
+	for i, r := range rules {
+		cr, err := compileRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule #%d: %w", i+1, err)
+		}
+		p.rules = append(p.rules, cr)
+		p.metricMatches[i] = make(map[*metrics.Metric]bool)
+		p.renamed[i] = make(map[*metrics.Metric]*metrics.Metric)
+	}
+	return p, nil
+}
+
+// Process returns the processed samples. The containers without any changed
+// sample are returned as they are, and the ones without any remaining sample
+// are dropped. The other ones keep their type, see rebuildContainer.
+func (p *Processor) Process(containers []metrics.SampleContainer) []metrics.SampleContainer {
+	result := make([]metrics.SampleContainer, 0, len(containers))
+	for _, container := range containers {
+		samples := container.GetSamples()
+
+		var processed metrics.Samples
+		changed := false
+		for i, sample := range samples {
+			ps, keep := p.processSample(sample)
+			if !changed && (!keep || ps.Metric != sample.Metric || ps.Tags != sample.Tags) {
+				changed = true
+				processed = make(metrics.Samples, i, len(samples))
+				copy(processed, samples[:i])
+			}
+			if changed && keep {
+				processed = append(processed, ps)
+			}
+		}
+
+		switch {
+		case !changed:
+			result = append(result, container)
+		case len(processed) > 0:
+			result = append(result, rebuildContainer(container, processed))
+		}
+	}
+	return result
+}
+
+// rebuildContainer returns a container of the same type as the original one with the
+// processed samples, for the outputs relying on the grouping of the connected samples.
+// Their shared tags are the ones of the processed samples if they all have the same,
+// and the original ones otherwise. Other container types are replaced by metrics.Samples.
+func rebuildContainer(container metrics.SampleContainer, processed metrics.Samples) metrics.SampleContainer {
+	switch c := container.(type) {
+	case metrics.Sample:
+		return processed[0]
+	case *httpext.Trail:
+		trail := *c
+		trail.Samples = processed
+		trail.Tags = sharedTags(c.Tags, processed)
+		return &trail
+	case metrics.ConnectedSampleContainer:
+		return metrics.ConnectedSamples{
+			Samples: processed,
+			Tags:    sharedTags(c.GetTags(), processed),
+			Time:    c.GetTime(),
+		}
+	default:
+		return processed
+	}
+}
+
+// sharedTags returns the tags of the samples if they all have the same, or else the given ones.
+func sharedTags(tags *metrics.TagSet, samples metrics.Samples) *metrics.TagSet {
+	for _, sample := range samples[1:] {
+		if sample.Tags != samples[0].Tags {
+			return tags
+		}
+	}
+	return samples[0].Tags
+}
+
+// processSample returns the processed sample, and false if it's dropped.
+func (p *Processor) processSample(sample metrics.Sample) (metrics.Sample, bool) {
+	for i, r := range p.rules {
+		matches := p.matches(i, sample)
+		if r.Action == ActionKeep {
+			if !matches {
+				return sample, false
+			}
+			continue
+		}
+		if !matches {
+			continue
+		}
+
+		switch r.Action {
+		case ActionDrop:
+			return sample, false
+		case ActionRename:
+			sample.Metric = p.rename(i, sample.Metric)
+		case ActionReplaceTag:
+			if v, ok := sample.Tags.Get(r.Tag); ok {
+				sample.Tags = sample.Tags.With(r.Tag, r.regex.ReplaceAllString(v, r.Replacement))
+			}
+		case ActionHashTag:
+			if v, ok := sample.Tags.Get(r.Tag); ok {
+				sum := sha256.Sum256([]byte(v))
+				sample.Tags = sample.Tags.With(r.Tag, hex.EncodeToString(sum[:])[:hashLength])
+			}
+		case ActionDropTag:
+			sample.Tags = sample.Tags.Without(r.Tag)
+		}
+	}
+	return sample, true
+}
+
+// matches returns true if the sample matches the rule's metric and tags matchers.
+func (p *Processor) matches(i int, sample metrics.Sample) bool {
+	r := p.rules[i]
+	if r.metric != nil {
+		matches, ok := p.metricMatches[i][sample.Metric]
+		if !ok {
+			matches = r.metric.MatchString(sample.Metric.Name)
+			p.metricMatches[i][sample.Metric] = matches
+		}
+		if !matches {
+			return false
+		}
+	}
+	for tag, re := range r.tags {
+		v, ok := sample.Tags.Get(tag)
+		if !ok || !re.MatchString(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// rename returns the metric renamed by the rule, or the original one
+// if the new metric can't be created, e.g. if its name is already used
+// by a metric of another type.
+func (p *Processor) rename(i int, m *metrics.Metric) *metrics.Metric {
+	if renamed, ok := p.renamed[i][m]; ok {
+		return renamed
+	}
+
+	r := p.rules[i]
+	name := r.metric.ReplaceAllString(m.Name, r.To)
+	renamed, err := p.registry.NewMetric(name, m.Type, m.Contains)
+	if err != nil {
+		p.logger.WithError(err).Warnf("Couldn't rename the metric %q to %q, the samples keep the original name",
+			m.Name, name)
+		renamed = m
+	}
+	p.renamed[i][m] = renamed
+	return renamed
+}
@@ -0,0 +1,169 @@
+package processing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/lib/netext/httpext"
+	"go.k6.io/k6/v2/metrics"
+)
+
+func TestProcess(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	duration := registry.MustNewMetric("http_req_duration", metrics.Trend, metrics.Time)
+	blocked := registry.MustNewMetric("http_req_blocked", metrics.Trend, metrics.Time)
+	vus := registry.MustNewMetric("vus", metrics.Gauge)
+	registry.MustNewMetric("request_waiting", metrics.Counter)
+	waiting := registry.MustNewMetric("http_req_waiting", metrics.Trend, metrics.Time)
+
+	config, err := ParseConfig([]byte(`{"csv": [
+		{"action": "drop", "metric": "http_req_blocked"},
+		{"action": "keep", "tags": {"status": "2..|3.."}},
+		{"action": "rename", "metric": "http_req_(.*)", "to": "request_$1"},
+		{"action": "replaceTag", "tag": "url", "regex": "/users/\\d+", "replacement": "/users/{id}"},
+		{"action": "hashTag", "tag": "name"},
+		{"action": "dropTag", "tag": "vu", "metric": "request_.*"}
+	]}`))
+	require.NoError(t, err)
+	p, err := New(config["csv"], registry, testutils.NewLogger(t))
+	require.NoError(t, err)
+
+	now := time.Now()
+	sample := func(m *metrics.Metric, tags map[string]string) metrics.Sample {
+		return metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: m, Tags: registry.RootTagSet().WithTagsFromMap(tags)},
+			Time:       now,
+			Value:      1,
+		}
+	}
+	unchanged := metrics.Samples{sample(vus, map[string]string{"status": "200"})}
+	containers := []metrics.SampleContainer{
+		unchanged,
+		metrics.Samples{
+			sample(blocked, map[string]string{"status": "200"}),
+			sample(duration, map[string]string{"status": "500"}),
+		},
+		metrics.Samples{
+			sample(vus, map[string]string{"status": "301"}),
+			sample(duration, map[string]string{
+				"status": "200", "url": "https://k6.io/users/42/posts", "name": "users", "vu": "1",
+			}),
+			sample(waiting, map[string]string{"status": "200", "vu": "1"}),
+		},
+	}
+
+	processed := p.Process(containers)
+	require.Len(t, processed, 2)
+	assert.Equal(t, unchanged, processed[0])
+
+	samples := processed[1].GetSamples()
+	require.Len(t, samples, 3)
+	assert.Equal(t, containers[2].GetSamples()[0], samples[0])
+
+	assert.Equal(t, "request_duration", samples[1].Metric.Name)
+	assert.Equal(t, metrics.Trend, samples[1].Metric.Type)
+	assert.Equal(t, metrics.Time, samples[1].Metric.Contains)
+	assert.Equal(t, map[string]string{
+		"status": "200", "url": "https://k6.io/users/{id}/posts", "name": "7dfb4cf67742cb06",
+	}, samples[1].Tags.Map())
+
+	// request_waiting is already a counter, the renaming fails
+	assert.Equal(t, waiting, samples[2].Metric)
+	assert.Equal(t, map[string]string{"status": "200", "vu": "1"}, samples[2].Tags.Map())
+
+	// the original samples are unchanged
+	assert.Equal(t, duration, containers[2].GetSamples()[1].Metric)
+	assert.Equal(t, "https://k6.io/users/42/posts", containers[2].GetSamples()[1].Tags.Map()["url"])
+}
+
+func TestProcessContainerTypes(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+	reqs := registry.MustNewMetric("http_reqs", metrics.Counter)
+	blocked := registry.MustNewMetric("http_req_blocked", metrics.Trend, metrics.Time)
+
+	config, err := ParseConfig([]byte(`{"csv": [
+		{"action": "drop", "metric": "http_req_blocked"},
+		{"action": "hashTag", "tag": "name"}
+	]}`))
+	require.NoError(t, err)
+	p, err := New(config["csv"], registry, testutils.NewLogger(t))
+	require.NoError(t, err)
+
+	now := time.Now()
+	tags := registry.RootTagSet().With("name", "users")
+	hashedTags := registry.RootTagSet().With("name", "7dfb4cf67742cb06")
+	samples := []metrics.Sample{
+		{TimeSeries: metrics.TimeSeries{Metric: reqs, Tags: tags}, Time: now, Value: 1},
+		{TimeSeries: metrics.TimeSeries{Metric: blocked, Tags: tags}, Time: now, Value: 2},
+	}
+	trail := &httpext.Trail{EndTime: now, Duration: time.Second, Tags: tags, Samples: samples}
+	containers := []metrics.SampleContainer{
+		metrics.ConnectedSamples{Samples: samples, Tags: tags, Time: now},
+		trail,
+		samples[0],
+		samples[1],
+	}
+
+	processed := p.Process(containers)
+	require.Len(t, processed, 3)
+
+	connected, ok := processed[0].(metrics.ConnectedSamples)
+	require.True(t, ok)
+	require.Len(t, connected.Samples, 1)
+	assert.Equal(t, reqs, connected.Samples[0].Metric)
+	assert.Equal(t, hashedTags, connected.Tags)
+	assert.Equal(t, now, connected.Time)
+
+	processedTrail, ok := processed[1].(*httpext.Trail)
+	require.True(t, ok)
+	require.Len(t, processedTrail.Samples, 1)
+	assert.Equal(t, hashedTags, processedTrail.Tags)
+	assert.Equal(t, time.Second, processedTrail.Duration)
+	assert.Equal(t, tags, trail.Tags, "the original trail is unchanged")
+	assert.Len(t, trail.Samples, 2, "the original trail is unchanged")
+
+	sample, ok := processed[2].(metrics.Sample)
+	require.True(t, ok)
+	assert.Equal(t, hashedTags, sample.Tags)
+}
+
+func TestInvalidRules(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config string
+		err    string
+	}{
+		"unknown field":  {`{"csv": [{"action": "drop", "metrics": "vus"}]}`, `unknown field "metrics"`},
+		"unknown action": {`{"csv": [{"action": "filter", "metric": "vus"}]}`, `unknown action "filter"`},
+		"drop all":       {`{"csv": [{"action": "drop"}]}`, "the drop action needs a metric or tags matcher"},
+		"invalid metric": {`{"csv": [{"action": "keep", "metric": "("}]}`, `invalid matcher "("`},
+		"invalid regex": {
+			`{"csv": [{"action": "replaceTag", "tag": "url", "regex": "["}]}`, `invalid regex "["`,
+		},
+		"rename without name": {
+			`{"csv": [{"action": "rename", "metric": "vus"}]}`, "the rename action needs a metric matcher",
+		},
+		"hash without tag": {`{"csv": [{"action": "hashTag"}]}`, "the hashTag action needs a tag"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := ParseConfig([]byte(tc.config))
+			if err == nil {
+				_, err = New(config["csv"], metrics.NewRegistry(), testutils.NewLogger(t))
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package prometheuspull
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mstoykov/envconfig"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/lib/types"
+)
+
+const (
+	defaultAddress            = "localhost:9464"
+	defaultPath               = "/metrics"
+	defaultFlushInterval      = time.Second
+	defaultStaleSeriesTimeout = 5 * time.Minute
+)
+
+// defaultTrendBuckets are the upper bounds of the classic histograms' buckets,
+// in the base unit of the values (seconds for time values).
+//
+//nolint:gochecknoglobals
+var defaultTrendBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// Config contains the configuration for the Output.
+type Config struct {
+	// Address is the host:port where the scrape endpoint listens.
+	Address null.String `json:"address" envconfig:"K6_PROMETHEUS_PULL_ADDRESS"`
+
+	// Path is the HTTP path of the scrape endpoint.
+	Path null.String `json:"path" envconfig:"K6_PROMETHEUS_PULL_PATH"`
+
+	// FlushInterval defines how often the buffered samples are aggregated,
+	// a scrape always aggregates the pending samples before responding.
+	FlushInterval types.NullDuration `json:"flushInterval" envconfig:"K6_PROMETHEUS_PULL_FLUSH_INTERVAL"`
+
+	// TrendAsNativeHistogram defines if the Trend metrics should be exposed
+	// as Prometheus' Native Histograms instead of classic histograms.
+	TrendAsNativeHistogram null.Bool `json:"trendAsNativeHistogram" envconfig:"K6_PROMETHEUS_PULL_TREND_AS_NATIVE_HISTOGRAM"`
+
+	// TrendBuckets are the upper bounds of the classic histograms' buckets
+	// for the Trend metrics, in seconds for time values.
+	TrendBuckets []float64 `json:"trendBuckets" envconfig:"K6_PROMETHEUS_PULL_TREND_BUCKETS"`
+
+	// StaleSeriesTimeout is how long a time series is still exposed after
+	// its last sample. It prevents the short-lived tag sets (e.g. the ones
+	// with an URL containing an ID) from growing the exposition forever.
+	// Zero disables it, so the time series are exposed until the end.
+	StaleSeriesTimeout types.NullDuration `json:"staleSeriesTimeout" envconfig:"K6_PROMETHEUS_PULL_STALE_SERIES_TIMEOUT"`
+}
+
+// NewConfig creates an Output's configuration.
+func NewConfig() Config {
+	return Config{
+		Address:                null.StringFrom(defaultAddress),
+		Path:                   null.StringFrom(defaultPath),
+		FlushInterval:          types.NullDurationFrom(defaultFlushInterval),
+		TrendAsNativeHistogram: null.BoolFrom(false),
+		TrendBuckets:           defaultTrendBuckets,
+		StaleSeriesTimeout:     types.NullDurationFrom(defaultStaleSeriesTimeout),
+	}
+}
+
+// Apply merges applied Config into base.
+func (conf Config) Apply(applied Config) Config {
+	if applied.Address.Valid {
+		conf.Address = applied.Address
+	}
+
+	if applied.Path.Valid {
+		conf.Path = applied.Path
+	}
+
+	if applied.FlushInterval.Valid {
+		conf.FlushInterval = applied.FlushInterval
+	}
+
+	if applied.TrendAsNativeHistogram.Valid {
+		conf.TrendAsNativeHistogram = applied.TrendAsNativeHistogram
+	}
+
+	if len(applied.TrendBuckets) > 0 {
+		conf.TrendBuckets = slices.Clone(applied.TrendBuckets)
+	}
+
+	if applied.StaleSeriesTimeout.Valid {
+		conf.StaleSeriesTimeout = applied.StaleSeriesTimeout
+	}
+
+	return conf
+}
+
+// Validate checks that the configuration is usable.
+func (conf Config) Validate() error {
+	if conf.Address.String == "" {
+		return errors.New("the address can't be empty")
+	}
+	if !strings.HasPrefix(conf.Path.String, "/") {
+		return fmt.Errorf("the path must start with a slash, got %q", conf.Path.String)
+	}
+	if conf.FlushInterval.Duration <= 0 {
+		return errors.New("the flush interval must be positive")
+	}
+	if conf.StaleSeriesTimeout.Duration < 0 {
+		return errors.New("the stale series timeout can't be negative")
+	}
+	if !slices.IsSorted(conf.TrendBuckets) {
+		return errors.New("the trend buckets must be sorted in increasing order")
+	}
+	return nil
+}
+
+// GetConsolidatedConfig combines the options' values from the different sources
+// and returns the merged options. The Order of precedence used is documented
+// in the k6 Documentation https://k6.io/docs/using-k6/k6-options/how-to/#order-of-precedence.
+func GetConsolidatedConfig(jsonRawConf json.RawMessage, env map[string]string, arg string) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		var jsonConf Config
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, fmt.Errorf("parse JSON options failed: %w", err)
+		}
+		result = result.Apply(jsonConf)
+	}
+
+	if len(env) > 0 {
+		var envConf Config
+		err := envconfig.Process("", &envConf, func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		})
+		if err != nil {
+			return result, fmt.Errorf("parse environment variables options failed: %w", err)
+		}
+		result = result.Apply(envConf)
+	}
+
+	if arg != "" {
+		argConf, err := parseArg(arg)
+		if err != nil {
+			return result, fmt.Errorf("parse argument string options failed: %w", err)
+		}
+		result = result.Apply(argConf)
+	}
+
+	return result, result.Validate()
+}
+
+// parseArg parses the supplied string of arguments into a Config.
+// A value without a key is the address, so `--out experimental-prometheus-pull=:9464`
+// works as expected.
+func parseArg(text string) (Config, error) {
+	var c Config
+	for opt := range strings.SplitSeq(text, ",") {
+		key, v, found := strings.Cut(opt, "=")
+		if !found {
+			c.Address = null.StringFrom(opt)
+			continue
+		}
+		switch key {
+		case "address":
+			c.Address = null.StringFrom(v)
+		case "path":
+			c.Path = null.StringFrom(v)
+		case "flushInterval":
+			if err := c.FlushInterval.UnmarshalText([]byte(v)); err != nil {
+				return c, fmt.Errorf("flushInterval must be a valid duration, not %q", v)
+			}
+		case "trendAsNativeHistogram":
+			if err := c.TrendAsNativeHistogram.UnmarshalText([]byte(v)); err != nil {
+				return c, fmt.Errorf("trendAsNativeHistogram value must be true or false, not %q", v)
+			}
+		case "trendBuckets":
+			// the comma is already used as the options' separator
+			for bucket := range strings.SplitSeq(v, ";") {
+				f, err := strconv.ParseFloat(bucket, 64)
+				if err != nil {
+					return c, fmt.Errorf("trendBuckets must be a semicolon separated list of numbers, not %q", v)
+				}
+				c.TrendBuckets = append(c.TrendBuckets, f)
+			}
+		case "staleSeriesTimeout":
+			if err := c.StaleSeriesTimeout.UnmarshalText([]byte(v)); err != nil {
+				return c, fmt.Errorf("staleSeriesTimeout must be a valid duration, not %q", v)
+			}
+		default:
+			return c, fmt.Errorf("%q is an unknown option's key", key)
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,100 @@
+package prometheuspull
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/lib/types"
+)
+
+func TestGetConsolidatedConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		jsonRaw json.RawMessage
+		env     map[string]string
+		arg     string
+		config  Config
+		errMsg  string
+	}{
+		"Defaults": {
+			config: NewConfig(),
+		},
+		"JSONSuccess": {
+			jsonRaw: json.RawMessage(`{"address":"0.0.0.0:9000","trendBuckets":[0.1,1],"staleSeriesTimeout":"1m"}`),
+			config: NewConfig().Apply(Config{
+				Address:            null.StringFrom("0.0.0.0:9000"),
+				TrendBuckets:       []float64{0.1, 1},
+				StaleSeriesTimeout: types.NullDurationFrom(time.Minute),
+			}),
+		},
+		"EnvOverridesJSON": {
+			jsonRaw: json.RawMessage(`{"address":"0.0.0.0:9000","path":"/k6"}`),
+			env: map[string]string{
+				"K6_PROMETHEUS_PULL_ADDRESS":                   ":9001",
+				"K6_PROMETHEUS_PULL_TREND_AS_NATIVE_HISTOGRAM": "true",
+				"K6_PROMETHEUS_PULL_TREND_BUCKETS":             "0.5,1,2",
+				"K6_PROMETHEUS_PULL_STALE_SERIES_TIMEOUT":      "0",
+				"K6_PROMETHEUS_RW_SERVER_URL":                  "ignored",
+				"K6_PROMETHEUS_PULL_FLUSH_INTERVAL":            "2s",
+			},
+			config: NewConfig().Apply(Config{
+				Address:                null.StringFrom(":9001"),
+				Path:                   null.StringFrom("/k6"),
+				FlushInterval:          types.NullDurationFrom(2 * time.Second),
+				TrendAsNativeHistogram: null.BoolFrom(true),
+				TrendBuckets:           []float64{0.5, 1, 2},
+				StaleSeriesTimeout:     types.NullDurationFrom(0),
+			}),
+		},
+		"ArgOverridesEnv": {
+			env: map[string]string{"K6_PROMETHEUS_PULL_ADDRESS": ":9001"},
+			arg: ":9002,path=/scrape,trendBuckets=1;10,staleSeriesTimeout=30s",
+			config: NewConfig().Apply(Config{
+				Address:            null.StringFrom(":9002"),
+				Path:               null.StringFrom("/scrape"),
+				TrendBuckets:       []float64{1, 10},
+				StaleSeriesTimeout: types.NullDurationFrom(30 * time.Second),
+			}),
+		},
+		"UnknownArg": {
+			arg:    "url=http://localhost",
+			errMsg: `"url" is an unknown option's key`,
+		},
+		"InvalidBuckets": {
+			arg:    "trendBuckets=1;a",
+			errMsg: "trendBuckets must be a semicolon separated list of numbers",
+		},
+		"UnsortedBuckets": {
+			arg:    "trendBuckets=10;1",
+			errMsg: "the trend buckets must be sorted in increasing order",
+		},
+		"InvalidPath": {
+			arg:    "path=metrics",
+			errMsg: `the path must start with a slash, got "metrics"`,
+		},
+		"NegativeStaleSeriesTimeout": {
+			env:    map[string]string{"K6_PROMETHEUS_PULL_STALE_SERIES_TIMEOUT": "-1s"},
+			errMsg: "the stale series timeout can't be negative",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := GetConsolidatedConfig(tc.jsonRaw, tc.env, tc.arg)
+			if tc.errMsg != "" {
+				require.ErrorContains(t, err, tc.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.config, c)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Package prometheuspull is a k6 output that exposes the metrics
+// on an HTTP endpoint to be scraped by Prometheus.
+//
+// The metrics are mapped in the same way as the Prometheus remote write output:
+// counters, gauges and rates keep the k6_ prefix and the same suffixes,
+// while the trends are exposed as histograms in the base unit of their values.
+// The native histograms are exposed alongside the classic buckets, so they are
+// only visible to the scrapers negotiating the protobuf exposition format.
+package prometheuspull
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/metrics"
+	"go.k6.io/k6/v2/output"
+)
+
+var (
+	_ output.Output       = new(Output)
+	_ prometheus.Gatherer = new(Output)
+)
+
+// Output is a k6 output that serves the metrics for Prometheus to scrape.
+type Output struct {
+	output.SampleBuffer
+
+	config          Config
+	logger          logrus.FieldLogger
+	now             func() time.Time
+	periodicFlusher *output.PeriodicFlusher
+	server          *http.Server
+	addr            net.Addr
+
+	m      sync.Mutex
+	series map[metrics.TimeSeries]*exposedSeries
+}
+
+// New creates a new Output instance.
+func New(params output.Params) (*Output, error) {
+	config, err := GetConsolidatedConfig(params.JSONConfig, params.Environment, params.ConfigArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	// The native-histograms feature flag maps Trend metrics to Prometheus native
+	// histograms, in the same way as for the remote write output.
+	if params.Features.NativeHistograms {
+		config.TrendAsNativeHistogram = null.BoolFrom(true)
+	}
+
+	return &Output{
+		config: config,
+		logger: params.Logger.WithFields(logrus.Fields{"output": "Prometheus pull"}),
+		now:    time.Now,
+		series: make(map[metrics.TimeSeries]*exposedSeries),
+	}, nil
+}
+
+// Description returns a short human-readable description of the output.
+func (o *Output) Description() string {
+	addr := o.config.Address.String
+	if o.addr != nil {
+		addr = o.addr.String()
+	}
+	return fmt.Sprintf("Prometheus pull (http://%s%s)", addr, o.config.Path.String)
+}
+
+// Start starts listening for the scrapes and aggregating the samples.
+func (o *Output) Start() error {
+	l, err := net.Listen("tcp", o.config.Address.String)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", o.config.Address.String, err)
+	}
+	o.addr = l.Addr()
+
+	mux := http.NewServeMux()
+	mux.Handle(o.config.Path.String, promhttp.HandlerFor(o, promhttp.HandlerOpts{
+		ErrorLog:          o.logger,
+		EnableOpenMetrics: true,
+	}))
+	o.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := o.server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			o.logger.WithError(err).Error("The scrape endpoint stopped unexpectedly")
+		}
+	}()
+
+	d := o.config.FlushInterval.TimeDuration()
+	periodicFlusher, err := output.NewPeriodicFlusher(d, o.flush)
+	if err != nil {
+		_ = o.server.Close()
+		return err
+	}
+	o.periodicFlusher = periodicFlusher
+	o.logger.WithField("addr", o.addr.String()).Debug("Output initialized")
+	return nil
+}
+
+// Stop stops aggregating the samples and shuts down the scrape endpoint.
+func (o *Output) Stop() error {
+	o.logger.Debug("Stopping the output")
+	defer o.logger.Debug("Output stopped")
+	o.periodicFlusher.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return o.server.Shutdown(ctx)
+}
+
+// flush aggregates the buffered samples into the exposed time series.
+func (o *Output) flush() {
+	samplesContainers := o.GetBufferedSamples()
+	if len(samplesContainers) < 1 {
+		return
+	}
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	now := o.now()
+	for _, samplesContainer := range samplesContainers {
+		for _, sample := range samplesContainer.GetSamples() {
+			s, ok := o.series[sample.TimeSeries]
+			if !ok {
+				s = newExposedSeries(sample.TimeSeries, o.config)
+				o.series[sample.TimeSeries] = s
+			}
+			s.add(sample)
+			s.lastSeen = now
+		}
+	}
+}
+
+// Gather implements prometheus.Gatherer, it aggregates the pending samples,
+// drops the stale time series and returns the exposed ones.
+func (o *Output) Gather() ([]*dto.MetricFamily, error) {
+	o.flush()
+
+	o.m.Lock()
+	defer o.m.Unlock()
+
+	o.dropStaleSeries()
+
+	families := make(map[string]*dto.MetricFamily)
+	for _, s := range o.series {
+		family, ok := families[s.name]
+		if !ok {
+			family = &dto.MetricFamily{Name: &s.name, Type: s.metricType().Enum()}
+			families[s.name] = family
+		}
+		family.Metric = append(family.Metric, s.write())
+	}
+
+	result := make([]*dto.MetricFamily, 0, len(families))
+	for _, family := range families {
+		sort.Slice(family.Metric, func(i, j int) bool {
+			return labelsKey(family.Metric[i].Label) < labelsKey(family.Metric[j].Label)
+		})
+		result = append(result, family)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetName() < result[j].GetName()
+	})
+	return result, nil
+}
+
+// dropStaleSeries removes the time series without any sample in the
+// configured timeout. If a dropped time series gets new samples, it starts
+// again from scratch, and Prometheus handles it as a counter reset.
+func (o *Output) dropStaleSeries() {
+	timeout := o.config.StaleSeriesTimeout.TimeDuration()
+	if timeout <= 0 {
+		return
+	}
+	now := o.now()
+	for ts, s := range o.series {
+		if now.Sub(s.lastSeen) > timeout {
+			delete(o.series, ts)
+		}
+	}
+}
+
+func labelsKey(labels []*dto.LabelPair) string {
+	var sb strings.Builder
+	for _, l := range labels {
+		sb.WriteString(l.GetName())
+		sb.WriteByte('=')
+		sb.WriteString(l.GetValue())
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
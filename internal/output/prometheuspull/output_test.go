@@ -0,0 +1,145 @@
+package prometheuspull
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/features"
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/metrics"
+	"go.k6.io/k6/v2/output"
+)
+
+func newOutput(t *testing.T, arg string) *Output {
+	t.Helper()
+
+	o, err := New(output.Params{
+		Logger:         testutils.NewLogger(t),
+		ConfigArgument: arg,
+		Features:       &features.Flags{},
+	})
+	require.NoError(t, err)
+	return o
+}
+
+func TestOutputScrape(t *testing.T) {
+	t.Parallel()
+
+	o := newOutput(t, "127.0.0.1:0,trendBuckets=0.1;1")
+	require.NoError(t, o.Start())
+	t.Cleanup(func() { require.NoError(t, o.Stop()) })
+
+	registry := metrics.NewRegistry()
+	tags := registry.RootTagSet().With("status", "200")
+	sample := func(m *metrics.Metric, v float64) metrics.Sample {
+		return metrics.Sample{TimeSeries: metrics.TimeSeries{Metric: m, Tags: tags}, Time: time.Now(), Value: v}
+	}
+	reqs := registry.MustNewMetric("http_reqs", metrics.Counter)
+	vus := registry.MustNewMetric("vus", metrics.Gauge)
+	checks := registry.MustNewMetric("checks", metrics.Rate)
+	duration := registry.MustNewMetric("http_req_duration", metrics.Trend, metrics.Time)
+
+	o.AddMetricSamples([]metrics.SampleContainer{metrics.Samples{
+		sample(reqs, 1), sample(reqs, 2),
+		sample(vus, 5), sample(vus, 3),
+		sample(checks, 1), sample(checks, 0),
+		sample(duration, 50), sample(duration, 500), sample(duration, 5000),
+	}})
+
+	resp, err := http.Get("http://" + o.addr.String() + "/metrics") //nolint:noctx
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	for _, line := range []string{
+		"# TYPE k6_checks_rate gauge",
+		`k6_checks_rate{status="200"} 0.5`,
+		"# TYPE k6_http_req_duration_seconds histogram",
+		`k6_http_req_duration_seconds_bucket{status="200",le="0.1"} 1`,
+		`k6_http_req_duration_seconds_bucket{status="200",le="1"} 2`,
+		`k6_http_req_duration_seconds_bucket{status="200",le="+Inf"} 3`,
+		`k6_http_req_duration_seconds_sum{status="200"} 5.55`,
+		`k6_http_req_duration_seconds_count{status="200"} 3`,
+		"# TYPE k6_http_reqs_total counter",
+		`k6_http_reqs_total{status="200"} 3`,
+		"# TYPE k6_vus gauge",
+		`k6_vus{status="200"} 3`,
+	} {
+		assert.Contains(t, string(body), line+"\n")
+	}
+}
+
+func TestOutputNativeHistogram(t *testing.T) {
+	t.Parallel()
+
+	o := newOutput(t, "trendAsNativeHistogram=true")
+
+	registry := metrics.NewRegistry()
+	duration := registry.MustNewMetric("http_req_duration", metrics.Trend, metrics.Time)
+	o.AddMetricSamples([]metrics.SampleContainer{metrics.Sample{
+		TimeSeries: metrics.TimeSeries{Metric: duration, Tags: registry.RootTagSet()},
+		Time:       time.Now(),
+		Value:      150,
+	}})
+
+	families, err := o.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, dto.MetricType_HISTOGRAM, families[0].GetType())
+	h := families[0].GetMetric()[0].GetHistogram()
+	assert.Equal(t, uint64(1), h.GetSampleCount())
+	assert.InDelta(t, 0.15, h.GetSampleSum(), 1e-9)
+	assert.NotZero(t, h.GetSchema())
+	assert.NotEmpty(t, h.GetPositiveSpan())
+}
+
+func TestOutputStaleSeries(t *testing.T) {
+	t.Parallel()
+
+	o := newOutput(t, "staleSeriesTimeout=1m")
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	o.now = func() time.Time { return now }
+
+	registry := metrics.NewRegistry()
+	reqs := registry.MustNewMetric("http_reqs", metrics.Counter)
+	add := func(url string) {
+		o.AddMetricSamples([]metrics.SampleContainer{metrics.Sample{
+			TimeSeries: metrics.TimeSeries{Metric: reqs, Tags: registry.RootTagSet().With("url", url)},
+			Time:       now,
+			Value:      1,
+		}})
+	}
+	urls := func() []string {
+		families, err := o.Gather()
+		require.NoError(t, err)
+		var result []string
+		for _, family := range families {
+			for _, m := range family.GetMetric() {
+				result = append(result, m.GetLabel()[0].GetValue())
+			}
+		}
+		return result
+	}
+
+	add("/users/1")
+	add("/users/2")
+	assert.Equal(t, []string{"/users/1", "/users/2"}, urls())
+
+	now = now.Add(time.Minute)
+	add("/users/2")
+	assert.Equal(t, []string{"/users/1", "/users/2"}, urls())
+
+	now = now.Add(time.Second)
+	assert.Equal(t, []string{"/users/2"}, urls())
+
+	now = now.Add(time.Minute)
+	assert.Empty(t, urls())
+}
@@ -0,0 +1,113 @@
+package prometheuspull
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"go.k6.io/k6/v2/internal/output/prometheusrw/remotewrite"
+	"go.k6.io/k6/v2/metrics"
+)
+
+const namelbl = "__name__"
+
+// exposedSeries is a k6 time series aggregated for being exposed
+// as a Prometheus' metric.
+type exposedSeries struct {
+	metrics.TimeSeries
+
+	name   string
+	labels []*dto.LabelPair
+
+	// sink aggregates the counters, gauges and rates.
+	sink metrics.Sink
+	// histogram aggregates the trends.
+	histogram prometheus.Histogram
+
+	// lastSeen is when the time series got its last sample.
+	lastSeen time.Time
+}
+
+func newExposedSeries(series metrics.TimeSeries, config Config) *exposedSeries {
+	var suffix string
+	es := &exposedSeries{TimeSeries: series}
+
+	switch series.Metric.Type {
+	case metrics.Counter:
+		suffix = "total"
+		es.sink = &metrics.CounterSink{}
+	case metrics.Gauge:
+		es.sink = &metrics.GaugeSink{}
+	case metrics.Rate:
+		suffix = "rate"
+		es.sink = &metrics.RateSink{}
+	case metrics.Trend:
+		suffix = remotewrite.BaseUnit(series.Metric.Contains)
+	default:
+		panic(fmt.Sprintf("the output reached an unrecoverable state; unable to recognize processed metric %s's type `%s`",
+			series.Metric.Name, series.Metric.Type))
+	}
+
+	for _, l := range remotewrite.MapSeries(series, suffix) {
+		if l.Name == namelbl {
+			es.name = l.Value
+			continue
+		}
+		es.labels = append(es.labels, &dto.LabelPair{Name: &l.Name, Value: &l.Value})
+	}
+
+	if series.Metric.Type == metrics.Trend {
+		opts := prometheus.HistogramOpts{
+			Name:    es.name,
+			Buckets: config.TrendBuckets,
+		}
+		if config.TrendAsNativeHistogram.Bool {
+			opts.NativeHistogramBucketFactor = remotewrite.NativeHistogramBucketFactor
+		}
+		es.histogram = prometheus.NewHistogram(opts)
+	}
+	return es
+}
+
+func (es *exposedSeries) add(sample metrics.Sample) {
+	if es.histogram != nil {
+		es.histogram.Observe(remotewrite.AdaptUnit(es.Metric.Contains, sample.Value))
+		return
+	}
+	es.sink.Add(sample)
+}
+
+func (es *exposedSeries) metricType() dto.MetricType {
+	switch es.Metric.Type {
+	case metrics.Counter:
+		return dto.MetricType_COUNTER
+	case metrics.Trend:
+		return dto.MetricType_HISTOGRAM
+	default:
+		return dto.MetricType_GAUGE
+	}
+}
+
+//nolint:forcetypeassert
+func (es *exposedSeries) write() *dto.Metric {
+	m := &dto.Metric{}
+	switch es.Metric.Type {
+	case metrics.Counter:
+		v := es.sink.(*metrics.CounterSink).Value
+		m.Counter = &dto.Counter{Value: &v}
+	case metrics.Gauge:
+		v := es.sink.(*metrics.GaugeSink).Value
+		m.Gauge = &dto.Gauge{Value: &v}
+	case metrics.Rate:
+		// pass zero duration here because time is useless for formatting rate
+		v := es.sink.(*metrics.RateSink).Format(0)["rate"]
+		m.Gauge = &dto.Gauge{Value: &v}
+	case metrics.Trend:
+		// it can't fail, the histogram doesn't have any label
+		_ = es.histogram.Write(m)
+	}
+	m.Label = es.labels
+	return m
+}
@@ -52,7 +52,7 @@ func (sink *extendedTrendSink) MapPrompb(series metrics.TimeSeries, t time.Time)
 	tg.CacheNameIndex()
 
 	for stat, statfn := range sink.trendStats {
-		tg.Append(stat, adaptUnit(series.Metric.Contains, statfn(sink.TrendSink)))
+		tg.Append(stat, AdaptUnit(series.Metric.Contains, statfn(sink.TrendSink)))
 	}
 	return tg.series
 }
@@ -117,6 +117,11 @@ func (tg *trendAsGauges) CacheNameIndex() {
 	}
 }
 
+// NativeHistogramBucketFactor is the growth factor of the native histograms
+// buckets. 1.1 is the starting value suggested by Prometheus, it sounds good
+// considering the general purpose it has to address.
+const NativeHistogramBucketFactor = 1.1
+
 type nativeHistogramSink struct {
 	H prometheus.Histogram
 }
@@ -125,12 +130,9 @@ func newNativeHistogramSink(m *metrics.Metric) *nativeHistogramSink {
 	return &nativeHistogramSink{
 		H: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name: m.Name,
-			// 1.1 is the starting value suggested by Prometheus'
-			// It sounds good considering the general purpose
-			// it have to address.
 			// In the future, we could consider to add more tuning
 			// if it will be required.
-			NativeHistogramBucketFactor: 1.1,
+			NativeHistogramBucketFactor: NativeHistogramBucketFactor,
 		}),
 	}
 }
@@ -146,7 +148,7 @@ func (sink *nativeHistogramSink) Add(s metrics.Sample) {
 	// in case of under-second and more relaxed in case of higher values.
 	// If the Value type is not defined any assumption can be done
 	// because the Sample's Value could contains any unit.
-	sink.H.Observe(adaptUnit(s.Metric.Contains, s.Value))
+	sink.H.Observe(AdaptUnit(s.Metric.Contains, s.Value))
 }
 
 // TODO: create a smaller Sink interface for this Output.
@@ -180,7 +182,7 @@ func (*nativeHistogramSink) Merge(_ []byte) error {
 
 // MapPrompb maps the Trend type to the experimental Native Histogram.
 func (sink *nativeHistogramSink) MapPrompb(series metrics.TimeSeries, t time.Time) []*prompb.TimeSeries {
-	suffix := baseUnit(series.Metric.Contains)
+	suffix := BaseUnit(series.Metric.Contains)
 	labels := MapSeries(series, suffix)
 	timestamp := t.UnixMilli()
 
@@ -224,7 +226,9 @@ func toBucketSpanProto(s []*dto.BucketSpan) []*prompb.BucketSpan {
 	return spans
 }
 
-func baseUnit(vt metrics.ValueType) string {
+// BaseUnit returns the suffix for the base unit of the values, as requested
+// by the Prometheus convention.
+func BaseUnit(vt metrics.ValueType) string {
 	switch vt {
 	case metrics.Time:
 		return "seconds"
@@ -235,13 +239,13 @@ func baseUnit(vt metrics.ValueType) string {
 	}
 }
 
-// adaptUnit converts the generated value into the expected base unit
+// AdaptUnit converts the generated value into the expected base unit
 // as requested by the Prometheus convention.
 //
 // Time: converted to seconds from milliseconds.
 // Data: k6 emits it in Bytes so it already fine.
 // Other: use the submitted unit.
-func adaptUnit(vt metrics.ValueType, v float64) float64 {
+func AdaptUnit(vt metrics.ValueType, v float64) float64 {
 	if vt == metrics.Time {
 		return v / 1000
 	}
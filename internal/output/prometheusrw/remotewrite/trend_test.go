@@ -215,7 +215,7 @@ func TestBaseUnit(t *testing.T) {
 		{in: metrics.Data, exp: "bytes"},
 	}
 	for _, tt := range tests {
-		assert.Equal(t, tt.exp, baseUnit(tt.in))
+		assert.Equal(t, tt.exp, BaseUnit(tt.in))
 	}
 }
 
@@ -0,0 +1,107 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mstoykov/envconfig"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/lib/types"
+)
+
+// Config is the config for the sqlite output
+type Config struct {
+	FileName     null.String        `json:"file_name" envconfig:"K6_SQLITE_FILENAME"`
+	SaveInterval types.NullDuration `json:"save_interval" envconfig:"K6_SQLITE_SAVE_INTERVAL"`
+}
+
+// NewConfig creates a new Config instance with default values for some fields.
+func NewConfig() Config {
+	return Config{
+		FileName:     null.NewString("results.db", false),
+		SaveInterval: types.NewNullDuration(1*time.Second, false),
+	}
+}
+
+// Apply merges two configs by overwriting properties in the old config
+func (c Config) Apply(cfg Config) Config {
+	if cfg.FileName.Valid {
+		c.FileName = cfg.FileName
+	}
+	if cfg.SaveInterval.Valid {
+		c.SaveInterval = cfg.SaveInterval
+	}
+	return c
+}
+
+// ParseArg takes an arg string and converts it to a config
+func ParseArg(arg string) (Config, error) {
+	c := NewConfig()
+
+	if !strings.Contains(arg, "=") {
+		c.FileName = null.StringFrom(arg)
+		return c, nil
+	}
+
+	for pair := range strings.SplitSeq(arg, ",") {
+		k, v, _ := strings.Cut(pair, "=")
+		if v == "" {
+			return c, fmt.Errorf("couldn't parse %q as argument for sqlite output", arg)
+		}
+		switch k {
+		case "saveInterval":
+			err := c.SaveInterval.UnmarshalText([]byte(v))
+			if err != nil {
+				return c, err
+			}
+		case "fileName":
+			c.FileName = null.StringFrom(v)
+		default:
+			return c, fmt.Errorf("unknown key %q as argument for sqlite output", k)
+		}
+	}
+
+	return c, nil
+}
+
+// GetConsolidatedConfig combines {default config values + JSON config +
+// environment vars + arg config values}, and returns the final result.
+func GetConsolidatedConfig(
+	jsonRawConf json.RawMessage, env map[string]string, arg string,
+) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+
+	envConfig := Config{}
+	if err := envconfig.Process("", &envConfig, func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}); err != nil {
+		return result, err
+	}
+	result = result.Apply(envConfig)
+
+	if arg != "" {
+		argConf, err := ParseArg(arg)
+		if err != nil {
+			return result, err
+		}
+		result = result.Apply(argConf)
+	}
+
+	if result.FileName.String == "" {
+		return result, errors.New("the sqlite output needs a file name")
+	}
+
+	return result, nil
+}
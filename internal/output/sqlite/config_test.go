@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/lib/types"
+)
+
+func TestGetConsolidatedConfig(t *testing.T) {
+	t.Parallel()
+
+	testData := []struct {
+		name   string
+		json   string
+		env    map[string]string
+		arg    string
+		config Config
+		err    string
+	}{
+		{
+			name:   "defaults",
+			config: NewConfig(),
+		},
+		{
+			name: "file name only",
+			arg:  "k6.db",
+			config: NewConfig().Apply(Config{
+				FileName: null.StringFrom("k6.db"),
+			}),
+		},
+		{
+			name: "all the sources",
+			json: `{"file_name":"json.db","save_interval":"10s"}`,
+			env: map[string]string{
+				"K6_SQLITE_FILENAME":      "env.db",
+				"K6_SQLITE_SAVE_INTERVAL": "2s",
+			},
+			arg: "saveInterval=5s",
+			config: NewConfig().Apply(Config{
+				FileName:     null.StringFrom("env.db"),
+				SaveInterval: types.NullDurationFrom(5 * time.Second),
+			}),
+		},
+		{
+			name: "unknown key",
+			arg:  "fileName=k6.db,journal=wal",
+			err:  `unknown key "journal" as argument for sqlite output`,
+		},
+		{
+			name: "empty file name",
+			json: `{"file_name":""}`,
+			err:  "the sqlite output needs a file name",
+		},
+	}
+
+	for _, tc := range testData {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var jsonConf []byte
+			if tc.json != "" {
+				jsonConf = []byte(tc.json)
+			}
+			config, err := GetConsolidatedConfig(jsonConf, tc.env, tc.arg)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.config, config)
+		})
+	}
+}
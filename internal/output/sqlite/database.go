@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+
+	"modernc.org/sqlite"
+)
+
+// schema creates the tables, it's documented in the package's description.
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id          INTEGER PRIMARY KEY,
+	script      TEXT NOT NULL,
+	k6_version  TEXT NOT NULL,
+	started_at  INTEGER NOT NULL,
+	finished_at INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS metrics (
+	run_id   INTEGER NOT NULL REFERENCES runs (id),
+	name     TEXT NOT NULL,
+	type     TEXT NOT NULL,
+	contains TEXT NOT NULL,
+	PRIMARY KEY (run_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS samples (
+	run_id    INTEGER NOT NULL REFERENCES runs (id),
+	metric    TEXT NOT NULL,
+	timestamp INTEGER NOT NULL,
+	value     REAL NOT NULL,
+	tags      TEXT NOT NULL,
+	metadata  TEXT
+);
+
+CREATE INDEX IF NOT EXISTS samples_metric ON samples (run_id, metric, timestamp);
+
+CREATE TABLE IF NOT EXISTS groups (
+	run_id INTEGER NOT NULL REFERENCES runs (id),
+	path   TEXT NOT NULL,
+	name   TEXT NOT NULL,
+	parent TEXT NOT NULL,
+	PRIMARY KEY (run_id, path)
+);
+
+CREATE TABLE IF NOT EXISTS checks (
+	run_id     INTEGER NOT NULL REFERENCES runs (id),
+	group_path TEXT NOT NULL,
+	name       TEXT NOT NULL,
+	passes     INTEGER NOT NULL,
+	fails      INTEGER NOT NULL,
+	PRIMARY KEY (run_id, group_path, name)
+);
+
+CREATE TABLE IF NOT EXISTS thresholds (
+	run_id        INTEGER NOT NULL REFERENCES runs (id),
+	metric        TEXT NOT NULL,
+	source        TEXT NOT NULL,
+	abort_on_fail INTEGER NOT NULL,
+	passed        INTEGER
+);
+`
+
+//nolint:gochecknoinits
+func init() {
+	sqlite.MustRegisterFunction("percentile", &sqlite.FunctionImpl{
+		NArgs:         2,
+		Deterministic: true,
+		MakeAggregate: func(sqlite.FunctionContext) (sqlite.AggregateFunction, error) {
+			return &percentile{}, nil
+		},
+	})
+}
+
+//nolint:gochecknoglobals
+var uriPathEscaper = strings.NewReplacer("%", "%25", "?", "%3F", "#", "%23")
+
+// Open opens the SQLite database in the given file, with the percentile(value, p)
+// aggregate function available to the queries.
+func Open(fileName string, readOnly bool) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	if readOnly {
+		params.Add("mode", "ro")
+	} else {
+		params.Add("_pragma", "journal_mode(WAL)")
+		params.Add("_pragma", "synchronous(NORMAL)")
+	}
+
+	// SQLite decodes the %-escapes of the URI filenames, and the ones of the
+	// characters delimiting the query and the fragment keep them in the path
+	dsn := url.URL{
+		Scheme:   "file",
+		Opaque:   uriPathEscaper.Replace(fileName),
+		RawQuery: params.Encode(),
+	}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("couldn't open the database %q: %w", fileName, err)
+	}
+	return db, nil
+}
+
+// percentile is an aggregate function returning the p-th percentile of the values,
+// with a linear interpolation between the closest ranks as the trend metrics do.
+type percentile struct {
+	values []float64
+	p      float64
+}
+
+func (pc *percentile) Step(_ *sqlite.FunctionContext, args []driver.Value) error {
+	v, ok, err := toFloat(args[0])
+	if err != nil || !ok {
+		return err
+	}
+	p, ok, err := toFloat(args[1])
+	if err != nil {
+		return err
+	}
+	if !ok || p < 0 || p > 100 {
+		return errors.New("the percentile must be a number between 0 and 100")
+	}
+	pc.p = p
+	pc.values = append(pc.values, v)
+	return nil
+}
+
+func (pc *percentile) WindowInverse(_ *sqlite.FunctionContext, args []driver.Value) error {
+	v, ok, err := toFloat(args[0])
+	if err != nil || !ok {
+		return err
+	}
+	if i := slices.Index(pc.values, v); i >= 0 {
+		pc.values = slices.Delete(pc.values, i, i+1)
+	}
+	return nil
+}
+
+func (pc *percentile) WindowValue(_ *sqlite.FunctionContext) (driver.Value, error) {
+	if len(pc.values) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+	sorted := slices.Clone(pc.values)
+	slices.Sort(sorted)
+
+	rank := pc.p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	if lower == len(sorted)-1 {
+		return sorted[lower], nil
+	}
+	return sorted[lower] + (sorted[lower+1]-sorted[lower])*(rank-float64(lower)), nil
+}
+
+func (pc *percentile) Final(_ *sqlite.FunctionContext) {}
+
+// toFloat converts an SQL value to a float, the NULLs are reported as not ok.
+func toFloat(v driver.Value) (float64, bool, error) {
+	switch v := v.(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		return float64(v), true, nil
+	case float64:
+		return v, true, nil
+	default:
+		return 0, false, fmt.Errorf("the percentile function expects numbers, got %T", v)
+	}
+}
@@ -0,0 +1,36 @@
+// Package sqlite implements an output writing the results in a SQLite database,
+// to analyze a test run with plain SQL queries, for example with `k6 x query`.
+//
+// The database is written with modernc.org/sqlite, a translation of SQLite's C
+// code to Go, as the k6 binaries are built with CGO_ENABLED=0 and a cgo driver
+// would make the output unavailable in them.
+//
+// A database can contain many test runs, each one with a row in the runs table.
+// The other tables reference it with their run_id column:
+//
+//   - runs: the script, the k6 version, started_at and finished_at
+//     (NULL while the test is running).
+//   - metrics: the name, type (counter, gauge, rate or trend) and contains
+//     (default, time or data) of each metric with samples.
+//   - samples: the metric's name, the timestamp, the value, the tags as a
+//     JSON object and the metadata as a JSON object, or NULL.
+//   - groups: the path, name and parent's path of each group with samples,
+//     the root group isn't included.
+//   - checks: the passes and fails of each check, by group_path and name.
+//   - thresholds: the metric, source and abort_on_fail of each threshold,
+//     and if it passed, written at the end of the test.
+//
+// The timestamps are the number of microseconds since the Unix epoch, and the
+// values of the time metrics are in milliseconds. The tags can be extracted with
+// the SQLite's JSON functions, and the percentile(value, p) aggregate function
+// computes the percentiles like the trend metrics do. For example, the 99th
+// percentile of the requests' duration per URL and per minute is:
+//
+//	SELECT json_extract(tags, '$.url') AS url,
+//	       datetime(timestamp / 60000000 * 60, 'unixepoch') AS minute,
+//	       percentile(value, 99) AS p99
+//	FROM samples
+//	WHERE metric = 'http_req_duration'
+//	GROUP BY url, minute
+//	ORDER BY url, minute
+package sqlite
@@ -0,0 +1,303 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"go.k6.io/k6/v2/internal/build"
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/metrics"
+	"go.k6.io/k6/v2/output"
+)
+
+// Output writes the samples and the test's results in a SQLite database.
+type Output struct {
+	output.SampleBuffer
+
+	params          output.Params
+	periodicFlusher *output.PeriodicFlusher
+
+	logger       logrus.FieldLogger
+	fname        string
+	saveInterval time.Duration
+
+	db    *sql.DB
+	runID int64
+
+	seenMetrics map[string]struct{}
+	seenGroups  map[string]struct{}
+	thresholds  map[string]metrics.Thresholds
+}
+
+var _ output.WithThresholds = &Output{}
+
+// New creates a new instance of the sqlite output
+func New(params output.Params) (output.Output, error) {
+	return newOutput(params)
+}
+
+func newOutput(params output.Params) (*Output, error) {
+	config, err := GetConsolidatedConfig(params.JSONConfig, params.Environment, params.ConfigArgument)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Output{
+		params:       params,
+		fname:        config.FileName.String,
+		saveInterval: config.SaveInterval.TimeDuration(),
+		logger: params.Logger.WithFields(logrus.Fields{
+			"output":   "sqlite",
+			"filename": config.FileName.String,
+		}),
+		seenMetrics: make(map[string]struct{}),
+		seenGroups:  make(map[string]struct{}),
+	}, nil
+}
+
+// Description returns a human-readable description of the output.
+func (o *Output) Description() string {
+	return fmt.Sprintf("sqlite (%s)", o.fname)
+}
+
+// SetThresholds receives the thresholds before the output is Start()-ed.
+func (o *Output) SetThresholds(thresholds map[string]metrics.Thresholds) {
+	if len(thresholds) == 0 {
+		return
+	}
+	o.thresholds = make(map[string]metrics.Thresholds, len(thresholds))
+	maps.Copy(o.thresholds, thresholds)
+}
+
+// Start opens the database, creates the tables if they don't exist yet,
+// records the new test run and starts a new output.PeriodicFlusher.
+func (o *Output) Start() error {
+	o.logger.Debug("Starting...")
+
+	db, err := Open(o.fname, false)
+	if err != nil {
+		return err
+	}
+	// a single connection is enough, and avoids the database being locked by ourselves
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return fmt.Errorf("couldn't create the sqlite output's tables: %w", err)
+	}
+
+	var script string
+	if o.params.ScriptPath != nil {
+		script = o.params.ScriptPath.String()
+	}
+	res, err := db.Exec(`INSERT INTO runs (script, k6_version, started_at) VALUES (?, ?, ?)`,
+		script, build.Version, time.Now().UnixMicro())
+	if err != nil {
+		_ = db.Close()
+		return err
+	}
+	o.runID, err = res.LastInsertId()
+	if err != nil {
+		_ = db.Close()
+		return err
+	}
+	o.db = db
+
+	pf, err := output.NewPeriodicFlusher(o.saveInterval, o.flushMetrics)
+	if err != nil {
+		return err
+	}
+	o.logger.Debug("Started!")
+	o.periodicFlusher = pf
+
+	return nil
+}
+
+// Stop flushes any remaining metrics, records the thresholds' results
+// and the end of the test run, and closes the database.
+func (o *Output) Stop() error {
+	o.logger.Debug("Stopping...")
+	defer o.logger.Debug("Stopped!")
+	o.periodicFlusher.Stop()
+
+	err := o.inTransaction(func(tx *sql.Tx) error {
+		for name, thresholds := range o.thresholds {
+			for _, t := range thresholds.Thresholds {
+				_, err := tx.Exec(
+					`INSERT INTO thresholds (run_id, metric, source, abort_on_fail, passed) VALUES (?, ?, ?, ?, ?)`,
+					o.runID, name, t.Source, t.AbortOnFail, !t.LastFailed)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		_, err := tx.Exec(`UPDATE runs SET finished_at = ? WHERE id = ?`, time.Now().UnixMicro(), o.runID)
+		return err
+	})
+	if err != nil {
+		_ = o.db.Close()
+		return err
+	}
+	return o.db.Close()
+}
+
+// inTransaction runs fn in a transaction, committed if fn doesn't return an error.
+func (o *Output) inTransaction(fn func(*sql.Tx) error) error {
+	tx, err := o.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// flushMetrics writes the buffered samples, with the new metrics,
+// groups and checks' results, in a single transaction.
+func (o *Output) flushMetrics() {
+	samples := o.GetBufferedSamples()
+	if len(samples) == 0 {
+		return
+	}
+	start := time.Now()
+
+	var count int
+	written := newWrittenRows()
+	err := o.inTransaction(func(tx *sql.Tx) error {
+		var err error
+		count, err = o.writeSamples(tx, samples, written)
+		return err
+	})
+	if err != nil {
+		o.logger.WithError(err).Error("SQLite: Error writing to the database")
+		return
+	}
+	// the metrics and groups are only seen once they are committed,
+	// so they are written again by the next flush after a rollback
+	maps.Copy(o.seenMetrics, written.metrics)
+	maps.Copy(o.seenGroups, written.groups)
+	o.logger.WithField("t", time.Since(start)).WithField("count", count).Debug("Wrote metrics to sqlite")
+}
+
+type checkKey struct {
+	group, name string
+}
+
+// writtenRows are the metrics and groups written in the current transaction.
+type writtenRows struct {
+	metrics map[string]struct{}
+	groups  map[string]struct{}
+}
+
+func newWrittenRows() *writtenRows {
+	return &writtenRows{metrics: make(map[string]struct{}), groups: make(map[string]struct{})}
+}
+
+func (o *Output) writeSamples(tx *sql.Tx, samples []metrics.SampleContainer, written *writtenRows) (int, error) {
+	insertSample, err := tx.Prepare(
+		`INSERT INTO samples (run_id, metric, timestamp, value, tags, metadata) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = insertSample.Close() }()
+
+	var count int
+	checks := make(map[checkKey][2]int64)
+	for _, sc := range samples {
+		for _, sample := range sc.GetSamples() {
+			if err := o.writeMetric(tx, sample.Metric, written); err != nil {
+				return count, err
+			}
+
+			tags := sample.Tags.Map()
+			if err := o.writeGroup(tx, tags["group"], written); err != nil {
+				return count, err
+			}
+			if sample.Metric.Name == metrics.ChecksName {
+				key := checkKey{group: tags["group"], name: tags["check"]}
+				results := checks[key]
+				if sample.Value != 0 {
+					results[0]++
+				} else {
+					results[1]++
+				}
+				checks[key] = results
+			}
+
+			// a map of strings can't fail to be marshaled
+			encodedTags, _ := json.Marshal(tags) //nolint:errchkjson
+			var metadata any
+			if len(sample.Metadata) > 0 {
+				b, _ := json.Marshal(sample.Metadata) //nolint:errchkjson
+				metadata = string(b)
+			}
+			_, err := insertSample.Exec(o.runID, sample.Metric.Name, sample.Time.UnixMicro(), sample.Value,
+				string(encodedTags), metadata)
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+
+	for key, results := range checks {
+		_, err := tx.Exec(`INSERT INTO checks (run_id, group_path, name, passes, fails) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (run_id, group_path, name)
+			DO UPDATE SET passes = passes + excluded.passes, fails = fails + excluded.fails`,
+			o.runID, key.group, key.name, results[0], results[1])
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// writeMetric writes the metric's definition, the first time it's seen.
+func (o *Output) writeMetric(tx *sql.Tx, m *metrics.Metric, written *writtenRows) error {
+	if _, ok := o.seenMetrics[m.Name]; ok {
+		return nil
+	}
+	if _, ok := written.metrics[m.Name]; ok {
+		return nil
+	}
+	_, err := tx.Exec(`INSERT OR IGNORE INTO metrics (run_id, name, type, contains) VALUES (?, ?, ?, ?)`,
+		o.runID, m.Name, m.Type.String(), m.Contains.String())
+	if err != nil {
+		return err
+	}
+	written.metrics[m.Name] = struct{}{}
+	return nil
+}
+
+// writeGroup writes the group and its ancestors, the first time they are seen.
+// The root group, with the empty path, isn't written.
+func (o *Output) writeGroup(tx *sql.Tx, path string, written *writtenRows) error {
+	for path != "" {
+		if _, ok := o.seenGroups[path]; ok {
+			return nil
+		}
+		if _, ok := written.groups[path]; ok {
+			return nil
+		}
+		parent, name := "", path
+		if i := strings.LastIndex(path, lib.GroupSeparator); i >= 0 {
+			parent, name = path[:i], path[i+len(lib.GroupSeparator):]
+		}
+		_, err := tx.Exec(`INSERT OR IGNORE INTO groups (run_id, path, name, parent) VALUES (?, ?, ?, ?)`,
+			o.runID, path, name, parent)
+		if err != nil {
+			return err
+		}
+		written.groups[path] = struct{}{}
+		path = parent
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package sqlite
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/lib/testutils"
+	"go.k6.io/k6/v2/metrics"
+	"go.k6.io/k6/v2/output"
+)
+
+// queryRows returns the rows of the query, with the values formatted as strings.
+func queryRows(t *testing.T, db *sql.DB, query string) [][]string {
+	t.Helper()
+
+	rows, err := db.QueryContext(t.Context(), query)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, rows.Close()) }()
+
+	columns, err := rows.Columns()
+	require.NoError(t, err)
+
+	var result [][]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		pointers := make([]any, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		require.NoError(t, rows.Scan(pointers...))
+
+		row := make([]string, len(values))
+		for i, v := range values {
+			row[i] = v.String
+			if !v.Valid {
+				row[i] = "NULL"
+			}
+		}
+		result = append(result, row)
+	}
+	require.NoError(t, rows.Err())
+	return result
+}
+
+func runTest(t *testing.T, fileName string, failedThreshold bool) {
+	t.Helper()
+
+	o, err := newOutput(output.Params{
+		Logger:         testutils.NewLogger(t),
+		ConfigArgument: "fileName=" + fileName,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "sqlite ("+fileName+")", o.Description())
+
+	registry := metrics.NewRegistry()
+	checks := registry.MustNewMetric(metrics.ChecksName, metrics.Rate)
+	duration := registry.MustNewMetric("http_req_duration", metrics.Trend, metrics.Time)
+	thresholds := metrics.NewThresholds([]string{"p(95)<500"})
+	thresholds.Thresholds[0].LastFailed = failedThreshold
+	o.SetThresholds(map[string]metrics.Thresholds{"http_req_duration": thresholds})
+
+	require.NoError(t, o.Start())
+	now := time.Unix(1700000000, 0)
+	for i := range 4 {
+		tags := registry.RootTagSet().With("group", "::login::form")
+		o.AddMetricSamples([]metrics.SampleContainer{metrics.Samples{
+			{
+				TimeSeries: metrics.TimeSeries{Metric: duration, Tags: tags.With("url", "https://k6.io")},
+				Time:       now.Add(time.Duration(i) * 20 * time.Second),
+				Value:      float64(100 * (i + 1)),
+				Metadata:   map[string]string{"vu": "1"},
+			},
+			{
+				TimeSeries: metrics.TimeSeries{Metric: checks, Tags: tags.With("check", "status is 200")},
+				Time:       now.Add(time.Duration(i) * 20 * time.Second),
+				Value:      float64(i % 2),
+			},
+		}})
+		o.flushMetrics()
+	}
+	require.NoError(t, o.Stop())
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "results.db")
+	runTest(t, fileName, false)
+	runTest(t, fileName, true)
+
+	db, err := Open(fileName, true)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	assert.Equal(t, [][]string{{"1", "1"}, {"2", "1"}},
+		queryRows(t, db, `SELECT id, finished_at > started_at FROM runs ORDER BY id`))
+
+	assert.Equal(t, [][]string{
+		{"checks", "rate", "default"},
+		{"http_req_duration", "trend", "time"},
+	}, queryRows(t, db, `SELECT name, type, contains FROM metrics WHERE run_id = 2 ORDER BY name`))
+
+	assert.Equal(t, [][]string{
+		{"checks", "1700000000000000", "0", "status is 200", "NULL"},
+		{"http_req_duration", "1700000000000000", "100", "https://k6.io", `{"vu":"1"}`},
+		{"checks", "1700000020000000", "1", "status is 200", "NULL"},
+	}, queryRows(t, db, `SELECT metric, timestamp, value, coalesce(tags ->> 'url', tags ->> 'check'), metadata
+		FROM samples WHERE run_id = 1 ORDER BY timestamp, metric LIMIT 3`))
+
+	assert.Equal(t, [][]string{
+		{"::login", "login", ""},
+		{"::login::form", "form", "::login"},
+	}, queryRows(t, db, `SELECT path, name, parent FROM groups WHERE run_id = 1 ORDER BY path`))
+
+	assert.Equal(t, [][]string{{"::login::form", "status is 200", "2", "2"}},
+		queryRows(t, db, `SELECT group_path, name, passes, fails FROM checks WHERE run_id = 1`))
+
+	assert.Equal(t, [][]string{
+		{"1", "http_req_duration", "p(95)<500", "0", "1"},
+		{"2", "http_req_duration", "p(95)<500", "0", "0"},
+	}, queryRows(t, db, `SELECT run_id, metric, source, abort_on_fail, passed FROM thresholds ORDER BY run_id`))
+
+	assert.Equal(t, [][]string{
+		{"1699999980", "150", "190"},
+		{"1700000040", "350", "390"},
+	}, queryRows(t, db, `SELECT timestamp / 60000000 * 60 AS minute, percentile(value, 50), percentile(value, 90)
+		FROM samples WHERE run_id = 1 AND metric = 'http_req_duration' GROUP BY minute ORDER BY minute`))
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	db, err := Open(filepath.Join(t.TempDir(), "results.db"), false)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	assert.Equal(t, [][]string{{"1", "2.875", "5", "NULL"}}, queryRows(t, db, `
+		SELECT percentile(value, 0), percentile(value, 62.5), percentile(value, 100), percentile(NULL, 50)
+		FROM (SELECT 2 AS value UNION ALL SELECT 5 UNION ALL SELECT NULL UNION ALL SELECT 1 UNION ALL SELECT 3)`))
+
+	assert.Equal(t, [][]string{{"1", "1"}, {"2", "1.5"}, {"3", "2.5"}}, queryRows(t, db, `
+		SELECT value, percentile(value, 50) OVER (ORDER BY value ROWS BETWEEN 1 PRECEDING AND CURRENT ROW)
+		FROM (SELECT 1 AS value UNION ALL SELECT 2 UNION ALL SELECT 3)`))
+
+	_, err = db.Exec(`SELECT percentile(1, 101)`)
+	require.ErrorContains(t, err, "the percentile must be a number between 0 and 100")
+	_, err = db.Exec(`SELECT percentile('a', 50)`)
+	require.ErrorContains(t, err, "the percentile function expects numbers, got string")
+}
+
+func TestOpenEscapedFileName(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "results?mode=ro#%41.db")
+	db, err := Open(fileName, false)
+	require.NoError(t, err)
+	_, err = db.Exec(`CREATE TABLE test (id INTEGER)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	assert.FileExists(t, fileName)
+}
+
+func TestFlushAfterRollback(t *testing.T) {
+	t.Parallel()
+
+	fileName := filepath.Join(t.TempDir(), "results.db")
+	o, err := newOutput(output.Params{
+		Logger:         testutils.NewLogger(t),
+		ConfigArgument: "fileName=" + fileName,
+	})
+	require.NoError(t, err)
+	require.NoError(t, o.Start())
+
+	registry := metrics.NewRegistry()
+	checks := registry.MustNewMetric(metrics.ChecksName, metrics.Rate)
+	sample := metrics.Samples{{
+		TimeSeries: metrics.TimeSeries{
+			Metric: checks,
+			Tags:   registry.RootTagSet().With("group", "::login").With("check", "status is 200"),
+		},
+		Time:  time.Unix(1700000000, 0),
+		Value: 1,
+	}}
+
+	// the checks are written last, so the metric and the group are rolled back
+	_, err = o.db.Exec(`ALTER TABLE checks RENAME TO checks_renamed`)
+	require.NoError(t, err)
+	o.AddMetricSamples([]metrics.SampleContainer{sample})
+	o.flushMetrics()
+
+	_, err = o.db.Exec(`ALTER TABLE checks_renamed RENAME TO checks`)
+	require.NoError(t, err)
+	o.AddMetricSamples([]metrics.SampleContainer{sample})
+	o.flushMetrics()
+	require.NoError(t, o.Stop())
+
+	db, err := Open(fileName, true)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, db.Close()) })
+
+	assert.Equal(t, [][]string{{"checks"}}, queryRows(t, db, `SELECT name FROM metrics`))
+	assert.Equal(t, [][]string{{"::login"}}, queryRows(t, db, `SELECT path FROM groups`))
+	assert.Equal(t, [][]string{{"1"}}, queryRows(t, db, `SELECT count(*) FROM samples`))
+}
@@ -0,0 +1,17 @@
+package common
+
+import "io"
+
+// ReadableFile is a file that a JS module lets other modules read, like the
+// files of k6/experimental/fs that k6/http uploads.
+//
+// It's meant for Go only, so it's implemented by a type of the module that
+// isn't exposed to the scripts, rather than by the file object itself.
+type ReadableFile interface {
+	// FilePath returns the path of the file, as presented by the script.
+	FilePath() string
+
+	// OpenContent returns a reader of the whole content of the file and its
+	// size. Reading it doesn't move the offset the script reads the file at.
+	OpenContent() (io.ReadCloser, int64, error)
+}
@@ -1,14 +1,25 @@
 package http
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/grafana/sobek"
+
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/fs"
+	"go.k6.io/k6/v2/js/common"
+	"go.k6.io/k6/v2/lib/netext/httpext"
 )
 
-// FileData represents a binary file requiring multipart request encoding
+// FileData represents a binary file requiring multipart request encoding.
+//
+// Data is either a string, an ArrayBuffer, or a file of another module, like
+// k6/experimental/fs, whose content is streamed when the request is sent.
 type FileData struct {
 	Data        any
 	Filename    string
@@ -37,7 +48,14 @@ func (mi *ModuleInstance) file(data any, args ...string) (*FileData, error) {
 	switch data.(type) {
 	case string, sobek.ArrayBuffer:
 	default:
-		return nil, fmt.Errorf("invalid type %T, expected string or ArrayBuffer", data)
+		f, ok := fs.ReadableFileOf(data)
+		if !ok {
+			return nil, fmt.Errorf("invalid type %T, expected string, ArrayBuffer or File", data)
+		}
+		// default to the name of the file itself
+		if len(args) == 0 {
+			fname = filepath.Base(f.FilePath())
+		}
 	}
 
 	return &FileData{
@@ -46,3 +64,106 @@ func (mi *ModuleInstance) file(data any, args ...string) (*FileData, error) {
 		ContentType: ct,
 	}, nil
 }
+
+// multipartBody is the body of a multipart request, written by a
+// [multipart.Writer], in which the content of the uploaded files is only read
+// when the request is sent, instead of being held in memory.
+type multipartBody struct {
+	parts []multipartPart
+
+	// pending holds what was written since the last file.
+	pending bytes.Buffer
+}
+
+// multipartPart is either a chunk written by the multipart.Writer, or the
+// content of a file.
+type multipartPart struct {
+	data []byte
+	file common.ReadableFile
+	size int64
+}
+
+// Write implements io.Writer for the multipart.Writer.
+func (b *multipartBody) Write(p []byte) (int, error) {
+	return b.pending.Write(p)
+}
+
+// addFile adds the content of a file, after the header of its part was written.
+func (b *multipartBody) addFile(file common.ReadableFile) error {
+	content, size, err := file.OpenContent()
+	if err != nil {
+		return err
+	}
+	_ = content.Close()
+
+	b.flush()
+	b.parts = append(b.parts, multipartPart{file: file, size: size})
+	return nil
+}
+
+func (b *multipartBody) flush() {
+	if b.pending.Len() == 0 {
+		return
+	}
+
+	data := bytes.Clone(b.pending.Bytes())
+	b.parts = append(b.parts, multipartPart{data: data, size: int64(len(data))})
+	b.pending.Reset()
+}
+
+// setBody sets the body of the request, which is only streamed if files are
+// uploaded.
+func (b *multipartBody) setBody(preq *httpext.ParsedHTTPRequest) {
+	if len(b.parts) == 0 {
+		preq.Body = &b.pending
+		return
+	}
+
+	b.flush()
+	var length int64
+	for _, part := range b.parts {
+		length += part.size
+	}
+	preq.StreamedBody = &httpext.StreamedBody{Open: b.open, Length: length}
+}
+
+// open returns a reader of the whole body, for every time the request is sent.
+func (b *multipartBody) open() (io.ReadCloser, error) {
+	readers := make([]io.Reader, 0, len(b.parts))
+	closers := make([]io.Closer, 0, len(b.parts))
+	for _, part := range b.parts {
+		if part.file == nil {
+			readers = append(readers, bytes.NewReader(part.data))
+			continue
+		}
+
+		content, _, err := part.file.OpenContent()
+		if err != nil {
+			closeAll(closers)
+			return nil, err
+		}
+		// the file may have grown since its part was added, the length of the body is already set
+		readers = append(readers, io.LimitReader(content, part.size))
+		closers = append(closers, content)
+	}
+
+	return &multiReadCloser{Reader: io.MultiReader(readers...), closers: closers}, nil
+}
+
+// multiReadCloser reads the files of a multipart body, and closes all of them.
+type multiReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *multiReadCloser) Close() error {
+	return closeAll(r.closers)
+}
+
+func closeAll(closers []io.Closer) error {
+	errs := make([]error, 0, len(closers))
+	for _, c := range closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -2,10 +2,14 @@ package http
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/grafana/sobek"
 	"github.com/stretchr/testify/require"
+
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/fs"
 )
 
 func TestHTTPFile(t *testing.T) {
@@ -39,7 +43,7 @@ func TestHTTPFile(t *testing.T) {
 			&FileData{Data: input, Filename: "test-ab.bin", ContentType: "application/octet-stream"},
 			"",
 		},
-		{func(*sobek.Runtime) any { return struct{}{} }, []string{}, &FileData{}, "GoError: invalid type struct {}, expected string or ArrayBuffer"},
+		{func(*sobek.Runtime) any { return struct{}{} }, []string{}, &FileData{}, "GoError: invalid type struct {}, expected string, ArrayBuffer or File"},
 	}
 
 	for i, tc := range testCases {
@@ -75,3 +79,43 @@ func TestHTTPFileDataInRequest(t *testing.T) {
     }`))
 	require.NoError(t, err)
 }
+
+func TestHTTPFileFromTempDirInRequest(t *testing.T) {
+	t.Parallel()
+	ts := newTestCase(t)
+
+	fsModule, ok := fs.New().NewModuleInstance(ts.runtime.VU).(*fs.ModuleInstance)
+	require.True(t, ok)
+	require.NoError(t, ts.runtime.VU.Runtime().Set("fs", fsModule.Exports().Named))
+
+	dir, err := fsModule.TempDir(sobek.Undefined())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(filepath.Dir(dir.Path)) })
+
+	_, err = ts.runtime.RunOnEventLoop(wrapInAsyncLambda(ts.tb.Replacer.Replace(`
+    const dir = fs.tempDir();
+    await dir.write("upload.txt", "hello, ");
+    await dir.append("upload.txt", "world");
+
+    const file = await dir.open("upload.txt");
+    if (file.filePath !== undefined || file.openContent !== undefined) {
+      throw new Error("Unexpected methods of the file exposed to the script")
+    }
+
+    const head = new Uint8Array(5);
+    await file.read(head);
+
+    const res = http.post("HTTPBIN_URL/post", { field: "value", upload: http.file(file) });
+    const files = res.json().files;
+    if (files.upload != "hello, world") {
+      throw new Error("Unexpected uploaded files " + JSON.stringify(files))
+    }
+
+    // the upload doesn't move the offset of the file
+    const rest = new Uint8Array(7);
+    const n = await file.read(rest);
+    if (n != 7 || String.fromCharCode(...rest) != ", world") {
+      throw new Error("Unexpected rest of the file " + String.fromCharCode(...rest.subarray(0, n)))
+    }`)))
+	require.NoError(t, err)
+}
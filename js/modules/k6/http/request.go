@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/sobek"
 	"gopkg.in/guregu/null.v3"
 
+	"go.k6.io/k6/v2/internal/js/modules/k6/experimental/fs"
 	"go.k6.io/k6/v2/js/common"
 	"go.k6.io/k6/v2/lib/netext/httpext"
 	"go.k6.io/k6/v2/lib/types"
@@ -201,11 +202,12 @@ func (c *Client) parseRequest(
 		}
 
 		// handling multipart request
-		result.Body = &bytes.Buffer{}
-		mpw := multipart.NewWriter(result.Body)
+		body := &multipartBody{}
+		mpw := multipart.NewWriter(body)
 
-		// For parameters of type common.FileData, created with open(file, "b"),
-		// we write the file boundary to the body buffer.
+		// For parameters of type common.FileData, created with open(file, "b")
+		// or with k6/experimental/fs files, we write the file boundary to the body,
+		// and the content of the latter files is streamed when the request is sent.
 		// Otherwise parameters are treated as standard form field.
 		for k, v := range data {
 			switch ve := v.(type) {
@@ -226,6 +228,15 @@ func (c *Client) parseRequest(
 					return err
 				}
 
+				if f, ok := fs.ReadableFileOf(ve.Data); ok {
+					// files are sent from their start, regardless of where
+					// the script has read them to
+					if err := body.addFile(f); err != nil {
+						return err
+					}
+					continue
+				}
+
 				data, err := common.ToBytes(ve.Data)
 				if err != nil {
 					return err
@@ -248,6 +259,7 @@ func (c *Client) parseRequest(
 		if err := mpw.Close(); err != nil {
 			return err
 		}
+		body.setBody(result)
 
 		result.Req.Header.Set("Content-Type", mpw.FormDataContentType())
 		return nil
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.k6.io/k6/v2/internal/js/compiler"
 	"go.k6.io/k6/v2/internal/js/eventloop"
+	"go.k6.io/k6/v2/internal/js/tc55/fetch"
 	"go.k6.io/k6/v2/internal/js/tc55/timers"
 
 	"go.k6.io/k6/v2/internal/js/modules/k6/webcrypto"
@@ -59,6 +60,7 @@ func NewRuntime(t testing.TB) *Runtime {
 	}
 	require.NoError(t, timers.SetupGlobally(vu))
 	require.NoError(t, webcrypto.SetupGlobally(vu))
+	require.NoError(t, fetch.SetupGlobally(vu))
 	// let's cancel again in case it has changed
 	t.Cleanup(func() { result.CancelContext() })
 	return result
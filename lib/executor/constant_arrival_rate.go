@@ -213,6 +213,8 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	returnedVUs := make(chan struct{})
 	waitOnProgressChannel := make(chan struct{})
 	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	regDurationCtx, stop := context.WithCancel(regDurationCtx)
+	defer stop()
 	defer func() {
 		cancel()
 		<-waitOnProgressChannel
@@ -257,6 +259,7 @@ func (car ConstantArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 		Executor:   car.config.Type,
 		StartTime:  startTime,
 		ProgressFn: progressFn,
+		Stop:       stop,
 	})
 
 	go func() {
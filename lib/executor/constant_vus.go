@@ -129,6 +129,8 @@ func (clv ConstantVUs) Run(parentCtx context.Context, _ chan<- metrics.SampleCon
 
 	waitOnProgressChannel := make(chan struct{})
 	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	regDurationCtx, stop := context.WithCancel(regDurationCtx)
+	defer stop()
 	defer func() {
 		cancel()
 		<-waitOnProgressChannel
@@ -156,6 +158,7 @@ func (clv ConstantVUs) Run(parentCtx context.Context, _ chan<- metrics.SampleCon
 		Executor:   clv.config.Type,
 		StartTime:  startTime,
 		ProgressFn: progressFn,
+		Stop:       stop,
 	})
 
 	go func() {
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"testing/synctest"
 	"time"
@@ -56,3 +57,28 @@ func TestConstantVUsRun(t *testing.T) {
 		assert.Equal(t, uint64(50), totalIters)
 	})
 }
+
+func TestConstantVUsRunStop(t *testing.T) {
+	t.Parallel()
+
+	synctest.Test(t, func(t *testing.T) {
+		var iterations atomic.Int64
+
+		runner := simpleRunner(func(ctx context.Context, _ *lib.State) error {
+			if iterations.Add(1) == 20 {
+				lib.GetScenarioState(ctx).Stop()
+			}
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		})
+
+		test := setupExecutorTest(t, "", "", lib.Options{}, runner, getTestConstantVUsConfig())
+		defer test.cancel()
+
+		start := time.Now()
+		require.NoError(t, test.executor.Run(test.ctx, nil))
+
+		assert.Equal(t, 200*time.Millisecond, time.Since(start))
+		assert.Equal(t, int64(20), iterations.Load())
+	})
+}
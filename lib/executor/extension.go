@@ -0,0 +1,28 @@
+package executor
+
+import (
+	"go.k6.io/k6/v2/ext"
+	"go.k6.io/k6/v2/lib"
+)
+
+// RegisterExtension registers the given executor extension config constructor,
+// making the executor available under the given name as the `executor` of
+// scenarios. This function panics if an executor extension with the same name
+// is already registered, and scenarios using a name that is also the one of a
+// built-in executor fail to be parsed.
+//
+// The constructor receives the name of the scenario and its raw JSON options,
+// and returns a [lib.ExecutorConfig], whose NewExecutor method returns the
+// [lib.Executor] running the scenario. As for the built-in executors, the
+// configs are expected to scale their requirements with the execution segment
+// of the given [lib.ExecutionTuple], and can embed [BaseConfig] to handle the
+// options common to all scenarios.
+//
+// This function must be called during package initialization, typically in
+// an init() function.
+func RegisterExtension(name string, constructor lib.ExecutorConfigConstructor) {
+	if constructor == nil {
+		panic("executor extensions: constructor is nil")
+	}
+	ext.Register(name, ext.ExecutorExtension, constructor)
+}
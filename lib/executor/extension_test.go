@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+
+	"go.k6.io/k6/v2/lib"
+	"go.k6.io/k6/v2/lib/types"
+)
+
+const (
+	testExtensionType    = "test-extension-vus"
+	testConflictingType  = "test-conflicting-vus"
+	testExtensionVUs     = 10
+	testExtensionSegment = "0:1/2"
+)
+
+// testExtensionConfig is an executor extension config reusing the constant-vus executor.
+type testExtensionConfig struct {
+	ConstantVUsConfig
+}
+
+func newTestExtensionConfig(name string, rawJSON []byte) (lib.ExecutorConfig, error) {
+	config := testExtensionConfig{ConstantVUsConfig: NewConstantVUsConfig(name)}
+	config.Type = testExtensionType
+	err := lib.StrictJSONUnmarshal(rawJSON, &config)
+	return config, err
+}
+
+func init() {
+	RegisterExtension(testExtensionType, newTestExtensionConfig)
+
+	lib.RegisterExecutorConfigType(testConflictingType, newTestExtensionConfig)
+	RegisterExtension(testConflictingType, newTestExtensionConfig)
+}
+
+func TestExecutorExtension(t *testing.T) {
+	t.Parallel()
+
+	t.Run("scenarios", func(t *testing.T) {
+		t.Parallel()
+
+		var scenarios lib.ScenarioConfigs
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"extension": {"executor": "test-extension-vus", "vus": 10, "duration": "10s"},
+			"builtin": {"executor": "constant-vus", "vus": 4, "duration": "10s", "startTime": "10s"}
+		}`), &scenarios))
+		require.Len(t, scenarios, 2)
+		require.Empty(t, scenarios.Validate())
+
+		config, ok := scenarios["extension"].(testExtensionConfig)
+		require.True(t, ok)
+		assert.Equal(t, testExtensionType, config.GetType())
+		assert.Equal(t, "extension", config.GetName())
+		assert.Equal(t, null.IntFrom(testExtensionVUs), config.VUs)
+		assert.Equal(t, types.NullDurationFrom(10*time.Second), config.Duration)
+
+		segment, err := lib.NewExecutionSegmentFromString(testExtensionSegment)
+		require.NoError(t, err)
+		et, err := lib.NewExecutionTuple(segment, nil)
+		require.NoError(t, err)
+
+		// the built-in scenario starts during the graceful stop of the extension one,
+		// so both are scaled to half of their VUs and added up
+		steps := scenarios.GetFullExecutionRequirements(et)
+		assert.Equal(t, uint64(7), lib.GetMaxPlannedVUs(steps))
+		assert.Equal(t, uint64(7), lib.GetMaxPossibleVUs(steps))
+	})
+
+	t.Run("conflicting built-in executor", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := lib.GetParsedExecutorConfig("conflict", testConflictingType, []byte(`{}`))
+		require.ErrorContains(t, err, "is both built-in and registered by an extension")
+	})
+
+	t.Run("nil constructor", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { RegisterExtension("test-nil-constructor", nil) })
+	})
+}
@@ -140,6 +140,8 @@ func (pvi PerVUIterations) Run(parentCtx context.Context, out chan<- metrics.Sam
 
 	waitOnProgressChannel := make(chan struct{})
 	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	regDurationCtx, stop := context.WithCancel(regDurationCtx)
+	defer stop()
 	defer func() {
 		cancel()
 		<-waitOnProgressChannel
@@ -179,6 +181,7 @@ func (pvi PerVUIterations) Run(parentCtx context.Context, out chan<- metrics.Sam
 		Executor:   pvi.config.Type,
 		StartTime:  startTime,
 		ProgressFn: progressFn,
+		Stop:       stop,
 	})
 	go func() {
 		trackProgress(parentCtx, maxDurationCtx, regDurationCtx, pvi, progressFn)
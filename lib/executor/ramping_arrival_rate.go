@@ -336,6 +336,8 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 	returnedVUs := make(chan struct{})
 	waitOnProgressChannel := make(chan struct{})
 	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	regDurationCtx, stop := context.WithCancel(regDurationCtx)
+	defer stop()
 
 	vusPool := newActiveVUPool(varr.executionState)
 
@@ -387,6 +389,7 @@ func (varr RampingArrivalRate) Run(parentCtx context.Context, out chan<- metrics
 		Executor:   varr.config.Type,
 		StartTime:  startTime,
 		ProgressFn: progressFn,
+		Stop:       stop,
 	})
 	go func() {
 		trackProgress(parentCtx, maxDurationCtx, regDurationCtx, &varr, progressFn)
@@ -508,6 +508,8 @@ func (vlv *RampingVUs) Run(ctx context.Context, _ chan<- metrics.SampleContainer
 		cancel()
 		<-waitOnProgressChannel
 	}()
+	stopCtx, stop := context.WithCancel(ctx)
+	defer stop()
 
 	maxVUs := lib.GetMaxPlannedVUs(vlv.gracefulSteps)
 
@@ -534,6 +536,7 @@ func (vlv *RampingVUs) Run(ctx context.Context, _ chan<- metrics.SampleContainer
 		Executor:   vlv.config.Type,
 		StartTime:  runState.started,
 		ProgressFn: progressFn,
+		Stop:       stop,
 	})
 	vlv.progress.Modify(pb.WithProgress(progressFn))
 	go func() {
@@ -550,10 +553,17 @@ func (vlv *RampingVUs) Run(ctx context.Context, _ chan<- metrics.SampleContainer
 		handleNewScheduledVUs  = runState.scheduledVUsHandlerStrategy()
 	)
 	handledGracefulSteps := runState.iterateSteps(
-		ctx,
+		stopCtx,
 		handleNewMaxAllowedVUs,
 		handleNewScheduledVUs,
 	)
+	if stopCtx.Err() != nil && ctx.Err() == nil {
+		// The scenario was stopped before its end, so no more VUs are started
+		// and the running ones are stopped once their iteration is finished.
+		for _, vh := range runState.vuHandles {
+			vh.gracefulStop()
+		}
+	}
 	go runState.runRemainingGracefulSteps(
 		ctx,
 		handleNewMaxAllowedVUs,
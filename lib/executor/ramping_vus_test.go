@@ -1210,3 +1210,41 @@ func TestSumRandomSegmentSequenceMatchesNoSegment(t *testing.T) {
 		})
 	}
 }
+
+func TestRampingVUsRunStop(t *testing.T) {
+	t.Parallel()
+
+	synctest.Test(t, func(t *testing.T) {
+		config := RampingVUsConfig{
+			BaseConfig:       BaseConfig{GracefulStop: types.NullDurationFrom(time.Second)},
+			GracefulRampDown: types.NullDurationFrom(time.Second),
+			StartVUs:         null.IntFrom(5),
+			Stages: []Stage{
+				{
+					Duration: types.NullDurationFrom(1 * time.Second),
+					Target:   null.IntFrom(10),
+				},
+			},
+		}
+
+		var iterCount atomic.Int64
+
+		runner := simpleRunner(func(ctx context.Context, _ *lib.State) error {
+			if iterCount.Add(1) == 5 {
+				lib.GetScenarioState(ctx).Stop()
+			}
+			time.Sleep(300 * time.Millisecond)
+			return nil
+		})
+
+		test := setupExecutorTest(t, "", "", lib.Options{}, runner, config)
+		defer test.cancel()
+
+		start := time.Now()
+		require.NoError(t, test.executor.Run(test.ctx, nil))
+
+		assert.Equal(t, 300*time.Millisecond, time.Since(start))
+		assert.Equal(t, int64(5), iterCount.Load())
+		assert.Zero(t, test.state.GetCurrentlyActiveVUsCount())
+	})
+}
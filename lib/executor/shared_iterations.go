@@ -172,6 +172,8 @@ func (si SharedIterations) Run(parentCtx context.Context, out chan<- metrics.Sam
 
 	waitOnProgressChannel := make(chan struct{})
 	startTime, maxDurationCtx, regDurationCtx, cancel := getDurationContexts(parentCtx, duration, gracefulStop)
+	regDurationCtx, stop := context.WithCancel(regDurationCtx)
+	defer stop()
 	defer func() {
 		cancel()
 		<-waitOnProgressChannel
@@ -204,6 +206,7 @@ func (si SharedIterations) Run(parentCtx context.Context, out chan<- metrics.Sam
 		Executor:   si.config.Type,
 		StartTime:  startTime,
 		ProgressFn: progressFn,
+		Stop:       stop,
 	})
 	go func() {
 		trackProgress(parentCtx, maxDurationCtx, regDurationCtx, &si, progressFn)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"go.k6.io/k6/v2/ext"
 	"go.k6.io/k6/v2/internal/ui/pb"
 	"go.k6.io/k6/v2/metrics"
 )
@@ -105,6 +106,10 @@ type ScenarioState struct {
 	Name, Executor string
 	StartTime      time.Time
 	ProgressFn     func() (float64, []string)
+
+	// Stop stops the scenario before its end, no new iterations are started
+	// and the running ones are given the graceful stop period to finish.
+	Stop func()
 }
 
 // InitVUFunc is just a shorthand so we don't have to type the function
@@ -321,12 +326,29 @@ func GetParsedExecutorConfig(name, configType string, rawJSON []byte) (result Ex
 	defer executorConfigTypesMutex.Unlock()
 
 	constructor, exists := executorConfigConstructors[configType]
-	if !exists {
+	extConstructor, extExists := getExtensionExecutorConfigConstructor(configType)
+	switch {
+	case exists && extExists:
+		return nil, fmt.Errorf("executor type '%s' is both built-in and registered by an extension", configType)
+	case extExists:
+		constructor = extConstructor
+	case !exists:
 		return nil, fmt.Errorf("unknown executor type '%s'", configType)
 	}
 	return constructor(name, rawJSON)
 }
 
+// getExtensionExecutorConfigConstructor returns the ExecutorConfigConstructor
+// of the executor extension registered with the given type, if any.
+func getExtensionExecutorConfigConstructor(configType string) (ExecutorConfigConstructor, bool) {
+	e, exists := ext.Get(ext.ExecutorExtension)[configType]
+	if !exists {
+		return nil, false
+	}
+	constructor, ok := e.Module.(ExecutorConfigConstructor)
+	return constructor, ok
+}
+
 type protoExecutorConfig struct {
 	executorType string
 	rawJSON      json.RawMessage
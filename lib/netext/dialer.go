@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -61,7 +62,15 @@ func (d *Dialer) DialContext(ctx context.Context, proto, addr string) (net.Conn,
 	if err != nil {
 		return nil, err
 	}
-	conn, err := d.Dialer.DialContext(ctx, proto, dialAddr.String())
+	dialer := &d.Dialer
+	// The local address from the localIPs option is always a TCP one,
+	// but net.Dialer requires it to match the network being dialed.
+	if localAddr, ok := d.LocalAddr.(*net.TCPAddr); ok && strings.HasPrefix(proto, "udp") {
+		udpDialer := d.Dialer
+		udpDialer.LocalAddr = &net.UDPAddr{IP: localAddr.IP, Port: localAddr.Port, Zone: localAddr.Zone}
+		dialer = &udpDialer
+	}
+	conn, err := dialer.DialContext(ctx, proto, dialAddr.String())
 	if err != nil {
 		return nil, err
 	}
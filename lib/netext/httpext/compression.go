@@ -78,6 +78,37 @@ func compressBody(algos []CompressionType, body io.ReadCloser) (*bytes.Buffer, s
 	return buf, contentEncoding, body.Close()
 }
 
+// Compress returns the data compressed with the given compression type.
+func Compress(compression CompressionType, data []byte) ([]byte, error) {
+	buf, _, err := compressBody([]CompressionType{compression}, io.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decompress returns the data decompressed with the given compression type.
+func Decompress(compression CompressionType, data []byte) ([]byte, error) {
+	rc := &readCloser{bytes.NewReader(data)}
+	decoder, err := pickDecoder(compression, rc)
+	if err != nil {
+		return nil, err
+	}
+
+	rc = &readCloser{decoder}
+	out, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rc.Close(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
 //nolint:gochecknoglobals
 var decompressionErrors = [...]error{
 	zlib.ErrChecksum, zlib.ErrDictionary, zlib.ErrHeader,
@@ -36,10 +36,22 @@ type Request struct {
 	Cookies map[string][]*HTTPRequestCookie `json:"cookies"`
 }
 
+// StreamedBody is a request body that is read when the request is sent,
+// instead of being held in memory. Unlike Body, it isn't kept as the body of
+// the response's request, unless it is compressed.
+type StreamedBody struct {
+	// Open returns a new reader of the body, every time the request is sent.
+	Open func() (io.ReadCloser, error)
+
+	// Length holds the length of the body.
+	Length int64
+}
+
 // ParsedHTTPRequest is a representation of a request after it has been parsed from a user script.
 type ParsedHTTPRequest struct {
 	URL              *URL
 	Body             *bytes.Buffer
+	StreamedBody     *StreamedBody // used when Body is nil
 	Req              *http.Request
 	Timeout          time.Duration
 	Auth             string
@@ -53,6 +65,21 @@ type ParsedHTTPRequest struct {
 	TagsAndMeta      metrics.TagsAndMeta
 }
 
+func readStreamedBody(streamed *StreamedBody) (*bytes.Buffer, error) {
+	body, err := streamed.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = body.Close() }()
+
+	buf := bytes.NewBuffer(make([]byte, 0, streamed.Length))
+	if _, err := buf.ReadFrom(body); err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
+
 // ncloser matches non-compliant io.Closer implementations (e.g. zstd.Decoder).
 type ncloser interface {
 	Close()
@@ -119,6 +146,21 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 		Headers: preq.Req.Header,
 	}
 
+	if preq.Body == nil && preq.StreamedBody != nil && len(preq.Compressions) > 0 {
+		// the body has to be read in memory to be compressed
+		body, err := readStreamedBody(preq.StreamedBody)
+		if err != nil {
+			return nil, err
+		}
+		preq.Body, preq.StreamedBody = body, nil
+	}
+
+	if preq.Body == nil && preq.StreamedBody != nil {
+		// the body is opened right before the request is sent, so that the client closes it
+		preq.Req.ContentLength = preq.StreamedBody.Length
+		preq.Req.GetBody = preq.StreamedBody.Open
+	}
+
 	if preq.Body != nil {
 		// TODO: maybe hide this behind of flag in order for this to not happen for big post/puts?
 		// should we set this after the compression? what will be the point ?
@@ -268,6 +310,13 @@ func MakeRequest(ctx context.Context, state *lib.State, preq *ParsedHTTPRequest)
 	reqCtx, cancelFunc := context.WithTimeout(ctx, preq.Timeout)
 	defer cancelFunc()
 	mreq := preq.Req.WithContext(reqCtx)
+	if preq.Body == nil && preq.StreamedBody != nil {
+		body, err := preq.StreamedBody.Open()
+		if err != nil {
+			return nil, err
+		}
+		mreq.Body = body
+	}
 	res, resErr := client.Do(mreq) //nolint:gosec
 
 	// TODO(imiric): It would be safer to check for a writeable
@@ -21,6 +21,7 @@ import (
 	"golang.org/x/time/rate"
 	"gopkg.in/guregu/null.v3"
 
+	"go.k6.io/k6/v2/internal/lib/trace"
 	"go.k6.io/k6/v2/lib"
 	"go.k6.io/k6/v2/metrics"
 )
@@ -80,6 +81,26 @@ func TestCompressionBodyError(t *testing.T) {
 	})
 }
 
+func TestCompressDecompress(t *testing.T) {
+	t.Parallel()
+	data := bytes.Repeat([]byte("k6 compression "), 100)
+	for _, compression := range CompressionTypeValues() {
+		t.Run(compression.String(), func(t *testing.T) {
+			t.Parallel()
+			compressed, err := Compress(compression, data)
+			require.NoError(t, err)
+			assert.Less(t, len(compressed), len(data))
+
+			decompressed, err := Decompress(compression, compressed)
+			require.NoError(t, err)
+			assert.Equal(t, data, decompressed)
+
+			_, err = Decompress(compression, data)
+			require.Error(t, err)
+		})
+	}
+}
+
 func TestMakeRequestError(t *testing.T) {
 	t.Parallel()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -591,3 +612,67 @@ func TestMakeRequestRPSLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestMakeRequestTraceRequests(t *testing.T) {
+	t.Parallel()
+
+	headers := make(chan string, 2)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Get(trace.TraceparentHeader)
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+
+	samples := make(chan metrics.SampleContainer, 10)
+	logger := logrus.New()
+	logger.Out = io.Discard
+
+	registry := metrics.NewRegistry()
+	state := &lib.State{
+		Options: lib.Options{
+			SystemTags:    &metrics.DefaultSystemTagSet,
+			MaxRedirects:  null.IntFrom(10),
+			TraceRequests: null.BoolFrom(true),
+		},
+		Transport:      ts.Client().Transport,
+		Samples:        samples,
+		Logger:         logger,
+		BufferPool:     lib.NewBufferPool(),
+		BuiltinMetrics: metrics.RegisterBuiltinMetrics(registry),
+		Tags:           lib.NewVUStateTags(registry.RootTagSet()),
+	}
+	state.Tags.Modify(trace.StartIteration)
+	iteration := state.Tags.GetCurrentValues().Metadata
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, ts.URL+"/redirect", nil)
+	require.NoError(t, err)
+	preq := &ParsedHTTPRequest{
+		Req:          req,
+		URL:          &URL{u: req.URL, URL: req.URL.String()},
+		Redirects:    null.IntFrom(10),
+		Timeout:      10 * time.Second,
+		TagsAndMeta:  state.Tags.GetCurrentValues(),
+		ResponseType: ResponseTypeNone,
+	}
+	_, err = MakeRequest(t.Context(), state, preq)
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get(trace.TraceparentHeader))
+
+	// each request of the redirect chain has its own span, child of the iteration's one
+	spanIDs := map[string]bool{}
+	for range 2 {
+		traceparent := <-headers
+		trail, ok := (<-samples).(*Trail)
+		require.True(t, ok)
+		for _, sample := range trail.GetSamples() {
+			assert.Equal(t, iteration[trace.MetadataTraceID], sample.Metadata[trace.MetadataTraceID])
+			assert.Equal(t, iteration[trace.MetadataSpanID], sample.Metadata[trace.MetadataParentSpanID])
+		}
+		spanID := trail.Samples[0].Metadata[trace.MetadataSpanID]
+		assert.Equal(t, trace.Traceparent(iteration[trace.MetadataTraceID], spanID), traceparent)
+		spanIDs[spanID] = true
+	}
+	assert.Len(t, spanIDs, 2)
+}
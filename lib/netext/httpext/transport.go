@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"sync"
 
+	"go.k6.io/k6/v2/internal/lib/trace"
 	"go.k6.io/k6/v2/lib"
 	"go.k6.io/k6/v2/lib/netext"
 	"go.k6.io/k6/v2/metrics"
@@ -35,6 +36,9 @@ type unfinishedRequest struct {
 	request  *http.Request
 	response *http.Response
 	err      error
+
+	// spanID is the span propagated in the request, if any
+	spanID string
 }
 
 // finishedRequest is produced once the request has been finalized; it is
@@ -81,6 +85,9 @@ func (t *transport) measureAndEmitMetrics(unfReq *unfinishedRequest) *finishedRe
 	}
 
 	tagsAndMeta := t.tagsAndMeta.Clone()
+	if unfReq.spanID != "" {
+		trace.SetSpan(&tagsAndMeta, unfReq.spanID, tagsAndMeta.Metadata[trace.MetadataSpanID])
+	}
 	enabledTags := t.state.Options.SystemTags
 	cleanURL := URL{u: unfReq.request.URL, URL: unfReq.request.URL.String()}.Clean()
 
@@ -203,6 +210,15 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	tracer := &Tracer{}
 	// nosemgrep: dynamic-httptrace-clienttrace // this is a false possitive
 	reqWithTracer := req.WithContext(httptrace.WithClientTrace(ctx, tracer.Trace()))
+
+	// each request of a redirect chain is a separate span
+	var spanID string
+	if traceID := t.tagsAndMeta.Metadata[trace.MetadataTraceID]; traceID != "" && t.state.Options.TraceRequests.Bool {
+		spanID = trace.NewSpanID()
+		reqWithTracer.Header = req.Header.Clone()
+		reqWithTracer.Header.Set(trace.TraceparentHeader, trace.Traceparent(traceID, spanID))
+	}
+
 	resp, err := t.state.Transport.RoundTrip(reqWithTracer)
 
 	var netError net.Error
@@ -221,6 +237,7 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		request:  req,
 		response: resp,
 		err:      err,
+		spanID:   spanID,
 	})
 
 	return resp, err
@@ -44,7 +44,16 @@ type cacheResolver struct {
 func NewResolver(
 	actRes MultiResolver, ttl time.Duration, sel types.DNSSelect, pol types.DNSPolicy,
 ) Resolver {
-	r := rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec
+	return NewSeededResolver(actRes, ttl, sel, pol, time.Now().UnixNano())
+}
+
+// NewSeededResolver returns a new DNS resolver like NewResolver, selecting
+// the random IPs with a generator seeded with the given seed, so the selected
+// IPs are reproducible.
+func NewSeededResolver(
+	actRes MultiResolver, ttl time.Duration, sel types.DNSSelect, pol types.DNSPolicy, seed int64,
+) Resolver {
+	r := rand.New(rand.NewSource(seed)) //nolint:gosec
 	res := resolver{
 		resolve:     actRes,
 		selectIndex: sel,
@@ -96,4 +96,21 @@ func TestResolver(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("SeededRandom", func(t *testing.T) {
+		t.Parallel()
+
+		lookupIPs := func(seed int64) []net.IP {
+			r := NewSeededResolver(mr.LookupIPAll, 0, types.DNSrandom, types.DNSany, seed)
+			ips := make([]net.IP, 0, 10)
+			for range 10 {
+				ip, err := r.LookupIP(host)
+				require.NoError(t, err)
+				ips = append(ips, ip)
+			}
+			return ips
+		}
+
+		assert.Equal(t, lookupIPs(42), lookupIPs(42))
+	})
 }
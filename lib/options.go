@@ -336,6 +336,16 @@ type Options struct {
 	// Do not reset cookies after a VU iteration
 	NoCookiesReset null.Bool `json:"noCookiesReset" envconfig:"K6_NO_COOKIES_RESET"`
 
+	// Start a trace for each iteration and propagate its context in the HTTP and gRPC requests
+	TraceRequests null.Bool `json:"traceRequests" envconfig:"K6_TRACE_REQUESTS"`
+
+	// Seed of the random generators of the VUs, which are then seeded for each
+	// iteration, making the random values of a test run reproducible.
+	//
+	// The seeded generators are also used for the random bytes of the crypto
+	// modules, which are then not cryptographically secure.
+	Seed null.Int `json:"seed" envconfig:"K6_SEED"`
+
 	// Discard Http Responses Body
 	DiscardResponseBodies null.Bool `json:"discardResponseBodies" envconfig:"K6_DISCARD_RESPONSE_BODIES"`
 
@@ -345,6 +355,10 @@ type Options struct {
 	// Specify client IP ranges and/or CIDR from which VUs will make requests
 	LocalIPs types.NullIPPool `json:"-" envconfig:"K6_LOCAL_IPS"`
 
+	// OutputProcessing holds the rules processing the samples before they are
+	// sent to the outputs, as a JSON object with a list of rules per output type.
+	OutputProcessing json.RawMessage `json:"outputProcessing,omitempty" ignored:"true"`
+
 	// Features decodes the "features" key from the JSON config file (a resolution
 	// surface) and from the script's exported options (unsupported, warned about).
 	Features []string `json:"features" ignored:"true"`
@@ -478,12 +492,21 @@ func (o Options) Apply(opts Options) Options {
 	if opts.NoCookiesReset.Valid {
 		o.NoCookiesReset = opts.NoCookiesReset
 	}
+	if opts.TraceRequests.Valid {
+		o.TraceRequests = opts.TraceRequests
+	}
+	if opts.Seed.Valid {
+		o.Seed = opts.Seed
+	}
 	if opts.Cloud != nil {
 		o.Cloud = opts.Cloud
 	}
 	if opts.External != nil {
 		o.External = opts.External
 	}
+	if opts.OutputProcessing != nil {
+		o.OutputProcessing = opts.OutputProcessing
+	}
 	if opts.SummaryTrendStats != nil {
 		o.SummaryTrendStats = opts.SummaryTrendStats
 	}
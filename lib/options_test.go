@@ -418,6 +418,12 @@ func TestOptions(t *testing.T) {
 		assert.True(t, opts.NoCookiesReset.Valid)
 		assert.True(t, opts.NoCookiesReset.Bool)
 	})
+	t.Run("TraceRequests", func(t *testing.T) {
+		t.Parallel()
+		opts := Options{}.Apply(Options{TraceRequests: null.BoolFrom(true)})
+		assert.True(t, opts.TraceRequests.Valid)
+		assert.True(t, opts.TraceRequests.Bool)
+	})
 	t.Run("BlacklistIPs", func(t *testing.T) {
 		t.Parallel()
 		opts := Options{}.Apply(Options{
@@ -494,6 +500,12 @@ func TestOptions(t *testing.T) {
 		opts := Options{}.Apply(Options{External: ext})
 		assert.Equal(t, ext, opts.External)
 	})
+	t.Run("OutputProcessing", func(t *testing.T) {
+		t.Parallel()
+		processing := json.RawMessage(`{"csv":[{"action":"drop","metric":"vus"}]}`)
+		opts := Options{}.Apply(Options{OutputProcessing: processing})
+		assert.Equal(t, processing, opts.OutputProcessing)
+	})
 
 	t.Run("JSON", func(t *testing.T) {
 		t.Parallel()
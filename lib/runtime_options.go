@@ -36,12 +36,19 @@ type RuntimeOptions struct {
 	// default one, so we can handle `k6 run --compatibility-mode=base es6_extended_archive.tar`
 	CompatibilityMode null.String `json:"compatibilityMode"`
 
+	// Whether to bundle the local modules imported by the test with it, in a
+	// single module, before running or archiving it. The TypeScript types are
+	// stripped without being checked.
+	Bundle null.Bool `json:"bundle"`
+
 	// Environment variables passed onto the runner
 	Env map[string]string `json:"env"`
 
 	NoThresholds  null.Bool   `json:"noThresholds"`
 	SummaryMode   null.String `json:"summaryMode"`
 	SummaryExport null.String `json:"summaryExport"`
+	SummaryJUnit  null.String `json:"summaryJUnit"`
+	SummaryTAP    null.String `json:"summaryTAP"`
 	KeyWriter     null.String `json:"-"`
 	TracesOutput  null.String `json:"tracesOutput"`
 
@@ -0,0 +1,23 @@
+package lib
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// DeriveSeed returns a seed derived from the seed option and the identifiers,
+// e.g. the numbers of a VU and its iteration, so each of them has its own
+// reproducible sequence of random values.
+func DeriveSeed(seed int64, ids ...uint64) int64 {
+	h := fnv.New64a()
+
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(seed)) //nolint:gosec
+	_, _ = h.Write(b[:])
+	for _, id := range ids {
+		binary.LittleEndian.PutUint64(b[:], id)
+		_, _ = h.Write(b[:])
+	}
+
+	return int64(h.Sum64()) //nolint:gosec
+}
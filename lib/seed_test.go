@@ -0,0 +1,17 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveSeed(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DeriveSeed(42, 1, 2), DeriveSeed(42, 1, 2))
+	assert.NotEqual(t, DeriveSeed(42, 1, 2), DeriveSeed(43, 1, 2))
+	assert.NotEqual(t, DeriveSeed(42, 1, 2), DeriveSeed(42, 2, 1))
+	assert.NotEqual(t, DeriveSeed(42, 1), DeriveSeed(42, 1, 0))
+	assert.NotEqual(t, DeriveSeed(42), DeriveSeed(42, 0))
+}
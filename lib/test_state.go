@@ -33,10 +33,23 @@ type TestPreInitState struct {
 	// initialization and stable for the whole run.
 	FeatureFlags *features.Flags
 
+	// DataController is the execution controller of the test run, it is set
+	// once the test is loaded and used to share data between its instances.
+	DataController DataController
+
 	// FIXME (@oleiade): is this the way?
 	TestStatus *TestStatus
 }
 
+// DataController is the part of the execution controller that is used for
+// sharing data between all the instances of a test run, e.g. by the k6/data
+// SharedMap and Counter.
+type DataController interface {
+	// GetOrCreateData returns the data chunk with the given ID, calling the
+	// callback to create it if this is the first call for the ID.
+	GetOrCreateData(ID string, callback func() ([]byte, error)) ([]byte, error)
+}
+
 // TestStatus holds the test execution status and is used to support marking a test as failed
 // while letting the execution go on.
 type TestStatus struct {
@@ -3,6 +3,7 @@ package lib
 import (
 	"context"
 	"crypto/tls"
+	"math/rand" // nosemgrep: math-random-used // used for the seeded random values
 	"net"
 	"net/http"
 	"net/http/cookiejar"
@@ -74,6 +75,10 @@ type State struct {
 	VUID, VUIDGlobal uint64
 	Iteration        int64
 
+	// Rand is the random generator of the VU, seeded for each iteration from
+	// the seed option. It's nil if the seed option isn't set.
+	Rand *rand.Rand
+
 	// TODO: rename this field with one more representative
 	// because it includes now also the metadata.
 	Tags *VUStateTags
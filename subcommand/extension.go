@@ -6,6 +6,9 @@
 package subcommand
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/spf13/cobra"
 	"go.k6.io/k6/v2/cmd/state"
 	"go.k6.io/k6/v2/ext"
@@ -21,6 +24,12 @@ import (
 // unpredictable behavior.
 type Constructor func(*state.GlobalState) *cobra.Command
 
+// reservedNames holds the names of the built-in subcommands of `k6 x`, which
+// extensions can't use.
+//
+//nolint:gochecknoglobals
+var reservedNames = []string{"query"}
+
 // RegisterExtension registers a subcommand extension with the given name and constructor function.
 //
 // The name parameter specifies the subcommand name that users will invoke (e.g., "k6 <name>").
@@ -28,9 +37,13 @@ type Constructor func(*state.GlobalState) *cobra.Command
 // instance for this subcommand.
 //
 // This function must be called during package initialization (typically in an init() function)
-// and will panic if a subcommand with the same name is already registered.
+// and will panic if a subcommand with the same name is already registered, or
+// if the name is reserved by a built-in subcommand, like "query".
 //
 // The name parameter and the returned Command's Name() must match.
 func RegisterExtension(name string, c Constructor) {
+	if slices.Contains(reservedNames, name) {
+		panic(fmt.Sprintf("subcommand name reserved by k6: %s", name))
+	}
 	ext.Register(name, ext.SubcommandExtension, c)
 }